@@ -29,7 +29,8 @@ sleep 2
 
 export NIL_RPC_ENDPOINT=http://127.0.0.1:8529
 nil -c config.ini config set rpc_endpoint "$NIL_RPC_ENDPOINT"
-export PRIVATE_KEY=$(nil -c config.ini keygen new -q)
+export NIL_KEYSTORE_PASSPHRASE=test
+export PRIVATE_KEY=$(nil -c config.ini keygen new -q --print-private-key)
 export SMART_ACCOUNT_ADDR=$(nil -c config.ini smart-account new -q)
 
 echo "Rpc endpoint: $NIL_RPC_ENDPOINT"
@@ -15,6 +15,10 @@ export default class TestHelper {
 
     for (const key of Object.keys(commands)) {
       switch (key) {
+        case "KEYGEN_COMMAND":
+          // The tests share the keystore, so the account is replaced on every run
+          result[key] = `${commands[key]} --force --config ${this.configFileName}`;
+          break;
         case "SMART_ACCOUNT_CREATION_COMMAND":
           result[key] = `${commands[key]} --config ${this.configFileName} --salt ${salt}`;
           break;
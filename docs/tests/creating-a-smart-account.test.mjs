@@ -19,7 +19,7 @@ const CONFIG_FLAG = `--config ./tests/${CONFIG_FILE_NAME}`;
 const CONFIG_COMMAND = `${NIL_GLOBAL} config init ${CONFIG_FLAG}`;
 const RPC_COMMAND = `${NIL_GLOBAL} config set rpc_endpoint ${RPC_ENDPOINT} ${CONFIG_FLAG}`;
 const FAUCET_COMMAND = `${NIL_GLOBAL} config set faucet_endpoint ${FAUCET_ENDPOINT} ${CONFIG_FLAG}`;
-const KEYGEN_COMMAND = `${NIL_GLOBAL} keygen new ${CONFIG_FLAG}`;
+const KEYGEN_COMMAND = `${NIL_GLOBAL} keygen new --force ${CONFIG_FLAG}`;
 
 //startSmartAccount
 const SMART_ACCOUNT_CREATION_COMMAND = `${NIL_GLOBAL} smart-account new --salt ${SALT} ${CONFIG_FLAG}`;
@@ -8,4 +8,7 @@ export const FAUCET_GLOBAL = process.env.USE_NILD
   ? "http://127.0.0.1:8529"
   : "https://api.devnet.nil.foundation/api/nil_user/TEK83KSDZH58AIK9PCYSNU4G86DU55I9/";
 export const NIL_GLOBAL = "nil";
+
+// The keystore passphrase for the keys generated by the CLI in the tests.
+process.env.NIL_KEYSTORE_PASSPHRASE ??= "test";
 export const NODE_MODULES = "--include-path ../node_modules/ --base-path ../ ";
@@ -6,7 +6,7 @@ import {
   CONTRACT_ADDRESS_PATTERN,
   FAUCET_PATTERN,
   NEW_SMART_ACCOUNT_PATTERN,
-  PRIVATE_KEY_PATTERN,
+  PUBKEY_PATTERN,
   RPC_PATTERN,
   SMART_ACCOUNT_ADDRESS_PATTERN,
   SMART_ACCOUNT_BALANCE_PATTERN,
@@ -41,7 +41,7 @@ afterAll(async () => {
 describe.sequential("initial smart account setup tests", () => {
   test.sequential("keygen generation works via CLI", async () => {
     const { stdout, stderr } = await exec(TEST_COMMANDS.KEYGEN_COMMAND);
-    expect(stdout).toMatch(PRIVATE_KEY_PATTERN);
+    expect(stdout).toMatch(PUBKEY_PATTERN);
   });
 
   test.sequential("endpoint command should set the endpoint", async () => {
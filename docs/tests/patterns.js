@@ -12,7 +12,6 @@ export const TOKEN_PATTERN = /5000/;
 export const SUCCESSFUL_EXECUTION_PATTERN = /Compiler run successful/;
 export const PREV_BLOCK_PATTERN = /PrevBlock/;
 export const HASH_PATTERN = /0x[a-fA-F0-9]{64}/g;
-export const PRIVATE_KEY_PATTERN = /\bPrivate key: [a-f0-9]{64}\b/;
 export const RPC_PATTERN = /Set "rpc_endpoint" to /;
 export const FAUCET_PATTERN = /Set "faucet_endpoint" to /;
 export const NEW_SMART_ACCOUNT_PATTERN = /New smart account address/;
@@ -0,0 +1,303 @@
+// Package bind contains the runtime of the Go contract bindings generated by `nil abigen` and the generator itself.
+package bind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+var (
+	ErrNoCode       = errors.New("no contract code at the given address")
+	ErrNoBytecode   = errors.New("the binding has no bytecode to deploy")
+	ErrNoSigner     = errors.New("the signer is required to send a transaction")
+	ErrEventMissing = errors.New("the log is not the expected event")
+)
+
+// MetaData holds the ABI and the bytecode of a generated contract binding.
+// The ABI is parsed once on the first use.
+type MetaData struct {
+	ABI string
+	Bin string
+
+	once   sync.Once
+	parsed *abi.ABI
+	err    error
+}
+
+// GetAbi returns the parsed ABI of the contract.
+func (m *MetaData) GetAbi() (*abi.ABI, error) {
+	m.once.Do(func() {
+		parsed, err := abi.JSON(strings.NewReader(m.ABI))
+		if err != nil {
+			m.err = fmt.Errorf("failed to parse the contract ABI: %w", err)
+			return
+		}
+		m.parsed = &parsed
+	})
+	return m.parsed, m.err
+}
+
+// CallOpts are the options of a read-only call.
+type CallOpts struct {
+	// Context of the request, context.Background() if nil
+	Context context.Context
+	// BlockId is the block the call is executed at, "latest" if nil
+	BlockId any
+	// Overrides are applied to the state before the call
+	Overrides *jsonrpc.StateOverrides
+}
+
+// TransactOpts are the options of a transaction sent to a contract.
+type TransactOpts struct {
+	// Context of the requests, context.Background() if nil
+	Context context.Context
+	// Signer signs the external transaction
+	Signer client.Signer
+	// SmartAccount sends the transaction as an internal one via the smart account.
+	// If empty, the external transaction is sent directly to the contract, which has to accept it.
+	SmartAccount types.Address
+	// Fee of the external transaction, estimated if the fee credit is zero
+	Fee types.FeePack
+
+	// The fields below are only used for the transactions sent via the smart account
+
+	// Value is the amount of the default token sent along with the transaction
+	Value types.Value
+	// Tokens are the custom tokens sent along with the transaction
+	Tokens []types.TokenBalance
+	// ForwardKind defines how the fee of the internal transaction is paid, ForwardKindRemaining by default
+	ForwardKind types.ForwardKind
+	// FeeCredit of the internal transaction, estimated if zero and ForwardKind is ForwardKindNone
+	FeeCredit types.Value
+}
+
+func (opts *TransactOpts) context() context.Context {
+	if opts.Context == nil {
+		return context.Background()
+	}
+	return opts.Context
+}
+
+// BoundContract is the base of the generated bindings, it packs the calls and unpacks their results.
+type BoundContract struct {
+	address types.Address
+	abi     abi.ABI
+	client  client.Client
+}
+
+// NewBoundContract creates the contract binding at the address.
+func NewBoundContract(address types.Address, contractAbi abi.ABI, c client.Client) *BoundContract {
+	return &BoundContract{
+		address: address,
+		abi:     contractAbi,
+		client:  c,
+	}
+}
+
+// Address returns the address of the contract.
+func (c *BoundContract) Address() types.Address {
+	return c.address
+}
+
+// Call executes the read-only call of the method. The results are unpacked into the single
+// value passed in the results, or are appended to the empty results as returned by the ABI.
+func (c *BoundContract) Call(opts *CallOpts, results *[]any, method string, params ...any) error {
+	if opts == nil {
+		opts = &CallOpts{}
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	blockId := opts.BlockId
+	if blockId == nil {
+		blockId = "latest"
+	}
+
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Call(ctx, &jsonrpc.CallArgs{
+		To:   c.address,
+		Data: (*hexutil.Bytes)(&input),
+	}, blockId, opts.Overrides)
+	if err != nil {
+		return err
+	}
+	if res.Error != "" {
+		return fmt.Errorf("call of %s failed: %s", method, res.Error)
+	}
+	if len(res.Data) == 0 && len(c.abi.Methods[method].Outputs) > 0 {
+		code, err := c.client.GetCode(ctx, c.address, blockId)
+		if err != nil {
+			return err
+		}
+		if len(code) == 0 {
+			return ErrNoCode
+		}
+	}
+
+	if results == nil {
+		return nil
+	}
+	if len(*results) == 0 {
+		*results, err = c.abi.Unpack(method, res.Data)
+		return err
+	}
+	return c.abi.UnpackIntoInterface((*results)[0], method, res.Data)
+}
+
+// Transact sends the transaction calling the method.
+func (c *BoundContract) Transact(opts *TransactOpts, method string, params ...any) (common.Hash, error) {
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return c.transact(opts, input)
+}
+
+// RawTransact sends the transaction with the calldata, e.g. to call the fallback function.
+func (c *BoundContract) RawTransact(opts *TransactOpts, calldata []byte) (common.Hash, error) {
+	return c.transact(opts, calldata)
+}
+
+func (c *BoundContract) transact(opts *TransactOpts, input types.Code) (common.Hash, error) {
+	if opts == nil || opts.Signer == nil {
+		return common.EmptyHash, ErrNoSigner
+	}
+	ctx := opts.context()
+
+	if opts.SmartAccount.IsEmpty() {
+		return client.SendExternalTransaction(ctx, c.client, input, c.address, opts.Signer, opts.Fee, false, false)
+	}
+
+	payload, err := internalPayload(ctx, c.client, opts, input, c.address, types.ExecutionTransactionKind)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	calldata, err := client.EncodeSmartAccountSend(payload)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return client.SendExternalTransaction(ctx, c.client, calldata, opts.SmartAccount, opts.Signer, opts.Fee, false, false)
+}
+
+// internalPayload builds the internal transaction sent by the smart account of the options.
+func internalPayload(
+	ctx context.Context, c client.Client, opts *TransactOpts, data types.Code, to types.Address,
+	kind types.TransactionKind,
+) (*types.InternalTransactionPayload, error) {
+	payload := &types.InternalTransactionPayload{
+		Kind:        kind,
+		To:          to,
+		Value:       opts.Value,
+		Token:       opts.Tokens,
+		ForwardKind: opts.ForwardKind,
+		FeeCredit:   opts.FeeCredit,
+		Data:        data,
+	}
+
+	if payload.ForwardKind == types.ForwardKindNone && payload.FeeCredit.IsZero() {
+		from := opts.SmartAccount
+		res, err := c.EstimateFee(ctx, &jsonrpc.CallArgs{
+			Flags: types.TransactionFlagsFromKind(true, kind),
+			From:  &from,
+			To:    to,
+			Value: opts.Value,
+			Data:  (*hexutil.Bytes)(&data),
+		}, "latest")
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate the fee of the internal transaction: %w", err)
+		}
+		payload.FeeCredit = res.FeeCredit
+	}
+	return payload, nil
+}
+
+// UnpackLog unpacks the log of the event into the generated event struct.
+func (c *BoundContract) UnpackLog(out any, event string, log *types.Log) error {
+	ev, ok := c.abi.Events[event]
+	if !ok {
+		return fmt.Errorf("event %s is not found in the ABI", event)
+	}
+	if len(log.Topics) == 0 || log.Topics[0] != ev.ID {
+		return ErrEventMissing
+	}
+	if len(log.Data) > 0 {
+		if err := c.abi.UnpackIntoInterface(out, event, log.Data); err != nil {
+			return err
+		}
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range ev.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	return abi.ParseTopics(out, indexed, log.Topics[1:])
+}
+
+// DeployContract deploys the contract to the shard and returns the binding of the deployed contract.
+// With the smart account in the options the contract is deployed by an internal transaction,
+// otherwise the deploy transaction is external and the fees are paid from the balance of the new contract.
+func DeployContract(
+	opts *TransactOpts, c client.Client, shardId types.ShardId, salt common.Hash,
+	contractAbi abi.ABI, bytecode types.Code, params ...any,
+) (common.Hash, types.Address, *BoundContract, error) {
+	if len(bytecode) == 0 {
+		return common.EmptyHash, types.EmptyAddress, nil, ErrNoBytecode
+	}
+	args, err := contractAbi.Pack("", params...)
+	if err != nil {
+		return common.EmptyHash, types.EmptyAddress, nil, err
+	}
+	payload := types.BuildDeployPayload(append(bytecode.Clone(), args...), salt)
+
+	var hash common.Hash
+	var address types.Address
+	if opts.SmartAccount.IsEmpty() {
+		hash, address, err = c.DeployExternal(opts.context(), shardId, payload, opts.Fee)
+	} else {
+		hash, address, err = deployViaSmartAccount(opts, c, shardId, payload)
+	}
+	if err != nil {
+		return common.EmptyHash, types.EmptyAddress, nil, err
+	}
+	return hash, address, NewBoundContract(address, contractAbi, c), nil
+}
+
+func deployViaSmartAccount(
+	opts *TransactOpts, c client.Client, shardId types.ShardId, payload types.DeployPayload,
+) (common.Hash, types.Address, error) {
+	if opts.Signer == nil {
+		return common.EmptyHash, types.EmptyAddress, ErrNoSigner
+	}
+	ctx := opts.context()
+
+	address := types.CreateAddress(shardId, payload)
+	intPayload, err := internalPayload(ctx, c, opts, payload.Bytes(), address, types.DeployTransactionKind)
+	if err != nil {
+		return common.EmptyHash, types.EmptyAddress, err
+	}
+	calldata, err := client.EncodeSmartAccountSend(intPayload)
+	if err != nil {
+		return common.EmptyHash, types.EmptyAddress, err
+	}
+	hash, err := client.SendExternalTransaction(ctx, c, calldata, opts.SmartAccount, opts.Signer, opts.Fee, false, false)
+	if err != nil {
+		return common.EmptyHash, types.EmptyAddress, err
+	}
+	return hash, address, nil
+}
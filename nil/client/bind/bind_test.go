@@ -0,0 +1,255 @@
+package bind
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const testAbi = `[
+	{"type":"constructor","inputs":[{"name":"_owner","type":"address"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"name","inputs":[],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"info","inputs":[{"name":"id","type":"uint64"}],
+		"outputs":[{"name":"owner","type":"address"},{"name":"amount","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"getOrder","inputs":[{"name":"type","type":"uint256"}],
+		"outputs":[{"name":"","type":"tuple","internalType":"struct Shop.Order","components":[
+			{"name":"buyer","type":"address"},
+			{"name":"items","type":"tuple[]","internalType":"struct Shop.Item[]","components":[
+				{"name":"sku","type":"string"},{"name":"price","type":"uint256"}]}]}],
+		"stateMutability":"view"},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],
+		"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"}],"outputs":[],"stateMutability":"payable"},
+	{"type":"event","name":"Transfer","inputs":[
+		{"name":"from","type":"address","indexed":true},
+		{"name":"to","type":"address","indexed":true},
+		{"name":"value","type":"uint256","indexed":false}],"anonymous":false},
+	{"type":"event","name":"Named","inputs":[
+		{"name":"label","type":"string","indexed":true},
+		{"name":"data","type":"bytes","indexed":false}],"anonymous":false},
+	{"type":"receive","stateMutability":"payable"}
+]`
+
+func TestBind(t *testing.T) {
+	t.Parallel()
+
+	code, err := Bind("shop", []*Contract{{Name: "Shop", ABI: testAbi, Bin: "0x6001"}})
+	require.NoError(t, err)
+
+	for _, expected := range []string{
+		"package shop",
+		"type ShopItem struct {\n\tSku   string\n\tPrice *big.Int\n}",
+		"type ShopOrder struct {\n\tBuyer bind.Address\n\tItems []ShopItem\n}",
+		"func NewShop(address bind.Address, c client.Client) (*Shop, error)",
+		"func DeployShop(opts *bind.TransactOpts, c client.Client, shardId bind.ShardId, salt common.Hash, owner bind.Address)",
+		"func (_Shop *ShopCaller) Name(opts *bind.CallOpts) (string, error)",
+		// The keyword is not a valid argument name
+		"func (_Shop *ShopCaller) GetOrder(opts *bind.CallOpts, arg0 *big.Int) (ShopOrder, error)",
+		"func (_Shop *ShopCaller) Info(opts *bind.CallOpts, id uint64) (struct {\n\tOwner  bind.Address\n\tAmount *big.Int\n}, error)",
+		"func (_Shop *ShopTransactor) Transfer(opts *bind.TransactOpts, to bind.Address, amount *big.Int) (common.Hash, error)",
+		"func (_Shop *ShopTransactor) Transfer0(opts *bind.TransactOpts, to bind.Address) (common.Hash, error)",
+		"func (_Shop *ShopTransactor) Receive(opts *bind.TransactOpts) (common.Hash, error)",
+		"type ShopNamed struct {\n\tLabel common.Hash\n\tData  []byte\n\tRaw   *bind.Log\n}",
+		"func (_Shop *ShopFilterer) ParseTransfer(log *bind.Log) (*ShopTransfer, error)",
+		"func (_Shop *ShopFilterer) ParseLog(log *bind.Log) (any, error)",
+	} {
+		require.Contains(t, code, expected)
+	}
+	require.NotContains(t, code, "Fallback")
+
+	// Without the bytecode the contract can't be deployed
+	code, err = Bind("shop", []*Contract{{Name: "Shop", ABI: testAbi}})
+	require.NoError(t, err)
+	require.NotContains(t, code, "DeployShop")
+
+	_, err = Bind("shop-bindings", []*Contract{{Name: "Shop", ABI: testAbi}})
+	require.Error(t, err)
+	_, err = Bind("shop", []*Contract{{Name: "Shop", ABI: "{"}})
+	require.Error(t, err)
+	_, err = Bind("shop", nil)
+	require.ErrorIs(t, err, errNoContracts)
+}
+
+// TestBindCompiles builds the generated bindings as a package of another module,
+// so the imports of the internal packages are rejected as they are for the users.
+func TestBindCompiles(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("builds the generated package with the go tool")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool is not found")
+	}
+
+	code, err := Bind("shop", []*Contract{{Name: "Shop", ABI: testAbi, Bin: "0x6001"}})
+	require.NoError(t, err)
+
+	root, err := filepath.Abs("../../..")
+	require.NoError(t, err)
+	goMod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	require.NoError(t, err)
+	goSum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	require.NoError(t, err)
+
+	// The module requires the same versions of the dependencies, so nothing is downloaded
+	_, requirements, found := strings.Cut(string(goMod), "\n")
+	require.True(t, found)
+	modFile := "module example.com/shop\n" + requirements +
+		"\nrequire github.com/NilFoundation/nil v0.0.0\n" +
+		"\nreplace github.com/NilFoundation/nil => " + root + "\n"
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(modFile), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), goSum, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shop.go"), []byte(code), 0o600))
+
+	cmd := exec.Command(goBin, "vet", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod", "GOPROXY=off")
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestBoundContract(t *testing.T) {
+	t.Parallel()
+
+	contractAbi, err := abi.JSON(strings.NewReader(testAbi))
+	require.NoError(t, err)
+	address := types.HexToAddress("0x0001111111111111111111111111111111111111")
+	owner := types.HexToAddress("0x0001222222222222222222222222222222222222")
+
+	t.Run("Call", func(t *testing.T) {
+		t.Parallel()
+
+		output, err := contractAbi.Methods["info"].Outputs.Pack(owner, big.NewInt(100))
+		require.NoError(t, err)
+		c := &client.ClientMock{
+			CallFunc: func(
+				_ context.Context, args *jsonrpc.CallArgs, blockId any, _ *jsonrpc.StateOverrides,
+			) (*jsonrpc.CallRes, error) {
+				require.Equal(t, address, args.To)
+				require.Equal(t, "latest", blockId)
+				expected, err := contractAbi.Pack("info", uint64(7))
+				require.NoError(t, err)
+				require.Equal(t, expected, []byte(*args.Data))
+				return &jsonrpc.CallRes{Data: output}, nil
+			},
+		}
+
+		var out []any
+		require.NoError(t, NewBoundContract(address, contractAbi, c).Call(nil, &out, "info", uint64(7)))
+		require.Len(t, out, 2)
+		require.Equal(t, owner, *abi.ConvertType(out[0], new(types.Address)).(*types.Address))
+		require.Equal(t, big.NewInt(100), *abi.ConvertType(out[1], new(*big.Int)).(**big.Int))
+	})
+
+	t.Run("CallFailed", func(t *testing.T) {
+		t.Parallel()
+
+		c := &client.ClientMock{
+			CallFunc: func(context.Context, *jsonrpc.CallArgs, any, *jsonrpc.StateOverrides) (*jsonrpc.CallRes, error) {
+				return &jsonrpc.CallRes{Error: "execution reverted"}, nil
+			},
+		}
+		var out []any
+		err := NewBoundContract(address, contractAbi, c).Call(nil, &out, "name")
+		require.ErrorContains(t, err, "execution reverted")
+	})
+
+	t.Run("TransactExternal", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+
+		var sent *types.ExternalTransaction
+		c := &client.ClientMock{
+			GetTransactionCountFunc: func(context.Context, types.Address, any) (types.Seqno, error) {
+				return 5, nil
+			},
+			EstimateFeeFunc: func(context.Context, *jsonrpc.CallArgs, any) (*jsonrpc.EstimateFeeRes, error) {
+				return &jsonrpc.EstimateFeeRes{FeeCredit: types.NewValueFromUint64(1000)}, nil
+			},
+			SendTransactionFunc: func(_ context.Context, txn *types.ExternalTransaction) (common.Hash, error) {
+				sent = txn
+				return common.HexToHash("0x1234"), nil
+			},
+		}
+
+		contract := NewBoundContract(address, contractAbi, c)
+		_, err = contract.Transact(&TransactOpts{}, "transfer", owner, big.NewInt(1))
+		require.ErrorIs(t, err, ErrNoSigner)
+
+		hash, err := contract.Transact(
+			&TransactOpts{Signer: client.NewPrivateKeySigner(key)}, "transfer", owner, big.NewInt(1))
+		require.NoError(t, err)
+		require.Equal(t, common.HexToHash("0x1234"), hash)
+
+		expected, err := contractAbi.Pack("transfer", owner, big.NewInt(1))
+		require.NoError(t, err)
+		require.Equal(t, address, sent.To)
+		require.Equal(t, types.Code(expected), sent.Data)
+		require.Equal(t, types.Seqno(5), sent.Seqno)
+		require.Equal(t, types.NewValueFromUint64(1000), sent.FeeCredit)
+		require.NotEmpty(t, sent.AuthData)
+	})
+
+	t.Run("UnpackLog", func(t *testing.T) {
+		t.Parallel()
+
+		from := types.HexToAddress("0x0001333333333333333333333333333333333333")
+		data, err := contractAbi.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(42))
+		require.NoError(t, err)
+		log := &types.Log{
+			Address: address,
+			Topics: []common.Hash{
+				contractAbi.Events["Transfer"].ID,
+				common.BytesToHash(from.Bytes()),
+				common.BytesToHash(owner.Bytes()),
+			},
+			Data: data,
+		}
+
+		var event struct {
+			From  types.Address
+			To    types.Address
+			Value *big.Int
+			Raw   *types.Log
+		}
+		contract := NewBoundContract(address, contractAbi, &client.ClientMock{})
+		require.NoError(t, contract.UnpackLog(&event, "Transfer", log))
+		require.Equal(t, from, event.From)
+		require.Equal(t, owner, event.To)
+		require.Equal(t, big.NewInt(42), event.Value)
+
+		require.ErrorIs(t, contract.UnpackLog(&event, "Named", log), ErrEventMissing)
+	})
+}
+
+func TestMetaData(t *testing.T) {
+	t.Parallel()
+
+	m := &MetaData{ABI: testAbi, Bin: "0x6001"}
+	parsed, err := m.GetAbi()
+	require.NoError(t, err)
+	require.Contains(t, parsed.Methods, "transfer0")
+	require.Equal(t, []byte{0x60, 0x01}, hexutil.FromHex(m.Bin))
+
+	_, err = (&MetaData{ABI: "["}).GetAbi()
+	require.Error(t, err)
+}
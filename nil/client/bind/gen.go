@@ -0,0 +1,342 @@
+package bind
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/NilFoundation/nil/nil/internal/abi"
+)
+
+var errNoContracts = errors.New("no contracts to generate the bindings for")
+
+// Contract is the source of a generated contract binding.
+type Contract struct {
+	// Name is the name of the Go type of the binding
+	Name string
+	// ABI is the JSON ABI of the contract
+	ABI string
+	// Bin is the hex encoded init code of the contract, the deploy function is generated if set
+	Bin string
+}
+
+type tmplData struct {
+	Package   string
+	Contracts []*tmplContract
+	Structs   []*tmplStruct
+}
+
+type tmplContract struct {
+	Type        string
+	InputABI    string
+	InputBin    string
+	Constructor abi.Method
+	Calls       []*tmplMethod
+	Transacts   []*tmplMethod
+	Events      []*tmplEvent
+	Fallback    bool
+	Receive     bool
+}
+
+type tmplMethod struct {
+	Original   abi.Method
+	Normalized abi.Method
+	Structured bool
+}
+
+type tmplEvent struct {
+	Original   abi.Event
+	Normalized abi.Event
+}
+
+type tmplField struct {
+	Name string
+	Type string
+}
+
+type tmplStruct struct {
+	Name   string
+	Fields []*tmplField
+}
+
+// generator keeps the structs of the tuple types shared by all contracts of the package.
+type generator struct {
+	structs map[string]*tmplStruct
+	names   map[string]struct{}
+}
+
+// Bind generates the Go package with the bindings of the contracts.
+func Bind(pkg string, contracts []*Contract) (string, error) {
+	if !token.IsIdentifier(pkg) {
+		return "", fmt.Errorf("invalid package name %q", pkg)
+	}
+	if len(contracts) == 0 {
+		return "", errNoContracts
+	}
+
+	g := &generator{
+		structs: make(map[string]*tmplStruct),
+		names:   make(map[string]struct{}),
+	}
+	data := &tmplData{Package: pkg}
+	for _, contract := range contracts {
+		c, err := g.contract(contract)
+		if err != nil {
+			return "", fmt.Errorf("contract %s: %w", contract.Name, err)
+		}
+		data.Contracts = append(data.Contracts, c)
+	}
+	for _, s := range g.structs {
+		data.Structs = append(data.Structs, s)
+	}
+	slices.SortFunc(data.Structs, func(a, b *tmplStruct) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	tmpl, err := template.New("bind").Funcs(template.FuncMap{
+		"bindtype":      g.bindType,
+		"bindtopictype": g.bindTopicType,
+		"capitalise":    capitalise,
+		"decapitalise":  decapitalise,
+	}).Parse(tmplSource)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("failed to format the generated code: %w", err)
+	}
+	return string(code), nil
+}
+
+func (g *generator) contract(contract *Contract) (*tmplContract, error) {
+	if !token.IsIdentifier(contract.Name) {
+		return nil, fmt.Errorf("invalid type name %q", contract.Name)
+	}
+	parsed, err := abi.JSON(strings.NewReader(contract.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the ABI: %w", err)
+	}
+
+	// The ABI is embedded as a single line
+	var minified bytes.Buffer
+	if err := json.Compact(&minified, []byte(contract.ABI)); err != nil {
+		return nil, fmt.Errorf("failed to compact the ABI: %w", err)
+	}
+
+	c := &tmplContract{
+		Type:        capitalise(contract.Name),
+		InputABI:    minified.String(),
+		InputBin:    strings.TrimPrefix(strings.TrimSpace(contract.Bin), "0x"),
+		Constructor: g.normalizeMethod(parsed.Constructor),
+		Fallback:    parsed.HasFallback(),
+		Receive:     parsed.HasReceive(),
+	}
+	for _, name := range sortedKeys(parsed.Methods) {
+		original := parsed.Methods[name]
+		normalized := g.normalizeMethod(original)
+		normalized.Name = capitalise(abi.ToCamelCase(original.Name))
+
+		method := &tmplMethod{Original: original, Normalized: normalized, Structured: structured(original.Outputs)}
+		if original.IsConstant() {
+			c.Calls = append(c.Calls, method)
+		} else {
+			c.Transacts = append(c.Transacts, method)
+		}
+	}
+	for _, name := range sortedKeys(parsed.Events) {
+		original := parsed.Events[name]
+		if original.Anonymous {
+			// The anonymous events can't be told apart by the topics
+			continue
+		}
+		normalized := original
+		normalized.Name = capitalise(abi.ToCamelCase(original.Name))
+		normalized.Inputs = make(abi.Arguments, len(original.Inputs))
+		copy(normalized.Inputs, original.Inputs)
+		for i, input := range normalized.Inputs {
+			if input.Name == "" {
+				return nil, fmt.Errorf("event %s has an unnamed argument %d", original.Name, i)
+			}
+			normalized.Inputs[i].Name = capitalise(abi.ToCamelCase(input.Name))
+		}
+		c.Events = append(c.Events, &tmplEvent{Original: original, Normalized: normalized})
+	}
+
+	// The structs of the tuples are collected before the code is generated
+	args := slices.Clone(c.Constructor.Inputs)
+	for _, method := range slices.Concat(c.Calls, c.Transacts) {
+		args = slices.Concat(args, method.Normalized.Inputs, method.Normalized.Outputs)
+	}
+	for _, event := range c.Events {
+		args = slices.Concat(args, event.Normalized.Inputs)
+	}
+	for _, arg := range args {
+		if arg.Indexed {
+			g.bindTopicType(arg.Type)
+		} else {
+			g.bindType(arg.Type)
+		}
+	}
+	return c, nil
+}
+
+// normalizeMethod names the unnamed inputs and the ones clashing with the Go keywords.
+func (g *generator) normalizeMethod(method abi.Method) abi.Method {
+	normalized := method
+	normalized.Inputs = make(abi.Arguments, len(method.Inputs))
+	copy(normalized.Inputs, method.Inputs)
+	for i, input := range normalized.Inputs {
+		name := decapitalise(abi.ToCamelCase(input.Name))
+		if name == "" || token.IsKeyword(name) || isReservedName(name) {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		normalized.Inputs[i].Name = name
+	}
+	normalized.Outputs = make(abi.Arguments, len(method.Outputs))
+	copy(normalized.Outputs, method.Outputs)
+	for i, output := range normalized.Outputs {
+		if output.Name != "" {
+			normalized.Outputs[i].Name = capitalise(abi.ToCamelCase(output.Name))
+		}
+	}
+	return normalized
+}
+
+// isReservedName reports whether the name clashes with the identifiers used by the generated methods.
+func isReservedName(name string) bool {
+	switch name {
+	case "opts", "out", "err", "outstruct", "event", "log":
+		return true
+	}
+	return false
+}
+
+// structured reports whether the outputs are returned as a struct, i.e. there are several of them and all are named.
+func structured(outputs abi.Arguments) bool {
+	if len(outputs) < 2 {
+		return false
+	}
+	for _, out := range outputs {
+		if out.Name == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// bindType returns the Go type of the ABI type, the structs of the tuples are generated on the fly.
+func (g *generator) bindType(kind abi.Type) string {
+	switch kind.T {
+	case abi.IntTy, abi.UintTy:
+		prefix := "int"
+		if kind.T == abi.UintTy {
+			prefix = "uint"
+		}
+		switch kind.Size {
+		case 8, 16, 32, 64:
+			return fmt.Sprintf("%s%d", prefix, kind.Size)
+		}
+		return "*big.Int"
+	case abi.BoolTy:
+		return "bool"
+	case abi.StringTy:
+		return "string"
+	case abi.AddressTy:
+		return "bind.Address"
+	case abi.FixedBytesTy:
+		return fmt.Sprintf("[%d]byte", kind.Size)
+	case abi.BytesTy:
+		return "[]byte"
+	case abi.FunctionTy:
+		return "[24]byte"
+	case abi.SliceTy:
+		return "[]" + g.bindType(*kind.Elem)
+	case abi.ArrayTy:
+		return fmt.Sprintf("[%d]", kind.Size) + g.bindType(*kind.Elem)
+	case abi.TupleTy:
+		return g.bindStruct(kind)
+	}
+	// The hash and fixed point types are never produced by the ABI parser
+	return "[32]byte"
+}
+
+// bindTopicType returns the Go type of the indexed event argument.
+// The dynamic types are stored as their hashes in the topics.
+func (g *generator) bindTopicType(kind abi.Type) string {
+	switch kind.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return "common.Hash"
+	}
+	return g.bindType(kind)
+}
+
+func (g *generator) bindStruct(kind abi.Type) string {
+	id := kind.TupleRawName + kind.String()
+	if s, ok := g.structs[id]; ok {
+		return s.Name
+	}
+
+	name := capitalise(kind.TupleRawName)
+	if name == "" {
+		name = "Struct"
+	}
+	if _, ok := g.names[name]; ok || kind.TupleRawName == "" {
+		for i := 0; ; i++ {
+			candidate := fmt.Sprintf("%s%d", name, i)
+			if _, ok := g.names[candidate]; !ok {
+				name = candidate
+				break
+			}
+		}
+	}
+	g.names[name] = struct{}{}
+
+	s := &tmplStruct{Name: name}
+	g.structs[id] = s
+	// The field names must match the struct of the ABI for the values to be convertible
+	for i, elem := range kind.TupleElems {
+		s.Fields = append(s.Fields, &tmplField{
+			Name: kind.TupleType.Field(i).Name,
+			Type: g.bindType(*elem),
+		})
+	}
+	return name
+}
+
+func capitalise(input string) string {
+	if input == "" {
+		return ""
+	}
+	input = strings.TrimLeft(input, "_")
+	if input == "" {
+		return ""
+	}
+	return strings.ToUpper(input[:1]) + input[1:]
+}
+
+func decapitalise(input string) string {
+	if input == "" {
+		return ""
+	}
+	return strings.ToLower(input[:1]) + input[1:]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,203 @@
+package bind
+
+// tmplSource is the template of the generated Go bindings.
+const tmplSource = `// Code generated by nil abigen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/client/bind"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = client.NewPrivateKeySigner
+	_ = common.EmptyHash
+	_ = hexutil.FromHex
+)
+{{range $struct := .Structs}}
+// {{.Name}} is an auto generated low-level Go binding around an user-defined struct.
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}}
+{{- end}}
+}
+{{end}}
+{{- range $contract := .Contracts}}
+// {{.Type}}MetaData contains the ABI and the bytecode of the {{.Type}} contract.
+var {{.Type}}MetaData = &bind.MetaData{
+	ABI: {{printf "%q" .InputABI}},
+{{- if .InputBin}}
+	Bin: "0x{{.InputBin}}",
+{{- end}}
+}
+
+// {{.Type}} is a binding of the {{.Type}} contract.
+type {{.Type}} struct {
+	{{.Type}}Caller
+	{{.Type}}Transactor
+	{{.Type}}Filterer
+}
+
+// {{.Type}}Caller is a binding of the read-only methods of the {{.Type}} contract.
+type {{.Type}}Caller struct {
+	contract *bind.BoundContract
+}
+
+// {{.Type}}Transactor is a binding of the state-changing methods of the {{.Type}} contract.
+type {{.Type}}Transactor struct {
+	contract *bind.BoundContract
+}
+
+// {{.Type}}Filterer is a binding of the events of the {{.Type}} contract.
+type {{.Type}}Filterer struct {
+	contract *bind.BoundContract
+}
+
+// New{{.Type}} creates the binding of the {{.Type}} contract deployed at the address.
+func New{{.Type}}(address bind.Address, c client.Client) (*{{.Type}}, error) {
+	parsed, err := {{.Type}}MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return new{{.Type}}(bind.NewBoundContract(address, *parsed, c)), nil
+}
+
+func new{{.Type}}(contract *bind.BoundContract) *{{.Type}} {
+	return &{{.Type}}{
+		{{.Type}}Caller:     {{.Type}}Caller{contract: contract},
+		{{.Type}}Transactor: {{.Type}}Transactor{contract: contract},
+		{{.Type}}Filterer:   {{.Type}}Filterer{contract: contract},
+	}
+}
+
+// Address returns the address of the {{.Type}} contract.
+func (_{{$contract.Type}} *{{.Type}}) Address() bind.Address {
+	return _{{$contract.Type}}.{{.Type}}Caller.contract.Address()
+}
+{{if .InputBin}}
+// Deploy{{.Type}} deploys the {{.Type}} contract to the shard and returns its binding.
+func Deploy{{.Type}}(opts *bind.TransactOpts, c client.Client, shardId bind.ShardId, salt common.Hash
+	{{- range .Constructor.Inputs}}, {{.Name}} {{bindtype .Type}}{{end}}) (common.Hash, bind.Address, *{{.Type}}, error) {
+	parsed, err := {{.Type}}MetaData.GetAbi()
+	if err != nil {
+		return common.EmptyHash, bind.EmptyAddress, nil, err
+	}
+	hash, address, contract, err := bind.DeployContract(opts, c, shardId, salt, *parsed,
+		hexutil.FromHex({{.Type}}MetaData.Bin){{range .Constructor.Inputs}}, {{.Name}}{{end}})
+	if err != nil {
+		return common.EmptyHash, bind.EmptyAddress, nil, err
+	}
+	return hash, address, new{{.Type}}(contract), nil
+}
+{{end}}
+{{- range .Calls}}
+// {{.Normalized.Name}} is a read-only call of the contract method 0x{{printf "%x" .Original.ID}}.
+//
+// Solidity: {{.Original.String}}
+func (_{{$contract.Type}} *{{$contract.Type}}Caller) {{.Normalized.Name}}(opts *bind.CallOpts
+	{{- range .Normalized.Inputs}}, {{.Name}} {{bindtype .Type}}{{end}}) (
+	{{- if .Structured}}struct {
+	{{- range .Normalized.Outputs}}
+		{{.Name}} {{bindtype .Type}}
+	{{- end}}
+	}, {{else}}{{range .Normalized.Outputs}}{{bindtype .Type}}, {{end}}{{end}}error) {
+	var out []any
+	err := _{{$contract.Type}}.contract.Call(opts, &out, "{{.Original.Name}}"
+		{{- range .Normalized.Inputs}}, {{.Name}}{{end}})
+{{- if .Structured}}
+	outstruct := new(struct {
+	{{- range .Normalized.Outputs}}
+		{{.Name}} {{bindtype .Type}}
+	{{- end}}
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+	{{- range $i, $t := .Normalized.Outputs}}
+	outstruct.{{.Name}} = *bind.ConvertType(out[{{$i}}], new({{bindtype .Type}})).(*{{bindtype .Type}})
+	{{- end}}
+	return *outstruct, nil
+{{- else}}
+	if err != nil {
+		return {{range .Normalized.Outputs}}*new({{bindtype .Type}}), {{end}}err
+	}
+	{{- range $i, $t := .Normalized.Outputs}}
+	out{{$i}} := *bind.ConvertType(out[{{$i}}], new({{bindtype .Type}})).(*{{bindtype .Type}})
+	{{- end}}
+	return {{range $i, $t := .Normalized.Outputs}}out{{$i}}, {{end}}nil
+{{- end}}
+}
+{{end}}
+{{- range .Transacts}}
+// {{.Normalized.Name}} sends a transaction calling the contract method 0x{{printf "%x" .Original.ID}}.
+//
+// Solidity: {{.Original.String}}
+func (_{{$contract.Type}} *{{$contract.Type}}Transactor) {{.Normalized.Name}}(opts *bind.TransactOpts
+	{{- range .Normalized.Inputs}}, {{.Name}} {{bindtype .Type}}{{end}}) (common.Hash, error) {
+	return _{{$contract.Type}}.contract.Transact(opts, "{{.Original.Name}}"{{range .Normalized.Inputs}}, {{.Name}}{{end}})
+}
+{{end}}
+{{- if .Fallback}}
+// Fallback sends a transaction with the calldata to the fallback function of the contract.
+func (_{{$contract.Type}} *{{$contract.Type}}Transactor) Fallback(opts *bind.TransactOpts, calldata []byte) (common.Hash, error) {
+	return _{{$contract.Type}}.contract.RawTransact(opts, calldata)
+}
+{{end}}
+{{- if .Receive}}
+// Receive sends a transaction without calldata to the receive function of the contract.
+func (_{{$contract.Type}} *{{$contract.Type}}Transactor) Receive(opts *bind.TransactOpts) (common.Hash, error) {
+	return _{{$contract.Type}}.contract.RawTransact(opts, nil)
+}
+{{end}}
+{{- range .Events}}
+// {{$contract.Type}}{{.Normalized.Name}} is the {{.Original.Name}} event of the {{$contract.Type}} contract.
+type {{$contract.Type}}{{.Normalized.Name}} struct {
+{{- range .Normalized.Inputs}}
+	{{.Name}} {{if .Indexed}}{{bindtopictype .Type}}{{else}}{{bindtype .Type}}{{end}}
+{{- end}}
+	Raw *bind.Log
+}
+
+// Parse{{.Normalized.Name}} decodes the log of the event 0x{{printf "%x" .Original.ID}}.
+//
+// Solidity: {{.Original.String}}
+func (_{{$contract.Type}} *{{$contract.Type}}Filterer) Parse{{.Normalized.Name}}(log *bind.Log) (*{{$contract.Type}}{{.Normalized.Name}}, error) {
+	event := new({{$contract.Type}}{{.Normalized.Name}})
+	if err := _{{$contract.Type}}.contract.UnpackLog(event, "{{.Original.Name}}", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+{{end}}
+{{- if .Events}}
+// ParseLog decodes the log of any event of the {{.Type}} contract.
+// It returns bind.ErrEventMissing if the log is not an event of the contract.
+func (_{{$contract.Type}} *{{$contract.Type}}Filterer) ParseLog(log *bind.Log) (any, error) {
+	if len(log.Topics) == 0 {
+		return nil, bind.ErrEventMissing
+	}
+	switch log.Topics[0] {
+	{{- range .Events}}
+	case common.HexToHash("{{.Original.ID.Hex}}"):
+		event, err := _{{$contract.Type}}.Parse{{.Normalized.Name}}(log)
+		if err != nil {
+			return nil, err
+		}
+		return event, nil
+	{{- end}}
+	}
+	return nil, bind.ErrEventMissing
+}
+{{end}}
+{{- end}}
+`
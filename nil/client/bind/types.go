@@ -0,0 +1,23 @@
+package bind
+
+import (
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// The types and helpers used by the generated bindings.
+// They are re-exported here, since the generated code can't import the internal packages outside of this module.
+
+type (
+	ABI     = abi.ABI
+	Address = types.Address
+	ShardId = types.ShardId
+	Log     = types.Log
+)
+
+var EmptyAddress = types.EmptyAddress
+
+// ConvertType converts the unpacked value to the type of the proto, see abi.ConvertType.
+func ConvertType(in any, proto any) any {
+	return abi.ConvertType(in, proto)
+}
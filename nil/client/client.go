@@ -76,20 +76,31 @@ type Client interface {
 
 	// GetDebugContract retrieves smart contract with its data, such as code, storage and proof
 	GetDebugContract(ctx context.Context, contractAddr types.Address, blockId any) (*jsonrpc.DebugRPCContract, error)
+
+	// GetResourceUsage retrieves gas and storage usage per contract aggregated over the given block range
+	GetResourceUsage(
+		ctx context.Context, shardId types.ShardId, fromBlock, toBlock types.BlockNumber,
+	) (*types.ResourceUsage, error)
+
+	// WaitForTransactionTree waits until the transaction and all transactions spawned by it are processed
+	WaitForTransactionTree(ctx context.Context, hash common.Hash, opts *WaitOptions) (*TransactionTree, error)
 }
 
 func EstimateFeeExternal(ctx context.Context, c Client, txn *types.ExternalTransaction, blockId any) (*jsonrpc.EstimateFeeRes, error) {
-	var flags types.TransactionFlags
-	if txn.Kind == types.DeployTransactionKind {
-		flags = types.NewTransactionFlags(types.TransactionFlagDeploy)
-	}
-
 	args := &jsonrpc.CallArgs{
 		Data:  (*hexutil.Bytes)(&txn.Data),
 		To:    txn.To,
-		Flags: flags,
+		Flags: types.TransactionFlagsFromKind(false, txn.Kind),
 		Seqno: txn.Seqno,
 	}
+	if !txn.Paymaster.IsEmpty() {
+		// The fields of the call arguments can't describe the paymaster, so the whole transaction is passed.
+		input, err := txn.ToTransaction().MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+		args.Transaction = (*hexutil.Bytes)(&input)
+	}
 
 	return c.EstimateFee(ctx, args, blockId)
 }
@@ -98,13 +109,17 @@ func CreateExternalTransaction(
 	ctx context.Context, c Client, bytecode types.Code, contractAddress types.Address,
 	fee types.FeePack, isDeploy bool, id int,
 ) (*types.ExternalTransaction, error) {
-	var kind types.TransactionKind
+	kind := types.ExecutionTransactionKind
 	if isDeploy {
 		kind = types.DeployTransactionKind
-	} else {
-		kind = types.ExecutionTransactionKind
 	}
+	return createExternalTransaction(ctx, c, bytecode, contractAddress, types.EmptyAddress, fee, kind, id)
+}
 
+func createExternalTransaction(
+	ctx context.Context, c Client, bytecode types.Code, contractAddress, paymaster types.Address,
+	fee types.FeePack, kind types.TransactionKind, id int,
+) (*types.ExternalTransaction, error) {
 	// Get the sequence number for the smart account
 	seqno, err := c.GetTransactionCount(ctx, contractAddress, "pending")
 	if err != nil {
@@ -121,6 +136,7 @@ func CreateExternalTransaction(
 		FeeCredit:            fee.FeeCredit,
 		MaxPriorityFeePerGas: fee.MaxPriorityFeePerGas,
 		MaxFeePerGas:         fee.MaxFeePerGas,
+		Paymaster:            paymaster,
 	}
 
 	if fee.FeeCredit.IsZero() {
@@ -140,7 +156,7 @@ func CreateExternalTransaction(
 
 func SendExternalTransaction(
 	ctx context.Context, c Client, bytecode types.Code, contractAddress types.Address,
-	pk *ecdsa.PrivateKey, fee types.FeePack, isDeploy bool, withRetry bool,
+	signer Signer, fee types.FeePack, isDeploy bool, withRetry bool,
 ) (common.Hash, error) {
 	extTxn, err := CreateExternalTransaction(ctx, c, bytecode, contractAddress, fee, isDeploy, 0)
 	if err != nil {
@@ -148,14 +164,30 @@ func SendExternalTransaction(
 	}
 
 	if withRetry {
-		return sendExternalTransactionWithSeqnoRetry(ctx, c, extTxn, pk)
+		return sendExternalTransactionWithSeqnoRetry(ctx, c, extTxn, signer)
 	}
 
-	if pk != nil {
-		err = extTxn.Sign(pk)
-		if err != nil {
-			return common.EmptyHash, err
-		}
+	if err := SignExternalTransaction(ctx, extTxn, signer); err != nil {
+		return common.EmptyHash, err
+	}
+
+	return c.SendTransaction(ctx, extTxn)
+}
+
+// SendSponsoredExternalTransaction sends an external transaction whose fees are paid by the paymaster.
+// The paymaster must be in the shard of the contract and approve the transaction in its `verifyPaymaster` method.
+func SendSponsoredExternalTransaction(
+	ctx context.Context, c Client, bytecode types.Code, contractAddress, paymaster types.Address,
+	signer Signer, fee types.FeePack,
+) (common.Hash, error) {
+	extTxn, err := createExternalTransaction(
+		ctx, c, bytecode, contractAddress, paymaster, fee, types.ExecutionTransactionKind, 0)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	if err := SignExternalTransaction(ctx, extTxn, signer); err != nil {
+		return common.EmptyHash, err
 	}
 
 	return c.SendTransaction(ctx, extTxn)
@@ -163,13 +195,11 @@ func SendExternalTransaction(
 
 // sendExternalTransactionWithSeqnoRetry tries to send an external transaction increasing seqno if needed.
 // Can be used to ensure sending transactions to common contracts like Faucet.
-func sendExternalTransactionWithSeqnoRetry(ctx context.Context, c Client, txn *types.ExternalTransaction, pk *ecdsa.PrivateKey) (common.Hash, error) {
+func sendExternalTransactionWithSeqnoRetry(ctx context.Context, c Client, txn *types.ExternalTransaction, signer Signer) (common.Hash, error) {
 	var err error
 	for range 20 {
-		if pk != nil {
-			if err := txn.Sign(pk); err != nil {
-				return common.EmptyHash, err
-			}
+		if err := SignExternalTransaction(ctx, txn, signer); err != nil {
+			return common.EmptyHash, err
 		}
 
 		var txHash common.Hash
@@ -206,26 +236,108 @@ func CreateInternalTransactionPayload(ctx context.Context, bytecode types.Code,
 		Kind:        kind,
 	}
 
-	intTxnData, err := intTxn.MarshalSSZ()
-	if err != nil {
-		return types.Code{}, err
-	}
+	return EncodeSmartAccountSend(intTxn)
+}
 
-	calldataExt, err := contracts.NewCallData(contracts.NameSmartAccount, "send", intTxnData)
+// EncodeSmartAccountSend returns the calldata of the smart account call sending the internal transaction.
+func EncodeSmartAccountSend(intTxn *types.InternalTransactionPayload) (types.Code, error) {
+	intTxnData, err := intTxn.MarshalSSZ()
 	if err != nil {
 		return types.Code{}, err
 	}
 
-	return calldataExt, err
+	return contracts.NewCallData(contracts.NameSmartAccount, "send", intTxnData)
 }
 
 func SendTransactionViaSmartAccount(
 	ctx context.Context, c Client, smartAccountAddress types.Address, bytecode types.Code, fee types.FeePack, value types.Value,
-	tokens []types.TokenBalance, contractAddress types.Address, pk *ecdsa.PrivateKey, isDeploy bool,
+	tokens []types.TokenBalance, contractAddress types.Address, signer Signer, isDeploy bool,
 ) (common.Hash, error) {
 	calldataExt, err := CreateInternalTransactionPayload(ctx, bytecode, value, tokens, contractAddress, isDeploy)
 	if err != nil {
 		return common.EmptyHash, err
 	}
-	return c.SendExternalTransaction(ctx, calldataExt, smartAccountAddress, pk, fee)
+	return SendExternalTransaction(ctx, c, calldataExt, smartAccountAddress, signer, fee, false, false)
+}
+
+// DeployContractViaSmartAccount deploys the contract by an internal transaction sent by the smart account.
+func DeployContractViaSmartAccount(
+	ctx context.Context, c Client, shardId types.ShardId, smartAccountAddress types.Address, payload types.DeployPayload,
+	value types.Value, fee types.FeePack, signer Signer,
+) (common.Hash, types.Address, error) {
+	contractAddr := types.CreateAddress(shardId, payload)
+	txHash, err := SendTransactionViaSmartAccount(ctx, c, smartAccountAddress, payload.Bytes(), fee, value,
+		[]types.TokenBalance{}, contractAddr, signer, true)
+	if err != nil {
+		return common.EmptyHash, types.EmptyAddress, err
+	}
+	return txHash, contractAddr, nil
+}
+
+// SetTokenName sets the name of the token of the contract.
+func SetTokenName(
+	ctx context.Context, c Client, contractAddr types.Address, name string, signer Signer,
+) (common.Hash, error) {
+	data, err := contracts.NewCallData(contracts.NameNilTokenBase, "setTokenName", name)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	return SendExternalTransaction(ctx, c, data, contractAddr, signer, types.NewFeePackFromGas(100_000), false, false)
+}
+
+// ChangeTokenAmount mints or burns the token of the contract.
+func ChangeTokenAmount(
+	ctx context.Context, c Client, contractAddr types.Address, amount types.Value, signer Signer, mint bool,
+) (common.Hash, error) {
+	method := "mintToken"
+	if !mint {
+		method = "burnToken"
+	}
+	data, err := contracts.NewCallData(contracts.NameNilTokenBase, method, amount.ToBig())
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	return SendExternalTransaction(ctx, c, data, contractAddr, signer, types.NewFeePackFromGas(100_000), false, false)
+}
+
+// SmartAccountCall is a transaction sent by a smart account as a call of a batch.
+type SmartAccountCall struct {
+	To     types.Address
+	Data   types.Code
+	Value  types.Value
+	Tokens []types.TokenBalance
+}
+
+// SendBatchViaSmartAccount sends the calls as a single batched external transaction to the smart account.
+// If the batch is atomic, the failure of any call reverts all of them.
+func SendBatchViaSmartAccount(
+	ctx context.Context, c Client, smartAccountAddress types.Address, calls []SmartAccountCall, atomic bool,
+	fee types.FeePack, signer Signer,
+) (common.Hash, error) {
+	payload := &types.BatchPayload{Atomic: atomic, Calls: make([]*types.BatchCall, len(calls))}
+	for i, call := range calls {
+		calldata, err := CreateInternalTransactionPayload(ctx, call.Data, call.Value, call.Tokens, call.To, false)
+		if err != nil {
+			return common.EmptyHash, err
+		}
+		payload.Calls[i] = &types.BatchCall{Data: calldata}
+	}
+	data, err := payload.Bytes()
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	extTxn, err := createExternalTransaction(
+		ctx, c, data, smartAccountAddress, types.EmptyAddress, fee, types.BatchTransactionKind, 0)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	if err := SignExternalTransaction(ctx, extTxn, signer); err != nil {
+		return common.EmptyHash, err
+	}
+
+	return c.SendTransaction(ctx, extTxn)
 }
@@ -8,7 +8,6 @@ import (
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/assert"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
-	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
@@ -206,13 +205,7 @@ func (c *DirectClient) DeployContract(
 	ctx context.Context, shardId types.ShardId, smartAccountAddress types.Address, payload types.DeployPayload,
 	value types.Value, fee types.FeePack, pk *ecdsa.PrivateKey,
 ) (common.Hash, types.Address, error) {
-	contractAddr := types.CreateAddress(shardId, payload)
-	txnHash, err := SendTransactionViaSmartAccount(ctx, c, smartAccountAddress, payload.Bytes(), fee, value,
-		[]types.TokenBalance{}, contractAddr, pk, true)
-	if err != nil {
-		return common.EmptyHash, types.EmptyAddress, err
-	}
-	return txnHash, contractAddr, nil
+	return DeployContractViaSmartAccount(ctx, c, shardId, smartAccountAddress, payload, value, fee, NewPrivateKeySigner(pk))
 }
 
 func (c *DirectClient) DeployExternal(ctx context.Context, shardId types.ShardId, deployPayload types.DeployPayload,
@@ -227,13 +220,14 @@ func (c *DirectClient) SendTransactionViaSmartAccount(
 	ctx context.Context, smartAccountAddress types.Address, bytecode types.Code, fee types.FeePack, value types.Value,
 	tokens []types.TokenBalance, contractAddress types.Address, pk *ecdsa.PrivateKey,
 ) (common.Hash, error) {
-	return SendTransactionViaSmartAccount(ctx, c, smartAccountAddress, bytecode, fee, value, tokens, contractAddress, pk, false)
+	return SendTransactionViaSmartAccount(
+		ctx, c, smartAccountAddress, bytecode, fee, value, tokens, contractAddress, NewPrivateKeySigner(pk), false)
 }
 
 func (c *DirectClient) SendExternalTransaction(
 	ctx context.Context, bytecode types.Code, contractAddress types.Address, pk *ecdsa.PrivateKey, fee types.FeePack,
 ) (common.Hash, error) {
-	return SendExternalTransaction(ctx, c, bytecode, contractAddress, pk, fee, false, false)
+	return SendExternalTransaction(ctx, c, bytecode, contractAddress, NewPrivateKeySigner(pk), fee, false, false)
 }
 
 func (c *DirectClient) Call(ctx context.Context, args *jsonrpc.CallArgs, blockId any, stateOverride *jsonrpc.StateOverrides) (*jsonrpc.CallRes, error) {
@@ -257,25 +251,11 @@ func (c *DirectClient) RawCall(_ context.Context, method string, params ...any)
 }
 
 func (c *DirectClient) SetTokenName(ctx context.Context, contractAddr types.Address, name string, pk *ecdsa.PrivateKey) (common.Hash, error) {
-	data, err := contracts.NewCallData(contracts.NameNilTokenBase, "setTokenName", name)
-	if err != nil {
-		return common.EmptyHash, err
-	}
-
-	return c.SendExternalTransaction(ctx, data, contractAddr, pk, types.NewFeePackFromGas(100_000))
+	return SetTokenName(ctx, c, contractAddr, name, NewPrivateKeySigner(pk))
 }
 
 func (c *DirectClient) ChangeTokenAmount(ctx context.Context, contractAddr types.Address, amount types.Value, pk *ecdsa.PrivateKey, mint bool) (common.Hash, error) {
-	method := "mintToken"
-	if !mint {
-		method = "burnToken"
-	}
-	data, err := contracts.NewCallData(contracts.NameNilTokenBase, method, amount.ToBig())
-	if err != nil {
-		return common.EmptyHash, err
-	}
-
-	return c.SendExternalTransaction(ctx, data, contractAddr, pk, types.NewFeePackFromGas(100_000))
+	return ChangeTokenAmount(ctx, c, contractAddr, amount, NewPrivateKeySigner(pk), mint)
 }
 
 func (c *DirectClient) DbInitTimestamp(ctx context.Context, ts uint64) error {
@@ -313,3 +293,15 @@ func (c *DirectClient) PlainTextCall(_ context.Context, requestBody []byte) (jso
 func (c *DirectClient) GetDebugContract(ctx context.Context, contractAddr types.Address, blockId any) (*jsonrpc.DebugRPCContract, error) {
 	panic("Not supported")
 }
+
+func (c *DirectClient) GetResourceUsage(
+	ctx context.Context, shardId types.ShardId, fromBlock, toBlock types.BlockNumber,
+) (*types.ResourceUsage, error) {
+	return c.debugApi.GetResourceUsage(ctx, shardId, fromBlock, toBlock)
+}
+
+func (c *DirectClient) WaitForTransactionTree(
+	ctx context.Context, hash common.Hash, opts *WaitOptions,
+) (*TransactionTree, error) {
+	return WaitForTransactionTree(ctx, c, hash, opts)
+}
@@ -19,7 +19,6 @@ import (
 	"github.com/NilFoundation/nil/nil/common/assert"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
-	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
@@ -78,6 +77,7 @@ const (
 	Debug_getBlockByHash                 = "debug_getBlockByHash"
 	Debug_getBlockByNumber               = "debug_getBlockByNumber"
 	Debug_getContract                    = "debug_getContract"
+	Debug_getResourceUsage               = "debug_getResourceUsage"
 )
 
 const (
@@ -671,13 +671,8 @@ func (c *Client) DeployContract(
 	ctx context.Context, shardId types.ShardId, smartAccountAddress types.Address, payload types.DeployPayload,
 	value types.Value, fee types.FeePack, pk *ecdsa.PrivateKey,
 ) (common.Hash, types.Address, error) {
-	contractAddr := types.CreateAddress(shardId, payload)
-	txHash, err := client.SendTransactionViaSmartAccount(ctx, c, smartAccountAddress, payload.Bytes(), fee,
-		value, []types.TokenBalance{}, contractAddr, pk, true)
-	if err != nil {
-		return common.EmptyHash, types.EmptyAddress, err
-	}
-	return txHash, contractAddr, nil
+	return client.DeployContractViaSmartAccount(
+		ctx, c, shardId, smartAccountAddress, payload, value, fee, client.NewPrivateKeySigner(pk))
 }
 
 func (c *Client) DeployExternal(ctx context.Context, shardId types.ShardId, deployPayload types.DeployPayload, fee types.FeePack) (common.Hash, types.Address, error) {
@@ -690,13 +685,14 @@ func (c *Client) SendTransactionViaSmartAccount(
 	ctx context.Context, smartAccountAddress types.Address, bytecode types.Code, fee types.FeePack, value types.Value,
 	tokens []types.TokenBalance, contractAddress types.Address, pk *ecdsa.PrivateKey,
 ) (common.Hash, error) {
-	return client.SendTransactionViaSmartAccount(ctx, c, smartAccountAddress, bytecode, fee, value, tokens, contractAddress, pk, false)
+	return client.SendTransactionViaSmartAccount(
+		ctx, c, smartAccountAddress, bytecode, fee, value, tokens, contractAddress, client.NewPrivateKeySigner(pk), false)
 }
 
 func (c *Client) SendExternalTransaction(
 	ctx context.Context, bytecode types.Code, contractAddress types.Address, pk *ecdsa.PrivateKey, fee types.FeePack,
 ) (common.Hash, error) {
-	return client.SendExternalTransaction(ctx, c, bytecode, contractAddress, pk, fee, false, false)
+	return client.SendExternalTransaction(ctx, c, bytecode, contractAddress, client.NewPrivateKeySigner(pk), fee, false, false)
 }
 
 func (c *Client) Call(ctx context.Context, args *jsonrpc.CallArgs, blockId any, stateOverride *jsonrpc.StateOverrides) (*jsonrpc.CallRes, error) {
@@ -736,25 +732,11 @@ func (c *Client) EstimateFee(ctx context.Context, args *jsonrpc.CallArgs, blockI
 }
 
 func (c *Client) SetTokenName(ctx context.Context, contractAddr types.Address, name string, pk *ecdsa.PrivateKey) (common.Hash, error) {
-	data, err := contracts.NewCallData(contracts.NameNilTokenBase, "setTokenName", name)
-	if err != nil {
-		return common.EmptyHash, err
-	}
-
-	return c.SendExternalTransaction(ctx, data, contractAddr, pk, types.NewFeePackFromGas(100_000))
+	return client.SetTokenName(ctx, c, contractAddr, name, client.NewPrivateKeySigner(pk))
 }
 
 func (c *Client) ChangeTokenAmount(ctx context.Context, contractAddr types.Address, amount types.Value, pk *ecdsa.PrivateKey, mint bool) (common.Hash, error) {
-	method := "mintToken"
-	if !mint {
-		method = "burnToken"
-	}
-	data, err := contracts.NewCallData(contracts.NameNilTokenBase, method, amount.ToBig())
-	if err != nil {
-		return common.EmptyHash, err
-	}
-
-	return c.SendExternalTransaction(ctx, data, contractAddr, pk, types.NewFeePackFromGas(100_000))
+	return client.ChangeTokenAmount(ctx, c, contractAddr, amount, client.NewPrivateKeySigner(pk), mint)
 }
 
 func callDbAPI[T any](ctx context.Context, c *Client, method string, params ...any) (T, error) {
@@ -930,3 +912,25 @@ func (c *Client) GetDebugContract(ctx context.Context, contractAddr types.Addres
 
 	return DebugRPCContract, err
 }
+
+func (c *Client) GetResourceUsage(
+	ctx context.Context, shardId types.ShardId, fromBlock, toBlock types.BlockNumber,
+) (*types.ResourceUsage, error) {
+	request := c.newRequest(Debug_getResourceUsage, shardId, fromBlock, toBlock)
+	res, err := c.performRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var usage *types.ResourceUsage
+	if err := json.Unmarshal(res, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func (c *Client) WaitForTransactionTree(
+	ctx context.Context, hash common.Hash, opts *client.WaitOptions,
+) (*client.TransactionTree, error) {
+	return client.WaitForTransactionTree(ctx, c, hash, opts)
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs external transactions on behalf of an account.
+// It allows keeping the private key out of the process, e.g. in an encrypted keystore or a remote signer.
+type Signer interface {
+	// PublicKey returns the public key of the account.
+	PublicKey(ctx context.Context) (*ecdsa.PublicKey, error)
+
+	// Sign returns the signature of the hash in the [R || S || V] format.
+	Sign(ctx context.Context, hash common.Hash) (types.Signature, error)
+}
+
+type privateKeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+var _ Signer = (*privateKeySigner)(nil)
+
+// NewPrivateKeySigner returns a signer holding the private key in memory.
+// It returns nil if the key is nil, so that the transactions are sent unsigned.
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) Signer {
+	if key == nil {
+		return nil
+	}
+	return &privateKeySigner{key: key}
+}
+
+func (s *privateKeySigner) PublicKey(context.Context) (*ecdsa.PublicKey, error) {
+	return &s.key.PublicKey, nil
+}
+
+func (s *privateKeySigner) Sign(_ context.Context, hash common.Hash) (types.Signature, error) {
+	sig, err := crypto.Sign(hash.Bytes(), s.key)
+	if err != nil {
+		return nil, err
+	}
+	return types.Signature(sig), nil
+}
+
+// SignExternalTransaction sets the auth data of the transaction to its signature.
+// The transaction is left unsigned if the signer is nil.
+func SignExternalTransaction(ctx context.Context, txn *types.ExternalTransaction, signer Signer) error {
+	if signer == nil {
+		return nil
+	}
+
+	hash, err := txn.SigningHash()
+	if err != nil {
+		return err
+	}
+
+	sig, err := signer.Sign(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	txn.AuthData = sig
+	return nil
+}
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/concurrent"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+const (
+	DefaultTransactionTreeTimeout      = time.Minute
+	DefaultTransactionTreePollInterval = 200 * time.Millisecond
+)
+
+// WaitOptions control waiting for a transaction tree.
+// The receipts are polled, as the client has no subscriptions to the new receipts.
+type WaitOptions struct {
+	// Timeout is the maximum time to wait for the whole tree, DefaultTransactionTreeTimeout if zero
+	Timeout time.Duration
+	// PollInterval is the interval between requests of the missing receipts,
+	// DefaultTransactionTreePollInterval if zero
+	PollInterval time.Duration
+	// Committed waits until the blocks of all receipts are included in the main chain
+	Committed bool
+}
+
+// BouncedValue is the value returned to the sender of a failed transaction of the tree.
+type BouncedValue struct {
+	TxnHash common.Hash          `json:"transactionHash"`
+	To      types.Address        `json:"to"`
+	Value   types.Value          `json:"value"`
+	Tokens  []types.TokenBalance `json:"tokens,omitempty"`
+}
+
+// TransactionTree is the outcome of a transaction together with all transactions spawned by it across the shards.
+type TransactionTree struct {
+	// Receipt is the receipt of the root transaction with the receipts of its children filled in
+	Receipt *jsonrpc.RPCReceipt `json:"receipt"`
+	// Receipts are all receipts of the tree in the breadth-first order
+	Receipts []*jsonrpc.RPCReceipt `json:"-"`
+	Success  bool                  `json:"success"`
+	// FirstFailure is the first failed receipt in the breadth-first order
+	FirstFailure *jsonrpc.RPCReceipt `json:"firstFailure,omitempty"`
+	GasUsed      types.Gas           `json:"gasUsed"`
+	Fees         types.Value         `json:"fees"`
+	Bounced      []*BouncedValue     `json:"bounced,omitempty"`
+}
+
+// WaitForTransactionTree waits until the transaction and all its asynchronous children are processed.
+// The children are found by the outgoing transactions of every receipt and their receipts are
+// requested from their shards. Only the receipts missing since the previous poll are requested.
+func WaitForTransactionTree(ctx context.Context, c Client, hash common.Hash, opts *WaitOptions) (*TransactionTree, error) {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultTransactionTreeTimeout
+	}
+	pollInterval := opts.PollInterval
+	if pollInterval == 0 {
+		pollInterval = DefaultTransactionTreePollInterval
+	}
+
+	w := &treeWaiter{
+		client:    c,
+		committed: opts.Committed,
+		receipts:  make(map[common.Hash]*jsonrpc.RPCReceipt),
+		pending:   []common.Hash{hash},
+	}
+	if _, err := concurrent.WaitFor(ctx, timeout, pollInterval, w.poll); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("transaction tree of %s is not completed, %d receipts are missing: %w",
+				hash, len(w.pending), err)
+		}
+		return nil, err
+	}
+	return w.result(ctx, hash)
+}
+
+type treeWaiter struct {
+	client    Client
+	committed bool
+	receipts  map[common.Hash]*jsonrpc.RPCReceipt
+	pending   []common.Hash
+}
+
+// poll requests the pending receipts and returns a non-nil value when none are left.
+func (w *treeWaiter) poll(ctx context.Context) (*struct{}, error) {
+	pending := w.pending
+	w.pending = nil
+	for _, hash := range pending {
+		receipt, err := w.client.GetInTransactionReceipt(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if !w.isFinal(receipt) {
+			w.pending = append(w.pending, hash)
+			continue
+		}
+		w.add(hash, receipt)
+	}
+	if len(w.pending) > 0 {
+		return nil, nil
+	}
+	return &struct{}{}, nil
+}
+
+func (w *treeWaiter) isFinal(receipt *jsonrpc.RPCReceipt) bool {
+	return receipt != nil && (!w.committed || receipt.IncludedInMain)
+}
+
+// add records the receipt and the receipts of its children returned along with it.
+// The children without the receipts are left pending.
+func (w *treeWaiter) add(hash common.Hash, receipt *jsonrpc.RPCReceipt) {
+	w.receipts[hash] = receipt
+	for i, child := range receipt.OutTransactions {
+		if i < len(receipt.OutReceipts) && w.isFinal(receipt.OutReceipts[i]) {
+			w.add(child, receipt.OutReceipts[i])
+		} else {
+			w.pending = append(w.pending, child)
+		}
+	}
+}
+
+func (w *treeWaiter) result(ctx context.Context, hash common.Hash) (*TransactionTree, error) {
+	tree := &TransactionTree{Success: true}
+	tree.Receipt = w.link(hash)
+
+	queue := []*jsonrpc.RPCReceipt{tree.Receipt}
+	for len(queue) > 0 {
+		receipt := queue[0]
+		queue = queue[1:]
+		queue = append(queue, receipt.OutReceipts...)
+
+		tree.Receipts = append(tree.Receipts, receipt)
+		tree.GasUsed = tree.GasUsed.Add(receipt.GasUsed)
+		tree.Fees = tree.Fees.Add(receipt.GasUsed.ToValue(receipt.GasPrice))
+		if !receipt.Success && tree.Success {
+			tree.Success = false
+			tree.FirstFailure = receipt
+		}
+
+		if receipt.Flags.IsBounce() {
+			txn, err := w.client.GetInTransactionByHash(ctx, receipt.TxnHash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get bounce transaction %s: %w", receipt.TxnHash, err)
+			}
+			if txn != nil {
+				tree.Bounced = append(tree.Bounced, &BouncedValue{
+					TxnHash: txn.Hash,
+					To:      txn.To,
+					Value:   txn.Value,
+					Tokens:  txn.Token,
+				})
+			}
+		}
+	}
+	return tree, nil
+}
+
+// link returns a copy of the receipt with the children replaced by the latest received receipts.
+func (w *treeWaiter) link(hash common.Hash) *jsonrpc.RPCReceipt {
+	receipt := *w.receipts[hash]
+	receipt.OutReceipts = make([]*jsonrpc.RPCReceipt, len(receipt.OutTransactions))
+	for i, child := range receipt.OutTransactions {
+		receipt.OutReceipts[i] = w.link(child)
+	}
+	return &receipt
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForTransactionTree(t *testing.T) {
+	t.Parallel()
+
+	root := common.HexToHash("0x01")
+	child := common.HexToHash("0x02")
+	bounce := common.HexToHash("0x03")
+	bounceTo := types.HexToAddress("0x0001aaaa")
+
+	// The child receipt appears only on the third poll
+	var childPolls atomic.Int32
+	receipts := func(hash common.Hash) *jsonrpc.RPCReceipt {
+		switch hash {
+		case root:
+			return &jsonrpc.RPCReceipt{
+				TxnHash:         root,
+				Success:         true,
+				GasUsed:         100,
+				GasPrice:        types.NewValueFromUint64(10),
+				OutTransactions: []common.Hash{child},
+				OutReceipts:     []*jsonrpc.RPCReceipt{nil},
+			}
+		case child:
+			if childPolls.Add(1) < 3 {
+				return nil
+			}
+			return &jsonrpc.RPCReceipt{
+				TxnHash:         child,
+				Success:         false,
+				Status:          "ExecutionReverted",
+				ErrorMessage:    "revert",
+				GasUsed:         50,
+				GasPrice:        types.NewValueFromUint64(10),
+				OutTransactions: []common.Hash{bounce},
+				OutReceipts: []*jsonrpc.RPCReceipt{{
+					TxnHash:  bounce,
+					Flags:    types.NewTransactionFlags(types.TransactionFlagInternal, types.TransactionFlagBounce),
+					Success:  true,
+					GasUsed:  5,
+					GasPrice: types.NewValueFromUint64(10),
+				}},
+			}
+		}
+		return nil
+	}
+
+	var requested []common.Hash
+	c := &ClientMock{
+		GetInTransactionReceiptFunc: func(_ context.Context, hash common.Hash) (*jsonrpc.RPCReceipt, error) {
+			requested = append(requested, hash)
+			return receipts(hash), nil
+		},
+		GetInTransactionByHashFunc: func(_ context.Context, hash common.Hash) (*jsonrpc.RPCInTransaction, error) {
+			require.Equal(t, bounce, hash)
+			return &jsonrpc.RPCInTransaction{Hash: bounce, To: bounceTo, Value: types.NewValueFromUint64(42)}, nil
+		},
+	}
+
+	tree, err := WaitForTransactionTree(t.Context(), c, root, &WaitOptions{PollInterval: time.Millisecond})
+	require.NoError(t, err)
+
+	// The root is requested once, the bounce comes along with the child receipt
+	require.Equal(t, []common.Hash{root, child, child, child}, requested)
+
+	require.False(t, tree.Success)
+	require.Len(t, tree.Receipts, 3)
+	require.Equal(t, child, tree.FirstFailure.TxnHash)
+	require.Equal(t, types.Gas(155), tree.GasUsed)
+	require.Equal(t, types.NewValueFromUint64(1550), tree.Fees)
+	require.Equal(t, child, tree.Receipt.OutReceipts[0].TxnHash)
+	require.Equal(t, bounce, tree.Receipt.OutReceipts[0].OutReceipts[0].TxnHash)
+
+	require.Len(t, tree.Bounced, 1)
+	require.Equal(t, bounceTo, tree.Bounced[0].To)
+	require.Equal(t, types.NewValueFromUint64(42), tree.Bounced[0].Value)
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		c := &ClientMock{
+			GetInTransactionReceiptFunc: func(_ context.Context, hash common.Hash) (*jsonrpc.RPCReceipt, error) {
+				return nil, nil
+			},
+		}
+		_, err := WaitForTransactionTree(t.Context(), c, root, &WaitOptions{
+			Timeout:      20 * time.Millisecond,
+			PollInterval: time.Millisecond,
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -25,7 +25,7 @@ func PrepareArgs(contractAbi abi.ABI, calldataOrMethod string, args []string) ([
 		var err error
 		calldata, err = ArgsToCalldata(contractAbi, calldataOrMethod, args)
 		if err != nil {
-			return nil, err
+			return nil, ValidationError(err)
 		}
 	}
 	return calldata, nil
@@ -140,10 +140,14 @@ type NamedArgValues struct {
 func ReadAbiFromFile(abiPath string) (abi.ABI, error) {
 	abiFile, err := os.ReadFile(abiPath)
 	if err != nil {
-		return abi.ABI{}, err
+		return abi.ABI{}, ValidationError(err)
 	}
 
-	return abi.JSON(bytes.NewReader(abiFile))
+	res, err := abi.JSON(bytes.NewReader(abiFile))
+	if err != nil {
+		return abi.ABI{}, ValidationError(err)
+	}
+	return res, nil
 }
 
 func FetchAbiFromCometa(addr types.Address) (abi.ABI, error) {
@@ -215,7 +219,15 @@ func CalldataToArgs(abi abi.ABI, method string, data []byte) ([]*ArgValue, error
 	return results, nil
 }
 
+// ReadBytecode reads the hex encoded bytecode from the file or from stdin,
+// the constructor arguments are packed with the ABI if it is given.
+// All the errors are validation ones.
 func ReadBytecode(filename string, abiPath string, args []string) (types.Code, error) {
+	code, err := readBytecode(filename, abiPath, args)
+	return code, ValidationError(err)
+}
+
+func readBytecode(filename string, abiPath string, args []string) (types.Code, error) {
 	var bytecode []byte
 	var err error
 	location := filename
@@ -259,7 +271,14 @@ func ReadBytecode(filename string, abiPath string, args []string) (types.Code, e
 	return bytecode, nil
 }
 
+// ParseTokens parses the tokens in the <tokenId>=<balance> format.
+// All the errors are validation ones.
 func ParseTokens(params []string) ([]types.TokenBalance, error) {
+	tokens, err := parseTokens(params)
+	return tokens, ValidationError(err)
+}
+
+func parseTokens(params []string) ([]types.TokenBalance, error) {
 	tokens := make([]types.TokenBalance, 0, len(params))
 	for _, token := range params {
 		tokAndBalance := strings.Split(token, "=")
@@ -42,11 +42,11 @@ func CallReadonly(
 	if params.InOverridesPath != "" {
 		inOverridesData, err := os.ReadFile(params.InOverridesPath)
 		if err != nil {
-			return err
+			return ValidationError(err)
 		}
 
 		if err := json.Unmarshal(inOverridesData, &inOverrides); err != nil {
-			return err
+			return ValidationError(err)
 		}
 	}
 
@@ -71,7 +71,7 @@ func CallReadonly(
 		}
 	}
 
-	if params.AsJson {
+	if params.AsJson || IsStructuredOutput() {
 		output := &callReadOnlyOutput{
 			Result: outputs,
 		}
@@ -82,6 +82,10 @@ func CallReadonly(
 			output.OutTransactions = res.OutTransactions
 		}
 
+		if IsStructuredOutput() {
+			return PrintResult(output, nil)
+		}
+
 		s, err := json.MarshalIndent(output, "", "  ")
 		if err != nil {
 			return err
@@ -3,6 +3,7 @@ package common
 import (
 	"crypto/ecdsa"
 
+	nilclient "github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
@@ -12,4 +13,8 @@ type Config struct {
 	FaucetEndpoint string            `mapstructure:"faucet_endpoint"`
 	PrivateKey     *ecdsa.PrivateKey `mapstructure:"private_key"`
 	Address        types.Address     `mapstructure:"address"`
+	Account        string            `mapstructure:"account"`
+
+	// Signer signs the transactions with the selected keystore account or the private key.
+	Signer nilclient.Signer `mapstructure:"-"`
 }
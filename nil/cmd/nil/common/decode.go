@@ -0,0 +1,133 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/spf13/cobra"
+)
+
+const (
+	DecodeAbiFlag       = "abi"
+	DecodeArtifactsFlag = "artifacts"
+	DecodeFlag          = "decode"
+)
+
+// DecodeParams are the options of decoding the calldata, the events and the errors in the output.
+type DecodeParams struct {
+	AbiPaths     []string
+	ArtifactsDir string
+	Decode       bool
+}
+
+// SetDecodeFlags adds the flags enabling the ABI-aware decoding to the command.
+func SetDecodeFlags(cmd *cobra.Command, params *DecodeParams) {
+	cmd.Flags().StringArrayVar(
+		&params.AbiPaths,
+		DecodeAbiFlag,
+		nil,
+		"The path to the ABI file used to decode the calldata, the events and the errors (can be repeated)",
+	)
+	cmd.Flags().StringVar(
+		&params.ArtifactsDir,
+		DecodeArtifactsFlag,
+		"",
+		"The directory with the ABI files or the compilation artifacts used to decode the output",
+	)
+	cmd.Flags().BoolVar(
+		&params.Decode,
+		DecodeFlag,
+		false,
+		"Decode the output with the ABIs of the contracts verified in Cometa",
+	)
+}
+
+// NewAbiDecoder creates the decoder from the flags, it returns nil if the decoding isn't requested.
+// The ABIs of the contracts verified in Cometa are used when no given ABI matches.
+func NewAbiDecoder(params *DecodeParams) (*cliservice.AbiDecoder, error) {
+	if len(params.AbiPaths) == 0 && params.ArtifactsDir == "" && !params.Decode {
+		return nil, nil
+	}
+
+	abis := make([]abi.ABI, 0, len(params.AbiPaths))
+	for _, path := range params.AbiPaths {
+		contractAbi, err := ReadAbiFromFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read abi from %s: %w", path, err)
+		}
+		abis = append(abis, contractAbi)
+	}
+	if params.ArtifactsDir != "" {
+		artifacts, err := ReadAbisFromArtifacts(params.ArtifactsDir)
+		if err != nil {
+			return nil, err
+		}
+		abis = append(abis, artifacts...)
+	}
+
+	if cometaClient == nil || !cometaClient.IsValid() {
+		return cliservice.NewAbiDecoder(abis, nil), nil
+	}
+	return cliservice.NewAbiDecoder(abis, FetchAbiFromCometa), nil
+}
+
+// ReadAbisFromArtifacts reads the ABIs from the directory recursively.
+// Both the plain ABI files and the JSON compilation artifacts with the "abi" field
+// (as produced by solc, Hardhat or Foundry) are read, the other files are skipped.
+func ReadAbisFromArtifacts(dir string) ([]abi.ABI, error) {
+	var abis []abi.ABI
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if d.IsDir() || (ext != ".abi" && ext != ".json") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if contractAbi, ok := abiFromArtifact(data); ok {
+			abis = append(abis, contractAbi)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifacts from %s: %w", dir, err)
+	}
+	return abis, nil
+}
+
+func abiFromArtifact(data []byte) (abi.ABI, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return abi.ABI{}, false
+	}
+	if data[0] != '[' {
+		var artifact struct {
+			Abi json.RawMessage `json:"abi"`
+		}
+		if err := json.Unmarshal(data, &artifact); err != nil || len(artifact.Abi) == 0 {
+			return abi.ABI{}, false
+		}
+		data = artifact.Abi
+		// The ABI may be embedded as a string, e.g. in the contract data of Cometa
+		var embedded string
+		if err := json.Unmarshal(data, &embedded); err == nil {
+			data = []byte(embedded)
+		}
+	}
+	contractAbi, err := abi.JSON(strings.NewReader(string(data)))
+	if err != nil {
+		return abi.ABI{}, false
+	}
+	return contractAbi, true
+}
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAbisFromArtifacts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	const contractAbi = `[{"type": "function", "name": "get", "inputs": [], "outputs": [{"name": "", "type": "uint256"}]}]`
+	files := map[string]string{
+		"Plain.abi":                    contractAbi,
+		"out/Counter.sol/Counter.json": `{"abi": ` + contractAbi + `, "bytecode": {"object": "0x00"}}`,
+		"cometa.json":                  `{"abi": ` + `"[{\"type\": \"error\", \"name\": \"Failed\", \"inputs\": []}]"}`,
+		"build-info/info.json":         `{"input": {}}`,
+		"broken.abi":                   `not an abi`,
+		"README.md":                    `# artifacts`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	abis, err := ReadAbisFromArtifacts(dir)
+	require.NoError(t, err)
+	require.Len(t, abis, 3)
+
+	var methods, errors int
+	for _, a := range abis {
+		methods += len(a.Methods)
+		errors += len(a.Errors)
+	}
+	require.Equal(t, 2, methods)
+	require.Equal(t, 1, errors)
+
+	_, err = ReadAbisFromArtifacts(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
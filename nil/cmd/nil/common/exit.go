@@ -0,0 +1,132 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NilFoundation/nil/nil/client/rpc"
+	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+// The exit codes of the CLI, the scripts can tell the kinds of the failures apart by them.
+const (
+	// ExitCodeError is returned for the failures of no specific kind
+	ExitCodeError = 1
+	// ExitCodeValidation is returned for the invalid arguments, flags and input files
+	ExitCodeValidation = 2
+	// ExitCodeRPC is returned if a request to the cluster (or to Cometa or the faucet) failed
+	ExitCodeRPC = 3
+	// ExitCodeReverted is returned if a transaction was processed but failed
+	ExitCodeReverted = 4
+)
+
+// ExitError is the error of the command with the exit code of the CLI.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError marks the error as caused by the invalid input of the command.
+func ValidationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: ExitCodeValidation, Err: err}
+}
+
+// RevertedError marks the error as caused by the failed transaction.
+func RevertedError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: ExitCodeReverted, Err: err}
+}
+
+// ExitCode returns the exit code of the CLI failed with the error.
+func ExitCode(err error) int {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	var callErr *rpc.CallError
+	if errors.As(err, &callErr) {
+		return ExitCodeRPC
+	}
+	if errors.Is(err, cliservice.ErrTransactionFailed) {
+		return ExitCodeReverted
+	}
+	return ExitCodeError
+}
+
+func exitCodeKind(code int) string {
+	switch code {
+	case ExitCodeValidation:
+		return "validation"
+	case ExitCodeRPC:
+		return "rpc"
+	case ExitCodeReverted:
+		return "reverted"
+	}
+	return "error"
+}
+
+type errorOutput struct {
+	Error struct {
+		Kind    string `json:"kind"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// PrintError prints the error the CLI failed with, as JSON or YAML in the structured output mode.
+func PrintError(w io.Writer, err error) {
+	printError(w, Output, err)
+}
+
+func printError(w io.Writer, format OutputFormat, err error) {
+	if format == OutputText {
+		_, _ = fmt.Fprintf(w, "Error: %v\n", err)
+		return
+	}
+
+	var out errorOutput
+	out.Error.Code = ExitCode(err)
+	out.Error.Kind = exitCodeKind(out.Error.Code)
+	out.Error.Message = err.Error()
+	if writeErr := writeStructured(w, format, &out); writeErr != nil {
+		_, _ = fmt.Fprintf(w, "Error: %v\n", err)
+	}
+}
+
+// CheckReceipt fails with the reverted error if the transaction or any transaction spawned by it failed.
+func CheckReceipt(receipt *jsonrpc.RPCReceipt) error {
+	if failure := firstFailure(receipt); failure != nil {
+		return RevertedError(receiptError(failure.TxnHash, failure.Status, failure.ErrorMessage))
+	}
+	return nil
+}
+
+func firstFailure(receipt *jsonrpc.RPCReceipt) *jsonrpc.RPCReceipt {
+	if receipt == nil {
+		return nil
+	}
+	if !receipt.Success {
+		return receipt
+	}
+	for _, out := range receipt.OutReceipts {
+		if failure := firstFailure(out); failure != nil {
+			return failure
+		}
+	}
+	return nil
+}
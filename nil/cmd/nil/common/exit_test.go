@@ -0,0 +1,61 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/client/rpc"
+	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	plain := errors.New("failed")
+	assert.Equal(t, ExitCodeError, ExitCode(plain))
+	assert.Equal(t, ExitCodeValidation, ExitCode(ValidationError(plain)))
+	assert.Equal(t, ExitCodeValidation, ExitCode(fmt.Errorf("wrapped: %w", ValidationError(plain))))
+	assert.Equal(t, ExitCodeReverted, ExitCode(RevertedError(plain)))
+	assert.Equal(t, ExitCodeReverted, ExitCode(fmt.Errorf("deploy: %w", cliservice.ErrTransactionFailed)))
+	assert.Equal(t, ExitCodeRPC, ExitCode(fmt.Errorf("%w: %w", rpc.ErrRPCError, plain)))
+
+	require.NoError(t, ValidationError(nil))
+	require.NoError(t, RevertedError(nil))
+}
+
+func TestCheckReceipt(t *testing.T) {
+	t.Parallel()
+
+	receipt := &jsonrpc.RPCReceipt{
+		Success: true,
+		OutReceipts: []*jsonrpc.RPCReceipt{
+			{Success: true},
+			{Success: false, Status: "ExecutionReverted", ErrorMessage: "boom"},
+		},
+	}
+	err := CheckReceipt(receipt)
+	require.ErrorContains(t, err, "failed with ExecutionReverted: boom")
+	assert.Equal(t, ExitCodeReverted, ExitCode(err))
+
+	receipt.OutReceipts[1].Success = true
+	require.NoError(t, CheckReceipt(receipt))
+}
+
+func TestPrintError(t *testing.T) {
+	t.Parallel()
+
+	err := ValidationError(errors.New("invalid address"))
+
+	var buf bytes.Buffer
+	printError(&buf, OutputText, err)
+	assert.Equal(t, "Error: invalid address\n", buf.String())
+
+	buf.Reset()
+	printError(&buf, OutputYaml, err)
+	assert.Equal(t, "error:\n  kind: validation\n  code: 2\n  message: invalid address\n", buf.String())
+}
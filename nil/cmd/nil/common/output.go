@@ -0,0 +1,109 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat is the format of the command results, set by the global "output" flag.
+type OutputFormat string
+
+const (
+	OutputText OutputFormat = "text"
+	OutputJson OutputFormat = "json"
+	OutputYaml OutputFormat = "yaml"
+)
+
+// Output is the format the results of the commands are printed in.
+var Output = OutputText
+
+func (f *OutputFormat) String() string {
+	return string(*f)
+}
+
+func (f *OutputFormat) Set(value string) error {
+	switch OutputFormat(value) {
+	case OutputText, OutputJson, OutputYaml:
+		*f = OutputFormat(value)
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, expected one of: text, json, yaml", value)
+}
+
+func (f *OutputFormat) Type() string {
+	return "text|json|yaml"
+}
+
+// IsStructuredOutput reports whether the results are printed as JSON or YAML.
+// The additional human-oriented output is suppressed then.
+func IsStructuredOutput() bool {
+	return Output != OutputText
+}
+
+// PrintResult prints the result of the command. In the text mode printText is called,
+// otherwise the result is printed as JSON or YAML. The result marshaled to JSON
+// (e.g. json.RawMessage) is printed as is.
+func PrintResult(result any, printText func() error) error {
+	if !IsStructuredOutput() {
+		return printText()
+	}
+	return writeStructured(os.Stdout, Output, result)
+}
+
+func writeStructured(w io.Writer, format OutputFormat, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the result: %w", err)
+	}
+
+	if format == OutputYaml {
+		data, err = jsonToYaml(data)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		return fmt.Errorf("failed to format the result: %w", err)
+	}
+	indented.WriteByte('\n')
+	_, err = w.Write(indented.Bytes())
+	return err
+}
+
+// jsonToYaml converts the JSON document to YAML keeping the order of the keys.
+func jsonToYaml(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("failed to convert the result to YAML: %w", err)
+	}
+	resetStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, fmt.Errorf("failed to marshal the result to YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resetStyle drops the flow style and the quotes of JSON, the encoder quotes
+// the strings only where they would be read as other types.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
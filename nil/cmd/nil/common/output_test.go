@@ -0,0 +1,67 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	libcommon "github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputFormatSet(t *testing.T) {
+	t.Parallel()
+
+	var format OutputFormat
+	require.NoError(t, format.Set("yaml"))
+	assert.Equal(t, OutputYaml, format)
+	require.NoError(t, format.Set("json"))
+	assert.Equal(t, OutputJson, format)
+	require.Error(t, format.Set("xml"))
+	assert.Equal(t, OutputJson, format)
+}
+
+func TestWriteStructured(t *testing.T) {
+	t.Parallel()
+
+	result := &TransactionOutput{
+		TransactionHash: libcommon.HexToHash("0x0102"),
+		Address:         &types.FaucetAddress,
+	}
+
+	t.Run("Json", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, writeStructured(&buf, OutputJson, result))
+
+		var decoded TransactionOutput
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, result.TransactionHash, decoded.TransactionHash)
+		assert.Equal(t, *result.Address, *decoded.Address)
+		assert.Nil(t, decoded.Receipt)
+	})
+
+	t.Run("Yaml", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, writeStructured(&buf, OutputYaml, result))
+		// The hash is quoted since it would be read as a number otherwise
+		assert.Equal(t,
+			"transactionHash: \""+result.TransactionHash.Hex()+"\"\n"+
+				"address: "+result.Address.Hex()+"\n",
+			buf.String())
+	})
+
+	t.Run("RawJson", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		raw := json.RawMessage(`{"b": [1, "2"], "a": {"c": true}}`)
+		require.NoError(t, writeStructured(&buf, OutputYaml, raw))
+		assert.Equal(t, "b:\n  - 1\n  - \"2\"\na:\n  c: true\n", buf.String())
+	})
+}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	libcommon "github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+// The results of the commands printed in the structured output mode.
+// The fields are a part of the CLI interface, they must not be renamed or removed.
+
+type AddressOutput struct {
+	Address types.Address `json:"address"`
+}
+
+type BalanceOutput struct {
+	Address types.Address `json:"address"`
+	Balance types.Value   `json:"balance"`
+}
+
+type SeqnoOutput struct {
+	Address types.Address `json:"address"`
+	Seqno   types.Seqno   `json:"seqno"`
+}
+
+type CodeOutput struct {
+	Address types.Address `json:"address"`
+	Code    string        `json:"code"`
+}
+
+// TransactionOutput is the result of the commands sending a transaction.
+// The address is set for the deploy transactions, the receipt is set if the command waited for it.
+type TransactionOutput struct {
+	TransactionHash libcommon.Hash      `json:"transactionHash"`
+	Address         *types.Address      `json:"address,omitempty"`
+	Receipt         *jsonrpc.RPCReceipt `json:"receipt,omitempty"`
+}
+
+// TopUpOutput is the balance of the token the contract was topped up with.
+type TopUpOutput struct {
+	Address types.Address `json:"address"`
+	TokenId types.TokenId `json:"tokenId"`
+	Token   string        `json:"token,omitempty"`
+	Balance types.Value   `json:"balance"`
+}
+
+type TokenOutput struct {
+	TokenId types.TokenId `json:"tokenId"`
+	Name    string        `json:"name,omitempty"`
+	Balance types.Value   `json:"balance"`
+}
+
+type TokensOutput struct {
+	Address types.Address  `json:"address"`
+	Tokens  []*TokenOutput `json:"tokens"`
+}
+
+// NewTokensOutput returns the tokens of the contract sorted by their ids.
+func NewTokensOutput(address types.Address, tokens types.TokensMap) *TokensOutput {
+	res := &TokensOutput{
+		Address: address,
+		Tokens:  make([]*TokenOutput, 0, len(tokens)),
+	}
+	for id, balance := range tokens {
+		res.Tokens = append(res.Tokens, &TokenOutput{
+			TokenId: id,
+			Name:    types.GetTokenName(id),
+			Balance: balance,
+		})
+	}
+	slices.SortFunc(res.Tokens, func(a, b *TokenOutput) int {
+		return bytes.Compare(a.TokenId[:], b.TokenId[:])
+	})
+	return res
+}
+
+// PrintFee prints the estimated fee in the text mode.
+func PrintFee(res *jsonrpc.EstimateFeeRes) {
+	if !Quiet {
+		fmt.Print("FeeCredit: ")
+	}
+	fmt.Println(res.FeeCredit)
+	if !Quiet {
+		fmt.Print("MaxBasFee: ")
+	}
+	fmt.Println(res.MaxBasFee)
+	if !Quiet {
+		fmt.Print("AveragePriorityFee: ")
+	}
+	fmt.Println(res.AveragePriorityFee)
+}
@@ -0,0 +1,138 @@
+package common
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	nilclient "github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/internal/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog"
+	"golang.org/x/term"
+)
+
+// PassphraseEnv is the environment variable with the passphrase unlocking the keystore account.
+// It allows using the encrypted keys in scripts and CI, where the passphrase can't be prompted.
+const PassphraseEnv = "NIL_KEYSTORE_PASSPHRASE"
+
+// KeystoreDir returns the directory of the keystore, which is kept next to the config file.
+func KeystoreDir(cfgFile string) string {
+	return filepath.Join(filepath.Dir(cfgFile), "keystore")
+}
+
+// ReadPassphrase returns the passphrase from the environment or prompts it in the terminal.
+// If confirm is set, the passphrase is prompted twice to protect from typos when encrypting a new key.
+func ReadPassphrase(prompt string, confirm bool) (string, error) {
+	if passphrase, ok := os.LookupEnv(PassphraseEnv); ok {
+		return passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("passphrase is required: set %s or run in a terminal", PassphraseEnv)
+	}
+
+	read := func(prompt string) (string, error) {
+		fmt.Fprint(os.Stderr, prompt)
+		defer fmt.Fprintln(os.Stderr)
+		passphrase, err := term.ReadPassword(fd)
+		return string(passphrase), err
+	}
+
+	passphrase, err := read(prompt)
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		repeated, err := read("Repeat the passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if repeated != passphrase {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// ReadPrivateKey reads the hex private key from stdin or prompts it in the terminal.
+// The key is never taken from the arguments, which leak to the shell history and the process list.
+func ReadPrivateKey(prompt string) (*ecdsa.PrivateKey, error) {
+	var hexKey string
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, prompt)
+		data, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, err
+		}
+		hexKey = string(data)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read the private key from stdin: %w", err)
+		}
+		hexKey = line
+	}
+
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return nil, ValidationError(err)
+	}
+	return key, nil
+}
+
+// ImportKey encrypts the key with a passphrase and stores it in the keystore under the name.
+// If replace is set, the existing account with the name is overwritten.
+func ImportKey(cfgFile string, name string, key *ecdsa.PrivateKey, replace bool) error {
+	passphrase, err := ReadPassphrase(fmt.Sprintf("Enter a passphrase for account %s: ", name), true)
+	if err != nil {
+		return err
+	}
+	ks := keystore.New(KeystoreDir(cfgFile))
+	if replace {
+		if err := ks.Delete(name); err != nil && !errors.Is(err, keystore.ErrAccountNotFound) {
+			return err
+		}
+	}
+	return ks.Import(name, key, passphrase)
+}
+
+// InitSigner sets the signer of the config. The account from the keystore is selected by the flag
+// or by the "account" config option, otherwise the plaintext private key from the config is used.
+func InitSigner(cfg *Config, account string, cfgFile string, logger zerolog.Logger) error {
+	if account == "" {
+		account = cfg.Account
+	}
+
+	if account == "" {
+		if cfg.PrivateKey != nil {
+			logger.Warn().Msg("Using the plaintext private key from the config. " +
+				"Consider moving it to the encrypted keystore with `nil keystore import <name> --from-config`.")
+		}
+		cfg.Signer = nilclient.NewPrivateKeySigner(cfg.PrivateKey)
+		return nil
+	}
+
+	ks := keystore.New(KeystoreDir(cfgFile))
+	names, err := ks.List()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(names, account) {
+		return fmt.Errorf("%w: %s (keystore: %s)", keystore.ErrAccountNotFound, account, ks.Dir())
+	}
+
+	cfg.Signer = keystore.NewSigner(ks, account, func(name string) (string, error) {
+		return ReadPassphrase(fmt.Sprintf("Enter the passphrase for account %s: ", name), false)
+	})
+	return nil
+}
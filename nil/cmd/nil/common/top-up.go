@@ -19,7 +19,7 @@ func RunTopUp(
 	if err != nil {
 		return err
 	}
-	service := cliservice.NewService(ctx, GetRpcClient(), cfg.PrivateKey, faucet)
+	service := cliservice.NewServiceWithSigner(ctx, GetRpcClient(), cfg.Signer, faucet)
 
 	faucetAddress := types.FaucetAddress
 	if len(tokId) == 0 {
@@ -30,7 +30,7 @@ func RunTopUp(
 		faucetAddress, ok = tokens[tokId]
 		if !ok {
 			if err = faucetAddress.Set(tokId); err != nil {
-				return fmt.Errorf("undefined token id: %s", tokId)
+				return ValidationError(fmt.Errorf("undefined token id: %s", tokId))
 			}
 		}
 	}
@@ -61,15 +61,22 @@ func RunTopUp(
 		}
 	}
 
-	if !quiet {
-		fmt.Printf("%s balance: ", titleCaser.String(name))
+	result := &TopUpOutput{
+		Address: address,
+		TokenId: types.TokenId(faucetAddress),
+		Token:   tokId,
+		Balance: balance,
 	}
+	return PrintResult(result, func() error {
+		if !quiet {
+			fmt.Printf("%s balance: ", titleCaser.String(name))
+		}
 
-	fmt.Print(balance)
-	if !quiet && len(tokId) > 0 {
-		fmt.Printf(" [%s]", tokId)
-	}
-	fmt.Println()
-
-	return nil
+		fmt.Print(balance)
+		if !quiet && len(tokId) > 0 {
+			fmt.Printf(" [%s]", tokId)
+		}
+		fmt.Println()
+		return nil
+	})
 }
@@ -0,0 +1,48 @@
+package common
+
+import (
+	"fmt"
+
+	libcommon "github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+// WaitForTransactionTree waits for the transaction and all transactions spawned by it,
+// prints the summary and fails if any of them failed.
+// The root receipt with the receipts of the children is returned.
+func WaitForTransactionTree(service *cliservice.Service, txnHash libcommon.Hash) (*jsonrpc.RPCReceipt, error) {
+	tree, err := service.WaitForTransactionTree(txnHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if !Quiet {
+		fmt.Print(cliservice.TransactionTreeToString(tree))
+	}
+
+	if failure := tree.FirstFailure; failure != nil {
+		return nil, RevertedError(receiptError(failure.TxnHash, failure.Status, failure.ErrorMessage))
+	}
+	return tree.Receipt, nil
+}
+
+// WaitForReceipt waits for the receipt of the transaction and fails if the transaction
+// or any transaction spawned by it failed.
+func WaitForReceipt(service *cliservice.Service, txnHash libcommon.Hash) (*jsonrpc.RPCReceipt, error) {
+	receipt, err := service.WaitForReceipt(txnHash)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckReceipt(receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+func receiptError(txnHash libcommon.Hash, status, message string) error {
+	if message != "" {
+		return fmt.Errorf("transaction %s failed with %s: %s", txnHash, status, message)
+	}
+	return fmt.Errorf("transaction %s failed with %s", txnHash, status)
+}
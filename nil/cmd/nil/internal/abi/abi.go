@@ -11,6 +11,14 @@ import (
 
 const pathFlag = "path"
 
+type encodeOutput struct {
+	Calldata string `json:"calldata"`
+}
+
+type decodeOutput struct {
+	Result []*common.ArgValue `json:"result"`
+}
+
 func GetCommand() *cobra.Command {
 	abiCmd := &cobra.Command{
 		Use:          "abi",
@@ -33,11 +41,13 @@ func GetCommand() *cobra.Command {
 
 			data, err := common.ArgsToCalldata(abi, args[0], args[1:])
 			if err != nil {
-				return err
+				return common.ValidationError(err)
 			}
 
-			fmt.Println(hexutil.Encode(data))
-			return nil
+			return common.PrintResult(&encodeOutput{Calldata: hexutil.Encode(data)}, func() error {
+				fmt.Println(hexutil.Encode(data))
+				return nil
+			})
 		},
 	}
 
@@ -49,7 +59,7 @@ func GetCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			data, err := hexutil.DecodeHex(args[1])
 			if err != nil {
-				return err
+				return common.ValidationError(err)
 			}
 
 			abi, err := common.ReadAbiFromFile(path)
@@ -59,14 +69,15 @@ func GetCommand() *cobra.Command {
 
 			outputs, err := common.CalldataToArgs(abi, args[0], data)
 			if err != nil {
-				return err
-			}
-
-			for _, output := range outputs {
-				fmt.Printf("%s: %v\n", output.Type, output.Value)
+				return common.ValidationError(err)
 			}
 
-			return nil
+			return common.PrintResult(&decodeOutput{Result: outputs}, func() error {
+				for _, output := range outputs {
+					fmt.Printf("%s: %v\n", output.Type, output.Value)
+				}
+				return nil
+			})
 		},
 	}
 
@@ -0,0 +1,204 @@
+package abigen
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/client/bind"
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	"github.com/NilFoundation/nil/nil/cmd/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/cometa"
+	"github.com/spf13/cobra"
+)
+
+const (
+	abiFlag            = "abi"
+	binFlag            = "bin"
+	typeFlag           = "type"
+	addressFlag        = "address"
+	cometaEndpointFlag = "cometa-endpoint"
+	pkgFlag            = "pkg"
+	outFlag            = "out"
+)
+
+var logger = logging.NewLogger("abigen")
+
+type abigenParams struct {
+	abiPath        string
+	binPath        string
+	typeName       string
+	addresses      []string
+	cometaEndpoint string
+	pkg            string
+	out            string
+}
+
+func GetCommand(cfgFile *string) *cobra.Command {
+	params := &abigenParams{}
+
+	cmd := &cobra.Command{
+		Use:   "abigen",
+		Short: "Generate Go bindings of contracts",
+		Long: "Generate the Go package with typed bindings of the contracts from the ABI and bytecode files " +
+			"or from the metadata of the deployed contracts registered in the Cometa service",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAbigen(params, *cfgFile)
+		},
+	}
+
+	cmd.Flags().StringVar(&params.abiPath, abiFlag, "", "The path to the ABI file of the contract")
+	cmd.Flags().StringVar(&params.binPath, binFlag, "",
+		"The path to the file with the hex encoded init code of the contract, the deploy function is generated if set")
+	cmd.Flags().StringVar(&params.typeName, typeFlag, "",
+		"The Go type name of the contract, the name of the ABI file by default")
+	cmd.Flags().StringArrayVar(&params.addresses, addressFlag, nil,
+		"The address of the contract to take the metadata from Cometa, can be set multiple times")
+	cmd.Flags().StringVar(&params.cometaEndpoint, cometaEndpointFlag, "",
+		"The Cometa endpoint, taken from the config file by default")
+	cmd.Flags().StringVar(&params.pkg, pkgFlag, "", "The Go package name of the generated code")
+	cmd.Flags().StringVar(&params.out, outFlag, "", "The output file, the code is printed to stdout if empty")
+
+	check.PanicIfErr(cmd.MarkFlagRequired(pkgFlag))
+	cmd.MarkFlagsMutuallyExclusive(abiFlag, addressFlag)
+	cmd.MarkFlagsMutuallyExclusive(typeFlag, addressFlag)
+	cmd.MarkFlagsOneRequired(abiFlag, addressFlag)
+
+	return cmd
+}
+
+func runAbigen(params *abigenParams, cfgFile string) error {
+	var contracts []*bind.Contract
+	var err error
+	if params.abiPath != "" {
+		contracts, err = contractsFromFiles(params)
+	} else {
+		contracts, err = contractsFromCometa(params, cfgFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	code, err := bind.Bind(params.pkg, contracts)
+	if err != nil {
+		return err
+	}
+
+	result := &abigenOutput{Package: params.pkg}
+	for _, contract := range contracts {
+		result.Types = append(result.Types, contract.Name)
+	}
+	if params.out == "" {
+		result.Code = code
+		return common.PrintResult(result, func() error {
+			fmt.Print(code)
+			return nil
+		})
+	}
+	if err := os.WriteFile(params.out, []byte(code), 0o644); err != nil { //nolint:gosec
+		return err
+	}
+	result.File = params.out
+	return common.PrintResult(result, func() error {
+		return nil
+	})
+}
+
+type abigenOutput struct {
+	Package string   `json:"package"`
+	Types   []string `json:"types"`
+	File    string   `json:"file,omitempty"`
+	Code    string   `json:"code,omitempty"`
+}
+
+func contractsFromFiles(params *abigenParams) ([]*bind.Contract, error) {
+	abiData, err := os.ReadFile(params.abiPath)
+	if err != nil {
+		return nil, common.ValidationError(fmt.Errorf("failed to read the ABI: %w", err))
+	}
+
+	contract := &bind.Contract{
+		Name: params.typeName,
+		ABI:  string(abiData),
+	}
+	if contract.Name == "" {
+		contract.Name = typeName(strings.TrimSuffix(filepath.Base(params.abiPath), filepath.Ext(params.abiPath)))
+	}
+
+	if params.binPath != "" {
+		bin, err := os.ReadFile(params.binPath)
+		if err != nil {
+			return nil, common.ValidationError(fmt.Errorf("failed to read the bytecode: %w", err))
+		}
+		contract.Bin = strings.TrimSpace(string(bin))
+	}
+	return []*bind.Contract{contract}, nil
+}
+
+func contractsFromCometa(params *abigenParams, cfgFile string) ([]*bind.Contract, error) {
+	endpoint := params.cometaEndpoint
+	if endpoint == "" {
+		cfg, err := config.LoadConfig(cfgFile, logger)
+		if err != nil {
+			return nil, err
+		}
+		endpoint = cfg.CometaEndpoint
+		if endpoint == "" {
+			endpoint = cfg.RPCEndpoint
+		}
+	}
+	if endpoint == "" {
+		return nil, errors.New("the Cometa endpoint is not set")
+	}
+	client := cometa.NewClient(endpoint)
+
+	contracts := make([]*bind.Contract, 0, len(params.addresses))
+	for _, a := range params.addresses {
+		var address types.Address
+		if err := address.Set(a); err != nil {
+			return nil, common.ValidationError(fmt.Errorf("invalid address %s: %w", a, err))
+		}
+		data, err := client.GetContract(address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the metadata of %s: %w", address, err)
+		}
+
+		// The contract name is "<file>:<contract>"
+		name := data.Name[strings.LastIndex(data.Name, ":")+1:]
+		if name == "" {
+			return nil, fmt.Errorf("the metadata of %s has no contract name", address)
+		}
+		contracts = append(contracts, &bind.Contract{
+			Name: typeName(name),
+			ABI:  data.Abi,
+			Bin:  hexutil.Encode(data.InitCode),
+		})
+	}
+	return contracts, nil
+}
+
+// typeName converts the file or contract name to the Go type name.
+func typeName(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '-' || r == '.' || r == ' ':
+			upper = true
+		case upper:
+			sb.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -1,6 +1,7 @@
 package block
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/cmd/nil/common"
@@ -36,16 +37,27 @@ func setFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&params.jsonOutput, jsonFlag, false, "Enable JSON output")
 	cmd.Flags().BoolVar(&params.fullOutput, fullFlag, false, "Do not cut any data")
 	cmd.Flags().BoolVar(&params.noColor, noColorFlag, false, "Do not colorize the output")
+
+	common.SetDecodeFlags(cmd, &params.decode)
 }
 
 func runCommand(cmd *cobra.Command, args []string) error {
 	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), nil, nil)
 
-	blockData, err := service.FetchDebugBlock(params.shardId, args[0], params.jsonOutput, params.fullOutput, params.noColor)
+	decoder, err := common.NewAbiDecoder(&params.decode)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput := params.jsonOutput || common.IsStructuredOutput()
+	blockData, err := service.FetchDebugBlock(
+		params.shardId, args[0], jsonOutput, params.fullOutput, params.noColor, decoder)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to fetch the block by number")
 		return err
 	}
-	fmt.Println(string(blockData))
-	return nil
+	return common.PrintResult(json.RawMessage(blockData), func() error {
+		fmt.Println(string(blockData))
+		return nil
+	})
 }
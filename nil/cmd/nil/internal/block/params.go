@@ -1,6 +1,7 @@
 package block
 
 import (
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
@@ -18,4 +19,5 @@ type blockParams struct {
 	fullOutput bool
 	noColor    bool
 	shardId    types.ShardId
+	decode     common.DecodeParams
 }
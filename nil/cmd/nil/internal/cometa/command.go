@@ -3,14 +3,27 @@ package cometa
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/cometa"
 	"github.com/spf13/cobra"
 )
 
+type infoOutput struct {
+	Address      types.Address `json:"address"`
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	SourceFiles  []string      `json:"sourceFiles"`
+	BytecodeSize int           `json:"bytecodeSize"`
+	SavedTo      string        `json:"savedTo,omitempty"`
+}
+
 func GetCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "cometa [options]",
@@ -67,12 +80,12 @@ func runRegisterCommand(_ *cobra.Command) error {
 
 	inputJsonData, err := os.ReadFile(params.inputJsonFile)
 	if err != nil {
-		return fmt.Errorf("failed to read the input JSON file: %w", err)
+		return common.ValidationError(fmt.Errorf("failed to read the input JSON file: %w", err))
 	}
 
 	inputJson, err := normalizeCompileInput(string(inputJsonData), params.inputJsonFile)
 	if err != nil {
-		return fmt.Errorf("failed to normalize the input JSON file: %w", err)
+		return common.ValidationError(fmt.Errorf("failed to normalize the input JSON file: %w", err))
 	}
 
 	err = cometaClient.RegisterContract(inputJson, params.address)
@@ -80,9 +93,10 @@ func runRegisterCommand(_ *cobra.Command) error {
 		return fmt.Errorf("failed to register the contract: %w", err)
 	}
 
-	fmt.Printf("Contract metadata for address %s has been registered\n", params.address)
-
-	return nil
+	return common.PrintResult(&common.AddressOutput{Address: params.address}, func() error {
+		fmt.Printf("Contract metadata for address %s has been registered\n", params.address)
+		return nil
+	})
 }
 
 func normalizeCompileInput(inputJson, inputJsonFile string) (string, error) {
@@ -116,22 +130,29 @@ func runInfoCommand(_ *cobra.Command) error {
 		if err = os.WriteFile(params.saveToFile, data, 0o600); err != nil {
 			return fmt.Errorf("failed to save metadata to a file: %w", err)
 		}
-		fmt.Printf("Contract metadata for address %s has been saved to file '%s'\n", params.address, params.saveToFile)
-	} else {
+	}
+
+	sourceFiles := slices.Sorted(maps.Keys(contract.SourceCode))
+	result := &infoOutput{
+		Address:      params.address,
+		Name:         contract.Name,
+		Description:  contract.Description,
+		SourceFiles:  sourceFiles,
+		BytecodeSize: len(contract.Code),
+		SavedTo:      params.saveToFile,
+	}
+	return common.PrintResult(result, func() error {
+		if len(params.saveToFile) > 0 {
+			fmt.Printf("Contract metadata for address %s has been saved to file '%s'\n", params.address, params.saveToFile)
+			return nil
+		}
 		fmt.Printf("Contract metadata for address %s\n", params.address)
 		fmt.Printf("  Name: %s\n", contract.Name)
 		if len(contract.Description) > 0 {
 			fmt.Printf("  Description:\n%s\n", contract.Description)
 		}
-		fmt.Printf("  Source files: [")
-		sep := ""
-		for name := range contract.SourceCode {
-			fmt.Print(sep + name)
-			sep = ", "
-		}
-		fmt.Printf("]\n")
+		fmt.Printf("  Source files: [%s]\n", strings.Join(sourceFiles, ", "))
 		fmt.Printf("  Bytecode size: %d\n", len(contract.Code))
-	}
-
-	return nil
+		return nil
+	})
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -21,9 +22,29 @@ var supportedOptions map[string]struct{} = map[string]struct{}{
 	"cometa_endpoint": {},
 	"faucet_endpoint": {},
 	"private_key":     {},
+	"account":         {},
 	"address":         {},
 }
 
+type initOutput struct {
+	Path string `json:"path"`
+}
+
+type showOutput struct {
+	Path   string         `json:"path"`
+	Values map[string]any `json:"values"`
+}
+
+type valueOutput struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+// noText is used by the commands reporting their results with the logs in the text mode.
+func noText() error {
+	return nil
+}
+
 func GetCommand(configPath *string) *cobra.Command {
 	configCmd := &cobra.Command{
 		Use:          "config",
@@ -56,7 +77,7 @@ func GetCommand(configPath *string) *cobra.Command {
 			}
 
 			logger.Info().Msgf("The config file has been initialized successfully: %s", path)
-			return nil
+			return common.PrintResult(&initOutput{Path: path}, noText)
 		},
 	}
 
@@ -66,16 +87,19 @@ func GetCommand(configPath *string) *cobra.Command {
 		Args:         cobra.ExactArgs(0),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			const printFormat = "%-18s: %v\n"
-			fmt.Printf(printFormat, "The config file", viper.ConfigFileUsed())
-			nilSection, ok := viper.AllSettings()["nil"].(map[string]interface{})
-			if !ok {
-				return nil
-			}
-			for key, value := range nilSection {
-				fmt.Printf(printFormat, key, value)
+			nilSection, _ := viper.AllSettings()["nil"].(map[string]interface{})
+			result := &showOutput{
+				Path:   viper.ConfigFileUsed(),
+				Values: nilSection,
 			}
-			return nil
+			return common.PrintResult(result, func() error {
+				const printFormat = "%-18s: %v\n"
+				fmt.Printf(printFormat, "The config file", result.Path)
+				for key, value := range nilSection {
+					fmt.Printf(printFormat, key, value)
+				}
+				return nil
+			})
 		},
 	}
 
@@ -89,10 +113,13 @@ func GetCommand(configPath *string) *cobra.Command {
 			value := viper.Get("nil." + key)
 			if value == nil {
 				logger.Warn().Msgf("Key %q is not found in the config file", key)
-				return nil
 			}
-			fmt.Printf("%s: %v\n", key, value)
-			return nil
+			return common.PrintResult(&valueOutput{Key: key, Value: value}, func() error {
+				if value != nil {
+					fmt.Printf("%s: %v\n", key, value)
+				}
+				return nil
+			})
 		},
 	}
 
@@ -103,8 +130,7 @@ func GetCommand(configPath *string) *cobra.Command {
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if _, supported := supportedOptions[args[0]]; !supported {
-				logger.Error().Msgf("Key %q is not known", args[0])
-				return nil
+				return common.ValidationError(fmt.Errorf("key %q is not known", args[0]))
 			}
 
 			if err := PatchConfig(map[string]interface{}{
@@ -114,7 +140,7 @@ func GetCommand(configPath *string) *cobra.Command {
 				return err
 			}
 			logger.Info().Msgf("Set %q to %q", args[0], args[1])
-			return nil
+			return common.PrintResult(&valueOutput{Key: args[0], Value: args[1]}, noText)
 		},
 	}
 
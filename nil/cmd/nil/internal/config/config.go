@@ -21,6 +21,7 @@ import (
 
 const (
 	AddressField     = "address"
+	AccountField     = "account"
 	PrivateKeyField  = "private_key"
 	RPCEndpointField = "rpc_endpoint"
 )
@@ -42,8 +43,12 @@ const InitConfigTemplate = `; Configuration for interacting with the =nil; clust
 ; For example, if your Faucet's RPC endpoint is at "http://127.0.0.1:8529", set it as below
 ; faucet_endpoint = "http://127.0.0.1:8529"
 
-; Specify the private key used for signing external transactions to your smart account.
-; You can generate a new key with "nil keygen new".
+; Specify the keystore account used for signing external transactions to your smart account.
+; The keys in the keystore are encrypted with a passphrase. You can create a new one with "nil keygen new".
+; account = "WRITE_YOUR_ACCOUNT_NAME_HERE"
+
+; Alternatively, specify the plaintext private key used for signing external transactions to your smart account.
+; You can move it to the keystore with "nil keystore import <name> --from-config".
 ; private_key = "WRITE_YOUR_PRIVATE_KEY_HERE"
 
 ; Specify the address of your smart account to be the receiver of your external transactions.
@@ -83,6 +88,7 @@ func InitDefaultConfig(configPath string) (string, error) {
 	return configPath, nil
 }
 
+// PatchConfig sets the values of the keys in the config file, the keys with nil values are removed.
 func PatchConfig(delta map[string]any, force bool) error {
 	configPath := viper.ConfigFileUsed()
 	if configPath == "" {
@@ -106,13 +112,17 @@ func PatchConfig(delta map[string]any, force bool) error {
 	result := strings.Builder{}
 	first := true
 	for _, line := range strings.Split(string(cfg), "\n") {
+		key := strings.TrimSpace(strings.Split(line, "=")[0])
+		value, ok := delta[key]
+		if ok && value == nil {
+			continue
+		}
 		if !first {
 			result.WriteByte('\n')
 		} else {
 			first = false
 		}
-		key := strings.TrimSpace(strings.Split(line, "=")[0])
-		if value, ok := delta[key]; ok {
+		if ok {
 			result.WriteString(fmt.Sprintf("%s = %v", key, value))
 			delete(delta, key)
 		} else {
@@ -120,7 +130,9 @@ func PatchConfig(delta map[string]any, force bool) error {
 		}
 	}
 	for key, value := range delta {
-		result.WriteString(fmt.Sprintf("%s = %v\n", key, value))
+		if value != nil {
+			result.WriteString(fmt.Sprintf("%s = %v\n", key, value))
+		}
 	}
 	return os.WriteFile(configPath, []byte(result.String()), 0o600)
 }
@@ -45,7 +45,7 @@ func GetAddressCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runAddress(cmd *cobra.Command, cmdArgs []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 
 	var filename string
 	var args []string
@@ -60,10 +60,11 @@ func runAddress(cmd *cobra.Command, cmdArgs []string, cfg *common.Config) error
 	}
 
 	address := service.ContractAddress(params.shardId, params.salt, bytecode)
-	if !common.Quiet {
-		fmt.Print("Contract address: ")
-	}
-	fmt.Println(address.Hex())
-
-	return nil
+	return common.PrintResult(&common.AddressOutput{Address: address}, func() error {
+		if !common.Quiet {
+			fmt.Print("Contract address: ")
+		}
+		fmt.Println(address.Hex())
+		return nil
+	})
 }
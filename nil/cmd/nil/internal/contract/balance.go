@@ -26,17 +26,19 @@ func GetBalanceCommand(cfg *common.Config) *cobra.Command {
 func runBalance(cmd *cobra.Command, args []string, cfg *common.Config) error {
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 	balance, err := service.GetBalance(address)
 	if err != nil {
 		return err
 	}
-	if !common.Quiet {
-		fmt.Print("Contract balance: ")
-	}
-	fmt.Println(balance)
-	return nil
+	return common.PrintResult(&common.BalanceOutput{Address: address, Balance: balance}, func() error {
+		if !common.Quiet {
+			fmt.Print("Contract balance: ")
+		}
+		fmt.Println(balance)
+		return nil
+	})
 }
@@ -69,11 +69,11 @@ func GetCallReadonlyCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runCallReadonly(cmd *cobra.Command, args []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return fmt.Errorf("invalid address: %w", err)
+		return common.ValidationError(fmt.Errorf("invalid address: %w", err))
 	}
 
 	var contractAbi abi.ABI
@@ -94,7 +94,7 @@ func runCallReadonly(cmd *cobra.Command, args []string, cfg *common.Config) erro
 
 	handler := func(res *jsonrpc.CallRes) ([]*common.ArgValue, []*common.NamedArgValues, error) {
 		if res.Error != "" {
-			return nil, nil, fmt.Errorf("error during the call: %s", res.Error)
+			return nil, nil, common.RevertedError(fmt.Errorf("error during the call: %s", res.Error))
 		}
 
 		logs, err := common.DecodeLogs(contractAbi, res.Logs)
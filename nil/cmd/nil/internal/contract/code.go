@@ -26,15 +26,19 @@ func GetCodeCommand(cfg *common.Config) *cobra.Command {
 func runCode(cmd *cobra.Command, args []string, cfg *common.Config) error {
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
-	_, _ = service.GetCode(address)
-	code, _ := service.GetCode(address)
-	if !common.Quiet {
-		fmt.Print("Contract code: ")
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
+	code, err := service.GetCode(address)
+	if err != nil {
+		return err
 	}
-	fmt.Println(code)
-	return nil
+	return common.PrintResult(&common.CodeOutput{Address: address, Code: code}, func() error {
+		if !common.Quiet {
+			fmt.Print("Contract code: ")
+		}
+		fmt.Println(code)
+		return nil
+	})
 }
@@ -7,6 +7,7 @@ import (
 	libcommon "github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/spf13/cobra"
 )
 
@@ -55,6 +56,14 @@ func setDeployFlags(cmd *cobra.Command) {
 		"Define whether the command should wait for the receipt",
 	)
 
+	cmd.Flags().BoolVar(
+		&params.waitAll,
+		waitAllFlag,
+		false,
+		"Wait for all transactions spawned by the transaction across the shards and fail if any of them failed",
+	)
+	cmd.MarkFlagsMutuallyExclusive(noWaitFlag, waitAllFlag)
+
 	cmd.Flags().Var(
 		&params.Fee.FeeCredit,
 		feeCreditFlag,
@@ -63,7 +72,7 @@ func setDeployFlags(cmd *cobra.Command) {
 }
 
 func runDeploy(cmd *cobra.Command, cmdArgs []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 
 	var filename string
 	var args []string
@@ -84,21 +93,33 @@ func runDeploy(cmd *cobra.Command, cmdArgs []string, cfg *common.Config) error {
 		return err
 	}
 
-	if !params.noWait {
-		if _, err := service.WaitForReceipt(txnHash); err != nil {
+	var receipt *jsonrpc.RPCReceipt
+	switch {
+	case params.waitAll:
+		if receipt, err = common.WaitForTransactionTree(service, txnHash); err != nil {
+			return err
+		}
+	case !params.noWait:
+		if receipt, err = common.WaitForReceipt(service, txnHash); err != nil {
 			return err
 		}
 	}
 
-	if !common.Quiet {
-		fmt.Print("Transaction hash: ")
-	}
-	fmt.Println(txnHash)
-
-	if !common.Quiet {
-		fmt.Print("Contract address: ")
+	result := &common.TransactionOutput{
+		TransactionHash: txnHash,
+		Address:         &addr,
+		Receipt:         receipt,
 	}
-	fmt.Println(addr)
+	return common.PrintResult(result, func() error {
+		if !common.Quiet {
+			fmt.Print("Transaction hash: ")
+		}
+		fmt.Println(txnHash)
 
-	return nil
+		if !common.Quiet {
+			fmt.Print("Contract address: ")
+		}
+		fmt.Println(addr)
+		return nil
+	})
 }
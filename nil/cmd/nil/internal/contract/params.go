@@ -10,6 +10,7 @@ const (
 	amountFlag       = "amount"
 	noSignFlag       = "no-sign"
 	noWaitFlag       = "no-wait"
+	waitAllFlag      = "wait-all"
 	saltFlag         = "salt"
 	shardIdFlag      = "shard-id"
 	feeCreditFlag    = "fee-credit"
@@ -20,6 +21,7 @@ const (
 	outOverridesFlag = "out-overrides"
 	withDetailsFlag  = "with-details"
 	asJsonFlag       = "json"
+	paymasterFlag    = "paymaster"
 )
 
 var params = &contractParams{
@@ -29,11 +31,13 @@ var params = &contractParams{
 type contractParams struct {
 	*common.Params
 
-	deploy   bool
-	internal bool
-	noSign   bool
-	noWait   bool
-	salt     types.Uint256
-	shardId  types.ShardId
-	value    types.Value
+	deploy    bool
+	internal  bool
+	noSign    bool
+	noWait    bool
+	waitAll   bool
+	paymaster types.Address
+	salt      types.Uint256
+	shardId   types.ShardId
+	value     types.Value
 }
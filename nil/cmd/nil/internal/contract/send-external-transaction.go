@@ -4,8 +4,10 @@ import (
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	libcommon "github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/spf13/cobra"
 )
 
@@ -45,15 +47,21 @@ func GetSendExternalTransactionCommand(cfg *common.Config) *cobra.Command {
 		"Define whether the command should wait for the receipt",
 	)
 
+	cmd.Flags().Var(
+		&params.paymaster,
+		paymasterFlag,
+		"The address of the paymaster contract which pays the fees of the transaction",
+	)
+
 	return cmd
 }
 
 func runSendExternalTransaction(cmd *cobra.Command, args []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return fmt.Errorf("invalid address: %w", err)
+		return common.ValidationError(fmt.Errorf("invalid address: %w", err))
 	}
 
 	abi, err := common.ReadAbiFromFile(params.AbiPath)
@@ -66,20 +74,32 @@ func runSendExternalTransaction(cmd *cobra.Command, args []string, cfg *common.C
 		return err
 	}
 
-	txnHash, err := service.SendExternalTransaction(calldata, address, params.noSign)
+	var txnHash libcommon.Hash
+	if params.paymaster.IsEmpty() {
+		txnHash, err = service.SendExternalTransaction(calldata, address, params.noSign)
+	} else {
+		txnHash, err = service.SendSponsoredExternalTransaction(calldata, address, params.paymaster, params.noSign)
+	}
 	if err != nil {
 		return err
 	}
 
+	var receipt *jsonrpc.RPCReceipt
 	if !params.noWait {
-		if _, err := service.WaitForReceipt(txnHash); err != nil {
+		if receipt, err = common.WaitForReceipt(service, txnHash); err != nil {
 			return err
 		}
 	}
 
-	if !common.Quiet {
-		fmt.Print("Transaction hash: ")
+	result := &common.TransactionOutput{
+		TransactionHash: txnHash,
+		Receipt:         receipt,
 	}
-	fmt.Println(txnHash)
-	return nil
+	return common.PrintResult(result, func() error {
+		if !common.Quiet {
+			fmt.Print("Transaction hash: ")
+		}
+		fmt.Println(txnHash)
+		return nil
+	})
 }
@@ -24,7 +24,7 @@ func GetSeqnoCommand() *cobra.Command {
 func runSeqno(cmd *cobra.Command, args []string) error {
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
 	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), nil, nil)
@@ -32,9 +32,11 @@ func runSeqno(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	if !common.Quiet {
-		fmt.Print("Contract seqno: ")
-	}
-	fmt.Println(seqno)
-	return nil
+	return common.PrintResult(&common.SeqnoOutput{Address: address, Seqno: seqno}, func() error {
+		if !common.Quiet {
+			fmt.Print("Contract seqno: ")
+		}
+		fmt.Println(seqno)
+		return nil
+	})
 }
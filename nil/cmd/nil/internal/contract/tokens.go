@@ -25,23 +25,25 @@ func GetTokensCommand(cfg *common.Config) *cobra.Command {
 func runTokens(cmd *cobra.Command, args []string, cfg *common.Config) error {
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 	tokens, err := service.GetTokens(address)
 	if err != nil {
 		return err
 	}
-	if !common.Quiet {
-		fmt.Println("Contract tokens:")
-	}
-	for k, v := range tokens {
-		fmt.Printf("%s\t%s", k, v)
-		if name := types.GetTokenName(k); len(name) > 0 && !common.Quiet {
-			fmt.Printf("\t[%s]", name)
+	return common.PrintResult(common.NewTokensOutput(address, tokens), func() error {
+		if !common.Quiet {
+			fmt.Println("Contract tokens:")
 		}
-		fmt.Println()
-	}
-	return nil
+		for k, v := range tokens {
+			fmt.Printf("%s\t%s", k, v)
+			if name := types.GetTokenName(k); len(name) > 0 && !common.Quiet {
+				fmt.Printf("\t[%s]", name)
+			}
+			fmt.Println()
+		}
+		return nil
+	})
 }
@@ -23,12 +23,12 @@ func GetTopUpCommand(cfg *common.Config) *cobra.Command {
 func runTopUp(cmd *cobra.Command, args []string, cfg *common.Config) error {
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
 	var amount types.Value
 	if err := amount.Set(args[1]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
 	var currId string
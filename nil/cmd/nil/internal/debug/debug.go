@@ -334,10 +334,10 @@ func runCommand(cmd *cobra.Command, args []string) error {
 
 	var txnHash libcommon.Hash
 	if err := txnHash.Set(hashStr); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 	if txnHash == libcommon.EmptyHash {
-		return errors.New("empty txnHash")
+		return common.ValidationError(errors.New("empty txnHash"))
 	}
 
 	cometa := common.GetCometaRpcClient()
@@ -358,11 +358,60 @@ func runCommand(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	debugHandler.PrintTransactionChain()
+	return common.PrintResult(debugHandler.RootReceipt.toOutput(), func() error {
+		debugHandler.PrintTransactionChain()
 
-	fmt.Println()
+		fmt.Println()
 
-	debugHandler.ShowFailures()
+		debugHandler.ShowFailures()
+		return nil
+	})
+}
 
-	return nil
+// receiptOutput is the transaction chain printed in the structured output mode.
+type receiptOutput struct {
+	Index           int                    `json:"index"`
+	TransactionHash libcommon.Hash         `json:"transactionHash"`
+	Contract        string                 `json:"contract,omitempty"`
+	Address         types.Address          `json:"address"`
+	Flags           string                 `json:"flags"`
+	Success         bool                   `json:"success"`
+	Status          string                 `json:"status"`
+	ErrorMessage    string                 `json:"errorMessage,omitempty"`
+	FailedPc        uint                   `json:"failedPc,omitempty"`
+	FailedLocation  string                 `json:"failedLocation,omitempty"`
+	GasUsed         types.Gas              `json:"gasUsed"`
+	Block           types.BlockNumber      `json:"block"`
+	DebugLogs       []*jsonrpc.RPCDebugLog `json:"debugLogs,omitempty"`
+	OutReceipts     []*receiptOutput       `json:"outReceipts,omitempty"`
+}
+
+func (r *ReceiptInfo) toOutput() *receiptOutput {
+	res := &receiptOutput{
+		Index:           r.Index,
+		TransactionHash: r.Transaction.Hash,
+		Address:         r.Receipt.ContractAddress,
+		Flags:           r.Transaction.Flags.String(),
+		Success:         r.Receipt.Success,
+		Status:          r.Receipt.Status,
+		ErrorMessage:    r.Receipt.ErrorMessage,
+		GasUsed:         r.Receipt.GasUsed,
+		Block:           r.Transaction.BlockNumber,
+		DebugLogs:       r.Receipt.DebugLogs,
+	}
+	if r.Contract != nil {
+		res.Contract = r.Contract.ShortName()
+	}
+	if !r.Receipt.Success {
+		res.FailedPc = r.Receipt.FailedPc
+		if r.Contract != nil && r.Receipt.FailedPc != 0 {
+			if loc, err := r.Contract.GetLocation(r.Receipt.FailedPc); err == nil {
+				res.FailedLocation = loc.String()
+			}
+		}
+	}
+	for _, out := range r.OutReceipts {
+		res.OutReceipts = append(res.OutReceipts, out.toOutput())
+	}
+	return res
 }
@@ -0,0 +1,414 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	"github.com/NilFoundation/nil/nil/cmd/nil/internal/config"
+	libcommon "github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/NilFoundation/nil/nil/services/cometa"
+	"github.com/spf13/cobra"
+)
+
+var logger = logging.NewLogger("deploy")
+
+const (
+	StatusDeployed = "deployed"
+	StatusSkipped  = "skipped"
+	StatusPending  = "pending"
+	// StatusCompleted is set when the steps of an interrupted deployment are completed.
+	StatusCompleted = "completed"
+)
+
+type applyParams struct {
+	lockPath string
+	dryRun   bool
+}
+
+type contractOutput struct {
+	Name string `json:"name"`
+	// Status is "deployed", "skipped" if the contract is already deployed, "completed" if the steps
+	// of an interrupted deployment are done, or "pending" in the dry run
+	Status string `json:"status"`
+	*LockEntry
+}
+
+type applyOutput struct {
+	Lock      string            `json:"lock,omitempty"`
+	Contracts []*contractOutput `json:"contracts"`
+}
+
+func GetCommand(cfg *common.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploy the systems of contracts described in the manifests",
+	}
+
+	cmd.AddCommand(ApplyCommand(cfg))
+
+	return cmd
+}
+
+func ApplyCommand(cfg *common.Config) *cobra.Command {
+	params := &applyParams{}
+
+	cmd := &cobra.Command{
+		Use:   "apply [path to manifest]",
+		Short: "Deploy the contracts from the manifest via the smart account",
+		Long: "Deploy the contracts from the manifest via the smart account in the order of their references. " +
+			"The contracts whose code is already deployed at their addresses are skipped. " +
+			"The resulting addresses are written to the lock file together with the steps still to be done, " +
+			"so that an interrupted deployment is completed by the next run.",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runApply(cmd, args, cfg, params)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&params.lockPath,
+		"lock",
+		"",
+		"The path to the lock file (default: <manifest>.lock.yaml next to the manifest)",
+	)
+	cmd.Flags().BoolVar(
+		&params.dryRun,
+		"dry-run",
+		false,
+		"Print the addresses and the contracts to deploy without sending the transactions",
+	)
+
+	return cmd
+}
+
+func runApply(cmd *cobra.Command, args []string, cfg *common.Config, params *applyParams) error {
+	if cfg.Signer == nil {
+		return common.ValidationError(config.MissingKeyError(config.PrivateKeyField, logger))
+	}
+	if cfg.Address == types.EmptyAddress {
+		return common.ValidationError(config.MissingKeyError(config.AddressField, logger))
+	}
+
+	manifest, err := ReadManifest(args[0])
+	if err != nil {
+		return common.ValidationError(err)
+	}
+	order, err := manifest.DeployOrder()
+	if err != nil {
+		return common.ValidationError(err)
+	}
+
+	lockPath := params.lockPath
+	if lockPath == "" {
+		lockPath = DefaultLockPath(args[0])
+	}
+	lock, err := ReadLock(lockPath)
+	if err != nil {
+		return common.ValidationError(err)
+	}
+
+	d := &deployer{
+		service:  cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil),
+		manifest: manifest,
+		lock:     lock,
+		lockPath: lockPath,
+		account:  cfg.Address,
+		dryRun:   params.dryRun,
+	}
+	if slices.ContainsFunc(order, func(c *Contract) bool { return c.CompileInput != "" }) {
+		d.cometa = common.GetCometaRpcClient()
+	}
+
+	result := &applyOutput{Contracts: make([]*contractOutput, 0, len(order))}
+	if !params.dryRun {
+		result.Lock = lockPath
+	}
+	addresses := make(map[string]types.Address, len(order))
+	for _, contract := range order {
+		out, err := d.apply(contract, addresses)
+		if err != nil {
+			return fmt.Errorf("contract %q: %w", contract.Name, err)
+		}
+		addresses[contract.Name] = out.Address
+		result.Contracts = append(result.Contracts, out)
+	}
+
+	if !params.dryRun {
+		// The lock also records the contracts deployed earlier without it
+		for _, out := range result.Contracts {
+			lock.Contracts[out.Name] = out.LockEntry
+		}
+		if err := lock.Write(lockPath); err != nil {
+			return err
+		}
+	}
+
+	return common.PrintResult(result, func() error {
+		for _, out := range result.Contracts {
+			if common.Quiet {
+				fmt.Printf("%s %s\n", out.Name, out.Address.Hex())
+			} else {
+				fmt.Printf("%-20s %-9s %s\n", out.Name, out.Status, out.Address.Hex())
+			}
+		}
+		if !common.Quiet && result.Lock != "" {
+			fmt.Printf("Lock file: %s\n", result.Lock)
+		}
+		return nil
+	})
+}
+
+type deployer struct {
+	service  *cliservice.Service
+	cometa   *cometa.Client
+	manifest *Manifest
+	lock     *Lock
+	lockPath string
+	account  types.Address
+	dryRun   bool
+}
+
+// record writes the entry to the lock file to keep the progress if a later step fails.
+func (d *deployer) record(name string, entry *LockEntry) error {
+	d.lock.Contracts[name] = entry
+	return d.lock.Write(d.lockPath)
+}
+
+func (d *deployer) apply(contract *Contract, addresses map[string]types.Address) (*contractOutput, error) {
+	args, err := contract.ResolveArgs(addresses)
+	if err != nil {
+		return nil, common.ValidationError(err)
+	}
+
+	bytecode, contractData, err := d.bytecode(contract, args)
+	if err != nil {
+		return nil, err
+	}
+	payload := types.BuildDeployPayload(bytecode, libcommon.Hash(contract.Salt.Bytes32()))
+	address := types.CreateAddress(contract.Shard, payload)
+
+	out := &contractOutput{
+		Name: contract.Name,
+		LockEntry: &LockEntry{
+			Address: address,
+			Shard:   contract.Shard,
+		},
+	}
+
+	// The address is derived from the init code and the salt, so the code at the address
+	// is the one of the manifest unless the contract has destroyed itself.
+	code, err := d.getCode(address)
+	if err != nil {
+		return nil, err
+	}
+
+	prev, ok := d.lock.Contracts[contract.Name]
+	if !ok || prev.Address != address {
+		prev = nil
+	}
+	// The deploy step is left pending if the previous run was interrupted before the deployment was confirmed
+	deployPending := prev != nil && len(prev.Pending) > 0 && prev.Pending[0] == StepDeploy
+	if len(code) == 0 && deployPending && !prev.TransactionHash.Empty() && !d.dryRun {
+		if code, err = d.waitForDeploy(address, prev.TransactionHash); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(code) > 0 {
+		out.Status = StatusSkipped
+		out.CodeHash = code.Hash()
+		if prev == nil {
+			logger.Info().Msgf("Contract %s is already deployed at %s", contract.Name, address.Hex())
+			return out, nil
+		}
+		out.TransactionHash = prev.TransactionHash
+		if deployPending {
+			// The code hash is unknown until the deployment is confirmed
+			out.Pending = slices.Clone(prev.Pending[1:])
+		} else {
+			if prev.CodeHash != out.CodeHash {
+				return nil, fmt.Errorf("the code at %s does not match the code hash %s of the lock file",
+					address.Hex(), prev.CodeHash)
+			}
+			if len(prev.Pending) == 0 {
+				logger.Info().Msgf("Contract %s is already deployed at %s", contract.Name, address.Hex())
+				return out, nil
+			}
+			out.Pending = slices.Clone(prev.Pending)
+		}
+
+		if d.dryRun {
+			out.Status = StatusPending
+			return out, nil
+		}
+		logger.Info().Msgf("Completing the deployment of contract %s: %s", contract.Name, strings.Join(prev.Pending, ", "))
+		if err := d.record(contract.Name, out.LockEntry); err != nil {
+			return nil, err
+		}
+		if err := d.runPending(contract, out.LockEntry, contractData); err != nil {
+			return nil, err
+		}
+		out.Status = StatusCompleted
+		return out, nil
+	}
+
+	if d.dryRun {
+		out.Status = StatusPending
+		return out, nil
+	}
+
+	// The entry is written before the transaction is sent and once its hash is known,
+	// so that the next run waits for this transaction instead of sending another one if this run is interrupted
+	out.Pending = append(out.Pending, StepDeploy)
+	if len(contract.Tokens) > 0 {
+		out.Pending = append(out.Pending, StepFund)
+	}
+	if contract.Register {
+		out.Pending = append(out.Pending, StepRegister)
+	}
+	if err := d.record(contract.Name, out.LockEntry); err != nil {
+		return nil, err
+	}
+
+	txnHash, _, err := d.service.DeployContractViaSmartAccount(contract.Shard, d.account, payload, contract.Value)
+	if err != nil {
+		return nil, err
+	}
+	out.TransactionHash = txnHash
+	if err := d.record(contract.Name, out.LockEntry); err != nil {
+		return nil, err
+	}
+	if _, err := common.WaitForReceipt(d.service, txnHash); err != nil {
+		return nil, err
+	}
+	out.Status = StatusDeployed
+
+	if code, err = d.getCode(address); err != nil {
+		return nil, err
+	}
+	out.CodeHash = code.Hash()
+	out.Pending = out.Pending[1:]
+	if err := d.record(contract.Name, out.LockEntry); err != nil {
+		return nil, err
+	}
+	if err := d.runPending(contract, out.LockEntry, contractData); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// waitForDeploy waits for the deploy transaction sent by the interrupted run and returns the code at the address.
+// The code is empty if the transaction has failed or is not found, then the contract is deployed again.
+func (d *deployer) waitForDeploy(address types.Address, txnHash libcommon.Hash) (types.Code, error) {
+	logger.Info().Msgf("Waiting for the deploy transaction %s of the interrupted run", txnHash)
+	if _, err := common.WaitForReceipt(d.service, txnHash); err != nil {
+		logger.Warn().Err(err).Msgf("The transaction %s has not deployed the contract", txnHash)
+	}
+	return d.getCode(address)
+}
+
+// runPending runs the pending steps of the deployment, the entry is written after each of them.
+func (d *deployer) runPending(contract *Contract, entry *LockEntry, contractData *cometa.ContractData) error {
+	for len(entry.Pending) > 0 {
+		switch step := entry.Pending[0]; step {
+		case StepFund:
+			if err := d.sendTokens(contract, entry.Address); err != nil {
+				return err
+			}
+		case StepRegister:
+			if contractData == nil {
+				return errors.New("the contract can't be registered without the compilation input")
+			}
+			if err := d.cometa.RegisterContractData(contractData, entry.Address); err != nil {
+				return fmt.Errorf("failed to register the contract: %w", err)
+			}
+		default:
+			return fmt.Errorf("unknown pending step %q in the lock file", step)
+		}
+		entry.Pending = entry.Pending[1:]
+		if err := d.record(contract.Name, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bytecode returns the init code of the contract with the packed constructor arguments.
+// The compilation result is returned for the contracts compiled by Cometa.
+func (d *deployer) bytecode(contract *Contract, args []string) (types.Code, *cometa.ContractData, error) {
+	if contract.CompileInput == "" {
+		bytecode, err := common.ReadBytecode(d.manifest.path(contract.Code), d.manifest.path(contract.Abi), args)
+		return bytecode, nil, err
+	}
+
+	contractData, err := d.cometa.CompileContract(d.manifest.path(contract.CompileInput))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compile the contract: %w", err)
+	}
+	bytecode := slices.Clone(contractData.InitCode)
+	if len(args) > 0 {
+		contractAbi, err := abi.JSON(strings.NewReader(contractData.Abi))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse the ABI of the compiled contract: %w", err)
+		}
+		calldata, err := common.ArgsToCalldata(contractAbi, "", args)
+		if err != nil {
+			return nil, nil, common.ValidationError(fmt.Errorf("failed to pack the constructor arguments: %w", err))
+		}
+		bytecode = append(bytecode, calldata...)
+	}
+	return bytecode, contractData, nil
+}
+
+func (d *deployer) getCode(address types.Address) (types.Code, error) {
+	code, err := d.service.GetCode(address)
+	if err != nil {
+		return nil, err
+	}
+	return hexutil.FromHex(code), nil
+}
+
+// sendTokens funds the deployed contract with the tokens of the manifest.
+func (d *deployer) sendTokens(contract *Contract, address types.Address) error {
+	if len(contract.Tokens) == 0 {
+		return nil
+	}
+
+	tokens := make([]types.TokenBalance, 0, len(contract.Tokens))
+	for _, name := range slices.Sorted(maps.Keys(contract.Tokens)) {
+		id, err := tokenId(name)
+		if err != nil {
+			return common.ValidationError(err)
+		}
+		tokens = append(tokens, types.TokenBalance{Token: id, Balance: contract.Tokens[name]})
+	}
+
+	txnHash, err := d.service.RunContract(d.account, nil, types.FeePack{}, types.Value{}, tokens, address)
+	if err != nil {
+		return fmt.Errorf("failed to send the tokens: %w", err)
+	}
+	_, err = common.WaitForReceipt(d.service, txnHash)
+	return err
+}
+
+// tokenId returns the id of the token given by the name (e.g. "NIL") or by the address of the minter.
+func tokenId(name string) (types.TokenId, error) {
+	if address, ok := types.GetTokens()[name]; ok {
+		return types.TokenId(address), nil
+	}
+	var address types.Address
+	if err := address.Set(name); err != nil {
+		return types.TokenId{}, errors.New("unknown token " + name)
+	}
+	return types.TokenId(address), nil
+}
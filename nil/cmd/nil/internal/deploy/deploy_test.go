@@ -0,0 +1,105 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyResumesInterruptedDeploy(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Registry.bin"), []byte("0x6001"), 0o600))
+	manifest, err := ParseManifest([]byte("contracts:\n  - name: Registry\n    shard: 1\n    code: Registry.bin\n"), dir)
+	require.NoError(t, err)
+	contract := manifest.Contracts[0]
+	lockPath := filepath.Join(dir, "system.lock.yaml")
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	newDeployer := func(c client.Client) *deployer {
+		t.Helper()
+
+		lock, err := ReadLock(lockPath)
+		require.NoError(t, err)
+		return &deployer{
+			service:  cliservice.NewServiceWithSigner(t.Context(), c, client.NewPrivateKeySigner(key), nil),
+			manifest: manifest,
+			lock:     lock,
+			lockPath: lockPath,
+			account:  types.HexToAddress("0x0001111111111111111111111111111111111111"),
+		}
+	}
+
+	txnHash := common.HexToHash("0x1234")
+	deployedCode := types.Code{0x60, 0x02}
+
+	// The run is interrupted after the deploy transaction is sent
+	var sent atomic.Int32
+	_, err = newDeployer(&client.ClientMock{
+		GetCodeFunc: func(context.Context, types.Address, any) (types.Code, error) {
+			return nil, nil
+		},
+		GetTransactionCountFunc: func(context.Context, types.Address, any) (types.Seqno, error) {
+			return 0, nil
+		},
+		SendTransactionFunc: func(context.Context, *types.ExternalTransaction) (common.Hash, error) {
+			sent.Add(1)
+			return txnHash, nil
+		},
+		GetInTransactionReceiptFunc: func(context.Context, common.Hash) (*jsonrpc.RPCReceipt, error) {
+			return nil, errors.New("connection lost")
+		},
+	}).apply(contract, nil)
+	require.ErrorContains(t, err, "connection lost")
+	require.Equal(t, int32(1), sent.Load())
+
+	lock, err := ReadLock(lockPath)
+	require.NoError(t, err)
+	entry := lock.Contracts["Registry"]
+	require.NotNil(t, entry)
+	assert.Equal(t, txnHash, entry.TransactionHash)
+	assert.Equal(t, []string{StepDeploy}, entry.Pending)
+
+	// The next run waits for the transaction sent before instead of sending another one
+	var confirmed atomic.Bool
+	out, err := newDeployer(&client.ClientMock{
+		GetCodeFunc: func(context.Context, types.Address, any) (types.Code, error) {
+			if confirmed.Load() {
+				return deployedCode, nil
+			}
+			return nil, nil
+		},
+		GetInTransactionReceiptFunc: func(_ context.Context, hash common.Hash) (*jsonrpc.RPCReceipt, error) {
+			require.Equal(t, txnHash, hash)
+			confirmed.Store(true)
+			return &jsonrpc.RPCReceipt{Success: true, TxnHash: hash}, nil
+		},
+	}).apply(contract, nil)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, out.Status)
+	assert.Equal(t, entry.Address, out.Address)
+	assert.Equal(t, txnHash, out.TransactionHash)
+	assert.Equal(t, deployedCode.Hash(), out.CodeHash)
+	assert.Empty(t, out.Pending)
+
+	lock, err = ReadLock(lockPath)
+	require.NoError(t, err)
+	entry = lock.Contracts["Registry"]
+	assert.Equal(t, deployedCode.Hash(), entry.CodeHash)
+	assert.Equal(t, txnHash, entry.TransactionHash)
+	assert.Empty(t, entry.Pending)
+}
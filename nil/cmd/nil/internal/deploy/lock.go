@@ -0,0 +1,79 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Lock holds the addresses of the contracts deployed from the manifest.
+type Lock struct {
+	Contracts map[string]*LockEntry `yaml:"contracts" json:"contracts"`
+}
+
+type LockEntry struct {
+	Address  types.Address `yaml:"address" json:"address"`
+	Shard    types.ShardId `yaml:"shard" json:"shard"`
+	CodeHash common.Hash   `yaml:"codeHash" json:"codeHash"`
+	// TransactionHash is the hash of the deploy transaction, it is empty if the contract was deployed earlier
+	// or the deployment was interrupted before the transaction was sent
+	TransactionHash common.Hash `yaml:"transactionHash,omitempty" json:"transactionHash,omitempty"`
+	// Pending are the steps of the deployment which are still to be done, see Step*.
+	// They are run by the next apply if the deployment was interrupted.
+	Pending []string `yaml:"pending,omitempty" json:"pending,omitempty"`
+}
+
+const (
+	// StepDeploy is sending the deploy transaction and waiting for its receipt.
+	// If the transaction hash is recorded, the next apply waits for that transaction before sending another one.
+	StepDeploy = "deploy"
+	// StepFund is sending the tokens of the manifest to the contract.
+	StepFund = "fund"
+	// StepRegister is registering the contract in Cometa.
+	StepRegister = "register"
+)
+
+// DefaultLockPath returns the path of the lock file next to the manifest,
+// e.g. "system.lock.yaml" for "system.yaml".
+func DefaultLockPath(manifestPath string) string {
+	ext := filepath.Ext(manifestPath)
+	return strings.TrimSuffix(manifestPath, ext) + ".lock.yaml"
+}
+
+// ReadLock reads the lock file, the empty lock is returned if the file does not exist.
+func ReadLock(path string) (*Lock, error) {
+	lock := &Lock{Contracts: make(map[string]*LockEntry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the lock file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse the lock file: %w", err)
+	}
+	if lock.Contracts == nil {
+		lock.Contracts = make(map[string]*LockEntry)
+	}
+	return lock, nil
+}
+
+// Write writes the lock file, the contracts are sorted by their names.
+func (l *Lock) Write(path string) error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the lock file: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil { //nolint:gosec
+		return fmt.Errorf("failed to write the lock file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest describes the system of contracts deployed by "nil deploy apply".
+// The paths in the manifest are relative to the manifest file.
+type Manifest struct {
+	Contracts []*Contract `yaml:"contracts"`
+
+	dir string
+}
+
+// Contract is the contract deployed via the smart account from the config.
+type Contract struct {
+	// Name identifies the contract in the references of the other contracts and in the lock file
+	Name  string        `yaml:"name"`
+	Shard types.ShardId `yaml:"shard"`
+	Salt  types.Uint256 `yaml:"salt"`
+
+	// Code is the file with the hex encoded bytecode, Abi is needed to pack the constructor arguments.
+	Code string `yaml:"code"`
+	Abi  string `yaml:"abi"`
+	// CompileInput is the Cometa compilation input, the contract is compiled by Cometa then.
+	CompileInput string `yaml:"compileInput"`
+	// Register registers the contract in Cometa after the deployment, requires the compilation input.
+	Register bool `yaml:"register"`
+
+	// Args are the constructor arguments, "${Name}" is replaced with the address of the contract Name.
+	// The elements of the lists are joined with commas, like the arguments of the CLI.
+	Args []yaml.Node `yaml:"args"`
+
+	// Value and Tokens are sent to the contract once, when it is deployed
+	Value  types.Value            `yaml:"value"`
+	Tokens map[string]types.Value `yaml:"tokens"`
+}
+
+var (
+	namePattern      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+	referencePattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+)
+
+// ReadManifest reads and validates the manifest.
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the manifest: %w", err)
+	}
+	return ParseManifest(data, filepath.Dir(path))
+}
+
+// ParseManifest parses and validates the manifest, the paths are resolved against dir.
+func ParseManifest(data []byte, dir string) (*Manifest, error) {
+	var manifest Manifest
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest: %w", err)
+	}
+	manifest.dir = dir
+
+	if err := manifest.validate(); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+func (m *Manifest) validate() error {
+	if len(m.Contracts) == 0 {
+		return errors.New("the manifest has no contracts")
+	}
+
+	names := make(map[string]struct{}, len(m.Contracts))
+	for _, c := range m.Contracts {
+		if !namePattern.MatchString(c.Name) {
+			return fmt.Errorf("invalid contract name %q", c.Name)
+		}
+		if _, ok := names[c.Name]; ok {
+			return fmt.Errorf("duplicate contract %q", c.Name)
+		}
+		names[c.Name] = struct{}{}
+	}
+
+	for _, c := range m.Contracts {
+		if err := c.validate(names); err != nil {
+			return fmt.Errorf("contract %q: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c *Contract) validate(names map[string]struct{}) error {
+	if c.Shard == types.MainShardId {
+		return errors.New("deploying to the main shard is not allowed, set the shard")
+	}
+	switch {
+	case c.Code == "" && c.CompileInput == "":
+		return errors.New("either code or compileInput must be set")
+	case c.Code != "" && c.CompileInput != "":
+		return errors.New("code and compileInput are mutually exclusive")
+	case c.CompileInput != "" && c.Abi != "":
+		return errors.New("the ABI is taken from the compilation result, abi must not be set with compileInput")
+	case c.Register && c.CompileInput == "":
+		return errors.New("register requires compileInput")
+	case c.Code != "" && c.Abi == "" && len(c.Args) > 0:
+		return errors.New("abi is required to pack the constructor arguments")
+	}
+
+	deps, err := c.Dependencies()
+	if err != nil {
+		return err
+	}
+	for _, dep := range deps {
+		if dep == c.Name {
+			return errors.New("the contract references itself")
+		}
+		if _, ok := names[dep]; !ok {
+			return fmt.Errorf("reference to unknown contract %q", dep)
+		}
+	}
+	return nil
+}
+
+// Dependencies returns the names of the contracts referenced in the constructor arguments.
+func (c *Contract) Dependencies() ([]string, error) {
+	var deps []string
+	for i := range c.Args {
+		values, err := argValues(&c.Args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		for _, value := range values {
+			for _, match := range referencePattern.FindAllStringSubmatch(value, -1) {
+				if !slices.Contains(deps, match[1]) {
+					deps = append(deps, match[1])
+				}
+			}
+		}
+	}
+	return deps, nil
+}
+
+// ResolveArgs returns the constructor arguments in the CLI format with the references replaced
+// by the addresses of the contracts.
+func (c *Contract) ResolveArgs(addresses map[string]types.Address) ([]string, error) {
+	args := make([]string, 0, len(c.Args))
+	for i := range c.Args {
+		values, err := argValues(&c.Args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		for j, value := range values {
+			values[j] = referencePattern.ReplaceAllStringFunc(value, func(ref string) string {
+				name := referencePattern.FindStringSubmatch(ref)[1]
+				address, ok := addresses[name]
+				if !ok {
+					err = fmt.Errorf("the address of %q is unknown", name)
+					return ref
+				}
+				return address.Hex()
+			})
+			if err != nil {
+				return nil, fmt.Errorf("argument %d: %w", i, err)
+			}
+		}
+		args = append(args, strings.Join(values, ","))
+	}
+	return args, nil
+}
+
+// argValues returns the raw values of the scalar argument or of the elements of the list.
+// The raw values keep the big numbers intact.
+func argValues(node *yaml.Node) ([]string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []string{node.Value}, nil
+	case yaml.SequenceNode:
+		values := make([]string, 0, len(node.Content))
+		for _, elem := range node.Content {
+			if elem.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: nested lists and maps are not supported", elem.Line)
+			}
+			values = append(values, elem.Value)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("line %d: an argument must be a scalar or a list", node.Line)
+}
+
+// DeployOrder returns the contracts sorted so that each contract follows the contracts it references.
+// The order of the manifest is kept where possible.
+func (m *Manifest) DeployOrder() ([]*Contract, error) {
+	deps := make(map[string][]string, len(m.Contracts))
+	for _, c := range m.Contracts {
+		d, err := c.Dependencies()
+		if err != nil {
+			return nil, fmt.Errorf("contract %q: %w", c.Name, err)
+		}
+		deps[c.Name] = d
+	}
+
+	order := make([]*Contract, 0, len(m.Contracts))
+	done := make(map[string]bool, len(m.Contracts))
+	for len(order) < len(m.Contracts) {
+		progress := false
+		for _, c := range m.Contracts {
+			if done[c.Name] {
+				continue
+			}
+			ready := true
+			for _, dep := range deps[c.Name] {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				order = append(order, c)
+				done[c.Name] = true
+				progress = true
+				break
+			}
+		}
+
+		if !progress {
+			var cycle []string
+			for _, c := range m.Contracts {
+				if !done[c.Name] {
+					cycle = append(cycle, c.Name)
+				}
+			}
+			return nil, fmt.Errorf("circular references between the contracts: %s", strings.Join(cycle, ", "))
+		}
+	}
+	return order, nil
+}
+
+// path resolves the path from the manifest against the manifest directory.
+func (m *Manifest) path(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(m.dir, path)
+}
@@ -0,0 +1,199 @@
+package deploy
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `
+contracts:
+  - name: Token
+    shard: 2
+    salt: 0x2a
+    compileInput: token.json
+    register: true
+    args: [Token, TKN, "${Registry}"]
+    value: 1000000
+    tokens:
+      NIL: 100
+  - name: Registry
+    shard: 1
+    code: build/Registry.bin
+    abi: /abs/Registry.abi
+  - name: Router
+    shard: 1
+    salt: 7
+    code: build/Router.bin
+    abi: build/Router.abi
+    args:
+      - ["${Token}", "${Registry}"]
+      - 115792089237316195423570985008687907853269984665640564039457584007913129639935
+`
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+
+	manifest, err := ParseManifest([]byte(testManifest), "/manifests")
+	require.NoError(t, err)
+	require.Len(t, manifest.Contracts, 3)
+
+	token := manifest.Contracts[0]
+	assert.Equal(t, types.ShardId(2), token.Shard)
+	assert.Equal(t, uint64(42), token.Salt.Uint64())
+	assert.Equal(t, "1000000", token.Value.String())
+	assert.Equal(t, "100", token.Tokens["NIL"].String())
+	assert.True(t, token.Register)
+
+	assert.Equal(t, "/manifests/build/Registry.bin", manifest.path(manifest.Contracts[1].Code))
+	assert.Equal(t, "/abs/Registry.abi", manifest.path(manifest.Contracts[1].Abi))
+	assert.True(t, manifest.Contracts[1].Salt.IsZero())
+
+	deps, err := manifest.Contracts[2].Dependencies()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Token", "Registry"}, deps)
+}
+
+func TestDeployOrder(t *testing.T) {
+	t.Parallel()
+
+	manifest, err := ParseManifest([]byte(testManifest), "")
+	require.NoError(t, err)
+
+	order, err := manifest.DeployOrder()
+	require.NoError(t, err)
+	names := make([]string, 0, len(order))
+	for _, c := range order {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"Registry", "Token", "Router"}, names)
+}
+
+func TestResolveArgs(t *testing.T) {
+	t.Parallel()
+
+	manifest, err := ParseManifest([]byte(testManifest), "")
+	require.NoError(t, err)
+	router := manifest.Contracts[2]
+
+	_, err = router.ResolveArgs(map[string]types.Address{"Registry": types.FaucetAddress})
+	require.ErrorContains(t, err, `the address of "Token" is unknown`)
+
+	token := types.HexToAddress("0x0002000000000000000000000000000000000001")
+	args, err := router.ResolveArgs(map[string]types.Address{
+		"Registry": types.FaucetAddress,
+		"Token":    token,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		token.Hex() + "," + types.FaucetAddress.Hex(),
+		"115792089237316195423570985008687907853269984665640564039457584007913129639935",
+	}, args)
+}
+
+func TestInvalidManifest(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		manifest string
+		err      string
+	}{
+		"Empty": {
+			manifest: `contracts: []`,
+			err:      "the manifest has no contracts",
+		},
+		"UnknownField": {
+			manifest: `contracts: [{name: A, shard: 1, code: a.bin, sald: 1}]`,
+			err:      "field sald not found",
+		},
+		"Duplicate": {
+			manifest: `contracts: [{name: A, shard: 1, code: a.bin}, {name: A, shard: 2, code: a.bin}]`,
+			err:      `duplicate contract "A"`,
+		},
+		"MainShard": {
+			manifest: `contracts: [{name: A, code: a.bin}]`,
+			err:      "deploying to the main shard is not allowed",
+		},
+		"NoCode": {
+			manifest: `contracts: [{name: A, shard: 1}]`,
+			err:      "either code or compileInput must be set",
+		},
+		"RegisterWithoutCompileInput": {
+			manifest: `contracts: [{name: A, shard: 1, code: a.bin, register: true}]`,
+			err:      "register requires compileInput",
+		},
+		"ArgsWithoutAbi": {
+			manifest: `contracts: [{name: A, shard: 1, code: a.bin, args: [1]}]`,
+			err:      "abi is required",
+		},
+		"UnknownReference": {
+			manifest: `contracts: [{name: A, shard: 1, code: a.bin, abi: a.abi, args: ["${B}"]}]`,
+			err:      `reference to unknown contract "B"`,
+		},
+		"SelfReference": {
+			manifest: `contracts: [{name: A, shard: 1, code: a.bin, abi: a.abi, args: ["${A}"]}]`,
+			err:      "the contract references itself",
+		},
+		"NestedArgs": {
+			manifest: `contracts: [{name: A, shard: 1, code: a.bin, abi: a.abi, args: [[[1]]]}]`,
+			err:      "nested lists and maps are not supported",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseManifest([]byte(test.manifest), "")
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func TestCircularReferences(t *testing.T) {
+	t.Parallel()
+
+	manifest, err := ParseManifest([]byte(`
+contracts:
+  - {name: A, shard: 1, code: a.bin, abi: a.abi, args: ["${B}"]}
+  - {name: B, shard: 1, code: b.bin, abi: b.abi, args: ["${C}"]}
+  - {name: C, shard: 1, code: c.bin, abi: c.abi, args: ["${A}"]}
+  - {name: D, shard: 1, code: d.bin}
+`), "")
+	require.NoError(t, err)
+
+	_, err = manifest.DeployOrder()
+	require.ErrorContains(t, err, "circular references between the contracts: A, B, C")
+}
+
+func TestLock(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "system.yaml")
+	lockPath := DefaultLockPath(path)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "system.lock.yaml"), lockPath)
+
+	lock, err := ReadLock(lockPath)
+	require.NoError(t, err)
+	assert.Empty(t, lock.Contracts)
+
+	lock.Contracts["Registry"] = &LockEntry{
+		Address:         types.FaucetAddress,
+		Shard:           types.FaucetAddress.ShardId(),
+		CodeHash:        common.HexToHash("0x01"),
+		TransactionHash: common.HexToHash("0x02"),
+	}
+	lock.Contracts["Token"] = &LockEntry{
+		Address:  types.HexToAddress("0x0002000000000000000000000000000000000001"),
+		Shard:    2,
+		CodeHash: common.HexToHash("0x03"),
+		Pending:  []string{StepFund, StepRegister},
+	}
+	require.NoError(t, lock.Write(lockPath))
+
+	read, err := ReadLock(lockPath)
+	require.NoError(t, err)
+	assert.Equal(t, lock, read)
+}
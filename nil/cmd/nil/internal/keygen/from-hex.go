@@ -1,26 +1,29 @@
 package keygen
 
 import (
-	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
 	"github.com/spf13/cobra"
 )
 
-func FromHexCommand(keygen *cliservice.Service) *cobra.Command {
+func FromHexCommand(p *params) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "from-hex",
 		Short: "Generate a key from a provided hex private key",
-		Args:  cobra.ExactArgs(1),
+		Long: "Generate a key from a provided hex private key. " +
+			"The key is read from stdin or prompted in the terminal.",
+		Args: cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runFromHex(cmd, args, keygen)
+			return runFromHex(cmd, args, p)
 		},
 		SilenceUsage: true,
 	}
 	return cmd
 }
 
-func runFromHex(_ *cobra.Command, args []string, keygen *cliservice.Service) error {
-	if err := keygen.GenerateKeyFromHex(args[0]); err != nil {
+func runFromHex(_ *cobra.Command, _ []string, p *params) error {
+	key, err := common.ReadPrivateKey("Enter the private key: ")
+	if err != nil {
 		return err
 	}
-	return nil
+	return storeKey(p, key)
 }
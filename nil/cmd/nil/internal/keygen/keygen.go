@@ -1,41 +1,111 @@
 package keygen
 
 import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
 	"github.com/NilFoundation/nil/nil/client/rpc"
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	nilcrypto "github.com/NilFoundation/nil/nil/internal/crypto"
+	"github.com/NilFoundation/nil/nil/internal/keystore"
 	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 )
 
 var logger = logging.NewLogger("keygenCommand")
 
-func GetCommand() *cobra.Command {
-	var keygen *cliservice.Service
+const (
+	nameFlag            = "name"
+	printPrivateKeyFlag = "print-private-key"
+	forceFlag           = "force"
+)
+
+type params struct {
+	cfgFile         *string
+	name            string
+	printPrivateKey bool
+	force           bool
+}
+
+func GetCommand(cfgFile *string) *cobra.Command {
+	p := &params{cfgFile: cfgFile}
 
 	keygenCmd := &cobra.Command{
 		Use:   "keygen",
 		Short: "Generate a new key or generate a key from the provided hex private key",
-		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
-			privateKey := keygen.GetPrivateKey()
-			logger.Info().Msgf("Private key: %v", privateKey)
-
-			if err := config.PatchConfig(map[string]interface{}{
-				config.PrivateKeyField: privateKey,
-			}, false); err != nil {
-				logger.Error().Err(err).Msg("failed to update the private key in the config file")
-			}
-			return nil
-		},
+		Long: "Generate a new key or generate a key from the provided hex private key. The key is encrypted " +
+			"with a passphrase, stored in the keystore and used by default. The passphrase is prompted " +
+			"or taken from the " + common.PassphraseEnv + " environment variable.",
 		SilenceUsage: true,
 	}
+	keygenCmd.PersistentFlags().StringVar(&p.name, nameFlag, "default", "The keystore account to store the key in")
+	keygenCmd.PersistentFlags().BoolVar(&p.printPrivateKey, printPrivateKeyFlag, false, "Print the private key")
+	keygenCmd.PersistentFlags().BoolVar(&p.force, forceFlag, false, "Replace the existing account in the keystore")
 
-	keygen = cliservice.NewService(keygenCmd.Context(), &rpc.Client{}, nil, nil)
+	keygen := cliservice.NewService(keygenCmd.Context(), &rpc.Client{}, nil, nil)
 
 	keygenCmd.AddCommand(
-		NewCommand(keygen),
-		FromHexCommand(keygen),
+		NewCommand(p),
+		FromHexCommand(p),
 		NewP2pCommand(keygen),
 	)
 	return keygenCmd
 }
+
+// storeKey puts the key into the keystore, selects the account in the config and prints the result.
+func storeKey(p *params, key *ecdsa.PrivateKey) error {
+	if err := common.ImportKey(*p.cfgFile, p.name, key, p.force); err != nil {
+		if errors.Is(err, keystore.ErrAccountExists) {
+			return fmt.Errorf("%w: use --%s to choose another account or --%s to replace it", err, nameFlag, forceFlag)
+		}
+		return err
+	}
+	if err := config.PatchConfig(map[string]any{
+		config.AccountField: p.name,
+	}, false); err != nil {
+		logger.Error().Err(err).Msg("failed to update the account in the config file")
+		return err
+	}
+
+	result := &keyOutput{
+		Account:   p.name,
+		PublicKey: hexutil.Encode(crypto.CompressPubkey(&key.PublicKey)),
+	}
+	if p.printPrivateKey {
+		result.PrivateKey = nilcrypto.PrivateKeyToEthereumFormat(key)
+	}
+	return common.PrintResult(result, func() error {
+		if common.Quiet {
+			// Only one value is printed in the quiet mode
+			if p.printPrivateKey {
+				fmt.Println(result.PrivateKey)
+			} else {
+				fmt.Println(result.PublicKey)
+			}
+			return nil
+		}
+		fmt.Printf("Account: %s\n", result.Account)
+		fmt.Printf("Public key: %s\n", result.PublicKey)
+		if p.printPrivateKey {
+			fmt.Printf("Private key: %s\n", result.PrivateKey)
+		}
+		return nil
+	})
+}
+
+type keyOutput struct {
+	Account    string `json:"account"`
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey,omitempty"`
+}
+
+type p2pKeyOutput struct {
+	PrivateKey string `json:"privateKey"`
+	PublicKey  string `json:"publicKey"`
+	Identity   string `json:"identity"`
+}
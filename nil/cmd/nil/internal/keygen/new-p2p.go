@@ -43,19 +43,26 @@ func runNewP2p(_ *cobra.Command, _ []string, keygen *cliservice.Service) error {
 		return nil
 	}
 
-	if !common.Quiet {
-		fmt.Printf("Private key: ")
+	result := &p2pKeyOutput{
+		PrivateKey: hexutil.Encode(privateKey),
+		PublicKey:  hexutil.Encode(pubKey),
+		Identity:   identity,
 	}
-	fmt.Println(hexutil.Encode(privateKey))
+	return common.PrintResult(result, func() error {
+		if !common.Quiet {
+			fmt.Printf("Private key: ")
+		}
+		fmt.Println(result.PrivateKey)
 
-	if !common.Quiet {
-		fmt.Printf("Public key: ")
-	}
-	fmt.Println(hexutil.Encode(pubKey))
+		if !common.Quiet {
+			fmt.Printf("Public key: ")
+		}
+		fmt.Println(result.PublicKey)
 
-	if !common.Quiet {
-		fmt.Printf("Identity: ")
-	}
-	fmt.Println(identity)
-	return nil
+		if !common.Quiet {
+			fmt.Printf("Identity: ")
+		}
+		fmt.Println(result.Identity)
+		return nil
+	})
 }
@@ -1,33 +1,27 @@
 package keygen
 
 import (
-	"fmt"
-
-	"github.com/NilFoundation/nil/nil/cmd/nil/common"
-	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 )
 
-func NewCommand(keygen *cliservice.Service) *cobra.Command {
+func NewCommand(p *params) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "new",
 		Short: "Generate a new key",
 		Args:  cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runNew(cmd, args, keygen)
+			return runNew(cmd, args, p)
 		},
 		SilenceUsage: true,
 	}
 	return cmd
 }
 
-func runNew(_ *cobra.Command, _ []string, keygen *cliservice.Service) error {
-	if err := keygen.GenerateNewKey(); err != nil {
+func runNew(_ *cobra.Command, _ []string, p *params) error {
+	key, err := crypto.GenerateKey()
+	if err != nil {
 		return err
 	}
-	if !common.Quiet {
-		fmt.Printf("Private key: ")
-	}
-	fmt.Println(keygen.GetPrivateKey())
-	return nil
+	return storeKey(p, key)
 }
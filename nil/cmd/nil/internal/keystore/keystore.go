@@ -0,0 +1,200 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	"github.com/NilFoundation/nil/nil/cmd/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	nilkeystore "github.com/NilFoundation/nil/nil/internal/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var logger = logging.NewLogger("keystoreCommand")
+
+const (
+	defaultFlag    = "default"
+	fromConfigFlag = "from-config"
+)
+
+func GetCommand(cfgFile *string) *cobra.Command {
+	keystoreCmd := &cobra.Command{
+		Use:   "keystore",
+		Short: "Manage the accounts in the encrypted keystore",
+		Long: "Manage the accounts in the encrypted keystore. The keys are encrypted with a passphrase " +
+			"in the Ethereum v3 keystore format and kept next to the config file. The passphrase is prompted " +
+			"or taken from the " + common.PassphraseEnv + " environment variable.",
+		SilenceUsage: true,
+	}
+
+	open := func() *nilkeystore.Keystore {
+		return nilkeystore.New(common.KeystoreDir(*cfgFile))
+	}
+
+	var setDefault bool
+
+	newCmd := &cobra.Command{
+		Use:          "new [name]",
+		Short:        "Generate a new key and store it in the keystore",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := crypto.GenerateKey()
+			if err != nil {
+				return err
+			}
+			passphrase, err := common.ReadPassphrase("Enter a passphrase for the new account: ", true)
+			if err != nil {
+				return err
+			}
+			if err := open().Import(args[0], key, passphrase); err != nil {
+				return err
+			}
+			if err := updateConfig(args[0], setDefault, false); err != nil {
+				return err
+			}
+
+			result := &accountOutput{
+				Name:      args[0],
+				PublicKey: hexutil.Encode(crypto.CompressPubkey(&key.PublicKey)),
+			}
+			return common.PrintResult(result, func() error {
+				if !common.Quiet {
+					fmt.Print("Public key: ")
+				}
+				fmt.Println(result.PublicKey)
+				return nil
+			})
+		},
+	}
+	newCmd.Flags().BoolVar(&setDefault, defaultFlag, false, "Use the account by default")
+
+	var fromConfig bool
+
+	importCmd := &cobra.Command{
+		Use:   "import [name]",
+		Short: "Encrypt a private key and store it in the keystore",
+		Long: "Encrypt a private key and store it in the keystore. The key is read from stdin or prompted " +
+			"in the terminal. With --" + fromConfigFlag + ", the private key from the config file is moved " +
+			"to the keystore and the account is used by default.",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var key *ecdsa.PrivateKey
+			var err error
+			if fromConfig {
+				if err := viper.ReadInConfig(); err != nil {
+					return fmt.Errorf("failed to read the config file: %w", err)
+				}
+				hexKey := viper.GetString("nil." + config.PrivateKeyField)
+				if hexKey == "" {
+					return errors.New("private_key not specified in config")
+				}
+				key, err = crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+				if err != nil {
+					return common.ValidationError(err)
+				}
+			} else {
+				key, err = common.ReadPrivateKey("Enter the private key: ")
+				if err != nil {
+					return err
+				}
+			}
+
+			passphrase, err := common.ReadPassphrase("Enter a passphrase for the account: ", true)
+			if err != nil {
+				return err
+			}
+			if err := open().Import(args[0], key, passphrase); err != nil {
+				return err
+			}
+			if err := updateConfig(args[0], setDefault || fromConfig, fromConfig); err != nil {
+				return err
+			}
+
+			result := &accountOutput{
+				Name:      args[0],
+				PublicKey: hexutil.Encode(crypto.CompressPubkey(&key.PublicKey)),
+			}
+			return common.PrintResult(result, func() error {
+				if !common.Quiet {
+					fmt.Print("Imported account: ")
+				}
+				fmt.Println(result.Name)
+				return nil
+			})
+		},
+	}
+	importCmd.Flags().BoolVar(&setDefault, defaultFlag, false, "Use the account by default")
+	importCmd.Flags().BoolVar(&fromConfig, fromConfigFlag, false, "Move the private key from the config file")
+
+	listCmd := &cobra.Command{
+		Use:          "list",
+		Short:        "Print the accounts in the keystore",
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, err := open().List()
+			if err != nil {
+				return err
+			}
+			if names == nil {
+				names = []string{}
+			}
+			return common.PrintResult(&listOutput{Accounts: names}, func() error {
+				for _, name := range names {
+					fmt.Println(name)
+				}
+				return nil
+			})
+		},
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:          "delete [name]",
+		Short:        "Delete the account from the keystore",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return open().Delete(args[0])
+		},
+	}
+
+	keystoreCmd.AddCommand(newCmd, importCmd, listCmd, deleteCmd)
+	return keystoreCmd
+}
+
+// updateConfig sets the account as the default one and removes the plaintext private key if requested.
+func updateConfig(name string, setDefault bool, removePrivateKey bool) error {
+	delta := make(map[string]any)
+	if setDefault {
+		delta[config.AccountField] = name
+	}
+	if removePrivateKey {
+		delta[config.PrivateKeyField] = nil
+	}
+	if len(delta) == 0 {
+		return nil
+	}
+
+	if err := config.PatchConfig(delta, false); err != nil {
+		logger.Error().Err(err).Msg("failed to update the account in the config file")
+		return err
+	}
+	return nil
+}
+
+type accountOutput struct {
+	Name      string `json:"name"`
+	PublicKey string `json:"publicKey"`
+}
+
+type listOutput struct {
+	Accounts []string `json:"accounts"`
+}
@@ -30,29 +30,36 @@ func ChangeTokenAmountCommand(cfg *common.Config, mint bool) *cobra.Command {
 }
 
 func runChangeTokenAmount(cmd *cobra.Command, args []string, cfg *common.Config, mint bool) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
 	var amount types.Value
 	if err := amount.Set(args[1]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
 	txHash, err := service.ChangeTokenAmount(address, amount, mint)
 	if err != nil {
 		return err
 	}
-	if !common.Quiet {
-		if mint {
-			fmt.Printf("Minted %v amount of token to %v, TX Hash: ", amount, address)
-		} else {
-			fmt.Printf("Burned %v amount of token from %v, TX Hash: ", amount, address)
-		}
+	result := &changeAmountOutput{
+		TransactionHash: txHash,
+		Address:         address,
+		Amount:          amount,
 	}
-	fmt.Println(txHash)
-	return nil
+	return common.PrintResult(result, func() error {
+		if !common.Quiet {
+			if mint {
+				fmt.Printf("Minted %v amount of token to %v, TX Hash: ", amount, address)
+			} else {
+				fmt.Printf("Burned %v amount of token from %v, TX Hash: ", amount, address)
+			}
+		}
+		fmt.Println(txHash)
+		return nil
+	})
 }
@@ -24,16 +24,16 @@ func CreateTokenCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runCreateToken(cmd *cobra.Command, args []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
 	var amount types.Value
 	if err := amount.Set(args[1]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
 	name := args[2]
@@ -42,9 +42,16 @@ func runCreateToken(cmd *cobra.Command, args []string, cfg *common.Config) error
 	if err != nil {
 		return err
 	}
-	if !common.Quiet {
-		fmt.Print("Created Token ID: ")
+	result := &createTokenOutput{
+		TokenId: *tokenId,
+		Name:    name,
+		Amount:  amount,
 	}
-	fmt.Println(tokenId)
-	return nil
+	return common.PrintResult(result, func() error {
+		if !common.Quiet {
+			fmt.Print("Created Token ID: ")
+		}
+		fmt.Println(tokenId)
+		return nil
+	})
 }
@@ -2,6 +2,8 @@ package minter
 
 import (
 	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	libcommon "github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/spf13/cobra"
 )
 
@@ -17,3 +19,15 @@ func GetCommand(cfg *common.Config) *cobra.Command {
 
 	return serverCmd
 }
+
+type createTokenOutput struct {
+	TokenId types.TokenId `json:"tokenId"`
+	Name    string        `json:"name"`
+	Amount  types.Value   `json:"amount"`
+}
+
+type changeAmountOutput struct {
+	TransactionHash libcommon.Hash `json:"transactionHash"`
+	Address         types.Address  `json:"address"`
+	Amount          types.Value    `json:"amount"`
+}
@@ -1,6 +1,7 @@
 package receipt
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -13,6 +14,8 @@ import (
 
 var logger = logging.NewLogger("receiptCommand")
 
+var decodeParams = &common.DecodeParams{}
+
 func GetCommand(cfg *common.Config) *cobra.Command {
 	serverCmd := &cobra.Command{
 		Use:          "receipt [hash]",
@@ -22,6 +25,8 @@ func GetCommand(cfg *common.Config) *cobra.Command {
 		SilenceUsage: true,
 	}
 
+	common.SetDecodeFlags(serverCmd, decodeParams)
+
 	return serverCmd
 }
 
@@ -30,20 +35,32 @@ func runCommand(cmd *cobra.Command, args []string) error {
 
 	var hash libcommon.Hash
 	if err := hash.Set(args[0]); err != nil {
+		return common.ValidationError(err)
+	}
+
+	decoder, err := common.NewAbiDecoder(decodeParams)
+	if err != nil {
 		return err
 	}
 
 	if hash != libcommon.EmptyHash {
-		receipt, err := service.FetchReceiptByHashJson(hash)
+		var receipt []byte
+		if decoder != nil {
+			receipt, err = service.FetchDecodedReceiptByHashJson(hash, decoder)
+		} else {
+			receipt, err = service.FetchReceiptByHashJson(hash)
+		}
 		if err != nil {
 			logger.Error().Err(err).Msg("Failed to fetch the receipt")
 			return err
 		}
-		if !common.Quiet {
-			fmt.Print("Receipt data: ")
-		}
-		fmt.Println(string(receipt))
-		return nil
+		return common.PrintResult(json.RawMessage(receipt), func() error {
+			if !common.Quiet {
+				fmt.Print("Receipt data: ")
+			}
+			fmt.Println(string(receipt))
+			return nil
+		})
 	}
-	return errors.New("empty hash")
+	return common.ValidationError(errors.New("empty hash"))
 }
@@ -23,14 +23,16 @@ func BalanceCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runBalance(cmd *cobra.Command, _ []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 	balance, err := service.GetBalance(cfg.Address)
 	if err != nil {
 		return err
 	}
-	if !common.Quiet {
-		fmt.Print("Smart account balance: ")
-	}
-	fmt.Println(balance)
-	return nil
+	return common.PrintResult(&common.BalanceOutput{Address: cfg.Address, Balance: balance}, func() error {
+		if !common.Quiet {
+			fmt.Print("Smart account balance: ")
+		}
+		fmt.Println(balance)
+		return nil
+	})
 }
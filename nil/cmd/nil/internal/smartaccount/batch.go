@@ -0,0 +1,80 @@
+package smartaccount
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// batchCall is an entry of the batch file. Method is either the bytecode or the name of the ABI method.
+type batchCall struct {
+	Address types.Address `json:"address"`
+	Abi     string        `json:"abi,omitempty"`
+	Method  string        `json:"method"`
+	Args    []string      `json:"args,omitempty"`
+	Amount  types.Value   `json:"amount"`
+	Tokens  []string      `json:"tokens,omitempty"`
+}
+
+func readBatchFile(path string) ([]client.SmartAccountCall, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []batchCall
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid batch file: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("batch file has no calls")
+	}
+
+	calls := make([]client.SmartAccountCall, len(entries))
+	for i, entry := range entries {
+		calls[i], err = entry.toCall()
+		if err != nil {
+			return nil, fmt.Errorf("invalid call #%d: %w", i, err)
+		}
+	}
+	return calls, nil
+}
+
+func (c *batchCall) toCall() (client.SmartAccountCall, error) {
+	var call client.SmartAccountCall
+
+	if c.Address.IsEmpty() {
+		return call, errors.New("address is not set")
+	}
+
+	var contractAbi abi.ABI
+	if c.Abi != "" {
+		var err error
+		if contractAbi, err = common.ReadAbiFromFile(c.Abi); err != nil {
+			return call, err
+		}
+	}
+
+	calldata, err := common.PrepareArgs(contractAbi, c.Method, c.Args)
+	if err != nil {
+		return call, err
+	}
+
+	tokens, err := common.ParseTokens(c.Tokens)
+	if err != nil {
+		return call, err
+	}
+
+	return client.SmartAccountCall{
+		To:     c.Address,
+		Data:   calldata,
+		Value:  c.Amount,
+		Tokens: tokens,
+	}, nil
+}
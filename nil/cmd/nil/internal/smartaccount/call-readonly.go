@@ -70,11 +70,11 @@ func CallReadonlyCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runCallReadonly(cmd *cobra.Command, args []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return fmt.Errorf("invalid address: %w", err)
+		return common.ValidationError(fmt.Errorf("invalid address: %w", err))
 	}
 
 	var contractAbi abi.ABI
@@ -113,7 +113,8 @@ func runCallReadonly(cmd *cobra.Command, args []string, cfg *common.Config) erro
 
 	handler := func(res *jsonrpc.CallRes) ([]*common.ArgValue, []*common.NamedArgValues, error) {
 		if res.Error != "" {
-			return nil, nil, fmt.Errorf("error during sending the transaction to the smart account: %s", res.Error)
+			return nil, nil, common.RevertedError(
+				fmt.Errorf("error during sending the transaction to the smart account: %s", res.Error))
 		}
 
 		if outTxnLen := len(res.OutTransactions); outTxnLen != 1 {
@@ -121,7 +122,8 @@ func runCallReadonly(cmd *cobra.Command, args []string, cfg *common.Config) erro
 		}
 
 		if outTxnErr := res.OutTransactions[0].Error; outTxnErr != "" {
-			return nil, nil, fmt.Errorf("error during processing the smart account transaction: %s", outTxnErr)
+			return nil, nil, common.RevertedError(
+				fmt.Errorf("error during processing the smart account transaction: %s", outTxnErr))
 		}
 
 		logs, err := common.DecodeLogs(contractAbi, res.OutTransactions[0].Logs)
@@ -72,6 +72,14 @@ func setDeployFlags(cmd *cobra.Command) {
 		"Define whether the command should wait for the receipt",
 	)
 
+	cmd.Flags().BoolVar(
+		&params.waitAll,
+		waitAllFlag,
+		false,
+		"Wait for all transactions spawned by the transaction across the shards and fail if any of them failed",
+	)
+	cmd.MarkFlagsMutuallyExclusive(noWaitFlag, waitAllFlag)
+
 	cmd.Flags().StringVar(
 		&params.compileInput,
 		compileInput,
@@ -82,10 +90,10 @@ func setDeployFlags(cmd *cobra.Command) {
 
 func runDeploy(cmd *cobra.Command, cmdArgs []string, cfg *common.Config) error {
 	if !params.token.IsZero() && params.noWait {
-		return errors.New("the \"no-wait\" flag cannot be used with the \"token\" flag")
+		return common.ValidationError(errors.New("the \"no-wait\" flag cannot be used with the \"token\" flag"))
 	}
 
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 
 	var cm *cometa.Client
 	if len(params.compileInput) != 0 {
@@ -93,7 +101,7 @@ func runDeploy(cmd *cobra.Command, cmdArgs []string, cfg *common.Config) error {
 	}
 
 	if len(params.compileInput) == 0 && len(cmdArgs) == 0 {
-		return errors.New("at least one arg is required (the path to the bytecode file)")
+		return common.ValidationError(errors.New("at least one arg is required (the path to the bytecode file)"))
 	}
 
 	var bytecode types.Code
@@ -113,7 +121,7 @@ func runDeploy(cmd *cobra.Command, cmdArgs []string, cfg *common.Config) error {
 			}
 			calldata, err = common.ArgsToCalldata(abi, "", cmdArgs)
 			if err != nil {
-				return fmt.Errorf("failed to pack the constructor arguments: %w", err)
+				return common.ValidationError(fmt.Errorf("failed to pack the constructor arguments: %w", err))
 			}
 		}
 		bytecode = append(contractData.InitCode, calldata...) //nolint:gocritic
@@ -148,33 +156,41 @@ https://ethereum.org/en/developers/tutorials/downsizing-contracts-to-fight-the-c
 	}
 
 	var receipt *jsonrpc.RPCReceipt
-	if !params.noWait {
-		if receipt, err = service.WaitForReceipt(txnHash); err != nil {
+	switch {
+	case params.waitAll:
+		if receipt, err = common.WaitForTransactionTree(service, txnHash); err != nil {
 			return err
 		}
-	} else {
+	case !params.noWait:
+		if receipt, err = common.WaitForReceipt(service, txnHash); err != nil {
+			return err
+		}
+	default:
 		if len(params.compileInput) != 0 {
 			return errors.New("the \"no-wait\" flag cannot be used with contract compilation")
 		}
 	}
-	if receipt == nil || !receipt.AllSuccess() {
-		return errors.New("deploy transaction processing failed")
-	}
-
 	if len(params.compileInput) != 0 {
 		if err = cm.RegisterContractData(contractData, contractAddr); err != nil {
 			return fmt.Errorf("failed to register the contract: %w", err)
 		}
 	}
 
-	if !common.Quiet {
-		fmt.Print("Transaction hash: ")
+	result := &common.TransactionOutput{
+		TransactionHash: txnHash,
+		Address:         &contractAddr,
+		Receipt:         receipt,
 	}
-	fmt.Printf("0x%x\n", txnHash)
+	return common.PrintResult(result, func() error {
+		if !common.Quiet {
+			fmt.Print("Transaction hash: ")
+		}
+		fmt.Printf("0x%x\n", txnHash)
 
-	if !common.Quiet {
-		fmt.Print("Contract address: ")
-	}
-	fmt.Printf("0x%x\n", contractAddr)
-	return nil
+		if !common.Quiet {
+			fmt.Print("Contract address: ")
+		}
+		fmt.Printf("0x%x\n", contractAddr)
+		return nil
+	})
 }
@@ -29,11 +29,11 @@ func GetEstimateFeeCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runEstimateFee(cmd *cobra.Command, args []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return fmt.Errorf("invalid address: %w", err)
+		return common.ValidationError(fmt.Errorf("invalid address: %w", err))
 	}
 
 	abi, err := common.ReadAbiFromFile(params.AbiPath)
@@ -74,18 +74,8 @@ func runEstimateFee(cmd *cobra.Command, args []string, cfg *common.Config) error
 		return err
 	}
 
-	if !common.Quiet {
-		fmt.Print("FeeCredit: ")
-	}
-	fmt.Println(res.FeeCredit)
-	if !common.Quiet {
-		fmt.Print("MaxBasFee: ")
-	}
-	fmt.Println(res.MaxBasFee)
-	if !common.Quiet {
-		fmt.Print("AveragePriorityFee: ")
-	}
-	fmt.Println(res.AveragePriorityFee)
-
-	return nil
+	return common.PrintResult(res, func() error {
+		common.PrintFee(res)
+		return nil
+	})
 }
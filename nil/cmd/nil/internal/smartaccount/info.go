@@ -23,21 +23,22 @@ func InfoCommand(cfg *common.Config) *cobra.Command {
 }
 
 func infoBalance(cmd *cobra.Command, _ []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 	addr, pub, err := service.GetInfo(cfg.Address)
 	if err != nil {
 		return err
 	}
 
-	if !common.Quiet {
-		fmt.Print("Smart account address: ")
-	}
-	fmt.Println(addr)
-
-	if !common.Quiet {
-		fmt.Print("Public key: ")
-	}
-	fmt.Println(pub)
-
-	return nil
+	return common.PrintResult(&infoOutput{Address: addr, PublicKey: pub}, func() error {
+		if !common.Quiet {
+			fmt.Print("Smart account address: ")
+		}
+		fmt.Println(addr)
+
+		if !common.Quiet {
+			fmt.Print("Public key: ")
+		}
+		fmt.Println(pub)
+		return nil
+	})
 }
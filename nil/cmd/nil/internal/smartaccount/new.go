@@ -67,10 +67,14 @@ func runNew(cmd *cobra.Command, _ []string, cfg *common.Config) error {
 	if err != nil {
 		return err
 	}
-	srv := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, faucet)
-	check.PanicIfNotf(cfg.PrivateKey != nil, "A private key is not set in the config file")
+	srv := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, faucet)
+	check.PanicIfNotf(cfg.Signer != nil, "A private key is not set in the config file")
+	pubKey, err := srv.GetPublicKey()
+	if err != nil {
+		return err
+	}
 	smartAccountAddress, err := srv.CreateSmartAccount(params.shardId, &params.salt, amount,
-		types.NewFeePackFromFeeCredit(params.Fee.FeeCredit), &cfg.PrivateKey.PublicKey)
+		types.NewFeePackFromFeeCredit(params.Fee.FeeCredit), pubKey)
 	if err != nil {
 		return err
 	}
@@ -81,9 +85,11 @@ func runNew(cmd *cobra.Command, _ []string, cfg *common.Config) error {
 		logger.Error().Err(err).Msg("failed to update the smart account address in the config file")
 	}
 
-	if !common.Quiet {
-		fmt.Print("New smart account address: ")
-	}
-	fmt.Println(smartAccountAddress.Hex())
-	return nil
+	return common.PrintResult(&common.AddressOutput{Address: smartAccountAddress}, func() error {
+		if !common.Quiet {
+			fmt.Print("New smart account address: ")
+		}
+		fmt.Println(smartAccountAddress.Hex())
+		return nil
+	})
 }
@@ -9,6 +9,7 @@ const (
 	abiFlag          = "abi"
 	amountFlag       = "amount"
 	noWaitFlag       = "no-wait"
+	waitAllFlag      = "wait-all"
 	saltFlag         = "salt"
 	shardIdFlag      = "shard-id"
 	feeCreditFlag    = "fee-credit"
@@ -21,6 +22,8 @@ const (
 	asJsonFlag       = "json"
 	compileInput     = "compile-input"
 	priorityFee      = "priority-fee"
+	batchFlag        = "batch"
+	bestEffortFlag   = "best-effort"
 )
 
 var params = &smartAccountParams{
@@ -32,6 +35,7 @@ type smartAccountParams struct {
 
 	deploy                bool
 	noWait                bool
+	waitAll               bool
 	amount                types.Value
 	newSmartAccountAmount types.Value
 	salt                  types.Uint256
@@ -41,4 +45,11 @@ type smartAccountParams struct {
 	tokens                []string
 	compileInput          string
 	priorityFee           string
+	batchFile             string
+	bestEffort            bool
+}
+
+type infoOutput struct {
+	Address   string `json:"address"`
+	PublicKey string `json:"publicKey"`
 }
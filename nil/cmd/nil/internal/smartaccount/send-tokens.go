@@ -44,16 +44,16 @@ func SendTokensCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runTransfer(cmd *cobra.Command, args []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
 	var amount types.Value
 	if err := amount.Set(args[1]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
 	tokens, err := common.ParseTokens(params.tokens)
@@ -66,16 +66,18 @@ func runTransfer(cmd *cobra.Command, args []string, cfg *common.Config) error {
 		return err
 	}
 
+	result := &common.TransactionOutput{TransactionHash: txnHash}
 	if !params.noWait {
-		if _, err := service.WaitForReceipt(txnHash); err != nil {
+		if result.Receipt, err = common.WaitForReceipt(service, txnHash); err != nil {
 			return err
 		}
 	}
 
-	if !common.Quiet {
-		fmt.Print("Transaction hash: ")
-	}
-
-	fmt.Println(txnHash)
-	return nil
+	return common.PrintResult(result, func() error {
+		if !common.Quiet {
+			fmt.Print("Transaction hash: ")
+		}
+		fmt.Println(txnHash)
+		return nil
+	})
 }
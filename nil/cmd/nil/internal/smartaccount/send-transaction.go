@@ -13,9 +13,18 @@ func SendTransactionCommand(cfg *common.Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "send-transaction [address] [bytecode or method] [args...]",
 		Short: "Send a transaction to a smart contract via the smart account",
-		Long:  "Send a transaction to the smart contract with the specified bytecode or command via the smart account",
-		Args:  cobra.MinimumNArgs(1),
+		Long: "Send a transaction to the smart contract with the specified bytecode or command via the smart account.\n" +
+			"With --batch, send the calls listed in the JSON file as a single batched transaction",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if params.batchFile != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if params.batchFile != "" {
+				return runSendBatch(cmd, cfg)
+			}
 			return runSend(cmd, args, cfg)
 		},
 		SilenceUsage: true,
@@ -41,6 +50,14 @@ func SendTransactionCommand(cfg *common.Config) *cobra.Command {
 		"Define whether the command should wait for the receipt",
 	)
 
+	cmd.Flags().BoolVar(
+		&params.waitAll,
+		waitAllFlag,
+		false,
+		"Wait for all transactions spawned by the transaction across the shards and fail if any of them failed",
+	)
+	cmd.MarkFlagsMutuallyExclusive(noWaitFlag, waitAllFlag)
+
 	cmd.Flags().Var(
 		&params.Fee.FeeCredit,
 		feeCreditFlag,
@@ -59,15 +76,27 @@ func SendTransactionCommand(cfg *common.Config) *cobra.Command {
 		"The priority fee for the message",
 	)
 
+	cmd.Flags().StringVar(&params.batchFile,
+		batchFlag,
+		"",
+		"The path to a JSON file with the list of calls to send as a single batched transaction",
+	)
+
+	cmd.Flags().BoolVar(&params.bestEffort,
+		bestEffortFlag,
+		false,
+		"Execute the remaining calls of the batch if one of them fails instead of reverting all of them",
+	)
+
 	return cmd
 }
 
 func runSend(cmd *cobra.Command, args []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
-		return fmt.Errorf("invalid address: %w", err)
+		return common.ValidationError(fmt.Errorf("invalid address: %w", err))
 	}
 
 	abi, err := common.ReadAbiFromFile(params.AbiPath)
@@ -90,16 +119,58 @@ func runSend(cmd *cobra.Command, args []string, cfg *common.Config) error {
 		return err
 	}
 
-	if !params.noWait {
-		if _, err := service.WaitForReceipt(txnHash); err != nil {
+	result := &common.TransactionOutput{TransactionHash: txnHash}
+	switch {
+	case params.waitAll:
+		if result.Receipt, err = common.WaitForTransactionTree(service, txnHash); err != nil {
+			return err
+		}
+	case !params.noWait:
+		if result.Receipt, err = common.WaitForReceipt(service, txnHash); err != nil {
 			return err
 		}
 	}
 
-	if !common.Quiet {
-		fmt.Print("Transaction hash: ")
+	return common.PrintResult(result, func() error {
+		if !common.Quiet {
+			fmt.Print("Transaction hash: ")
+		}
+		fmt.Println(txnHash)
+		return nil
+	})
+}
+
+func runSendBatch(cmd *cobra.Command, cfg *common.Config) error {
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
+
+	calls, err := readBatchFile(params.batchFile)
+	if err != nil {
+		return common.ValidationError(err)
+	}
+
+	txnHash, err := service.RunBatch(
+		cfg.Address, calls, !params.bestEffort, types.NewFeePackFromFeeCredit(params.Fee.FeeCredit))
+	if err != nil {
+		return err
 	}
 
-	fmt.Println(txnHash)
-	return nil
+	result := &common.TransactionOutput{TransactionHash: txnHash}
+	switch {
+	case params.waitAll:
+		if result.Receipt, err = common.WaitForTransactionTree(service, txnHash); err != nil {
+			return err
+		}
+	case !params.noWait:
+		if result.Receipt, err = common.WaitForReceipt(service, txnHash); err != nil {
+			return err
+		}
+	}
+
+	return common.PrintResult(result, func() error {
+		if !common.Quiet {
+			fmt.Print("Transaction hash: ")
+		}
+		fmt.Println(txnHash)
+		return nil
+	})
 }
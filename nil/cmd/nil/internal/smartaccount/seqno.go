@@ -23,14 +23,16 @@ func SeqnoCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runSeqno(cmd *cobra.Command, _ []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 	seqno, err := service.GetSeqno(cfg.Address)
 	if err != nil {
 		return err
 	}
-	if !common.Quiet {
-		fmt.Print("Smart account seqno: ")
-	}
-	fmt.Println(seqno)
-	return nil
+	return common.PrintResult(&common.SeqnoOutput{Address: cfg.Address, Seqno: seqno}, func() error {
+		if !common.Quiet {
+			fmt.Print("Smart account seqno: ")
+		}
+		fmt.Println(seqno)
+		return nil
+	})
 }
@@ -24,7 +24,7 @@ func GetCommand(cfg *common.Config) *cobra.Command {
 					}
 				}
 			}
-			if cfg.PrivateKey == nil {
+			if cfg.Signer == nil {
 				return config.MissingKeyError(config.PrivateKeyField, logger)
 			}
 			if cfg.Address == types.EmptyAddress && cmd.Name() != "new" {
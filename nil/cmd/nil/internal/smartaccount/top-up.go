@@ -23,7 +23,7 @@ func TopUpCommand(cfg *common.Config) *cobra.Command {
 func runTopUp(cmd *cobra.Command, args []string, cfg *common.Config) error {
 	var amount types.Value
 	if err := amount.Set(args[0]); err != nil {
-		return err
+		return common.ValidationError(err)
 	}
 
 	var currId string
@@ -10,6 +10,28 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const defaultTopContractsBlocks = 100
+
+type shardsOutput struct {
+	Shards []types.ShardId `json:"shards"`
+}
+
+type gasPriceOutput struct {
+	ShardId  types.ShardId `json:"shardId"`
+	GasPrice types.Value   `json:"gasPrice"`
+}
+
+type chainIdOutput struct {
+	ChainId types.ChainId `json:"chainId"`
+}
+
+type topContractsOutput struct {
+	ShardId types.ShardId     `json:"shardId"`
+	From    types.BlockNumber `json:"from"`
+	To      types.BlockNumber `json:"to"`
+	*types.ResourceUsage
+}
+
 func GetCommand(cfg *common.Config) *cobra.Command {
 	var svc *cliservice.Service
 
@@ -21,7 +43,7 @@ func GetCommand(cfg *common.Config) *cobra.Command {
 			if err := cmd.Parent().Parent().PersistentPreRunE(cmd, args); err != nil {
 				return err
 			}
-			svc = cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+			svc = cliservice.NewServiceWithSigner(cmd.Context(), common.GetRpcClient(), cfg.Signer, nil)
 			return nil
 		},
 	}
@@ -37,11 +59,13 @@ func GetCommand(cfg *common.Config) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			if !common.Quiet {
-				fmt.Println("Shards: ")
-			}
-			fmt.Print(cliservice.ShardsToString(list))
-			return nil
+			return common.PrintResult(&shardsOutput{Shards: list}, func() error {
+				if !common.Quiet {
+					fmt.Println("Shards: ")
+				}
+				fmt.Print(cliservice.ShardsToString(list))
+				return nil
+			})
 		},
 	}
 
@@ -55,18 +79,20 @@ func GetCommand(cfg *common.Config) *cobra.Command {
 
 			var shardId types.ShardId
 			if err := shardId.Set(args[0]); err != nil {
-				return err
+				return common.ValidationError(err)
 			}
 
 			val, err := svc.GetGasPrice(shardId)
 			if err != nil {
 				return err
 			}
-			if !common.Quiet {
-				fmt.Printf("Gas price for shard %v: ", shardId)
-			}
-			fmt.Println(val)
-			return nil
+			return common.PrintResult(&gasPriceOutput{ShardId: shardId, GasPrice: val}, func() error {
+				if !common.Quiet {
+					fmt.Printf("Gas price for shard %v: ", shardId)
+				}
+				fmt.Println(val)
+				return nil
+			})
 		},
 	}
 
@@ -81,17 +107,74 @@ func GetCommand(cfg *common.Config) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			if !common.Quiet {
-				fmt.Print("ChainId: ")
+			return common.PrintResult(&chainIdOutput{ChainId: chainId}, func() error {
+				if !common.Quiet {
+					fmt.Print("ChainId: ")
+				}
+				fmt.Println(chainId)
+				return nil
+			})
+		},
+	}
+
+	var topFrom, topTo types.BlockNumber
+	var topLimit int
+	topContractsCmd := &cobra.Command{
+		Use:   "top-contracts [shard-id]",
+		Short: "Print the contracts using the most gas in a block range",
+		Long: "Print the contracts using the most gas in a block range along with the gas used per method " +
+			"and the storage slots created and deleted. Requires the node to record the resource usage.",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			check.PanicIfNot(svc != nil)
+
+			var shardId types.ShardId
+			if err := shardId.Set(args[0]); err != nil {
+				return common.ValidationError(err)
 			}
-			fmt.Println(chainId)
-			return nil
+
+			if !cmd.Flags().Changed("to") {
+				latest, err := svc.GetLatestBlockNumber(shardId)
+				if err != nil {
+					return err
+				}
+				topTo = latest
+			}
+			if !cmd.Flags().Changed("from") {
+				topFrom = 0
+				if topTo >= defaultTopContractsBlocks {
+					topFrom = topTo - defaultTopContractsBlocks + 1
+				}
+			}
+
+			usage, err := svc.GetTopContracts(shardId, topFrom, topTo, topLimit)
+			if err != nil {
+				return err
+			}
+			result := &topContractsOutput{
+				ShardId:       shardId,
+				From:          topFrom,
+				To:            topTo,
+				ResourceUsage: usage,
+			}
+			return common.PrintResult(result, func() error {
+				if !common.Quiet {
+					fmt.Printf("Top contracts of shard %v in blocks %d-%d:\n", shardId, topFrom, topTo)
+				}
+				fmt.Print(cliservice.ResourceUsageToString(usage))
+				return nil
+			})
 		},
 	}
+	topContractsCmd.Flags().Var(&topFrom, "from", "The first block of the range (default: the last 100 blocks)")
+	topContractsCmd.Flags().Var(&topTo, "to", "The last block of the range (default: the latest block)")
+	topContractsCmd.Flags().IntVar(&topLimit, "limit", 10, "The maximum number of contracts to print")
 
 	configCmd.AddCommand(shardsCmd)
 	configCmd.AddCommand(gasPriceCmd)
 	configCmd.AddCommand(chainIdCmd)
+	configCmd.AddCommand(topContractsCmd)
 
 	return configCmd
 }
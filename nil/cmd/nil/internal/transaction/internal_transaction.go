@@ -5,12 +5,19 @@ import (
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	libcommon "github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/spf13/cobra"
 )
 
+type encodedTransactionOutput struct {
+	Transaction *types.InternalTransactionPayload `json:"transaction"`
+	Result      string                            `json:"result"`
+	Hash        libcommon.Hash                    `json:"hash"`
+}
+
 func GetInternalTransactionCommand() *cobra.Command {
 	var (
 		kind                   types.TransactionKind = types.ExecutionTransactionKind
@@ -51,18 +58,26 @@ func GetInternalTransactionCommand() *cobra.Command {
 			}
 
 			transactionSszHex := hexutil.Encode(transactionSsz)
+			hash := transaction.ToTransaction(types.EmptyAddress, types.Seqno(0)).Hash()
 
-			if !common.Quiet {
-				fmt.Println("Transaction:")
-				fmt.Println(string(transactionStr))
-				fmt.Print("Result: ")
-			}
-			fmt.Println(transactionSszHex)
-
-			if !common.Quiet {
-				fmt.Printf("Hash: %x\n", transaction.ToTransaction(types.EmptyAddress, types.Seqno(0)).Hash())
+			result := &encodedTransactionOutput{
+				Transaction: transaction,
+				Result:      transactionSszHex,
+				Hash:        hash,
 			}
-			return nil
+			return common.PrintResult(result, func() error {
+				if !common.Quiet {
+					fmt.Println("Transaction:")
+					fmt.Println(string(transactionStr))
+					fmt.Print("Result: ")
+				}
+				fmt.Println(transactionSszHex)
+
+				if !common.Quiet {
+					fmt.Printf("Hash: %x\n", hash)
+				}
+				return nil
+			})
 		},
 		SilenceUsage: true,
 	}
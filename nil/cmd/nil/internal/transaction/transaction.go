@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/cmd/nil/common"
@@ -13,6 +14,8 @@ import (
 
 var logger = logging.NewLogger("transactionCommand")
 
+var decodeParams = &common.DecodeParams{}
+
 func GetCommand(cfgPath *string) *cobra.Command {
 	serverCmd := &cobra.Command{
 		Use:   "transaction [hash]",
@@ -24,6 +27,8 @@ func GetCommand(cfgPath *string) *cobra.Command {
 		SilenceUsage: true,
 	}
 
+	common.SetDecodeFlags(serverCmd, decodeParams)
+
 	serverCmd.AddCommand(GetInternalTransactionCommand())
 
 	return serverCmd
@@ -40,19 +45,32 @@ func runCommand(cmd *cobra.Command, cfgPath *string, args []string) error {
 
 	var hash libcommon.Hash
 	if err := hash.Set(args[0]); err != nil {
+		return common.ValidationError(err)
+	}
+
+	decoder, err := common.NewAbiDecoder(decodeParams)
+	if err != nil {
 		return err
 	}
 
 	if hash != libcommon.EmptyHash {
-		txnDataJson, err := service.FetchTransactionByHashJson(hash)
+		var txnDataJson []byte
+		if decoder != nil {
+			txnDataJson, err = service.FetchDecodedTransactionByHashJson(hash, decoder)
+		} else {
+			txnDataJson, err = service.FetchTransactionByHashJson(hash)
+		}
 		if err != nil {
 			logger.Error().Err(err).Msg("Failed to fetch the transaction")
 			return err
 		}
-		if !common.Quiet {
-			fmt.Print("Transaction data: ")
-		}
-		fmt.Println(string(txnDataJson))
+		return common.PrintResult(json.RawMessage(txnDataJson), func() error {
+			if !common.Quiet {
+				fmt.Print("Transaction data: ")
+			}
+			fmt.Println(string(txnDataJson))
+			return nil
+		})
 	}
 	return nil
 }
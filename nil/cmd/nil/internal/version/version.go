@@ -2,7 +2,9 @@ package version
 
 import (
 	"fmt"
+	"runtime"
 
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
 	"github.com/NilFoundation/nil/nil/common/version"
 	"github.com/spf13/cobra"
 )
@@ -11,13 +13,32 @@ const (
 	appTitle = "=;Nil CLI"
 )
 
+type versionOutput struct {
+	Version  string `json:"version"`
+	Commit   string `json:"commit"`
+	Revision string `json:"revision"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+}
+
 func GetCommand() *cobra.Command {
 	versionCmd := &cobra.Command{
 		Use:          "version",
 		Short:        "Get the current version",
 		SilenceUsage: true,
-		Run: func(cmd *cobra.Command, args []string) {
-			PrintVersionString()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := version.GetVersionInfo()
+			result := &versionOutput{
+				Version:  info.GitTag,
+				Commit:   info.GitCommit,
+				Revision: version.GetGitRevision(),
+				OS:       runtime.GOOS,
+				Arch:     runtime.GOARCH,
+			}
+			return common.PrintResult(result, func() error {
+				PrintVersionString()
+				return nil
+			})
 		},
 	}
 	return versionCmd
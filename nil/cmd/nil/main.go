@@ -1,17 +1,20 @@
 package main
 
 import (
-	"fmt"
 	"os"
+	"strings"
 
 	"github.com/NilFoundation/nil/nil/cmd/nil/common"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/cmd/nil/internal/abigen"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/block"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/cometa"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/contract"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/debug"
+	"github.com/NilFoundation/nil/nil/cmd/nil/internal/deploy"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/keygen"
+	"github.com/NilFoundation/nil/nil/cmd/nil/internal/keystore"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/minter"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/receipt"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/smartaccount"
@@ -30,15 +33,18 @@ type RootCommand struct {
 	cfgFile  string
 	logLevel string
 	verbose  bool
+	account  string
 }
 
 var logger = logging.NewLogger("root")
 
 var noConfigCmd = map[string]struct{}{
 	"abi":              {},
+	"abigen":           {},
 	"config":           {},
 	"help":             {},
 	"keygen":           {},
+	"keystore":         {},
 	"completion":       {},
 	"__complete":       {},
 	"__completeNoDesc": {},
@@ -54,6 +60,11 @@ func main() {
 			Use:   "nil",
 			Short: "The CLI tool for interacting with the =nil; cluster",
 			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+				// Only the result is printed in the structured output mode
+				if common.IsStructuredOutput() {
+					common.Quiet = true
+				}
+
 				if !rootCmd.verbose {
 					zerolog.SetGlobalLevel(zerolog.Disabled)
 				} else {
@@ -63,7 +74,7 @@ func main() {
 				}
 
 				// Set the config file for all commands because some commands can write something to it.
-				// E.g. "keygen" command writes a private key to the config file (and creates if it doesn't exist)
+				// E.g. "keygen" command writes the account to the config file (and creates if it doesn't exist)
 				config.SetConfigFile(rootCmd.cfgFile)
 
 				// Traverse up to find the top-level command
@@ -78,7 +89,10 @@ func main() {
 				var err error
 				cfg, err := config.LoadConfig(rootCmd.cfgFile, logger)
 				if err != nil {
-					return err
+					return common.ValidationError(err)
+				}
+				if err := common.InitSigner(cfg, rootCmd.account, rootCmd.cfgFile, logger); err != nil {
+					return common.ValidationError(err)
 				}
 				rootCmd.config = *cfg
 				common.InitRpcClient(cfg, logger)
@@ -91,6 +105,12 @@ func main() {
 
 	rootCmd.baseCmd.PersistentFlags().StringVarP(&rootCmd.cfgFile, "config", "c", config.DefaultConfigPath, "The path to the config file")
 	rootCmd.baseCmd.PersistentFlags().StringVarP(&rootCmd.logLevel, "log-level", "l", "info", "Log level: trace|debug|info|warn|error|fatal|panic")
+	rootCmd.baseCmd.PersistentFlags().StringVar(
+		&rootCmd.account,
+		"account",
+		"",
+		"The keystore account signing the transactions (overrides the account in the config file)",
+	)
 	rootCmd.baseCmd.PersistentFlags().BoolVarP(
 		&common.Quiet,
 		"quiet",
@@ -98,6 +118,12 @@ func main() {
 		false,
 		"Quiet mode (print only the result and exit)",
 	)
+	rootCmd.baseCmd.PersistentFlags().VarP(
+		&common.Output,
+		"output",
+		"o",
+		"The output format of the results and the errors: text|json|yaml",
+	)
 	rootCmd.baseCmd.PersistentFlags().BoolVarP(
 		&rootCmd.verbose,
 		"verbose",
@@ -107,6 +133,10 @@ func main() {
 	)
 
 	rootCmd.registerSubCommands()
+	rootCmd.baseCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return common.ValidationError(err)
+	})
+	wrapArgsValidation(rootCmd.baseCmd)
 	rootCmd.Execute()
 }
 
@@ -114,10 +144,12 @@ func main() {
 func (rc *RootCommand) registerSubCommands() {
 	rc.baseCmd.AddCommand(
 		abi.GetCommand(),
+		abigen.GetCommand(&rc.cfgFile),
 		block.GetCommand(&rc.config),
 		config.GetCommand(&rc.cfgFile),
 		contract.GetCommand(&rc.config),
-		keygen.GetCommand(),
+		keygen.GetCommand(&rc.cfgFile),
+		keystore.GetCommand(&rc.cfgFile),
 		transaction.GetCommand(&rc.cfgFile),
 		minter.GetCommand(&rc.config),
 		receipt.GetCommand(&rc.config),
@@ -125,15 +157,32 @@ func (rc *RootCommand) registerSubCommands() {
 		version.GetCommand(),
 		smartaccount.GetCommand(&rc.config),
 		debug.GetCommand(),
+		deploy.GetCommand(&rc.config),
 		cometa.GetCommand(),
 	)
 }
 
+// wrapArgsValidation marks the errors of the positional arguments checks as the validation ones
+func wrapArgsValidation(cmd *cobra.Command) {
+	if validateArgs := cmd.Args; validateArgs != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			return common.ValidationError(validateArgs(cmd, args))
+		}
+	}
+	for _, child := range cmd.Commands() {
+		wrapArgsValidation(child)
+	}
+}
+
 // Execute runs the root command and handles any errors
 func (rc *RootCommand) Execute() {
 	if err := rc.baseCmd.Execute(); err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		// Cobra reports the unknown subcommands of the commands without the arguments validator itself
+		if strings.HasPrefix(err.Error(), "unknown command ") {
+			err = common.ValidationError(err)
+		}
+		common.PrintError(os.Stderr, err)
 
-		os.Exit(1)
+		os.Exit(common.ExitCode(err))
 	}
 }
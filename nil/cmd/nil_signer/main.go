@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/NilFoundation/nil/nil/internal/keys"
+	"github.com/NilFoundation/nil/nil/internal/remotesigner"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+type Command uint
+
+const (
+	CommandRun Command = iota + 1
+)
+
+type config struct {
+	command           Command
+	endpoint          string
+	validatorKeyPaths []string
+	l1PrivateKey      string
+	l1PrivateKeyFile  string
+	l1ChainId         uint64
+	l1Destinations    []string
+	protectionDbPath  string
+	retention         uint64
+	transport         remotesigner.TransportConfig
+}
+
+func main() {
+	cfg := parseArgs()
+
+	if cfg.command != CommandRun {
+		fmt.Printf("Signer failed: unknown command\n")
+		os.Exit(1)
+	}
+
+	if err := processRun(cfg); err != nil {
+		fmt.Printf("Signer failed: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func processRun(cfg *config) error {
+	logger := logging.NewLogger("nil_signer")
+
+	blsKeys := make([]bls.PrivateKey, 0, len(cfg.validatorKeyPaths))
+	for _, path := range cfg.validatorKeyPaths {
+		manager := keys.NewValidatorKeyManager(path)
+		if err := manager.InitKey(); err != nil {
+			return err
+		}
+		key, err := manager.GetKey()
+		if err != nil {
+			return err
+		}
+		blsKeys = append(blsKeys, key)
+	}
+
+	var ecdsaKeys []*ecdsa.PrivateKey
+	var policy *remotesigner.TransactionPolicy
+	if cfg.l1PrivateKey != "" || cfg.l1PrivateKeyFile != "" {
+		key, err := loadL1Key(cfg)
+		if err != nil {
+			return err
+		}
+		ecdsaKeys = append(ecdsaKeys, key)
+
+		if policy, err = loadTransactionPolicy(cfg); err != nil {
+			return err
+		}
+	}
+
+	if len(blsKeys) == 0 && len(ecdsaKeys) == 0 {
+		return errors.New("no keys are configured")
+	}
+
+	protection, err := remotesigner.NewSlashingProtection(cfg.protectionDbPath, cfg.retention)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	server := remotesigner.NewServer(blsKeys, ecdsaKeys, protection, policy, &cfg.transport, logger)
+	return server.Serve(ctx, cfg.endpoint)
+}
+
+func loadL1Key(cfg *config) (*ecdsa.PrivateKey, error) {
+	if cfg.l1PrivateKeyFile != "" {
+		key, err := crypto.LoadECDSA(cfg.l1PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load L1 private key: %w", err)
+		}
+		return key, nil
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.l1PrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode L1 private key: %w", err)
+	}
+	return key, nil
+}
+
+// loadTransactionPolicy restricts the L1 transactions to the chain and the rollup contracts.
+func loadTransactionPolicy(cfg *config) (*remotesigner.TransactionPolicy, error) {
+	if cfg.l1ChainId == 0 || len(cfg.l1Destinations) == 0 {
+		return nil, errors.New("the L1 chain ID and the allowed destinations are required to sign L1 transactions")
+	}
+	policy := &remotesigner.TransactionPolicy{ChainID: new(big.Int).SetUint64(cfg.l1ChainId)}
+	for _, destination := range cfg.l1Destinations {
+		if !ethcommon.IsHexAddress(destination) {
+			return nil, fmt.Errorf("invalid L1 destination address %s", destination)
+		}
+		policy.Destinations = append(policy.Destinations, ethcommon.HexToAddress(destination))
+	}
+	return policy, nil
+}
+
+func parseArgs() *config {
+	cfg := &config{}
+	rootCmd := &cobra.Command{
+		Use:           "nil_signer [global flags] [command]",
+		Short:         "remote signer of the validator and L1 keys",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	runCmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run signer server",
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg.command = CommandRun
+		},
+	}
+	runCmd.Flags().StringVar(&cfg.endpoint, "listen", "unix://nil_signer.sock",
+		"endpoint to listen on (unix:///path/to/socket or tcp://host:port)")
+	runCmd.Flags().StringSliceVar(&cfg.validatorKeyPaths, "validator-keys-path", nil,
+		"path to validator keys, the keys are generated if the file doesn't exist (can be repeated)")
+	runCmd.Flags().StringVar(&cfg.l1PrivateKey, "l1-private-key", "", "L1 account private key (hex)")
+	runCmd.Flags().StringVar(&cfg.l1PrivateKeyFile, "l1-private-key-file", "", "file with the L1 account private key (hex)")
+	runCmd.Flags().Uint64Var(&cfg.l1ChainId, "l1-chain-id", 0, "L1 chain ID the transactions are signed for")
+	runCmd.Flags().StringSliceVar(&cfg.l1Destinations, "l1-allowed-destination", nil,
+		"L1 contract address the transactions may be sent to, e.g. the rollup contract (can be repeated)")
+	runCmd.Flags().StringVar(&cfg.transport.TLSCertFile, "tls-cert", "", "TLS certificate, required to listen on TCP")
+	runCmd.Flags().StringVar(&cfg.transport.TLSKeyFile, "tls-key", "", "TLS private key, required to listen on TCP")
+	runCmd.Flags().StringVar(&cfg.transport.AuthTokenFile, "auth-token-file", "",
+		"file with the token the clients are authenticated with, required to listen on TCP")
+	runCmd.Flags().StringVar(&cfg.protectionDbPath, "slashing-protection-db", "slashing-protection.json",
+		"path to the slashing protection records")
+	runCmd.Flags().Uint64Var(&cfg.retention, "slashing-protection-retention", remotesigner.DefaultProtectionRetention,
+		"number of the latest heights whose signed proposals are kept")
+	runCmd.MarkFlagsMutuallyExclusive("l1-private-key", "l1-private-key-file")
+	rootCmd.AddCommand(runCmd)
+
+	logLevel := rootCmd.PersistentFlags().StringP("log-level", "l", "info", "log level: trace|debug|info|warn|error|fatal|panic")
+	logging.SetupGlobalLogger(*logLevel)
+
+	check.PanicIfErr(rootCmd.Execute())
+
+	return cfg
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/NilFoundation/nil/nil/cmd/nild/nildconfig"
+	"github.com/NilFoundation/nil/nil/services/admin"
+	"github.com/spf13/cobra"
+)
+
+// AdminCommand returns the client of the admin server of a running node.
+// The node is reached by the --admin-socket-path flag or the adminSocket value of the config.
+func AdminCommand(cfg *nildconfig.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Manage a running node via its admin socket",
+		// The node must not be started after the command is executed
+		PersistentPostRun: func(*cobra.Command, []string) {
+			os.Exit(0)
+		},
+	}
+
+	call := func(cmd *cobra.Command, handle string, params url.Values, out io.Writer) error {
+		if cfg.AdminSocketPath == "" {
+			return errors.New("admin socket path is not specified")
+		}
+		if err := admin.NewClient(cfg.AdminSocketPath).Call(cmd.Context(), handle, params, out); err != nil {
+			return err
+		}
+		if out == os.Stdout {
+			fmt.Println()
+		}
+		return nil
+	}
+	callFunc := func(handle string) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, _ []string) error {
+			return call(cmd, handle, nil, os.Stdout)
+		}
+	}
+
+	var shard string
+	shardParams := func() url.Values {
+		params := url.Values{}
+		if shard != "" {
+			params.Set("shard", shard)
+		}
+		return params
+	}
+	pauseCmd := &cobra.Command{
+		Use:   "pause-collation",
+		Short: "Stop producing blocks of the shard (all collated shards by default)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return call(cmd, "/collation/pause", shardParams(), os.Stdout)
+		},
+	}
+	resumeCmd := &cobra.Command{
+		Use:   "resume-collation",
+		Short: "Resume producing blocks of the shard (all collated shards by default)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return call(cmd, "/collation/resume", shardParams(), os.Stdout)
+		},
+	}
+	for _, c := range []*cobra.Command{pauseCmd, resumeCmd} {
+		c.Flags().StringVar(&shard, "shard", "", "shard id")
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "collation-status",
+		Short: "Show which shards have collation paused",
+		RunE:  callFunc("/collation/status"),
+	}
+
+	var discardRatio float64
+	gcCmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Run the database value log garbage collection",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := url.Values{}
+			params.Set("discard_ratio", strconv.FormatFloat(discardRatio, 'f', -1, 64))
+			return call(cmd, "/db/gc", params, os.Stdout)
+		},
+	}
+	gcCmd.Flags().Float64Var(&discardRatio, "discard-ratio", 0.5, "rewrite value log files with at least this ratio of garbage")
+
+	backupCmd := &cobra.Command{
+		Use:   "backup [path]",
+		Short: "Write a consistent database backup to the path on the node's host",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return call(cmd, "/db/backup", url.Values{"path": args}, os.Stdout)
+		},
+	}
+
+	var profileOutput string
+	var profileDebug int
+	profileCmd := &cobra.Command{
+		Use:       "profile [goroutine|heap]",
+		Short:     "Dump a runtime profile of the node",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"goroutine", "heap"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := url.Values{}
+			params.Set("debug", strconv.Itoa(profileDebug))
+
+			out := io.Writer(os.Stdout)
+			if profileOutput != "" {
+				file, err := os.Create(profileOutput)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				out = file
+			}
+			return call(cmd, "/pprof/"+args[0], params, out)
+		},
+	}
+	profileCmd.Flags().StringVarP(&profileOutput, "output", "o", "", "file to write the profile to (stdout by default)")
+	profileCmd.Flags().IntVar(&profileDebug, "debug", 0, "0 for the binary format, 1 or 2 for text")
+
+	var peers []string
+	reloadPeersCmd := &cobra.Command{
+		Use:   "reload-peers",
+		Short: "Reconnect to the bootstrap peers, optionally replacing them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return call(cmd, "/peers/reload", url.Values{"peer": peers}, os.Stdout)
+		},
+	}
+	reloadPeersCmd.Flags().StringArrayVar(&peers, "peer", nil, "new bootstrap peer, can be repeated")
+
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show the effective config of the node with secrets redacted",
+		RunE:  callFunc("/config"),
+	}
+
+	logLevelCmd := &cobra.Command{
+		Use:   "set-log-level [level]",
+		Short: "Change the log level of the node",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return call(cmd, "/set_log_level", url.Values{"level": args}, os.Stdout)
+		},
+	}
+
+	cmd.AddCommand(pauseCmd, resumeCmd, statusCmd, gcCmd, backupCmd, profileCmd, reloadPeersCmd, configCmd, logLevelCmd)
+	return cmd
+}
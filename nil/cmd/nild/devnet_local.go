@@ -0,0 +1,825 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/client"
+	rpc_client "github.com/NilFoundation/nil/nil/client/rpc"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/concurrent"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/cometa"
+	"github.com/NilFoundation/nil/nil/services/faucet"
+	"github.com/NilFoundation/nil/nil/services/nilservice"
+	"github.com/NilFoundation/nil/nil/services/rollup"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/proofprovider"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	devnetStateFile = "devnet.yaml"
+	devnetPidFile   = "devnet.pid"
+	devnetLogFile   = "devnet.log"
+
+	devnetStartTimeout = 2 * time.Minute
+	devnetStopTimeout  = time.Minute
+	devnetPollInterval = 200 * time.Millisecond
+
+	// localL1BlockPeriod is the block time of the in-process L1 chain
+	localL1BlockPeriod = 2 * time.Second
+	// localL1Endpoint is shown as the endpoint of the in-process L1 chain
+	localL1Endpoint = "in-process"
+	// fakeProofSkipRate is the proof provider skip rate (out of 10) which skips all the proofs
+	fakeProofSkipRate = 10
+)
+
+// localL1GenesisStateRoot is the proved state root the rollup contract of the in-process L1 chain starts with.
+// The fake contract only checks that each batch is finalized on top of the previous one, so any root works.
+var localL1GenesisStateRoot = common.Keccak256Hash([]byte("nil devnet genesis"))
+
+// devnetState is the layout of the local devnet started by "nild devnet up".
+// It is kept in the devnet directory, so the devnet is restarted with the same keys and databases.
+type devnetState struct {
+	Shards        uint32 `yaml:"shards"`
+	Validators    int    `yaml:"validators"`
+	Port          int    `yaml:"port"`
+	ValidatorPort int    `yaml:"validatorPort"`
+	P2pPort       int    `yaml:"p2pPort"`
+	SyncCommittee bool   `yaml:"syncCommittee"`
+	// L1Endpoint and L1ContractAddress are the L1 node and the rollup contract of the sync committee,
+	// the in-process L1 chain is used if the endpoint is empty
+	L1Endpoint        string `yaml:"l1Endpoint,omitempty"`
+	L1ContractAddress string `yaml:"l1ContractAddress,omitempty"`
+
+	Components []devnetComponent `yaml:"components"`
+}
+
+type devnetComponent struct {
+	Name     string `yaml:"name"`
+	Endpoint string `yaml:"endpoint"`
+	P2p      string `yaml:"p2p,omitempty"`
+}
+
+type devnetUpParams struct {
+	devnetState
+
+	collatorTickMs uint32
+	detach         bool
+}
+
+// LocalDevnetCommand returns the commands managing the devnet which runs all its components
+// in a single process: the validators, the RPC node, Cometa, the faucet and optionally the sync committee.
+func LocalDevnetCommand() *cobra.Command {
+	var dir string
+
+	cmd := &cobra.Command{
+		Use:   "devnet",
+		Short: "Run a local devnet",
+		// The node must not be started after the command is executed
+		PersistentPostRun: func(*cobra.Command, []string) {
+			os.Exit(0)
+		},
+	}
+	cmd.PersistentFlags().StringVar(&dir, "dir", "devnet", "directory with the keys, the databases and the logs of the devnet")
+
+	upCmd := devnetUpCommand(&dir)
+
+	downCmd := &cobra.Command{
+		Use:          "down",
+		Short:        "Stop the devnet started in the background",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return stopDevnet(dir)
+		},
+	}
+
+	resetCmd := &cobra.Command{
+		Use:          "reset",
+		Short:        "Stop the devnet and remove its directory",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resetDevnet(dir)
+		},
+	}
+
+	cmd.AddCommand(upCmd, downCmd, resetCmd)
+	return cmd
+}
+
+func devnetUpCommand(dir *string) *cobra.Command {
+	params := &devnetUpParams{}
+
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Start the devnet",
+		Long: "Start the validators, the RPC node, Cometa with the badger backend and the faucet, " +
+			"and optionally the sync committee with the fake proofs and the local L1, then print their endpoints. " +
+			"The devnet in the existing directory is restarted with its layout unless it is overridden by the flags.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDevnetUp(cmd, *dir, params)
+		},
+	}
+
+	params.addFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (p *devnetUpParams) addFlags(flags *pflag.FlagSet) {
+	flags.Uint32Var(&p.Shards, "shards", 3, "number of shards including the main shard")
+	flags.IntVar(&p.Validators, "validators", 1, "number of validators, each of them validates all shards")
+	flags.IntVar(&p.Port, "rpc-port", 8529,
+		"port of the RPC node; Cometa, the faucet, the sync committee and the proof provider use the port -1, -2, +1 and +2")
+	flags.IntVar(&p.ValidatorPort, "validator-port", 8540, "RPC port of the first validator, the next validators use the following ports")
+	flags.IntVar(&p.P2pPort, "p2p-port", 8600, "p2p port of the first validator, the next validators use the following ports")
+	flags.BoolVar(&p.SyncCommittee, "sync-committee", false, "run the sync committee with the proof provider which skips all proofs")
+	flags.Uint32Var(&p.collatorTickMs, "collator-tick-ms", 0, "collator tick period in milliseconds")
+	flags.StringVar(&p.L1Endpoint, "l1-endpoint", "",
+		"endpoint of the L1 node with the deployed rollup contract, the in-process L1 chain with a fake rollup contract is used by default")
+	flags.StringVar(&p.L1ContractAddress, "l1-contract-address", core.NewDefaultProposerParams().ContractAddress,
+		"L1 rollup contract address")
+	flags.BoolVarP(&p.detach, "detach", "d", false, "run the devnet in the background, stop it with \"nild devnet down\"")
+}
+
+func runDevnetUp(cmd *cobra.Command, dir string, params *devnetUpParams) error {
+	logLevel, err := cmd.Flags().GetString("log-level")
+	if err != nil {
+		return err
+	}
+	logging.SetupGlobalLogger(logLevel)
+	logging.ApplyComponentsFilter(logFilter)
+
+	if dir, err = filepath.Abs(dir); err != nil {
+		return fmt.Errorf("failed to get absolute path for the devnet directory: %w", err)
+	}
+	if pid, running := devnetPid(dir); running {
+		return fmt.Errorf("the devnet in %s is already running (pid %d)", dir, pid)
+	}
+	stored, err := readDevnetState(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if stored != nil {
+		if err := params.inherit(cmd.Flags(), stored); err != nil {
+			return err
+		}
+	}
+	if err := params.validate(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, directoryPermissions); err != nil {
+		return err
+	}
+	if params.detach {
+		return detachDevnet(dir)
+	}
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+
+	return runLocalDevnet(ctx, dir, params)
+}
+
+// inherit takes the layout of the existing devnet for the flags which are not set.
+// The number of shards and validators can't be changed since they are written to the zero state.
+func (p *devnetUpParams) inherit(flags *pflag.FlagSet, stored *devnetState) error {
+	if !flags.Changed("shards") {
+		p.Shards = stored.Shards
+	}
+	if !flags.Changed("validators") {
+		p.Validators = stored.Validators
+	}
+	if !flags.Changed("rpc-port") {
+		p.Port = stored.Port
+	}
+	if !flags.Changed("validator-port") {
+		p.ValidatorPort = stored.ValidatorPort
+	}
+	if !flags.Changed("p2p-port") {
+		p.P2pPort = stored.P2pPort
+	}
+	if !flags.Changed("sync-committee") {
+		p.SyncCommittee = stored.SyncCommittee
+	}
+	if !flags.Changed("l1-endpoint") {
+		p.L1Endpoint = stored.L1Endpoint
+	}
+	if !flags.Changed("l1-contract-address") && stored.L1ContractAddress != "" {
+		p.L1ContractAddress = stored.L1ContractAddress
+	}
+
+	if p.Shards != stored.Shards || p.Validators != stored.Validators {
+		return fmt.Errorf("the devnet has %d shards and %d validators, run \"nild devnet reset\" to change them",
+			stored.Shards, stored.Validators)
+	}
+	return nil
+}
+
+func (p *devnetUpParams) validate() error {
+	if p.Shards < 2 {
+		return errors.New("the devnet needs at least 2 shards (main shard + 1)")
+	}
+	if p.Validators < 1 {
+		return errors.New("the devnet needs at least 1 validator")
+	}
+	ports := map[int]string{}
+	use := func(port int, name string) error {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("invalid port %d of %s", port, name)
+		}
+		if other, ok := ports[port]; ok {
+			return fmt.Errorf("port %d is used by both %s and %s", port, other, name)
+		}
+		ports[port] = name
+		return nil
+	}
+
+	for name, port := range p.servicePorts() {
+		if err := use(port, name); err != nil {
+			return err
+		}
+	}
+	for i := range p.Validators {
+		name := fmt.Sprintf("validator-%d", i)
+		if err := use(p.ValidatorPort+i, name); err != nil {
+			return err
+		}
+		if err := use(p.P2pPort+i, name+" (p2p)"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// servicePorts returns the ports of the components except the validators.
+func (p *devnetUpParams) servicePorts() map[string]int {
+	ports := map[string]int{
+		"rpc":    p.Port,
+		"cometa": p.Port - 1,
+		"faucet": p.Port - 2,
+	}
+	if p.SyncCommittee {
+		ports["sync-committee"] = p.Port + 1
+		ports["proof-provider"] = p.Port + 2
+	}
+	return ports
+}
+
+func localEndpoint(port int) string {
+	return fmt.Sprintf("tcp://127.0.0.1:%d", port)
+}
+
+func httpEndpoint(port int) string {
+	return fmt.Sprintf("http://127.0.0.1:%d", port)
+}
+
+// localDevnet holds the components of the running devnet.
+type localDevnet struct {
+	dir    string
+	params *devnetUpParams
+	logger zerolog.Logger
+
+	group     *errgroup.Group
+	ctx       context.Context
+	databases []db.DB
+}
+
+func runLocalDevnet(ctx context.Context, dir string, params *devnetUpParams) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	d := &localDevnet{
+		dir:    dir,
+		params: params,
+		logger: logging.NewLogger("devnet"),
+		group:  group,
+		ctx:    groupCtx,
+	}
+	defer d.closeDatabases()
+
+	state, err := d.start()
+	if err != nil {
+		// Stop the components started so far
+		cancel()
+		_ = group.Wait()
+		return err
+	}
+	group.Go(func() error {
+		return d.announce(state)
+	})
+
+	err = group.Wait()
+	d.removePid()
+	// The components may fail on the shutdown caused by a signal
+	if err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+func (d *localDevnet) start() (*devnetState, error) {
+	p := d.params
+
+	nodeSpecs := make([]nodeSpec, p.Validators)
+	for i := range nodeSpecs {
+		nodeSpecs[i].ID = i
+		nodeSpecs[i].Shards = make([]uint, p.Shards)
+		for shard := range nodeSpecs[i].Shards {
+			nodeSpecs[i].Shards[shard] = uint(shard)
+		}
+	}
+	spec := &devnetSpec{NildCredentialsDir: d.dir, NShards: p.Shards}
+	validators, err := spec.makeServers(nodeSpecs, p.P2pPort, 0, p.ValidatorPort, "validator", d.dir, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup validators: %w", err)
+	}
+	zeroState, err := devnet{spec: spec}.generateZeroState(p.Shards, validators)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate zero state: %w", err)
+	}
+
+	// The state is written before the components are started, so "reset" cleans up after a failed start too
+	state := d.state(validators)
+	if err := writeDevnetState(d.dir, state); err != nil {
+		return nil, err
+	}
+
+	var l1Fetcher rollup.L1BlockFetcher = noL1Fetcher{}
+	var ethClient l1Client
+	if p.SyncCommittee {
+		if ethClient, err = d.startL1(); err != nil {
+			return nil, err
+		}
+		l1Fetcher = &l1HeaderFetcher{client: ethClient}
+	}
+
+	for i, srv := range validators {
+		cfg := nilservice.NewDefaultConfig()
+		cfg.NShards = p.Shards
+		cfg.RPCPort = srv.rpcPort
+		cfg.MainKeysPath = filepath.Join(d.dir, "keys.yaml")
+		cfg.NetworkKeysPath = srv.NetworkKeysFile()
+		cfg.ValidatorKeysPath = validatorKeysFile(srv.credsDir)
+		cfg.Network.TcpPort = srv.port
+		cfg.Network.DHTEnabled = true
+		for j, other := range validators {
+			if j != i {
+				cfg.Network.DHTBootstrapPeers = append(cfg.Network.DHTBootstrapPeers, getPeer(other))
+			}
+		}
+		cfg.ZeroState = zeroState
+		cfg.CollatorTickPeriodMs = p.collatorTickMs
+		cfg.L1Fetcher = l1Fetcher
+		if err := d.startNode(srv.name, cfg, filepath.Join(srv.workDir, "database")); err != nil {
+			return nil, err
+		}
+	}
+
+	rpcCfg := nilservice.NewDefaultConfig()
+	rpcCfg.RunMode = nilservice.RpcRunMode
+	rpcCfg.NShards = p.Shards
+	rpcCfg.RPCPort = p.Port
+	rpcCfg.Network.DHTEnabled = true
+	for _, srv := range validators {
+		rpcCfg.Network.DHTBootstrapPeers = append(rpcCfg.Network.DHTBootstrapPeers, getPeer(srv))
+	}
+	rpcCfg.ZeroState = zeroState
+	if err := d.startNode("rpc", rpcCfg, filepath.Join(d.dir, "rpc", "database")); err != nil {
+		return nil, err
+	}
+
+	nodeClient := rpc_client.NewClient(localEndpoint(p.Port), d.logger)
+
+	if err := d.startCometa(nodeClient); err != nil {
+		return nil, err
+	}
+
+	faucetService, err := faucet.NewService(nodeClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create faucet service: %w", err)
+	}
+	d.group.Go(func() error {
+		return faucetService.Run(d.ctx, localEndpoint(p.Port-2))
+	})
+
+	if p.SyncCommittee {
+		if err := d.startSyncCommittee(ethClient); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+// state returns the layout of the devnet with the endpoints of its components.
+func (d *localDevnet) state(validators []server) *devnetState {
+	p := d.params
+	state := &devnetState{
+		Shards:        p.Shards,
+		Validators:    p.Validators,
+		Port:          p.Port,
+		ValidatorPort: p.ValidatorPort,
+		P2pPort:       p.P2pPort,
+		SyncCommittee: p.SyncCommittee,
+	}
+	if p.SyncCommittee {
+		state.L1Endpoint = p.L1Endpoint
+		state.L1ContractAddress = p.L1ContractAddress
+	}
+
+	for _, srv := range validators {
+		state.Components = append(state.Components, devnetComponent{
+			Name:     srv.name,
+			Endpoint: httpEndpoint(srv.rpcPort),
+			P2p:      identityToAddress(srv.port, srv.identity),
+		})
+	}
+	state.Components = append(state.Components,
+		devnetComponent{Name: "rpc", Endpoint: httpEndpoint(p.Port)},
+		devnetComponent{Name: "cometa", Endpoint: httpEndpoint(p.Port - 1)},
+		devnetComponent{Name: "faucet", Endpoint: httpEndpoint(p.Port - 2)},
+	)
+
+	if p.SyncCommittee {
+		l1Endpoint := p.L1Endpoint
+		if l1Endpoint == "" {
+			l1Endpoint = localL1Endpoint
+		}
+		state.Components = append(state.Components,
+			devnetComponent{Name: "sync-committee", Endpoint: httpEndpoint(p.Port + 1)},
+			devnetComponent{Name: "proof-provider", Endpoint: httpEndpoint(p.Port + 2)},
+			devnetComponent{Name: "l1", Endpoint: l1Endpoint},
+		)
+	}
+	return state
+}
+
+// openDb opens the database of the component, the outdated database is cleared.
+func (d *localDevnet) openDb(path string) (db.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), directoryPermissions); err != nil {
+		return nil, err
+	}
+	database, err := openDb(path, true, d.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	d.databases = append(d.databases, database)
+	return database, nil
+}
+
+func (d *localDevnet) closeDatabases() {
+	for _, database := range d.databases {
+		database.Close()
+	}
+}
+
+func (d *localDevnet) startNode(name string, cfg *nilservice.Config, dbPath string) error {
+	database, err := d.openDb(dbPath)
+	if err != nil {
+		return err
+	}
+	node, err := nilservice.CreateNode(d.ctx, name, cfg, database, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	d.group.Go(func() error {
+		defer node.Close(d.ctx)
+		return node.Run()
+	})
+	return nil
+}
+
+func (d *localDevnet) startCometa(nodeClient client.Client) error {
+	cfg := &cometa.Config{}
+	cfg.ResetToDefault()
+	cfg.UseBadger = true
+	cfg.DbPath = filepath.Join(d.dir, "cometa", "database")
+	cfg.OwnEndpoint = localEndpoint(d.params.Port - 1)
+	cfg.NodeEndpoint = httpEndpoint(d.params.Port)
+	if err := os.MkdirAll(filepath.Dir(cfg.DbPath), directoryPermissions); err != nil {
+		return err
+	}
+
+	service, err := cometa.NewService(d.ctx, cfg, nodeClient)
+	if err != nil {
+		return fmt.Errorf("failed to create cometa service: %w", err)
+	}
+	d.group.Go(func() error {
+		return service.Run(d.ctx, cfg)
+	})
+	return nil
+}
+
+// l1Client is the L1 client of the sync committee, it also provides the last L1 block to the validators.
+type l1Client interface {
+	bind.ContractBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+	TransactionReceipt(ctx context.Context, txHash ethcommon.Hash) (*ethtypes.Receipt, error)
+	NonceAt(ctx context.Context, account ethcommon.Address, blockNumber *big.Int) (uint64, error)
+}
+
+// startL1 connects to the L1 node given by the flag or starts the in-process L1 chain
+// with the fake rollup contract and the funded account of the proposer.
+func (d *localDevnet) startL1() (l1Client, error) {
+	if d.params.L1Endpoint == "" {
+		params := core.NewDefaultProposerParams()
+		params.ContractAddress = d.params.L1ContractAddress
+		l1, err := core.NewLocalL1(params, localL1GenesisStateRoot, localL1BlockPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the local L1 chain: %w", err)
+		}
+		d.group.Go(func() error {
+			return l1.Run(d.ctx)
+		})
+		return l1, nil
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, core.NewDefaultProposerParams().EthClientTimeout)
+	defer cancel()
+	ethClient, err := ethclient.DialContext(ctx, d.params.L1Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to ETH RPC node: %w", err)
+	}
+	return ethClient, nil
+}
+
+func (d *localDevnet) startSyncCommittee(ethClient l1Client) error {
+	p := d.params
+
+	cfg := core.NewDefaultConfig()
+	cfg.RpcEndpoint = localEndpoint(p.Port)
+	cfg.TaskListenerRpcEndpoint = localEndpoint(p.Port + 1)
+	cfg.ProposerParams.Endpoint = p.L1Endpoint
+	cfg.ProposerParams.ContractAddress = p.L1ContractAddress
+
+	database, err := d.openComponentDb("sync-committee")
+	if err != nil {
+		return err
+	}
+	syncCommittee, err := core.New(cfg, database, ethClient)
+	if err != nil {
+		return fmt.Errorf("can't create sync committee service: %w", err)
+	}
+	d.group.Go(func() error {
+		return syncCommittee.Run(d.ctx)
+	})
+
+	providerCfg := proofprovider.NewDefaultConfig()
+	providerCfg.SyncCommitteeRpcEndpoint = cfg.TaskListenerRpcEndpoint
+	providerCfg.TaskListenerRpcEndpoint = localEndpoint(p.Port + 2)
+	providerCfg.SkipRate = fakeProofSkipRate
+
+	if database, err = d.openComponentDb("proof-provider"); err != nil {
+		return err
+	}
+	proofProvider, err := proofprovider.New(providerCfg, database)
+	if err != nil {
+		return fmt.Errorf("can't create proof provider service: %w", err)
+	}
+	d.group.Go(func() error {
+		return proofProvider.Run(d.ctx)
+	})
+	return nil
+}
+
+// openComponentDb opens the database of the sync committee service, it has no schema version.
+func (d *localDevnet) openComponentDb(name string) (db.DB, error) {
+	path := filepath.Join(d.dir, name, "database")
+	if err := os.MkdirAll(filepath.Dir(path), directoryPermissions); err != nil {
+		return nil, err
+	}
+	database, err := db.NewBadgerDb(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	d.databases = append(d.databases, database)
+	return database, nil
+}
+
+// announce waits until the blocks of all shards are available via the RPC node,
+// then writes the pid file and prints the endpoints.
+func (d *localDevnet) announce(state *devnetState) error {
+	nodeClient := rpc_client.NewClient(localEndpoint(d.params.Port), d.logger)
+	for shardId := range types.ShardId(d.params.Shards) {
+		_, err := concurrent.WaitFor(d.ctx, devnetStartTimeout, devnetPollInterval,
+			func(ctx context.Context) (*jsonrpc.RPCBlock, error) {
+				// The errors are expected until the RPC node connects to the validators
+				block, _ := nodeClient.GetBlock(ctx, shardId, "latest", false)
+				return block, nil
+			})
+		if err != nil {
+			return fmt.Errorf("shard %d is not available via the RPC node: %w", shardId, err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(d.dir, devnetPidFile), []byte(strconv.Itoa(os.Getpid())), filePermissions); err != nil {
+		return err
+	}
+	printDevnet(d.dir, state)
+	return nil
+}
+
+func (d *localDevnet) removePid() {
+	if pid, err := readDevnetPid(d.dir); err == nil && pid == os.Getpid() {
+		if err := os.Remove(filepath.Join(d.dir, devnetPidFile)); err != nil {
+			d.logger.Error().Err(err).Msg("Failed to remove the pid file")
+		}
+	}
+}
+
+func printDevnet(dir string, state *devnetState) {
+	fmt.Printf("Devnet with %d shards and %d validators is running in %s\n", state.Shards, state.Validators, dir)
+	for _, c := range state.Components {
+		if c.P2p != "" {
+			fmt.Printf("  %-16s %-24s p2p %s\n", c.Name, c.Endpoint, c.P2p)
+		} else {
+			fmt.Printf("  %-16s %s\n", c.Name, c.Endpoint)
+		}
+	}
+	fmt.Printf("Main keys: %s\n", filepath.Join(dir, "keys.yaml"))
+}
+
+// detachDevnet restarts the command in the background with the output written to the log file,
+// and waits until the devnet is up.
+func detachDevnet(dir string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := slices.DeleteFunc(slices.Clone(os.Args[1:]), func(arg string) bool {
+		return arg == "-d" || arg == "--detach" || strings.HasPrefix(arg, "--detach=")
+	})
+
+	logPath := filepath.Join(dir, devnetLogFile)
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePermissions)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	child := exec.Command(executable, args...)
+	child.Stdout = logFile
+	child.Stderr = logFile
+	child.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("failed to start the devnet: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- child.Wait()
+	}()
+
+	timeout := time.After(devnetStartTimeout)
+	ticker := time.NewTicker(devnetPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-exited:
+			return fmt.Errorf("the devnet has stopped (%v), see %s", err, logPath)
+		case <-timeout:
+			return fmt.Errorf("the devnet has not started in %s, see %s", devnetStartTimeout, logPath)
+		case <-ticker.C:
+			if pid, err := readDevnetPid(dir); err == nil && pid == child.Process.Pid {
+				state, err := readDevnetState(dir)
+				if err != nil {
+					return err
+				}
+				printDevnet(dir, state)
+				fmt.Printf("Logs: %s\n", logPath)
+				return nil
+			}
+		}
+	}
+}
+
+func stopDevnet(dir string) error {
+	pid, running := devnetPid(dir)
+	if running {
+		if err := syscall.Kill(pid, syscall.SIGTERM); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return fmt.Errorf("failed to stop the devnet (pid %d): %w", pid, err)
+		}
+		deadline := time.Now().Add(devnetStopTimeout)
+		for processExists(pid) {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("the devnet (pid %d) has not stopped in %s", pid, devnetStopTimeout)
+			}
+			time.Sleep(devnetPollInterval)
+		}
+		fmt.Printf("Devnet in %s is stopped\n", dir)
+	} else {
+		fmt.Printf("Devnet in %s is not running\n", dir)
+	}
+
+	if err := os.Remove(filepath.Join(dir, devnetPidFile)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func resetDevnet(dir string) error {
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	// Refuse to remove the directory which wasn't created by "nild devnet up"
+	if _, err := os.Stat(filepath.Join(dir, devnetStateFile)); err != nil {
+		return fmt.Errorf("%s is not a devnet directory: %w", dir, err)
+	}
+
+	if err := stopDevnet(dir); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	fmt.Printf("Devnet in %s is removed\n", dir)
+	return nil
+}
+
+// devnetPid returns the pid of the devnet process and whether it's running.
+func devnetPid(dir string) (int, bool) {
+	pid, err := readDevnetPid(dir)
+	if err != nil {
+		return 0, false
+	}
+	return pid, processExists(pid)
+}
+
+func readDevnetPid(dir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, devnetPidFile))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+func processExists(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+func readDevnetState(dir string) (*devnetState, error) {
+	data, err := os.ReadFile(filepath.Join(dir, devnetStateFile))
+	if err != nil {
+		return nil, err
+	}
+	state := &devnetState{}
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("can't parse devnet state: %w", err)
+	}
+	return state, nil
+}
+
+func writeDevnetState(dir string, state *devnetState) error {
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, devnetStateFile), data, filePermissions)
+}
+
+// noL1Fetcher is used without the sync committee, the blocks don't refer to L1 then.
+type noL1Fetcher struct{}
+
+func (noL1Fetcher) GetLastBlockInfo(context.Context) (*ethtypes.Header, error) {
+	return nil, nil
+}
+
+// l1HeaderFetcher provides the main shard with the last block of the L1 used by the sync committee.
+type l1HeaderFetcher struct {
+	client l1Client
+}
+
+func (f *l1HeaderFetcher) GetLastBlockInfo(ctx context.Context) (*ethtypes.Header, error) {
+	return f.client.HeaderByNumber(ctx, nil)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func parseDevnetUpFlags(t *testing.T, args ...string) (*devnetUpParams, *pflag.FlagSet) {
+	t.Helper()
+
+	params := &devnetUpParams{}
+	flags := pflag.NewFlagSet("up", pflag.ContinueOnError)
+	params.addFlags(flags)
+	require.NoError(t, flags.Parse(args))
+	return params, flags
+}
+
+func TestDevnetUpValidate(t *testing.T) {
+	t.Parallel()
+
+	params, _ := parseDevnetUpFlags(t)
+	require.NoError(t, params.validate())
+
+	params, _ = parseDevnetUpFlags(t, "--sync-committee")
+	require.NoError(t, params.validate(), "the sync committee uses the in-process L1 chain by default")
+
+	params, _ = parseDevnetUpFlags(t, "--shards", "1")
+	require.ErrorContains(t, params.validate(), "at least 2 shards")
+
+	params, _ = parseDevnetUpFlags(t, "--validators", "0")
+	require.ErrorContains(t, params.validate(), "at least 1 validator")
+
+	params, _ = parseDevnetUpFlags(t, "--rpc-port", "8540")
+	require.ErrorContains(t, params.validate(), "port 8540 is used by both")
+
+	params, _ = parseDevnetUpFlags(t, "--validators", "2", "--p2p-port", "8541")
+	require.ErrorContains(t, params.validate(), "port 8541 is used by both")
+
+	params, _ = parseDevnetUpFlags(t, "--sync-committee", "--validator-port", "8530")
+	require.ErrorContains(t, params.validate(), "port 8530 is used by both")
+
+	params, _ = parseDevnetUpFlags(t, "--rpc-port", "1")
+	require.ErrorContains(t, params.validate(), "invalid port")
+}
+
+func TestDevnetUpInherit(t *testing.T) {
+	t.Parallel()
+
+	stored := &devnetState{
+		Shards:            4,
+		Validators:        2,
+		Port:              9529,
+		ValidatorPort:     9540,
+		P2pPort:           9600,
+		SyncCommittee:     true,
+		L1Endpoint:        "http://127.0.0.1:8545",
+		L1ContractAddress: "0x0000000000000000000000000000000000000001",
+	}
+
+	params, flags := parseDevnetUpFlags(t)
+	require.NoError(t, params.inherit(flags, stored))
+	require.Equal(t, *stored, params.devnetState)
+
+	params, flags = parseDevnetUpFlags(t, "--rpc-port", "7529", "--sync-committee=false", "--l1-endpoint", "")
+	require.NoError(t, params.inherit(flags, stored))
+	require.Equal(t, 7529, params.Port)
+	require.Equal(t, stored.ValidatorPort, params.ValidatorPort)
+	require.False(t, params.SyncCommittee)
+	require.Empty(t, params.L1Endpoint, "the in-process L1 chain is used instead of the stored endpoint")
+
+	params, flags = parseDevnetUpFlags(t, "--shards", "3")
+	require.ErrorContains(t, params.inherit(flags, stored), "nild devnet reset")
+
+	params, flags = parseDevnetUpFlags(t, "--validators", "1")
+	require.ErrorContains(t, params.inherit(flags, stored), "nild devnet reset")
+}
+
+func TestDevnetStateRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	params, _ := parseDevnetUpFlags(t, "--sync-committee")
+	d := &localDevnet{dir: dir, params: params}
+
+	state := d.state(nil)
+	require.Contains(t, state.Components, devnetComponent{Name: "l1", Endpoint: localL1Endpoint})
+	require.Contains(t, state.Components, devnetComponent{Name: "rpc", Endpoint: httpEndpoint(params.Port)})
+
+	require.NoError(t, writeDevnetState(dir, state))
+	read, err := readDevnetState(dir)
+	require.NoError(t, err)
+	require.Equal(t, state, read)
+
+	restarted, flags := parseDevnetUpFlags(t)
+	require.NoError(t, restarted.inherit(flags, read))
+	require.Equal(t, params.devnetState, restarted.devnetState)
+}
@@ -106,6 +106,7 @@ func addNetworkFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
 
 func addTelemetryFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
 	fset.BoolVar(&cfg.Telemetry.ExportMetrics, "metrics", cfg.Telemetry.ExportMetrics, "export metrics via grpc")
+	fset.StringVar(&cfg.Telemetry.PrometheusEndpoint, "metrics-endpoint", cfg.Telemetry.PrometheusEndpoint, "address to serve metrics for Prometheus scraping at /metrics, e.g. localhost:9091")
 }
 
 func addAllowDbClearFlag(fset *pflag.FlagSet, cfg *nildconfig.Config) {
@@ -116,10 +117,28 @@ func addRpcNodeFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
 	fset.Var(&cfg.RpcNode.ArchiveNodeList, "archive-nodes", "list of archive nodes")
 }
 
+func addRpcAccessFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
+	access := &cfg.RpcAccess
+	fset.StringSliceVar(&access.Namespaces, "rpc-namespaces", access.Namespaces, "served API namespaces (all public namespaces by default)")
+	fset.StringSliceVar(&access.DisabledNamespaces, "rpc-disabled-namespaces", access.DisabledNamespaces, "API namespaces which are not served, e.g. db,debug")
+	fset.StringSliceVar(&access.AllowedMethods, "rpc-allowed-methods", access.AllowedMethods, "served API methods (all methods of the served namespaces by default)")
+	fset.StringSliceVar(&access.DisabledMethods, "rpc-disabled-methods", access.DisabledMethods, "API methods which are not served")
+	fset.IntVar(&access.MaxBatchSize, "rpc-max-batch-size", access.MaxBatchSize, "maximum number of requests in a batch (unlimited if 0)")
+	fset.IntVar(&access.MaxResponseSize, "rpc-max-response-size", access.MaxResponseSize, "maximum size of a response in bytes (unlimited if 0)")
+	fset.Float64Var(&access.RateLimit.Rate, "rpc-rate-limit", access.RateLimit.Rate, "requests per second allowed to an IP address without API key (unlimited if 0)")
+	fset.IntVar(&access.RateLimit.Burst, "rpc-rate-burst", access.RateLimit.Burst, "requests which an IP address without API key can send at once")
+	fset.BoolVar(&access.RequireAPIKey, "rpc-require-api-key", access.RequireAPIKey, "reject requests without a valid API key (the keys are set in the config file)")
+	fset.StringVar(&access.ForwardedForHeader, "rpc-forwarded-for-header", access.ForwardedForHeader, "header with the client IP address set by a trusted reverse proxy, e.g. X-Forwarded-For")
+	fset.IntVar(&access.TrustedProxies, "rpc-trusted-proxies", access.TrustedProxies, "number of trusted reverse proxies appending to the forwarded-for header (1 if 0)")
+}
+
 func addBasicFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
 	fset.UintSliceVar(&cfg.MyShards, "my-shards", cfg.MyShards, "run only specified shard(s)")
 	addAllowDbClearFlag(fset, cfg)
 	fset.Uint32Var(&cfg.CollatorTickPeriodMs, "collator-tick-ms", cfg.CollatorTickPeriodMs, "collator tick period in milliseconds")
+	fset.BoolVar(&cfg.RecordStateDiff, "record-state-diff", cfg.RecordStateDiff, "store the state diff of every block (served by debug_getStateDiff)")
+	fset.BoolVar(&cfg.RecordResourceUsage, "record-resource-usage", cfg.RecordResourceUsage, "store the gas and storage used by the contracts in every block (served by debug_getResourceUsage)")
+	fset.BoolVar(&cfg.ParallelExecution, "parallel-execution", cfg.ParallelExecution, "execute the transactions of a block in parallel (not applied to the main shard)")
 }
 
 func parseArgs() *nildconfig.Config {
@@ -162,10 +181,17 @@ func parseArgs() *nildconfig.Config {
 	runCmd.Flags().BoolVar(&cfg.SplitShards, "split-shards", cfg.SplitShards, "run each shard in separate process")
 	runCmd.Flags().StringVar(&cfg.CometaConfig, "cometa-config", "", "path to Cometa config")
 	runCmd.Flags().StringVar(&cfg.ValidatorKeysPath, "validator-keys-path", cfg.ValidatorKeysPath, "path to write validator keys")
+	runCmd.Flags().StringVar(&cfg.RemoteSigner, "remote-signer", cfg.RemoteSigner,
+		"endpoint of the signer holding the validator key (unix:///path/to/socket, tcp://host:port or HTTPS URL)")
+	runCmd.Flags().StringVar(&cfg.RemoteSignerTransport.AuthTokenFile, "remote-signer-token-file",
+		cfg.RemoteSignerTransport.AuthTokenFile, "file with the auth token of the remote signer")
+	runCmd.Flags().StringVar(&cfg.RemoteSignerTransport.TLSCAFile, "remote-signer-ca",
+		cfg.RemoteSignerTransport.TLSCAFile, "certificate authority of the remote signer")
 
 	addBasicFlags(runCmd.Flags(), cfg)
 	addNetworkFlags(runCmd.Flags(), cfg)
 	addTelemetryFlags(runCmd.Flags(), cfg)
+	addRpcAccessFlags(runCmd.Flags(), cfg)
 
 	replayCmd := &cobra.Command{
 		Use:   "replay-block",
@@ -189,6 +215,7 @@ func parseArgs() *nildconfig.Config {
 	addBasicFlags(archiveCmd.Flags(), cfg)
 	addNetworkFlags(archiveCmd.Flags(), cfg)
 	addTelemetryFlags(archiveCmd.Flags(), cfg)
+	addRpcAccessFlags(archiveCmd.Flags(), cfg)
 
 	rpcCmd := &cobra.Command{
 		Use:   "rpc",
@@ -202,6 +229,7 @@ func parseArgs() *nildconfig.Config {
 	addAllowDbClearFlag(rpcCmd.Flags(), cfg)
 	addNetworkFlags(rpcCmd.Flags(), cfg)
 	addTelemetryFlags(rpcCmd.Flags(), cfg)
+	addRpcAccessFlags(rpcCmd.Flags(), cfg)
 
 	versionCmd := &cobra.Command{
 		Use:   "version",
@@ -213,8 +241,11 @@ func parseArgs() *nildconfig.Config {
 	}
 
 	devnetCmd := DevnetCommand()
+	localDevnetCmd := LocalDevnetCommand()
+	adminCmd := AdminCommand(cfg)
+	verifyChainCmd := VerifyChainCommand(cfg)
 
-	rootCmd.AddCommand(runCmd, replayCmd, archiveCmd, rpcCmd, devnetCmd, versionCmd)
+	rootCmd.AddCommand(runCmd, replayCmd, archiveCmd, rpcCmd, devnetCmd, localDevnetCmd, adminCmd, verifyChainCmd, versionCmd)
 
 	f := rootCmd.HelpFunc()
 	rootCmd.SetHelpFunc(func(c *cobra.Command, s []string) {
@@ -222,7 +253,13 @@ func parseArgs() *nildconfig.Config {
 		os.Exit(0)
 	})
 
-	check.PanicIfErr(rootCmd.Execute())
+	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+		check.PanicIfErr(err)
+	}
 
 	logging.SetupGlobalLogger(*logLevel)
 
@@ -260,6 +297,13 @@ func openDb(dbPath string, allowDrop bool, logger zerolog.Logger) (db.DB, error)
 		return nil, err
 	}
 
+	if dbExists {
+		if err := migrateDb(badger, logger); err != nil {
+			badger.Close()
+			return nil, err
+		}
+	}
+
 	tx, err := badger.CreateRwTx(context.Background())
 	if err != nil {
 		return nil, err
@@ -274,7 +318,8 @@ func openDb(dbPath string, allowDrop bool, logger zerolog.Logger) (db.DB, error)
 
 	if isVersionOutdated {
 		if !allowDrop {
-			return nil, errors.New("database schema is outdated; remove database or use --allow-db-clear")
+			return nil, errors.New("database schema is outdated and there is no migration for it; " +
+				"remove database or use --allow-db-clear")
 		}
 
 		logger.Info().Msg("Clearing database from old data...")
@@ -287,6 +332,9 @@ func openDb(dbPath string, allowDrop bool, logger zerolog.Logger) (db.DB, error)
 		if err := db.WriteVersionInfo(tx, types.NewVersionInfo()); err != nil {
 			return nil, err
 		}
+		if err := db.WriteSchemaVersion(tx, db.LatestSchemaVersion()); err != nil {
+			return nil, err
+		}
 		if err := tx.Commit(); err != nil {
 			return nil, err
 		}
@@ -294,3 +342,20 @@ func openDb(dbPath string, allowDrop bool, logger zerolog.Logger) (db.DB, error)
 
 	return badger, nil
 }
+
+// migrateDb applies the schema migrations which are missing in the existing database
+func migrateDb(database db.DB, logger zerolog.Logger) error {
+	migrator, err := db.NewMigrator(database, db.SchemaMigrations, logger)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Run(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	if applied > 0 {
+		logger.Info().Int("migrations", applied).Msg("Database is migrated to the latest schema")
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/NilFoundation/nil/nil/cmd/nild/nildconfig"
+	"github.com/NilFoundation/nil/nil/internal/collate"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	verifyChainExitDiverged = 1
+	verifyChainExitFailed   = 2
+)
+
+var errChainDiverged = errors.New("re-executed blocks diverge from the stored ones")
+
+// exitError makes nild exit with the code instead of failing on the error of the command.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// VerifyChainCommand re-executes the stored blocks and reports the first block of every shard
+// which doesn't match its re-execution. The node must be stopped: the database is opened read-only,
+// the re-executed blocks are kept in memory.
+func VerifyChainCommand(cfg *nildconfig.Config) *cobra.Command {
+	var shards []uint
+	var from, to uint64
+	var asJson bool
+
+	cmd := &cobra.Command{
+		Use:   "verify-chain",
+		Short: "Re-execute stored blocks and compare the results with the stored blocks",
+		Long: "Re-execute stored blocks and compare the results with the stored blocks. " +
+			"Exits with code 1 if any block diverges and with code 2 if the verification fails.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results, err := verifyChain(cmd.Context(), cfg.DB.Path, shards,
+				types.BlockNumber(from), types.BlockNumber(to))
+			if err != nil {
+				return &exitError{code: verifyChainExitFailed, err: err}
+			}
+
+			diverged := false
+			for _, res := range results {
+				diverged = diverged || res.Divergence != nil
+			}
+			if asJson {
+				data, err := json.MarshalIndent(results, "", "  ")
+				if err != nil {
+					return &exitError{code: verifyChainExitFailed, err: err}
+				}
+				fmt.Println(string(data))
+			} else {
+				printVerificationResults(results)
+			}
+
+			if diverged {
+				return &exitError{code: verifyChainExitDiverged, err: errChainDiverged}
+			}
+			return nil
+		},
+		// The node must not be started after the command is executed
+		PostRun: func(*cobra.Command, []string) {
+			os.Exit(0)
+		},
+	}
+	cmd.Flags().UintSliceVar(&shards, "shard", nil, "shard(s) to verify, all shards by default; shards are verified in parallel")
+	cmd.Flags().Uint64Var(&from, "from", 1, "first block to verify")
+	cmd.Flags().Uint64Var(&to, "to", 0, "last block to verify, the last block of the shard by default")
+	cmd.Flags().BoolVar(&asJson, "json", false, "print the results in JSON")
+	return cmd
+}
+
+func verifyChain(
+	ctx context.Context, dbPath string, shards []uint, from, to types.BlockNumber,
+) ([]*collate.ChainVerificationResult, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	database, err := db.NewReadOnlyBadgerDb(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	nShards, err := checkVerifiedDb(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	shardIds := make([]types.ShardId, 0, nShards)
+	if len(shards) == 0 {
+		for i := range nShards {
+			shardIds = append(shardIds, types.ShardId(i))
+		}
+	}
+	for _, shard := range shards {
+		if uint32(shard) >= nShards {
+			return nil, fmt.Errorf("shard %d doesn't exist, the network has %d shards", shard, nShards)
+		}
+		shardIds = append(shardIds, types.ShardId(shard))
+	}
+
+	results := make([]*collate.ChainVerificationResult, len(shardIds))
+	eg, ctx := errgroup.WithContext(ctx)
+	for i, shardId := range shardIds {
+		eg.Go(func() error {
+			verifier := collate.NewChainVerifier(database, collate.ChainVerifierParams{
+				BlockGeneratorParams: execution.NewBlockGeneratorParams(shardId, nShards),
+				FirstBlock:           from,
+				LastBlock:            to,
+			})
+			var err error
+			results[i], err = verifier.Run(ctx)
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// checkVerifiedDb checks that the database can be verified by this binary and returns the number of shards.
+func checkVerifiedDb(ctx context.Context, database db.DB) (uint32, error) {
+	tx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	outdated, err := db.IsVersionOutdated(tx)
+	if err != nil {
+		return 0, err
+	}
+	if outdated {
+		return 0, errors.New("database schema is outdated; start the node once to migrate it")
+	}
+
+	configAccessor, err := config.NewConfigAccessorTx(tx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return config.GetParamNShards(configAccessor)
+}
+
+func printVerificationResults(results []*collate.ChainVerificationResult) {
+	for _, res := range results {
+		if res.Divergence == nil {
+			fmt.Printf("shard %d: blocks [%d - %d] match\n", res.ShardId, res.FirstBlock, res.LastBlock)
+			continue
+		}
+
+		d := res.Divergence
+		fmt.Printf("shard %d: block %d (%s) diverges after %d matching blocks\n",
+			res.ShardId, d.BlockId, d.BlockHash, res.Verified)
+		for _, m := range d.Mismatches {
+			fmt.Printf("  %s: expected %s, got %s\n", m.Field, m.Expected, m.Actual)
+		}
+		for _, acc := range d.Accounts {
+			fmt.Printf("  account %s:\n", acc.Address)
+			fmt.Printf("    expected: %s\n", formatAccount(acc.Expected))
+			fmt.Printf("    actual:   %s\n", formatAccount(acc.Actual))
+		}
+	}
+}
+
+func formatAccount(contract *types.SmartContract) string {
+	if contract == nil {
+		return "<not exists>"
+	}
+	return fmt.Sprintf("balance=%s seqno=%d extSeqno=%d storageRoot=%s tokenRoot=%s codeHash=%s",
+		contract.Balance, contract.Seqno, contract.ExtSeqno, contract.StorageRoot, contract.TokenRoot, contract.CodeHash)
+}
@@ -54,6 +54,7 @@ func addFlags(cmd *cobra.Command, cfg *cmdConfig) {
 	cmd.Flags().StringVar(&cfg.TaskListenerRpcEndpoint, "own-endpoint", cfg.TaskListenerRpcEndpoint, "own rpc server endpoint")
 	cmd.Flags().StringVar(&cfg.DbPath, "db-path", "proof_provider.db", "path to database")
 	cmd.Flags().BoolVar(&cfg.Telemetry.ExportMetrics, "metrics", cfg.Telemetry.ExportMetrics, "export metrics via grpc")
+	cmd.Flags().StringVar(&cfg.Telemetry.PrometheusEndpoint, "metrics-endpoint", cfg.Telemetry.PrometheusEndpoint, "address to serve metrics for Prometheus scraping at /metrics, e.g. localhost:9091")
 	cmd.Flags().IntVar(&cfg.SkipRate, "skip", cfg.SkipRate, "rate of skip tasks, will skip N from 10, where N is value of option (0 means no skip). Possible values: [0,10]")
 	logLevel := cmd.Flags().String("log-level", "info", "log level: trace|debug|info|warn|error|fatal|panic")
 
@@ -3,15 +3,19 @@ package main
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"os"
 	"time"
 
+	nilrpc "github.com/NilFoundation/nil/nil/client/rpc"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/profiling"
+	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/spf13/cobra"
 )
 
@@ -45,6 +49,7 @@ func execute() error {
 	addFlags(runCmd, cfg)
 
 	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(buildResetCmd())
 
 	return rootCmd.Execute()
 }
@@ -56,16 +61,75 @@ func addFlags(cmd *cobra.Command, cfg *cmdConfig) {
 	cmd.Flags().StringVar(&cfg.DbPath, "db-path", "sync_committee.db", "path to database")
 	cmd.Flags().StringVar(&cfg.ProposerParams.Endpoint, "l1-endpoint", cfg.ProposerParams.Endpoint, "L1 endpoint")
 	cmd.Flags().StringVar(&cfg.ProposerParams.PrivateKey, "l1-private-key", cfg.ProposerParams.PrivateKey, "L1 account private key")
+	cmd.Flags().StringVar(&cfg.ProposerParams.RemoteSigner, "l1-remote-signer", cfg.ProposerParams.RemoteSigner, "endpoint of the signer holding the L1 account key, overrides the private key")
+	cmd.Flags().StringVar(&cfg.ProposerParams.RemoteTransport.AuthTokenFile, "l1-remote-signer-token-file", "", "file with the auth token of the L1 remote signer")
+	cmd.Flags().StringVar(&cfg.ProposerParams.RemoteTransport.TLSCAFile, "l1-remote-signer-ca", "", "certificate authority of the L1 remote signer")
 	cmd.Flags().StringVar(&cfg.ProposerParams.ContractAddress, "l1-contract-address", cfg.ProposerParams.ContractAddress, "L1 update state contract address")
 	cmd.Flags().DurationVar(&cfg.ProposerParams.EthClientTimeout, "l1-client-timeout", cfg.ProposerParams.EthClientTimeout, "L1 client timeout")
+
+	// L1 fee flags
+	feePolicy := cfg.ProposerParams.FeePolicy
+	maxGasTipCap := cmd.Flags().Uint64("l1-max-priority-fee", gweiOf(feePolicy.MaxGasTipCap), "max L1 priority fee per gas (gwei)")
+	maxGasFeeCap := cmd.Flags().Uint64("l1-max-fee", gweiOf(feePolicy.MaxGasFeeCap), "max L1 fee per gas (gwei)")
+	maxBlobFeeCap := cmd.Flags().Uint64("l1-max-blob-fee", gweiOf(feePolicy.MaxBlobFeeCap), "max L1 fee per blob gas (gwei)")
+	maxSpendPerBatch := cmd.Flags().Uint64("l1-max-spend-per-batch", gweiOf(feePolicy.MaxSpendPerBatch), "max worst-case cost of L1 transactions of a single batch (gwei)")
+	cmd.Flags().Uint64Var(&feePolicy.FeeBumpPercent, "l1-fee-bump-percent", feePolicy.FeeBumpPercent, "fee increase of a stuck L1 transaction replacement (%)")
+	cmd.Flags().Uint64Var(&feePolicy.BlobFeeBumpPercent, "l1-blob-fee-bump-percent", feePolicy.BlobFeeBumpPercent, "fee increase of a stuck L1 blob transaction replacement (%)")
+	cmd.Flags().DurationVar(&feePolicy.StuckTimeout, "l1-stuck-timeout", feePolicy.StuckTimeout, "time after which a pending L1 transaction is replaced")
+	cmd.Flags().Uint64Var(&feePolicy.ConfirmationDepth, "l1-confirmations", feePolicy.ConfirmationDepth, "number of L1 blocks required to confirm a transaction")
 	logLevel := cmd.Flags().String("log-level", "info", "log level: trace|debug|info|warn|error|fatal|panic")
 
 	// Telemetry flags
 	cmd.Flags().BoolVar(&cfg.Telemetry.ExportMetrics, "metrics", cfg.Telemetry.ExportMetrics, "export metrics via grpc")
+	cmd.Flags().StringVar(&cfg.Telemetry.PrometheusEndpoint, "metrics-endpoint", cfg.Telemetry.PrometheusEndpoint, "address to serve metrics for Prometheus scraping at /metrics, e.g. localhost:9091")
+
+	cmd.PreRun = func(cmd *cobra.Command, args []string) {
+		logging.SetupGlobalLogger(*logLevel)
+
+		feePolicy.MaxGasTipCap = fromGwei(*maxGasTipCap)
+		feePolicy.MaxGasFeeCap = fromGwei(*maxGasFeeCap)
+		feePolicy.MaxBlobFeeCap = fromGwei(*maxBlobFeeCap)
+		feePolicy.MaxSpendPerBatch = fromGwei(*maxSpendPerBatch)
+	}
+}
+
+func buildResetCmd() *cobra.Command {
+	var (
+		endpoint    string
+		dbPath      string
+		blockNumber uint64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Reset the sync committee state to the given main shard block (the service must be stopped)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return reset(endpoint, dbPath, types.BlockNumber(blockNumber))
+		},
+	}
+
+	cmd.Flags().StringVar(&endpoint, "endpoint", core.NewDefaultConfig().RpcEndpoint, "rpc endpoint")
+	cmd.Flags().StringVar(&dbPath, "db-path", "sync_committee.db", "path to database")
+	cmd.Flags().Uint64Var(&blockNumber, "block-number", 0, "number of the main shard block to continue from")
+	logLevel := cmd.Flags().String("log-level", "info", "log level: trace|debug|info|warn|error|fatal|panic")
 
 	cmd.PreRun = func(cmd *cobra.Command, args []string) {
 		logging.SetupGlobalLogger(*logLevel)
 	}
+	return cmd
+}
+
+func reset(endpoint string, dbPath string, blockNumber types.BlockNumber) error {
+	database, err := openDB(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	logger := logging.NewLogger("sync_committee")
+	client := nilrpc.NewClient(endpoint, logger)
+
+	return core.ResetToMainBlock(context.Background(), database, client, blockNumber, logger)
 }
 
 func run(cfg *cmdConfig) error {
@@ -103,6 +167,14 @@ func openDB(dbPath string) (db.DB, error) {
 	return badger, nil
 }
 
+func gweiOf(wei *big.Int) uint64 {
+	return new(big.Int).Div(wei, big.NewInt(params.GWei)).Uint64()
+}
+
+func fromGwei(gwei uint64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(gwei), big.NewInt(params.GWei))
+}
+
 func connectToEthClient(url string, timeout time.Duration) (*ethclient.Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
@@ -38,7 +38,7 @@ func NewExecutor[P CmdParams](writer io.StringWriter, params P, logger zerolog.L
 }
 
 func (t *Executor[P]) Run(
-	command func(context.Context, P, public.TaskDebugApi) (CmdOutput, error),
+	command func(context.Context, P, public.DebugApi) (CmdOutput, error),
 ) error {
 	if err := t.params.Validate(); err != nil {
 		return fmt.Errorf("invalid command params: %w", err)
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+)
+
+type GetBatchesParams struct {
+	ExecutorParams
+}
+
+func (p *GetBatchesParams) GetExecutorParams() *ExecutorParams {
+	return &p.ExecutorParams
+}
+
+func GetBatches(ctx context.Context, _ *GetBatchesParams, api public.DebugApi) (CmdOutput, error) {
+	batches, err := api.GetBatches(ctx)
+	if err != nil {
+		return EmptyOutput, fmt.Errorf("failed to get batches from debug API: %w", err)
+	}
+
+	if len(batches) == 0 {
+		return EmptyOutput, fmt.Errorf("%w: no batches were found", ErrNoDataFound)
+	}
+
+	return buildTableOutput(toBatchesTable(batches)), nil
+}
+
+func toBatchesTable(batches []*public.BatchView) *table {
+	header := []string{"Id", "MainBlockNumber", "MainBlockHash", "ChildBlocks", "Status", "FetchedAt"}
+	rows := make([][]string, 0, len(batches))
+	for _, batch := range batches {
+		rows = append(rows, []string{
+			batch.Id.String(),
+			batch.MainBlockNumber.String(),
+			batch.MainBlockHash.String(),
+			strconv.Itoa(batch.ChildBlocks),
+			batch.Status.String(),
+			batch.FetchedAt.Format(timeFormat),
+		})
+	}
+
+	return &table{header: header, rows: rows}
+}
@@ -19,7 +19,7 @@ func (p *GetTaskTreeParams) GetExecutorParams() *ExecutorParams {
 	return &p.ExecutorParams
 }
 
-func GetTaskTree(ctx context.Context, params *GetTaskTreeParams, api public.TaskDebugApi) (CmdOutput, error) {
+func GetTaskTree(ctx context.Context, params *GetTaskTreeParams, api public.DebugApi) (CmdOutput, error) {
 	taskTree, err := api.GetTaskTree(ctx, params.TaskId)
 	if err != nil {
 		return EmptyOutput, fmt.Errorf("failed to get task tree from debug API: %w", err)
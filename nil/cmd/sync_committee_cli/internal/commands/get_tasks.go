@@ -36,7 +36,7 @@ func (p *GetTasksParams) GetExecutorParams() *ExecutorParams {
 	return &p.ExecutorParams
 }
 
-func GetTasks(ctx context.Context, params *GetTasksParams, api public.TaskDebugApi) (CmdOutput, error) {
+func GetTasks(ctx context.Context, params *GetTasksParams, api public.DebugApi) (CmdOutput, error) {
 	tasks, err := api.GetTasks(ctx, &params.TaskDebugRequest)
 	if err != nil {
 		return EmptyOutput, fmt.Errorf("failed to get tasks from debug API: %w", err)
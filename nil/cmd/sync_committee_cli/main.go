@@ -38,6 +38,9 @@ func execute() error {
 	}
 	rootCmd.AddCommand(getTaskTreeCmd)
 
+	getBatchesCmd := buildGetBatchesCmd(executorParams, logger)
+	rootCmd.AddCommand(getBatchesCmd)
+
 	decodeBatchCmd := buildDecodeBatchCmd(executorParams, logger)
 	rootCmd.AddCommand(decodeBatchCmd)
 
@@ -113,6 +116,23 @@ func buildGetTaskTreeCmd(commonParam *commands.ExecutorParams, logger zerolog.Lo
 	return cmd, nil
 }
 
+func buildGetBatchesCmd(commonParam *commands.ExecutorParams, logger zerolog.Logger) *cobra.Command {
+	cmdParams := &commands.GetBatchesParams{
+		ExecutorParams: *commonParam,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "get_batches",
+		Short: "Get batches which are not finalized yet along with their lifecycle status",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.NewExecutor(os.Stdout, cmdParams, logger).Run(commands.GetBatches)
+		},
+	}
+
+	addCommonFlags(cmd, &cmdParams.ExecutorParams)
+	return cmd
+}
+
 func buildDecodeBatchCmd(_ *commands.ExecutorParams, logger zerolog.Logger) *cobra.Command {
 	params := &commands.DecodeBatchParams{}
 
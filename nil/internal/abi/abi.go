@@ -26,8 +26,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
+	"strings"
 
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -265,24 +268,115 @@ func (abi *ABI) HasReceive() bool {
 	return abi.Receive.Type == Receive
 }
 
-// revertSelector is a special function selector for revert reason unpacking.
-var revertSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+// ErrorByID looks up an error by the 4-byte id,
+// returns nil if none found.
+func (abi *ABI) ErrorByID(sigdata [4]byte) (*Error, error) {
+	for _, errABI := range abi.Errors {
+		if bytes.Equal(errABI.ID[:4], sigdata[:]) {
+			//nolint:scopelint
+			return &errABI, nil
+		}
+	}
+	return nil, fmt.Errorf("no error with id: %#x", sigdata[:])
+}
+
+var (
+	// revertSelector is a special function selector for revert reason unpacking.
+	revertSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
+	// panicSelector is a special function selector for panic reason unpacking.
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+
+	// panicReasons map is for readable panic codes
+	// see this linkage for the details
+	// https://docs.soliditylang.org/en/v0.8.21/control-structures.html#panic-via-assert-and-error-via-require
+	// the reason string list is copied from ether.js
+	// https://github.com/ethers-io/ethers.js/blob/fa3a883ff7c88611ce766f58bdd4b8ac90814470/src.ts/abi/interface.ts#L207-L218
+	panicReasons = map[uint64]string{
+		0x00: "generic panic",
+		0x01: "assert(false)",
+		0x11: "arithmetic underflow or overflow",
+		0x12: "division or modulo by zero",
+		0x21: "enum overflow",
+		0x22: "invalid encoded storage byte array accessed",
+		0x31: "out-of-bounds array access; popping on an empty array",
+		0x32: "out-of-bounds access of an array or bytesN",
+		0x41: "out of memory",
+		0x51: "uninitialized function",
+	}
+)
 
 // UnpackRevert resolves the abi-encoded revert reason. According to the solidity
 // spec https://solidity.readthedocs.io/en/latest/control-structures.html#revert,
-// the provided revert reason is abi-encoded as if it were a call to a function
-// `Error(string)`. So it's a special tool for it.
+// the provided revert reason is abi-encoded as if it were a call to function
+// `Error(string)` or `Panic(uint256)`. So it's a special tool for it.
 func UnpackRevert(data []byte) (string, error) {
 	if len(data) < 4 {
 		return "", errors.New("invalid data for unpacking")
 	}
-	if !bytes.Equal(data[:4], revertSelector) {
+	switch {
+	case bytes.Equal(data[:4], revertSelector):
+		typ, _ := NewType("string", "", nil)
+		unpacked, err := (Arguments{{Type: typ}}).Unpack(data[4:])
+		if err != nil {
+			return "", err
+		}
+		return unpacked[0].(string), nil //nolint:forcetypeassert
+	case bytes.Equal(data[:4], panicSelector):
+		typ, _ := NewType("uint256", "", nil)
+		unpacked, err := (Arguments{{Type: typ}}).Unpack(data[4:])
+		if err != nil {
+			return "", err
+		}
+		pCode := unpacked[0].(*big.Int) //nolint:forcetypeassert
+		// uint64 safety check for future
+		// but the code is not bigger than MAX(uint64) now
+		if pCode.IsUint64() {
+			if reason, ok := panicReasons[pCode.Uint64()]; ok {
+				return reason, nil
+			}
+		}
+		return fmt.Sprintf("unknown panic code: %#x", pCode), nil
+	default:
 		return "", errors.New("invalid data for unpacking")
 	}
-	typ, _ := NewType("string", "", nil)
-	unpacked, err := (Arguments{{Type: typ}}).Unpack(data[4:])
-	if err != nil {
-		return "", err
+}
+
+const (
+	// revertDataPrefix precedes the hex encoded revert data in the reasons of the reverts
+	// with custom errors, which can only be decoded with the ABI of the contract.
+	revertDataPrefix = "revert data: "
+
+	// maxRevertDataSize limits the size of the revert data kept in the reason.
+	maxRevertDataSize = 1024
+)
+
+// RevertReason returns the human-readable reason of the revert.
+// The reasons of Error(string) and Panic(uint256) are decoded, the other revert data
+// (e.g. custom errors) is kept hex encoded to be decoded by RevertDataFromReason later.
+func RevertReason(data []byte) string {
+	if reason, err := UnpackRevert(data); err == nil {
+		return reason
+	}
+	if len(data) < 4 {
+		return ""
+	}
+	if len(data) > maxRevertDataSize {
+		data = data[:maxRevertDataSize]
+	}
+	return revertDataPrefix + hexutil.Encode(data)
+}
+
+// RevertDataFromReason extracts the revert data kept in the reason (or in the error message containing it)
+// by RevertReason. It returns nil if there is no revert data.
+func RevertDataFromReason(reason string) []byte {
+	index := strings.LastIndex(reason, revertDataPrefix)
+	if index < 0 {
+		return nil
+	}
+	data, err := hexutil.Decode(strings.TrimSpace(reason[index+len(revertDataPrefix):]))
+	if err != nil || len(data) < 4 {
+		return nil
 	}
-	return unpacked[0].(string), nil //nolint:forcetypeassert
+	return data
 }
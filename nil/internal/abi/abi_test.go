@@ -1179,6 +1179,9 @@ func TestUnpackRevert(t *testing.T) { //nolint:tparallel
 		{"", "", errors.New("invalid data for unpacking")},
 		{"08c379a1", "", errors.New("invalid data for unpacking")},
 		{"08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000d72657665727420726561736f6e00000000000000000000000000000000000000", "revert reason", nil},
+		{"4e487b710000000000000000000000000000000000000000000000000000000000000000", "generic panic", nil},
+		{"4e487b710000000000000000000000000000000000000000000000000000000000000011", "arithmetic underflow or overflow", nil},
+		{"4e487b710000000000000000000000000000000000000000000000000000000000000099", "unknown panic code: 0x99", nil},
 	}
 	for index, c := range cases { //nolint:paralleltest
 		t.Run(fmt.Sprintf("case %d", index), func(t *testing.T) {
@@ -1215,3 +1218,53 @@ func TestCustomErrors(t *testing.T) {
 	}
 	check("MyError", "MyError(uint256)")
 }
+
+func TestErrorByID(t *testing.T) {
+	t.Parallel()
+
+	abi, err := JSON(strings.NewReader(`[
+		{"inputs": [{"name": "balance", "type": "uint256"}], "name": "Insufficient", "type": "error"},
+		{"inputs": [], "name": "Unauthorized", "type": "error"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, errABI := range abi.Errors {
+		var id [4]byte
+		copy(id[:], errABI.ID[:4])
+		found, err := abi.ErrorByID(id)
+		if err != nil {
+			t.Fatalf("Failed to look up error %s: %v", name, err)
+		}
+		if found.Name != name {
+			t.Fatalf("Error mismatch, want %s, got %s", name, found.Name)
+		}
+	}
+	if _, err := abi.ErrorByID([4]byte{1, 2, 3, 4}); err == nil {
+		t.Fatal("Expected an error for the unknown id")
+	}
+}
+
+func TestRevertReason(t *testing.T) {
+	t.Parallel()
+
+	revert, _ := hex.DecodeString("08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000d72657665727420726561736f6e00000000000000000000000000000000000000")
+	if got := RevertReason(revert); got != "revert reason" {
+		t.Fatalf("Reason mismatch, want %q, got %q", "revert reason", got)
+	}
+	if got := RevertReason([]byte{1, 2}); got != "" {
+		t.Fatalf("Expected empty reason, got %q", got)
+	}
+	if got := RevertDataFromReason("ExecutionReverted: revert reason"); got != nil {
+		t.Fatalf("Expected no revert data, got %x", got)
+	}
+
+	custom, _ := hex.DecodeString("82b429000000000000000000000000000000000000000000000000000000000000000001")
+	reason := RevertReason(custom)
+	if reason != "revert data: 0x82b429000000000000000000000000000000000000000000000000000000000000000001" {
+		t.Fatalf("Unexpected reason %q", reason)
+	}
+	if got := RevertDataFromReason("ExecutionReverted: " + reason); !bytes.Equal(got, custom) {
+		t.Fatalf("Revert data mismatch, want %x, got %x", custom, got)
+	}
+}
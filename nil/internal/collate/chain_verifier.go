@@ -0,0 +1,245 @@
+package collate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/rs/zerolog"
+)
+
+type ChainVerifierParams struct {
+	execution.BlockGeneratorParams
+
+	FirstBlock types.BlockNumber
+	// LastBlock is the last block to verify, the last block of the shard if zero.
+	LastBlock types.BlockNumber
+}
+
+// FieldMismatch is a field of the re-executed block which differs from the stored one.
+type FieldMismatch struct {
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// BlockDivergence describes a block whose re-execution doesn't match the stored block.
+type BlockDivergence struct {
+	ShardId    types.ShardId           `json:"shardId"`
+	BlockId    types.BlockNumber       `json:"blockId"`
+	BlockHash  common.Hash             `json:"blockHash"`
+	Mismatches []FieldMismatch         `json:"mismatches"`
+	Accounts   []execution.AccountDiff `json:"accounts,omitempty"`
+}
+
+type ChainVerificationResult struct {
+	ShardId    types.ShardId     `json:"shardId"`
+	FirstBlock types.BlockNumber `json:"firstBlock"`
+	LastBlock  types.BlockNumber `json:"lastBlock"`
+	Verified   uint64            `json:"verified"`
+	// Divergence is the first block which doesn't match its re-execution, nil if all blocks match.
+	Divergence *BlockDivergence `json:"divergence,omitempty"`
+}
+
+// ChainVerifier re-executes the stored blocks of a shard and checks that the results match the stored blocks.
+// Blocks are built in transactions which are always rolled back, so the database is never modified.
+type ChainVerifier struct {
+	txFabric db.DB
+
+	params ChainVerifierParams
+
+	logger zerolog.Logger
+}
+
+func NewChainVerifier(txFabric db.DB, params ChainVerifierParams) *ChainVerifier {
+	return &ChainVerifier{
+		txFabric: txFabric,
+		params:   params,
+		logger: logging.NewLogger("chain-verifier").With().
+			Stringer(logging.FieldShardId, params.ShardId).
+			Logger(),
+	}
+}
+
+// Run verifies the blocks one by one and stops at the first divergence.
+func (v *ChainVerifier) Run(ctx context.Context) (*ChainVerificationResult, error) {
+	first, last, err := v.blockRange(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ChainVerificationResult{ShardId: v.params.ShardId, FirstBlock: first, LastBlock: last}
+	v.logger.Info().Msgf("Verifying blocks [%d - %d]...", first, last)
+
+	for blockId := first; blockId <= last; blockId++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		divergence, err := v.verifyBlock(ctx, blockId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify block %d of shard %d: %w", blockId, v.params.ShardId, err)
+		}
+		if divergence != nil {
+			v.logger.Error().
+				Stringer(logging.FieldBlockNumber, blockId).
+				Stringer(logging.FieldBlockHash, divergence.BlockHash).
+				Msg("Re-executed block doesn't match the stored one")
+			result.Divergence = divergence
+			return result, nil
+		}
+		result.Verified++
+
+		if result.Verified%1000 == 0 {
+			v.logger.Info().Stringer(logging.FieldBlockNumber, blockId).Msg("Verification in progress...")
+		}
+	}
+
+	v.logger.Info().Uint64("blocks", result.Verified).Msg("All blocks match")
+	return result, nil
+}
+
+func (v *ChainVerifier) blockRange(ctx context.Context) (types.BlockNumber, types.BlockNumber, error) {
+	first, last := v.params.FirstBlock, v.params.LastBlock
+	if first == 0 {
+		// The zerostate is generated from the config, not executed
+		first = 1
+	}
+
+	if last == 0 {
+		tx, err := v.txFabric.CreateRoTx(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer tx.Rollback()
+
+		block, _, err := db.ReadLastBlock(tx, v.params.ShardId)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to read last block of shard %d: %w", v.params.ShardId, err)
+		}
+		last = block.Id
+	}
+
+	if first > last {
+		return 0, 0, fmt.Errorf("invalid block range [%d - %d]", first, last)
+	}
+	return first, last, nil
+}
+
+func (v *ChainVerifier) verifyBlock(ctx context.Context, blockId types.BlockNumber) (*BlockDivergence, error) {
+	roTx, err := v.txFabric.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer roTx.Rollback()
+
+	blockHash, err := db.ReadBlockHashByNumber(roTx, v.params.ShardId, blockId)
+	if err != nil {
+		return nil, err
+	}
+	block, err := db.ReadBlock(roTx, v.params.ShardId, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal, prevBlock, err := v.buildProposal(roTx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	gen, err := execution.NewBlockGenerator(ctx, v.params.BlockGeneratorParams, v.txFabric, prevBlock)
+	if err != nil {
+		return nil, err
+	}
+	defer gen.Rollback()
+
+	res, err := gen.BuildBlock(proposal, gen.CollectGasPrices(proposal.PrevBlockId))
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := compareBlocks(block, res.Block)
+	if len(mismatches) == 0 {
+		return nil, nil
+	}
+
+	accounts, err := gen.DiffAccounts(block.SmartContractsRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockDivergence{
+		ShardId:    v.params.ShardId,
+		BlockId:    blockId,
+		BlockHash:  blockHash,
+		Mismatches: mismatches,
+		Accounts:   accounts,
+	}, nil
+}
+
+// buildProposal restores the proposal the block was generated from.
+func (v *ChainVerifier) buildProposal(tx db.RoTx, block *types.Block) (*execution.Proposal, *types.Block, error) {
+	prevBlock, err := db.ReadBlock(tx, v.params.ShardId, block.PrevBlock)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read previous block: %w", err)
+	}
+
+	proposal := &execution.Proposal{
+		PrevBlockId:   prevBlock.Id,
+		PrevBlockHash: block.PrevBlock,
+		MainChainHash: block.MainChainHash,
+	}
+
+	inTxns, err := collectTxns(tx, v.params.ShardId, block.InTransactionsRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	proposal.InternalTxns, proposal.ExternalTxns = execution.SplitInTransactions(inTxns)
+
+	outTxns, err := collectTxns(tx, v.params.ShardId, block.OutTransactionsRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	proposal.ForwardTxns, _ = execution.SplitOutTransactions(outTxns, v.params.ShardId)
+
+	if v.params.ShardId.IsMainShard() && !block.ChildBlocksRootHash.Empty() {
+		treeShards := execution.NewDbShardBlocksTrieReader(tx, types.MainShardId, block.Id)
+		treeShards.SetRootHash(block.ChildBlocksRootHash)
+		entries, err := treeShards.Entries()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read child blocks: %w", err)
+		}
+		proposal.ShardHashes = make([]common.Hash, len(entries))
+		for _, entry := range entries {
+			if entry.Key == types.MainShardId || int(entry.Key) > len(entries) {
+				return nil, nil, errors.New("unexpected shard in child blocks")
+			}
+			proposal.ShardHashes[entry.Key-1] = *entry.Val
+		}
+	}
+
+	return proposal, prevBlock, nil
+}
+
+func compareBlocks(expected, actual *types.Block) []FieldMismatch {
+	var mismatches []FieldMismatch
+	compare := func(field string, expected, actual fmt.Stringer) {
+		if expected.String() != actual.String() {
+			mismatches = append(mismatches, FieldMismatch{
+				Field:    field,
+				Expected: expected.String(),
+				Actual:   actual.String(),
+			})
+		}
+	}
+
+	compare("SmartContractsRoot", expected.SmartContractsRoot, actual.SmartContractsRoot)
+	compare("ReceiptsRoot", expected.ReceiptsRoot, actual.ReceiptsRoot)
+	compare("OutTransactionsRoot", expected.OutTransactionsRoot, actual.OutTransactionsRoot)
+	compare("GasUsed", expected.GasUsed, actual.GasUsed)
+	return mismatches
+}
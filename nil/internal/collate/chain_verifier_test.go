@@ -0,0 +1,77 @@
+package collate
+
+import (
+	"github.com/NilFoundation/nil/nil/internal/contracts"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+func (s *ProposerTestSuite) TestVerifyChain() {
+	params := s.newParams()
+	pool := &MockTxnPool{}
+	p := newTestProposer(params, pool)
+
+	execution.GenerateZeroState(s.T(), types.MainShardId, s.db)
+	execution.GenerateZeroState(s.T(), s.shardId, s.db)
+
+	to := contracts.CounterAddress(s.T(), s.shardId)
+	pool.Txns = []*types.Transaction{
+		execution.NewSendMoneyTransaction(s.T(), to, 0),
+		execution.NewSendMoneyTransaction(s.T(), to, 1),
+	}
+	for range 3 {
+		proposal := s.generateProposal(p)
+		pool.Txns = nil
+
+		tx, err := s.db.CreateRoTx(s.T().Context())
+		s.Require().NoError(err)
+		block, err := db.ReadBlock(tx, s.shardId, proposal.PrevBlockHash)
+		tx.Rollback()
+		s.Require().NoError(err)
+
+		gen, err := execution.NewBlockGenerator(s.T().Context(), params.BlockGeneratorParams, s.db, block)
+		s.Require().NoError(err)
+		_, err = gen.GenerateBlock(proposal, &types.ConsensusParams{})
+		gen.Rollback()
+		s.Require().NoError(err)
+	}
+
+	verify := func() *ChainVerificationResult {
+		s.T().Helper()
+
+		res, err := NewChainVerifier(s.db, ChainVerifierParams{BlockGeneratorParams: params.BlockGeneratorParams}).
+			Run(s.T().Context())
+		s.Require().NoError(err)
+		return res
+	}
+
+	s.Run("Match", func() {
+		res := verify()
+		s.Nil(res.Divergence)
+		s.Equal(types.BlockNumber(1), res.FirstBlock)
+		s.Equal(types.BlockNumber(3), res.LastBlock)
+		s.EqualValues(3, res.Verified)
+	})
+
+	s.Run("Diverge", func() {
+		tx, err := s.db.CreateRwTx(s.T().Context())
+		s.Require().NoError(err)
+		defer tx.Rollback()
+
+		hash, err := db.ReadBlockHashByNumber(tx, s.shardId, 2)
+		s.Require().NoError(err)
+		block, err := db.ReadBlock(tx, s.shardId, hash)
+		s.Require().NoError(err)
+		block.GasUsed++
+		s.Require().NoError(db.WriteBlock(tx, s.shardId, hash, block))
+		s.Require().NoError(tx.Commit())
+
+		res := verify()
+		s.EqualValues(1, res.Verified)
+		s.Require().NotNil(res.Divergence)
+		s.Equal(types.BlockNumber(2), res.Divergence.BlockId)
+		s.Require().Len(res.Divergence.Mismatches, 1)
+		s.Equal("GasUsed", res.Divergence.Mismatches[0].Field)
+	})
+}
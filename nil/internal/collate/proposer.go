@@ -25,6 +25,7 @@ const (
 	defaultMaxInternalGasInBlock         = 100_000_000
 	defaultMaxGasInBlock                 = 2 * defaultMaxInternalGasInBlock
 	maxTxnsFromPool                      = 1000
+	maxScheduledCallsInBlock             = 100
 	defaultMaxForwardTransactionsInBlock = 200
 )
 
@@ -96,16 +97,19 @@ func (p *proposer) GenerateProposal(ctx context.Context, txFabric db.DB) (*execu
 		return nil, err
 	}
 
-	if err = p.executionState.UpdateBaseFee(block); err != nil {
-		return nil, fmt.Errorf("failed to update gas price: %w", err)
-	}
-
 	p.logger.Trace().Msg("Collating...")
 
 	if err := p.fetchLastBlockHashes(tx); err != nil {
 		return nil, fmt.Errorf("failed to fetch last block hashes: %w", err)
 	}
 
+	// Transactions are validated by the rules of the fork active for the proposed block.
+	// The base fee is updated by the formula of the fork as well.
+	p.executionState.MainChainHash = p.proposal.MainChainHash
+	if err := p.executionState.UpdateFork(block.Id + 1); err != nil {
+		return nil, fmt.Errorf("failed to select fork: %w", err)
+	}
+
 	if err := p.handleL1Attributes(tx); err != nil {
 		// TODO: change to Error severity once Consensus/Proposer increase time intervals
 		p.logger.Trace().Err(err).Msg("Failed to handle L1 attributes")
@@ -115,6 +119,10 @@ func (p *proposer) GenerateProposal(ctx context.Context, txFabric db.DB) (*execu
 		return nil, fmt.Errorf("failed to handle transactions from neighbors: %w", err)
 	}
 
+	if err := p.handleScheduledCalls(); err != nil {
+		return nil, fmt.Errorf("failed to handle scheduled calls: %w", err)
+	}
+
 	if err := p.handleTransactionsFromPool(tx); err != nil {
 		return nil, fmt.Errorf("failed to handle transactions from pool: %w", err)
 	}
@@ -293,12 +301,12 @@ func (p *proposer) handleTransactionsFromPool(tx db.RoTx) error {
 			return false, nil
 		}
 
-		acc, err := p.executionState.GetAccount(txn.To)
+		payer, err := execution.NewExternalTransactionPayer(p.executionState, txn)
 		if err != nil {
 			return false, err
 		}
 
-		if err := p.handleTransaction(txn, execution.NewAccountPayer(acc, txn)); err != nil {
+		if err := p.handleTransaction(txn, payer); err != nil {
 			return false, err
 		}
 
@@ -338,6 +346,37 @@ func (p *proposer) handleTransactionsFromPool(tx db.RoTx) error {
 	return nil
 }
 
+// handleScheduledCalls includes the calls which are due in the proposed block ahead of the transactions from the pool.
+func (p *proposer) handleScheduledCalls() error {
+	txns, err := p.executionState.DueScheduledCalls(maxScheduledCallsInBlock)
+	if err != nil {
+		return err
+	}
+
+	included := 0
+	for _, txn := range txns {
+		if p.executionState.GasUsed >= p.params.MaxInternalGasInBlock ||
+			len(p.proposal.InternalTxns) >= p.params.MaxInternalTransactionsInBlock {
+			break
+		}
+
+		if err := p.executionState.ConsumeScheduledCall(txn); err != nil {
+			return err
+		}
+		if err := p.handleTransaction(txn, execution.NewTransactionPayer(txn, p.executionState)); err != nil {
+			return err
+		}
+
+		p.proposal.InternalTxns = append(p.proposal.InternalTxns, txn)
+		included++
+	}
+
+	if included > 0 {
+		p.logger.Debug().Msgf("Collected %d of %d due scheduled calls", included, len(txns))
+	}
+	return nil
+}
+
 func (p *proposer) handleTransactionsFromNeighbors(tx db.RoTx) error {
 	state, err := db.ReadCollatorState(tx, p.params.ShardId)
 	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
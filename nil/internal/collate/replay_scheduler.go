@@ -147,13 +147,13 @@ func (s *ReplayScheduler) buildProposalFromPrevBlock(
 	// we could also consider option with fairly collecting these transactions
 	// from neighbor shards and running proposer
 	// however it's not a purpose of replay mode (at least now)
-	inTxns, err := s.collectTxns(tx, block.InTransactionsRoot)
+	inTxns, err := collectTxns(tx, s.params.ShardId, block.InTransactionsRoot)
 	if err != nil {
 		return nil, nil, err
 	}
 	proposal.InternalTxns, proposal.ExternalTxns = execution.SplitInTransactions(inTxns)
 
-	forwardTxns, err := s.collectTxns(tx, block.OutTransactionsRoot)
+	forwardTxns, err := collectTxns(tx, s.params.ShardId, block.OutTransactionsRoot)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -162,8 +162,8 @@ func (s *ReplayScheduler) buildProposalFromPrevBlock(
 	return proposal, prevBlock, nil
 }
 
-func (s *ReplayScheduler) collectTxns(roTx db.RoTx, root common.Hash) ([]*types.Transaction, error) {
-	inTxnsReader := execution.NewDbTransactionTrieReader(roTx, s.params.ShardId)
+func collectTxns(roTx db.RoTx, shardId types.ShardId, root common.Hash) ([]*types.Transaction, error) {
+	inTxnsReader := execution.NewDbTransactionTrieReader(roTx, shardId)
 	inTxnsReader.SetRootHash(root)
 	entries, err := inTxnsReader.Entries()
 	if err != nil {
@@ -2,6 +2,7 @@ package collate
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
@@ -52,6 +53,8 @@ type Scheduler struct {
 	logger zerolog.Logger
 
 	l1Fetcher rollup.L1BlockFetcher
+
+	paused atomic.Bool
 }
 
 func NewScheduler(txFabric db.DB, pool txnpool.Pool, params Params, networkManager *network.Manager) *Scheduler {
@@ -92,6 +95,9 @@ func (s *Scheduler) Run(ctx context.Context, syncer *Syncer, consensus Consensus
 	for {
 		select {
 		case <-ticker.C:
+			if s.paused.Load() {
+				continue
+			}
 			if err := s.doCollate(ctx); err != nil {
 				if ctx.Err() != nil {
 					s.logger.Info().Msg("Stopping collation...")
@@ -106,6 +112,24 @@ func (s *Scheduler) Run(ctx context.Context, syncer *Syncer, consensus Consensus
 	}
 }
 
+// Pause stops producing new blocks until Resume is called.
+// The block which is being collated at the moment is finished.
+func (s *Scheduler) Pause() {
+	if !s.paused.Swap(true) {
+		s.logger.Info().Msg("Collation paused")
+	}
+}
+
+func (s *Scheduler) Resume() {
+	if s.paused.Swap(false) {
+		s.logger.Info().Msg("Collation resumed")
+	}
+}
+
+func (s *Scheduler) IsPaused() bool {
+	return s.paused.Load()
+}
+
 func (s *Scheduler) doCollate(ctx context.Context) error {
 	if s.params.DisableConsensus {
 		v := s.Validator()
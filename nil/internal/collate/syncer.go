@@ -431,6 +431,10 @@ func (s *Syncer) validateRepliedBlock(
 		return returnErrorOrPanic(fmt.Errorf("config root mismatch. Expected %x, got %x",
 			in.ConfigRoot, replied.Block.ConfigRoot))
 	}
+	if replied.Block.ScheduledCallsRoot != in.ScheduledCallsRoot {
+		return returnErrorOrPanic(fmt.Errorf("scheduled calls root mismatch. Expected %x, got %x",
+			in.ScheduledCallsRoot, replied.Block.ScheduledCallsRoot))
+	}
 	if replied.BlockHash != inHash {
 		return s.logBlockDiffError(in, replied.Block, inHash, replied.BlockHash)
 	}
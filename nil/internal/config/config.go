@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	ssz "github.com/NilFoundation/fastssz"
 	"github.com/NilFoundation/nil/nil/common"
@@ -20,7 +21,7 @@ var (
 )
 
 func init() {
-	for _, param := range ParamsList {
+	for _, param := range slices.Concat(ParamsList, OptionalParamsList) {
 		ParamsMap[param.Name()] = param.Accessor()
 	}
 }
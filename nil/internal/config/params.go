@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
@@ -10,6 +11,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/rs/zerolog/log"
 )
@@ -20,14 +22,22 @@ const (
 	NameValidators = "curr_validators"
 	NameGasPrice   = "gas_price"
 	NameL1Block    = "l1block"
+	NameForks      = "forks"
 )
 
+// ParamsList is the list of the params initialized in the config at genesis.
 var ParamsList = []IConfigParam{
 	new(ParamValidators),
 	new(ParamGasPrice),
 	new(ParamL1BlockInfo),
 }
 
+// OptionalParamsList is the list of the params which are written to the config only if they are set,
+// so the genesis of the networks launched before they were introduced stays the same.
+var OptionalParamsList = []IConfigParam{
+	new(ParamForks),
+}
+
 type Pubkey [ValidatorPubkeySize]byte
 
 func (k Pubkey) MarshalText() ([]byte, error) {
@@ -111,6 +121,55 @@ func (p *ParamL1BlockInfo) Accessor() *ParamAccessor {
 	return CreateAccessor[ParamL1BlockInfo]()
 }
 
+type ForkActivation struct {
+	Fork   uint32 `json:"fork" yaml:"fork"`
+	Height uint64 `json:"height" yaml:"height"`
+}
+
+// ParamForks is the schedule of protocol upgrades. A fork becomes active starting from the main shard block
+// of the given height; blocks of other shards use the fork active at their main chain block.
+// An empty schedule means that the genesis rules are always active.
+type ParamForks struct {
+	Schedule []ForkActivation `json:"schedule" ssz-max:"256" yaml:"schedule"`
+}
+
+var _ IConfigParam = new(ParamForks)
+
+func (p *ParamForks) Name() string {
+	return NameForks
+}
+
+func (p *ParamForks) Accessor() *ParamAccessor {
+	return CreateAccessor[ParamForks]()
+}
+
+// Validate checks that the forks are scheduled in the order of their numbers.
+func (p *ParamForks) Validate() error {
+	for i := 1; i < len(p.Schedule); i++ {
+		prev, cur := p.Schedule[i-1], p.Schedule[i]
+		if cur.Fork <= prev.Fork {
+			return fmt.Errorf("fork %d is scheduled after fork %d", cur.Fork, prev.Fork)
+		}
+		if cur.Height < prev.Height {
+			return fmt.Errorf("fork %d is activated at height %d, before fork %d at height %d",
+				cur.Fork, cur.Height, prev.Fork, prev.Height)
+		}
+	}
+	return nil
+}
+
+// ActiveFork returns the fork active at the given height of the main shard.
+func (p *ParamForks) ActiveFork(mainShardHeight types.BlockNumber) params.Fork {
+	fork := params.ForkGenesis
+	for _, activation := range p.Schedule {
+		if uint64(mainShardHeight) < activation.Height {
+			break
+		}
+		fork = params.Fork(activation.Fork)
+	}
+	return fork
+}
+
 func CreateAccessor[T any, paramPtr IConfigParamPointer[T]]() *ParamAccessor {
 	return &ParamAccessor{
 		func(c ConfigAccessor) (any, error) {
@@ -229,6 +288,42 @@ func GetValidatorListForShard(
 	return validatorsList.Validators[shardId-1].List, nil
 }
 
+// GetActiveForkForShard returns the fork the validators of the shard agree on the block at the height with.
+// Like the validators list, it's taken from the config of the previous block. For the shards other than the main one,
+// the fork is selected by the main chain block referenced by the previous block.
+func GetActiveForkForShard(
+	ctx context.Context, database db.DB, height types.BlockNumber, shardId types.ShardId,
+) (params.Fork, error) {
+	tx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	block, err := db.ReadBlockByNumber(tx, shardId, height-1)
+	if err != nil {
+		return 0, err
+	}
+
+	c, err := NewConfigAccessorFromBlockWithTx(tx, block, shardId)
+	if err != nil {
+		return 0, err
+	}
+
+	mainShardHeight := height
+	if !shardId.IsMainShard() {
+		mainShardHeight = 0
+		if !block.MainChainHash.Empty() {
+			mainBlock, err := db.ReadBlock(tx, types.MainShardId, block.MainChainHash)
+			if err != nil {
+				return 0, err
+			}
+			mainShardHeight = mainBlock.Id
+		}
+	}
+	return GetActiveFork(c, mainShardHeight)
+}
+
 type PublicKeyMap struct {
 	m    map[Pubkey]uint32
 	keys []bls.PublicKey
@@ -292,6 +387,39 @@ func SetParamL1Block(c ConfigAccessor, params *ParamL1BlockInfo) error {
 	return setParamImpl(c, params)
 }
 
+func GetParamForks(c ConfigAccessor) (*ParamForks, error) {
+	return getParamImpl[ParamForks](c)
+}
+
+func SetParamForks(c ConfigAccessor, params *ParamForks) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	return setParamImpl(c, params)
+}
+
+// GetActiveFork returns the fork active at the given height of the main shard.
+// Configs without the fork schedule (e.g. of the networks launched before it was introduced) use the genesis rules.
+// An error is returned if the active fork is not supported by this node, so it must be upgraded.
+func GetActiveFork(c ConfigAccessor, mainShardHeight types.BlockNumber) (params.Fork, error) {
+	forks, err := GetParamForks(c)
+	if errors.Is(err, ErrParamNotFound) {
+		return params.ForkGenesis, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := forks.Validate(); err != nil {
+		return 0, fmt.Errorf("invalid fork schedule: %w", err)
+	}
+	fork := forks.ActiveFork(mainShardHeight)
+	if !fork.IsKnown() {
+		return 0, fmt.Errorf("fork %s is active at height %d, but the latest fork supported by the node is %s; "+
+			"upgrade the node", fork, mainShardHeight, params.LatestFork)
+	}
+	return fork, nil
+}
+
 func GetParamNShards(c ConfigAccessor) (uint32, error) {
 	param, err := getParamImpl[ParamGasPrice](c)
 	if err != nil {
@@ -15,6 +15,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/remotesigner"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/rs/zerolog"
 )
@@ -26,7 +27,7 @@ type ConsensusParams struct {
 	Db         db.DB
 	Validator  validator
 	NetManager *network.Manager
-	PrivateKey bls.PrivateKey
+	Signer     remotesigner.BlsSigner
 }
 
 type validator interface {
@@ -50,6 +51,7 @@ type backendIBFT struct {
 	transport       transport
 	signer          *Signer
 	validatorsCache *validatorsMap
+	mh              *metricsHandler
 }
 
 var _ core.Backend = &backendIBFT{}
@@ -181,7 +183,10 @@ func (i *backendIBFT) InsertProposal(proposal *protoIBFT.Proposal, committedSeal
 		Signature:     sig,
 	}); err != nil {
 		logger.Error().Err(err).Msg("Failed to insert proposal")
+		return
 	}
+
+	i.mh.RecordBlockInserted(i.ctx, proposal.Round)
 }
 
 func (i *backendIBFT) ID() []byte {
@@ -192,7 +197,12 @@ func (i *backendIBFT) isActiveValidator() bool {
 	return true
 }
 
-func NewConsensus(cfg *ConsensusParams) *backendIBFT {
+func NewConsensus(cfg *ConsensusParams) (*backendIBFT, error) {
+	mh, err := newMetricsHandler(cfg.ShardId)
+	if err != nil {
+		return nil, err
+	}
+
 	logger := logging.NewLogger("consensus").With().Stringer(logging.FieldShardId, cfg.ShardId).Logger()
 	l := &ibftLogger{
 		logger: logger.With().CallerWithSkipFrameCount(3).Logger(),
@@ -204,11 +214,12 @@ func NewConsensus(cfg *ConsensusParams) *backendIBFT {
 		validator:       cfg.Validator,
 		logger:          logger,
 		nm:              cfg.NetManager,
-		signer:          NewSigner(cfg.PrivateKey),
+		signer:          NewSigner(cfg.Signer, cfg.ShardId),
 		validatorsCache: newValidatorsMap(cfg.Db, cfg.ShardId),
+		mh:              mh,
 	}
 	backend.consensus = core.NewIBFT(l, backend, backend)
-	return backend
+	return backend, nil
 }
 
 func (i *backendIBFT) Init(ctx context.Context) error {
@@ -13,7 +13,15 @@ func (i *backendIBFT) signMessage(msg *protoIBFT.IbftMessage) *protoIBFT.IbftMes
 		return nil
 	}
 
-	if msg.Signature, err = i.signer.Sign(raw); err != nil {
+	domain, err := i.validatorsCache.messageDomain(i.ctx, msg.GetView().GetHeight())
+	if err != nil {
+		i.logger.Error().Err(err).
+			Uint64(logging.FieldHeight, msg.GetView().GetHeight()).
+			Msg("Failed to get the active fork")
+		return nil
+	}
+
+	if msg.Signature, err = i.signer.Sign(i.ctx, raw, domain); err != nil {
 		event := i.logger.Error().Err(err).
 			Stringer("type", msg.GetType())
 		if view := msg.GetView(); view != nil {
@@ -81,7 +89,7 @@ func (i *backendIBFT) BuildPrepareMessage(proposalHash []byte, view *protoIBFT.V
 }
 
 func (i *backendIBFT) BuildCommitMessage(proposalHash []byte, view *protoIBFT.View) *protoIBFT.IbftMessage {
-	seal, err := i.signer.SignHash(proposalHash)
+	seal, err := i.signer.SignSeal(i.ctx, view, proposalHash)
 	if err != nil {
 		i.logger.Error().Err(err).
 			Hex(logging.FieldPublicKey, i.signer.GetPublicKey()).
@@ -121,5 +129,7 @@ func (i *backendIBFT) BuildRoundChangeMessage(
 		}},
 	}
 
+	i.mh.RecordRoundChange(i.ctx)
+
 	return i.signMessage(msg)
 }
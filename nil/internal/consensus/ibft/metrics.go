@@ -0,0 +1,65 @@
+package ibft
+
+import (
+	"context"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type metricsHandler struct {
+	option metric.MeasurementOption
+
+	// rounds is the number of rounds it took to reach consensus on a block
+	rounds       telemetry.Histogram
+	roundChanges telemetry.Counter
+	// blockTime is the time between consecutive blocks inserted by the consensus
+	blockTime telemetry.Histogram
+
+	lastInsertedAt time.Time
+}
+
+func newMetricsHandler(shardId types.ShardId) (*metricsHandler, error) {
+	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/internal/consensus/ibft")
+
+	rounds, err := meter.Int64Histogram("consensus_rounds",
+		metric.WithDescription("Number of rounds it took to reach consensus on a block"))
+	if err != nil {
+		return nil, err
+	}
+
+	roundChanges, err := meter.Int64Counter("consensus_round_changes")
+	if err != nil {
+		return nil, err
+	}
+
+	blockTime, err := meter.Int64Histogram("block_time", metric.WithUnit("ms"),
+		metric.WithDescription("Time between consecutive blocks"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &metricsHandler{
+		option:       telattr.With(telattr.ShardId(shardId)),
+		rounds:       rounds,
+		roundChanges: roundChanges,
+		blockTime:    blockTime,
+	}, nil
+}
+
+func (mh *metricsHandler) RecordRoundChange(ctx context.Context) {
+	mh.roundChanges.Add(ctx, 1, mh.option)
+}
+
+func (mh *metricsHandler) RecordBlockInserted(ctx context.Context, round uint64) {
+	mh.rounds.Record(ctx, int64(round)+1, mh.option)
+
+	now := time.Now()
+	if !mh.lastInsertedAt.IsZero() {
+		mh.blockTime.Record(ctx, now.Sub(mh.lastInsertedAt).Milliseconds(), mh.option)
+	}
+	mh.lastInsertedAt = now
+}
@@ -1,50 +1,63 @@
 package ibft
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
+	protoIBFT "github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
 	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/NilFoundation/nil/nil/internal/remotesigner"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
+// Signer signs the consensus messages with the validator key, which may be held by a remote signer.
 type Signer struct {
-	privateKey   bls.PrivateKey
+	signer       remotesigner.BlsSigner
+	shardId      types.ShardId
+	publicKey    bls.PublicKey
 	rawPublicKey []byte
 }
 
-func getHash(data []byte) []byte {
-	return common.PoseidonHash(data).Bytes()
-}
-
-func NewSigner(privateKey bls.PrivateKey) *Signer {
-	rawPublicKey, err := privateKey.PublicKey().Marshal()
+func NewSigner(signer remotesigner.BlsSigner, shardId types.ShardId) *Signer {
+	rawPublicKey := signer.PublicKey()
+	publicKey, err := bls.PublicKeyFromBytes(rawPublicKey)
 	check.PanicIfErr(err)
 	return &Signer{
-		privateKey:   privateKey,
+		signer:       signer,
+		shardId:      shardId,
+		publicKey:    publicKey,
 		rawPublicKey: rawPublicKey,
 	}
 }
 
-func (s *Signer) SignHash(hash []byte) (types.BlsSignature, error) {
-	sig, err := s.privateKey.Sign(hash)
-	if err != nil {
-		return nil, err
-	}
-	return sig.Marshal()
+// SignSeal signs the proposal hash committed in the view.
+func (s *Signer) SignSeal(ctx context.Context, view *protoIBFT.View, proposalHash []byte) (types.BlsSignature, error) {
+	return s.signer.SignBls(ctx, &remotesigner.BlsSigningRequest{
+		Kind:    remotesigner.BlsSigningSeal,
+		ShardId: s.shardId,
+		Height:  view.GetHeight(),
+		Round:   view.GetRound(),
+		Data:    proposalHash,
+	})
 }
 
-func (s *Signer) Sign(data []byte) (types.BlsSignature, error) {
-	return s.SignHash(getHash(data))
+// Sign signs the serialized message, with the domain if params.ForkConsensusMessageDomain is active.
+func (s *Signer) Sign(ctx context.Context, data []byte, domain bool) (types.BlsSignature, error) {
+	return s.signer.SignBls(ctx, &remotesigner.BlsSigningRequest{
+		Kind:          remotesigner.BlsSigningMessage,
+		ShardId:       s.shardId,
+		Data:          data,
+		MessageDomain: domain,
+	})
 }
 
-func (s *Signer) Verify(data []byte, sig types.BlsSignature) error {
+func (s *Signer) Verify(data []byte, sig types.BlsSignature, domain bool) error {
 	signature, err := bls.SignatureFromBytes(sig)
 	if err != nil {
 		return err
 	}
-	return signature.Verify(s.privateKey.PublicKey(), getHash(data))
+	return signature.Verify(s.publicKey, remotesigner.MessageSigningData(data, domain))
 }
 
 func (s *Signer) VerifyWithKeyHash(publicKey []byte, hash []byte, sig types.BlsSignature) error {
@@ -59,8 +72,8 @@ func (s *Signer) VerifyWithKeyHash(publicKey []byte, hash []byte, sig types.BlsS
 	return signature.Verify(pk, hash)
 }
 
-func (s *Signer) VerifyWithKey(publicKey []byte, data []byte, sig types.BlsSignature) error {
-	return s.VerifyWithKeyHash(publicKey, getHash(data), sig)
+func (s *Signer) VerifyWithKey(publicKey []byte, data []byte, sig types.BlsSignature, domain bool) error {
+	return s.VerifyWithKeyHash(publicKey, remotesigner.MessageSigningData(data, domain), sig)
 }
 
 func (s *Signer) GetPublicKey() []byte {
@@ -7,6 +7,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
@@ -48,6 +49,17 @@ func newValidatorsMap(txFabric db.DB, shardId types.ShardId) *validatorsMap {
 }
 
 func (m *validatorsMap) getValidators(ctx context.Context, height uint64) ([]config.ValidatorInfo, error) {
+	v := m.load(ctx, height)
+	return v.value, v.err
+}
+
+// messageDomain returns true if the consensus messages at the height are signed with the domain.
+func (m *validatorsMap) messageDomain(ctx context.Context, height uint64) (bool, error) {
+	v := m.load(ctx, height)
+	return v.fork >= params.ForkConsensusMessageDomain, v.err
+}
+
+func (m *validatorsMap) load(ctx context.Context, height uint64) *validatorValue {
 	vAny, _ := m.m.LoadOrStore(height, &validatorValue{
 		txFabric: m.txFabtic,
 		shardId:  m.shardId,
@@ -60,7 +72,7 @@ func (m *validatorsMap) getValidators(ctx context.Context, height uint64) ([]con
 		// In this case, we should not cache the error, because the error is not permanent.
 		m.m.Delete(height)
 	}
-	return v.value, v.err
+	return v
 }
 
 type validatorValue struct {
@@ -70,6 +82,7 @@ type validatorValue struct {
 	height  uint64
 
 	value []config.ValidatorInfo
+	fork  params.Fork
 	err   error
 
 	once sync.Once
@@ -82,5 +95,8 @@ func (v *validatorValue) getValidators(ctx context.Context) ([]config.ValidatorI
 func (v *validatorValue) init(ctx context.Context) {
 	v.once.Do(func() {
 		v.value, v.err = v.getValidators(ctx)
+		if v.err == nil {
+			v.fork, v.err = config.GetActiveForkForShard(ctx, v.txFabric, types.BlockNumber(v.height), v.shardId)
+		}
 	})
 }
@@ -59,7 +59,15 @@ func (i *backendIBFT) IsValidValidator(msg *protoIBFT.IbftMessage) bool {
 		return false
 	}
 
-	if err := i.signer.VerifyWithKey(msg.From, msgNoSig, msg.Signature); err != nil {
+	domain, err := i.validatorsCache.messageDomain(i.transportCtx, msg.View.Height)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to get the active fork")
+		return false
+	}
+
+	if err := i.signer.VerifyWithKey(msg.From, msgNoSig, msg.Signature, domain); err != nil {
 		logger.Err(err).Msg("Failed to verify signature")
 		return false
 	}
@@ -151,6 +151,30 @@ func WriteBlock(tx RwTx, shardId types.ShardId, hash common.Hash, block *types.B
 	return writeEncodable(tx, blockTable, shardId, hash, block)
 }
 
+func WriteStateDiff(tx RwTx, shardId types.ShardId, blockHash common.Hash, diff *types.BlockStateDiff) error {
+	return writeEncodable(tx, stateDiffTable, shardId, blockHash, diff)
+}
+
+func ReadStateDiff(tx RoTx, shardId types.ShardId, blockHash common.Hash) (*types.BlockStateDiff, error) {
+	return readDecodable[*types.BlockStateDiff](tx, stateDiffTable, shardId, blockHash)
+}
+
+func ReadStateDiffSSZ(tx RoTx, shardId types.ShardId, blockHash common.Hash) ([]byte, error) {
+	return tx.GetFromShard(shardId, stateDiffTable, blockHash.Bytes())
+}
+
+func WriteResourceUsage(tx RwTx, shardId types.ShardId, blockHash common.Hash, usage *types.ResourceUsage) error {
+	return writeEncodable(tx, resourceUsageTable, shardId, blockHash, usage)
+}
+
+func ReadResourceUsage(tx RoTx, shardId types.ShardId, blockHash common.Hash) (*types.ResourceUsage, error) {
+	return readDecodable[*types.ResourceUsage](tx, resourceUsageTable, shardId, blockHash)
+}
+
+func ReadResourceUsageSSZ(tx RoTx, shardId types.ShardId, blockHash common.Hash) ([]byte, error) {
+	return tx.GetFromShard(shardId, resourceUsageTable, blockHash.Bytes())
+}
+
 func WriteError(tx RwTx, txnHash common.Hash, errMsg string) error {
 	return tx.Put(errorByTransactionHashTable, txnHash.Bytes(), []byte(errMsg))
 }
@@ -163,6 +187,14 @@ func ReadError(tx RoTx, txnHash common.Hash) (string, error) {
 	return string(res), nil
 }
 
+func WriteRevertData(tx RwTx, txnHash common.Hash, data []byte) error {
+	return tx.Put(revertDataByTransactionHashTable, txnHash.Bytes(), data)
+}
+
+func ReadRevertData(tx RoTx, txnHash common.Hash) ([]byte, error) {
+	return tx.Get(revertDataByTransactionHashTable, txnHash.Bytes())
+}
+
 func WriteCode(tx RwTx, shardId types.ShardId, hash common.Hash, code types.Code) error {
 	return tx.PutToShard(shardId, codeTable, hash.Bytes(), code[:])
 }
@@ -10,15 +10,20 @@ import (
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common/assert"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/dgraph-io/badger/v4"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
 type badgerDB struct {
 	db       *badger.DB
 	txLedger assert.TxLedger
 	lock     sync.Mutex
+
+	sizeGauge metric.Registration
 }
 
 type BadgerDBOptions struct {
@@ -70,7 +75,16 @@ func IsKeyFromTable(table TableName, fullKey []byte) bool {
 
 func NewBadgerDb(pathToDb string) (*badgerDB, error) {
 	opts := badger.DefaultOptions(pathToDb).WithLogger(nil)
-	return newBadgerDb(&opts)
+	db, err := newBadgerDb(&opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.registerSizeGauge(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
 }
 
 func NewBadgerDbInMemory() (*badgerDB, error) {
@@ -88,7 +102,31 @@ func newBadgerDb(opts *badger.Options) (*badgerDB, error) {
 	return db, nil
 }
 
+// registerSizeGauge reports the size of the LSM tree and the value log on disk
+func (db *badgerDB) registerSizeGauge() error {
+	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/internal/db")
+	size, err := meter.Int64ObservableGauge("db_size", metric.WithUnit("By"),
+		metric.WithDescription("Size of the database on disk"))
+	if err != nil {
+		return err
+	}
+
+	lsmOption := metric.WithAttributes(attribute.String("kind", "lsm"))
+	vlogOption := metric.WithAttributes(attribute.String("kind", "vlog"))
+	db.sizeGauge, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		lsm, vlog := db.db.Size()
+		o.ObserveInt64(size, lsm, lsmOption)
+		o.ObserveInt64(size, vlog, vlogOption)
+		return nil
+	}, size)
+	return err
+}
+
 func (db *badgerDB) Close() {
+	if db.sizeGauge != nil {
+		// nothing to do with the error
+		_ = db.sizeGauge.Unregister()
+	}
 	db.db.Close()
 	db.txLedger.CheckLeakyTransactions()
 }
@@ -163,10 +201,7 @@ func (db *badgerDB) LogGC(ctx context.Context, discardRation float64, gcFrequenc
 		select {
 		case <-ticker.C:
 			log.Debug().Msg("Execute badger LogGC")
-			var err error
-			for ; err == nil; err = db.db.RunValueLogGC(discardRation) {
-			}
-			if !errors.Is(err, badger.ErrNoRewrite) {
+			if err := db.RunGC(ctx, discardRation); err != nil {
 				log.Error().Err(err).Msg("Error during badger LogGC")
 				return err
 			}
@@ -177,6 +212,17 @@ func (db *badgerDB) LogGC(ctx context.Context, discardRation float64, gcFrequenc
 	}
 }
 
+// RunGC rewrites value log files until there are no more files with enough garbage to discard.
+func (db *badgerDB) RunGC(_ context.Context, discardRation float64) error {
+	var err error
+	for ; err == nil; err = db.db.RunValueLogGC(discardRation) {
+	}
+	if errors.Is(err, badger.ErrNoRewrite) {
+		return nil
+	}
+	return err
+}
+
 func (tx *BadgerRwTx) Commit() error {
 	tx.onFinish()
 	return tx.tx.Commit()
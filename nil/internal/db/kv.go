@@ -63,6 +63,7 @@ type DB interface {
 
 	DropAll() error
 	LogGC(ctx context.Context, discardRation float64, gcFrequency time.Duration) error
+	RunGC(ctx context.Context, discardRation float64) error
 	Fetch(_ context.Context, reader io.Reader) error
 	Close()
 }
@@ -0,0 +1,462 @@
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	schemaVersionKey     = "SchemaVersion"
+	migrationProgressKey = "MigrationProgress"
+
+	defaultMigrationBatchSize  = 1000
+	defaultMigrationBatchBytes = 16 << 20
+)
+
+var (
+	// versionInfoBaseline is the layout of types.SchemesInsideDb before the migrations were introduced.
+	versionInfoBaseline = common.HexToHash("0x2d254f41d40ed6a6c4c4e859ab5f2cbd9a0083033f6cf990342769f299cf2400")
+	// versionInfoPaymaster is the layout with the transaction paymaster.
+	versionInfoPaymaster = common.HexToHash("0x195f9c4182842c1df64ec212caabd66efcc08c8e7c69ca8ff1f77c093c5217b7")
+	// versionInfoScheduledCalls is the layout with the scheduled calls root of blocks
+	// and the timeout call id of async contexts.
+	versionInfoScheduledCalls = common.HexToHash("0x2b8e845e6dbde6ea1502d391381ff332397174e1cb844991a06ed1305585aa9d")
+)
+
+// SchemaMigrations is the list of all database migrations ordered by version.
+// A change of any struct from types.SchemesInsideDb has to come with a migration
+// which converts the stored records, otherwise nodes have to drop their databases on upgrade.
+var SchemaMigrations = []*Migration{
+	{
+		// The paymaster is encoded only if it's set, so the stored transactions keep their encoding
+		Version: 1,
+		Name:    "transaction paymaster",
+		From:    versionInfoBaseline,
+		To:      versionInfoPaymaster,
+	},
+	{
+		// The batch receipts are encoded only if they're set, so the stored receipts keep their encoding
+		Version: 2,
+		Name:    "receipt batch receipts",
+		From:    versionInfoPaymaster,
+		To:      versionInfoPaymaster,
+	},
+	{
+		// The scheduled calls root is encoded only if it's set, so the stored blocks keep their encoding
+		Version: 3,
+		Name:    "block scheduled calls root",
+		From:    versionInfoPaymaster,
+		To:      versionInfoScheduledCalls,
+	},
+	{
+		// The timeout call id is encoded only if it's set, so the stored async contexts keep their encoding
+		Version: 4,
+		Name:    "async context timeout call id",
+		From:    versionInfoScheduledCalls,
+		To:      versionInfoScheduledCalls,
+	},
+}
+
+// Migration converts the records of the previous schema version to the next one.
+type Migration struct {
+	// Version is the schema version of the database after the migration.
+	// Versions start from 1 and go without gaps.
+	Version uint64
+	Name    string
+
+	// From and To are the version infos (see types.NewVersionInfo) of the layout of types.SchemesInsideDb
+	// the migration starts from and results in. The migration is applied only to the databases
+	// with the From version info, so a layout change without a migration is still reported as outdated.
+	From common.Hash
+	To   common.Hash
+
+	// Tables lists the tables touched by the migration.
+	// A sharded table is listed by its name (e.g. TableName(ContractTable)) and covers all the shards.
+	Tables []TableName
+
+	// Migrate converts the value of a record. Returning nil value deletes the record.
+	// Every record is converted exactly once, even if the migration is interrupted and resumed.
+	// A migration without tables only bumps the schema version, e.g. when a field is added
+	// without changing the encoding of the stored records. The binaries which don't know the version
+	// refuse to open the database then.
+	Migrate func(table TableName, key, value []byte) ([]byte, error)
+}
+
+// migrationProgress is the position of an unfinished migration: the next record to convert
+// is the first record of Tables[TableIndex] after LastKey.
+type migrationProgress struct {
+	Version    uint64
+	TableIndex uint64
+	LastKey    []byte
+}
+
+func (p *migrationProgress) marshal() []byte {
+	data := binary.BigEndian.AppendUint64(nil, p.Version)
+	data = binary.BigEndian.AppendUint64(data, p.TableIndex)
+	return append(data, p.LastKey...)
+}
+
+func (p *migrationProgress) unmarshal(data []byte) error {
+	if len(data) < 16 {
+		return fmt.Errorf("invalid migration progress of %d bytes", len(data))
+	}
+	p.Version = binary.BigEndian.Uint64(data)
+	p.TableIndex = binary.BigEndian.Uint64(data[8:])
+	if len(data) > 16 {
+		p.LastKey = data[16:]
+	}
+	return nil
+}
+
+// LatestSchemaVersion returns the schema version of the databases created by the current binary.
+func LatestSchemaVersion() uint64 {
+	if len(SchemaMigrations) == 0 {
+		return 0
+	}
+	return SchemaMigrations[len(SchemaMigrations)-1].Version
+}
+
+// ReadSchemaVersion returns the version of the database schema.
+// Databases created before the migrations were introduced have version 0.
+func ReadSchemaVersion(tx RoTx) (uint64, error) {
+	data, err := tx.Get(schemeVersionTable, []byte(schemaVersionKey))
+	if errors.Is(err, ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 8 {
+		return 0, fmt.Errorf("invalid schema version of %d bytes", len(data))
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+func WriteSchemaVersion(tx RwTx, version uint64) error {
+	return tx.Put(schemeVersionTable, []byte(schemaVersionKey), binary.BigEndian.AppendUint64(nil, version))
+}
+
+func readMigrationProgress(tx RoTx) (*migrationProgress, error) {
+	data, err := tx.Get(schemeVersionTable, []byte(migrationProgressKey))
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	progress := &migrationProgress{}
+	if err := progress.unmarshal(data); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// Migrator applies the schema migrations which are not applied to the database yet.
+type Migrator struct {
+	db         DB
+	migrations []*Migration
+	logger     zerolog.Logger
+
+	// BatchSize and BatchBytes limit the number and the total size of records converted in a single transaction.
+	BatchSize  int
+	BatchBytes int
+}
+
+func NewMigrator(db DB, migrations []*Migration, logger zerolog.Logger) (*Migrator, error) {
+	for i, m := range migrations {
+		if m.Version != uint64(i+1) {
+			return nil, fmt.Errorf("migration %q has version %d, expected %d", m.Name, m.Version, i+1)
+		}
+		if m.Migrate == nil && len(m.Tables) > 0 {
+			return nil, fmt.Errorf("migration %q has no Migrate function", m.Name)
+		}
+		if i > 0 && m.From != migrations[i-1].To {
+			return nil, fmt.Errorf("migration %q starts from version info %s, expected %s",
+				m.Name, m.From, migrations[i-1].To)
+		}
+	}
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		logger:     logger,
+		BatchSize:  defaultMigrationBatchSize,
+		BatchBytes: defaultMigrationBatchBytes,
+	}, nil
+}
+
+// Pending returns the migrations which are not applied to the database yet.
+func (m *Migrator) Pending(ctx context.Context) ([]*Migration, error) {
+	tx, err := m.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	version, err := ReadSchemaVersion(tx)
+	if err != nil {
+		return nil, err
+	}
+	if version > uint64(len(m.migrations)) {
+		return nil, fmt.Errorf("database schema version %d is newer than the latest known version %d",
+			version, len(m.migrations))
+	}
+	return m.migrations[version:], nil
+}
+
+// Run applies the pending migrations one by one and returns the number of applied migrations.
+// Before a migration changes anything, all the records it's going to convert are checked with a dry run.
+// An interrupted migration is resumed from the last committed batch.
+// The version info of the database is updated along with the schema version. If the stored version info
+// doesn't match the layout the next migration starts from, the migrations are stopped
+// and the database stays outdated.
+func (m *Migrator) Run(ctx context.Context) (int, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, migration := range pending {
+		logger := m.logger.With().
+			Uint64("version", migration.Version).
+			Str("migration", migration.Name).
+			Logger()
+
+		versionInfo, err := m.versionInfo(ctx)
+		if err != nil {
+			return i, err
+		}
+		if versionInfo != nil && versionInfo.Version != migration.From {
+			logger.Warn().
+				Stringer("versionInfo", versionInfo.Version).
+				Stringer("expected", migration.From).
+				Msg("Database layout doesn't match the migration, it's left outdated")
+			return i, nil
+		}
+
+		progress, err := m.progress(ctx, migration)
+		if err != nil {
+			return i, err
+		}
+
+		logger.Info().Msg("Checking migration...")
+		records, err := m.dryRun(ctx, migration, progress)
+		if err != nil {
+			return i, fmt.Errorf("dry run of migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+		}
+
+		logger.Info().Int("records", records).Msg("Applying migration...")
+		if err := m.apply(ctx, migration, progress, versionInfo != nil, logger); err != nil {
+			return i, fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+		}
+		logger.Info().Msg("Migration applied")
+	}
+	return len(pending), nil
+}
+
+// versionInfo returns the version info of the database, nil if it's not stored.
+func (m *Migrator) versionInfo(ctx context.Context) (*types.VersionInfo, error) {
+	tx, err := m.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	versionInfo, err := ReadVersionInfo(tx)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, nil
+	}
+	return versionInfo, err
+}
+
+// progress returns the position to start (or resume) the migration from.
+func (m *Migrator) progress(ctx context.Context, migration *Migration) (*migrationProgress, error) {
+	tx, err := m.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	progress, err := readMigrationProgress(tx)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil {
+		return &migrationProgress{Version: migration.Version}, nil
+	}
+	if progress.Version != migration.Version {
+		return nil, fmt.Errorf("found progress of migration %d while migration %d is pending",
+			progress.Version, migration.Version)
+	}
+	m.logger.Info().
+		Uint64("version", migration.Version).
+		Uint64("tableIndex", progress.TableIndex).
+		Msg("Resuming interrupted migration")
+	return progress, nil
+}
+
+// dryRun converts the records without writing the results and returns the number of records to convert.
+func (m *Migrator) dryRun(ctx context.Context, migration *Migration, progress *migrationProgress) (int, error) {
+	tx, err := m.db.CreateRoTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	records := 0
+	for i := progress.TableIndex; i < uint64(len(migration.Tables)); i++ {
+		table := migration.Tables[i]
+		from := []byte(nil)
+		if i == progress.TableIndex {
+			from = nextKey(progress.LastKey)
+		}
+
+		iter, err := tx.Range(table, from, nil)
+		if err != nil {
+			return 0, err
+		}
+		for iter.HasNext() {
+			key, value, err := iter.Next()
+			if err != nil {
+				iter.Close()
+				return 0, err
+			}
+			if _, err := migration.Migrate(table, key, value); err != nil {
+				iter.Close()
+				return 0, fmt.Errorf("failed to convert record %x of table %s: %w", key, table, err)
+			}
+			records++
+		}
+		iter.Close()
+	}
+	return records, nil
+}
+
+type migrationRecord struct {
+	key   []byte
+	value []byte
+}
+
+// apply converts the records and bumps the schema version. The version info is updated as well,
+// unless the database has no version info stored.
+func (m *Migrator) apply(
+	ctx context.Context, migration *Migration, progress *migrationProgress, updateVersionInfo bool, logger zerolog.Logger,
+) error {
+	for progress.TableIndex < uint64(len(migration.Tables)) {
+		if err := m.applyTable(ctx, migration, progress, logger); err != nil {
+			return err
+		}
+	}
+
+	tx, err := m.db.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.Delete(schemeVersionTable, []byte(migrationProgressKey)); err != nil {
+		return err
+	}
+	if err := WriteSchemaVersion(tx, migration.Version); err != nil {
+		return err
+	}
+	if updateVersionInfo {
+		if err := WriteVersionInfo(tx, &types.VersionInfo{Version: migration.To}); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// applyTable converts the remaining records of the current table and moves the progress to the next table.
+func (m *Migrator) applyTable(
+	ctx context.Context, migration *Migration, progress *migrationProgress, logger zerolog.Logger,
+) error {
+	table := migration.Tables[progress.TableIndex]
+	migrated := 0
+	for {
+		n, err := m.applyBatch(ctx, migration, table, progress)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+		migrated += n
+		logger.Info().Str("table", string(table)).Int("records", migrated).Msg("Migration in progress...")
+	}
+	progress.TableIndex++
+	progress.LastKey = nil
+	return nil
+}
+
+// applyBatch converts the next batch of records of the table and commits it along with the progress.
+// Returns the number of converted records, zero if the table is done.
+func (m *Migrator) applyBatch(
+	ctx context.Context, migration *Migration, table TableName, progress *migrationProgress,
+) (int, error) {
+	tx, err := m.db.CreateRwTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Records are read before writing to avoid modifying the table under the iterator.
+	iter, err := tx.Range(table, nextKey(progress.LastKey), nil)
+	if err != nil {
+		return 0, err
+	}
+	var batch []migrationRecord
+	size := 0
+	for iter.HasNext() && len(batch) < m.BatchSize && size < m.BatchBytes {
+		key, value, err := iter.Next()
+		if err != nil {
+			iter.Close()
+			return 0, err
+		}
+		batch = append(batch, migrationRecord{key: key, value: value})
+		size += len(key) + len(value)
+	}
+	iter.Close()
+
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	for _, record := range batch {
+		value, err := migration.Migrate(table, record.key, record.value)
+		if err != nil {
+			return 0, fmt.Errorf("failed to convert record %x of table %s: %w", record.key, table, err)
+		}
+		if value == nil {
+			err = tx.Delete(table, record.key)
+		} else {
+			err = tx.Put(table, record.key, value)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	progress.LastKey = batch[len(batch)-1].key
+	if err := tx.Put(schemeVersionTable, []byte(migrationProgressKey), progress.marshal()); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(batch), nil
+}
+
+// nextKey returns the smallest key which is greater than the given one, nil for nil.
+func nextKey(key []byte) []byte {
+	if key == nil {
+		return nil
+	}
+	return append(append([]byte(nil), key...), 0)
+}
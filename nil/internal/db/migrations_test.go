@@ -0,0 +1,288 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SuiteMigrations struct {
+	suite.Suite
+	db     DB
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+const (
+	testMigrationTable = TableName("MigrationTest")
+	testShardedTable   = ShardedTableName("ShardedMigrationTest")
+)
+
+func (s *SuiteMigrations) SetupTest() {
+	var err error
+	s.db, err = NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+	for i := range 10 {
+		s.Require().NoError(tx.Put(testMigrationTable, []byte(fmt.Sprintf("key%d", i)), []byte("value")))
+	}
+	for _, shardId := range []types.ShardId{1, 2} {
+		s.Require().NoError(tx.PutToShard(shardId, testShardedTable, []byte("key"), []byte("value")))
+	}
+	s.Require().NoError(tx.Commit())
+}
+
+func (s *SuiteMigrations) TearDownTest() {
+	s.db.Close()
+	s.cancel()
+}
+
+func (s *SuiteMigrations) newMigrator(migrations ...*Migration) *Migrator {
+	s.T().Helper()
+
+	migrator, err := NewMigrator(s.db, migrations, logging.NewLogger("migrations_test"))
+	s.Require().NoError(err)
+	migrator.BatchSize = 3
+	return migrator
+}
+
+func appendSuffix(_ TableName, _, value []byte) ([]byte, error) {
+	return append(value, '+'), nil
+}
+
+func (s *SuiteMigrations) values(table TableName) map[string]string {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRoTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	iter, err := tx.Range(table, nil, nil)
+	s.Require().NoError(err)
+	defer iter.Close()
+
+	values := make(map[string]string)
+	for iter.HasNext() {
+		key, value, err := iter.Next()
+		s.Require().NoError(err)
+		values[string(key)] = string(value)
+	}
+	return values
+}
+
+func (s *SuiteMigrations) schemaVersion() uint64 {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRoTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	version, err := ReadSchemaVersion(tx)
+	s.Require().NoError(err)
+	return version
+}
+
+func (s *SuiteMigrations) TestVersionsValidation() {
+	_, err := NewMigrator(s.db, []*Migration{{Version: 2, Migrate: appendSuffix}}, zerolog.Nop())
+	s.Require().ErrorContains(err, "expected 1")
+}
+
+func (s *SuiteMigrations) TestVersionBump() {
+	_, err := NewMigrator(s.db, []*Migration{{Version: 1, Tables: []TableName{testMigrationTable}}}, zerolog.Nop())
+	s.Require().ErrorContains(err, "no Migrate function")
+
+	migrator := s.newMigrator(&Migration{Version: 1, Name: "version bump"})
+	applied, err := migrator.Run(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(1, applied)
+	s.Require().Equal(uint64(1), s.schemaVersion())
+	s.Require().Len(s.values(testMigrationTable), 10)
+}
+
+func (s *SuiteMigrations) TestApply() {
+	migrator := s.newMigrator(
+		&Migration{
+			Version: 1,
+			Name:    "append suffix",
+			Tables:  []TableName{testMigrationTable, TableName(testShardedTable)},
+			Migrate: appendSuffix,
+		},
+		&Migration{
+			Version: 2,
+			Name:    "delete odd keys",
+			Tables:  []TableName{testMigrationTable},
+			Migrate: func(_ TableName, key, value []byte) ([]byte, error) {
+				if (key[len(key)-1]-'0')%2 == 1 {
+					return nil, nil
+				}
+				return value, nil
+			},
+		},
+	)
+
+	applied, err := migrator.Run(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(2, applied)
+	s.Require().Equal(uint64(2), s.schemaVersion())
+
+	values := s.values(testMigrationTable)
+	s.Require().Len(values, 5)
+	for key, value := range values {
+		s.Require().Equal("value+", value, key)
+	}
+	s.Require().Equal(map[string]string{"1:key": "value+", "2:key": "value+"}, s.values(TableName(testShardedTable)))
+
+	tx, err := s.db.CreateRoTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+	s.Require().False(IsVersionOutdated(tx))
+
+	applied, err = migrator.Run(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Zero(applied, "migrations are applied only once")
+}
+
+func (s *SuiteMigrations) TestDryRunFailure() {
+	migrator := s.newMigrator(&Migration{
+		Version: 1,
+		Name:    "failing",
+		Tables:  []TableName{testMigrationTable},
+		Migrate: func(_ TableName, key, value []byte) ([]byte, error) {
+			if string(key) == "key7" {
+				return nil, errors.New("unexpected format")
+			}
+			return append(value, '+'), nil
+		},
+	})
+
+	_, err := migrator.Run(s.ctx)
+	s.Require().ErrorContains(err, "dry run of migration 1 (failing) failed")
+	s.Require().ErrorContains(err, "unexpected format")
+	s.Require().Zero(s.schemaVersion())
+	for key, value := range s.values(testMigrationTable) {
+		s.Require().Equal("value", value, "record %s is changed", key)
+	}
+}
+
+func (s *SuiteMigrations) TestResume() {
+	migration := &Migration{
+		Version: 1,
+		Name:    "append suffix",
+		Tables:  []TableName{TableName(testShardedTable), testMigrationTable},
+		Migrate: appendSuffix,
+	}
+	migrator := s.newMigrator(migration)
+
+	// Emulate the migration interrupted after the first batch of the second table
+	progress := &migrationProgress{Version: 1}
+	s.Require().NoError(migrator.applyTable(s.ctx, migration, progress, zerolog.Nop()))
+	_, err := migrator.applyBatch(s.ctx, migration, testMigrationTable, progress)
+	s.Require().NoError(err)
+
+	applied, err := migrator.Run(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(1, applied)
+
+	for key, value := range s.values(testMigrationTable) {
+		s.Require().Equal("value+", value, "record %s is converted exactly once", key)
+	}
+	s.Require().Equal(map[string]string{"1:key": "value+", "2:key": "value+"}, s.values(TableName(testShardedTable)))
+}
+
+func (s *SuiteMigrations) TestNewerSchema() {
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+	s.Require().NoError(WriteSchemaVersion(tx, 3))
+	s.Require().NoError(tx.Commit())
+
+	_, err = s.newMigrator().Run(s.ctx)
+	s.Require().ErrorContains(err, "newer than the latest known version")
+}
+
+func (s *SuiteMigrations) writeVersionInfo(version common.Hash) {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+	s.Require().NoError(WriteVersionInfo(tx, &types.VersionInfo{Version: version}))
+	s.Require().NoError(tx.Commit())
+}
+
+func (s *SuiteMigrations) TestVersionInfo() {
+	_, err := NewMigrator(s.db, []*Migration{
+		{Version: 1, Name: "first", To: common.HexToHash("0x01")},
+		{Version: 2, Name: "second", From: common.HexToHash("0x02")},
+	}, zerolog.Nop())
+	s.Require().ErrorContains(err, "starts from version info")
+
+	baseline := common.HexToHash("0x01")
+	latest := types.NewVersionInfo().Version
+	s.writeVersionInfo(baseline)
+	migrator := s.newMigrator(
+		&Migration{Version: 1, Name: "layout change", From: baseline, To: latest},
+		&Migration{Version: 2, Name: "version bump", From: latest, To: latest},
+	)
+
+	applied, err := migrator.Run(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(2, applied)
+
+	tx, err := s.db.CreateRoTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+	s.Require().False(IsVersionOutdated(tx))
+}
+
+// The layout changed without a migration is not marked as the current one
+func (s *SuiteMigrations) TestVersionInfoMismatch() {
+	unknown := common.HexToHash("0x03")
+	s.writeVersionInfo(unknown)
+	migrator := s.newMigrator(&Migration{
+		Version: 1,
+		Name:    "layout change",
+		From:    common.HexToHash("0x01"),
+		To:      types.NewVersionInfo().Version,
+	})
+
+	applied, err := migrator.Run(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Zero(applied)
+	s.Require().Zero(s.schemaVersion())
+
+	tx, err := s.db.CreateRoTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+	s.Require().True(IsVersionOutdated(tx))
+	versionInfo, err := ReadVersionInfo(tx)
+	s.Require().NoError(err)
+	s.Require().Equal(unknown, versionInfo.Version)
+}
+
+func TestSchemaMigrations(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewMigrator(nil, SchemaMigrations, zerolog.Nop())
+	require.NoError(t, err)
+	require.Equal(t, types.NewVersionInfo().Version, SchemaMigrations[len(SchemaMigrations)-1].To,
+		"the latest migration has to result in the current layout of types.SchemesInsideDb")
+}
+
+func TestSuiteMigrations(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(SuiteMigrations))
+}
@@ -0,0 +1,237 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/dgraph-io/badger/v4"
+)
+
+var ErrReadOnly = errors.New("the database is opened read-only")
+
+// readOnlyBadgerDB is the database opened without write access, e.g. to re-execute the stored blocks
+// while the data stays intact. Its write transactions keep the changes in memory on top of the stored data,
+// they can be read back within the transaction but can't be committed.
+type readOnlyBadgerDB struct {
+	*badgerDB
+}
+
+var _ DB = new(readOnlyBadgerDB)
+
+// NewReadOnlyBadgerDb opens the existing database in read-only mode.
+// Badger refuses to open the database if it's used by another process or wasn't closed properly.
+func NewReadOnlyBadgerDb(pathToDb string) (DB, error) {
+	opts := badger.DefaultOptions(pathToDb).WithLogger(nil).WithReadOnly(true)
+	db, err := newBadgerDb(&opts)
+	if err != nil {
+		return nil, err
+	}
+	return &readOnlyBadgerDB{db}, nil
+}
+
+func (db *readOnlyBadgerDB) CreateRwTx(ctx context.Context) (RwTx, error) {
+	tx, err := db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &overlayRwTx{RoTx: tx, changes: make(map[string][]byte)}, nil
+}
+
+func (db *readOnlyBadgerDB) DropAll() error {
+	return ErrReadOnly
+}
+
+func (db *readOnlyBadgerDB) Fetch(context.Context, io.Reader) error {
+	return ErrReadOnly
+}
+
+func (db *readOnlyBadgerDB) LogGC(context.Context, float64, time.Duration) error {
+	return ErrReadOnly
+}
+
+func (db *readOnlyBadgerDB) RunGC(context.Context, float64) error {
+	return ErrReadOnly
+}
+
+// overlayRwTx keeps the changes of the transaction in memory, a nil value marks a deleted key.
+type overlayRwTx struct {
+	RoTx
+
+	lock    sync.RWMutex
+	changes map[string][]byte
+}
+
+var _ RwTx = new(overlayRwTx)
+
+func (tx *overlayRwTx) Put(tableName TableName, key, value []byte) error {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	// The value is never nil to distinguish it from a deleted key
+	tx.changes[string(MakeKey(tableName, key))] = append([]byte{}, value...)
+	return nil
+}
+
+func (tx *overlayRwTx) Delete(tableName TableName, key []byte) error {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	tx.changes[string(MakeKey(tableName, key))] = nil
+	return nil
+}
+
+func (tx *overlayRwTx) change(tableName TableName, key []byte) ([]byte, bool) {
+	tx.lock.RLock()
+	defer tx.lock.RUnlock()
+	value, ok := tx.changes[string(MakeKey(tableName, key))]
+	return value, ok
+}
+
+func (tx *overlayRwTx) Get(tableName TableName, key []byte) ([]byte, error) {
+	if value, ok := tx.change(tableName, key); ok {
+		if value == nil {
+			return nil, ErrKeyNotFound
+		}
+		return bytes.Clone(value), nil
+	}
+	return tx.RoTx.Get(tableName, key)
+}
+
+func (tx *overlayRwTx) Exists(tableName TableName, key []byte) (bool, error) {
+	if value, ok := tx.change(tableName, key); ok {
+		return value != nil, nil
+	}
+	return tx.RoTx.Exists(tableName, key)
+}
+
+func (tx *overlayRwTx) Range(tableName TableName, from []byte, to []byte) (Iter, error) {
+	base, err := tx.RoTx.Range(tableName, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := tableName + ":"
+	fromKey := string(MakeKey(tableName, from))
+	var toKey string
+	if to != nil {
+		toKey = string(MakeKey(tableName, to))
+	}
+
+	tx.lock.RLock()
+	var keys []string
+	for key := range tx.changes {
+		if strings.HasPrefix(key, string(prefix)) && key >= fromKey && (to == nil || key <= toKey) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	changes := make([]overlayRecord, len(keys))
+	for i, key := range keys {
+		changes[i] = overlayRecord{key: []byte(key[len(prefix):]), value: tx.changes[key]}
+	}
+	tx.lock.RUnlock()
+
+	return &overlayIter{base: base, changes: changes}, nil
+}
+
+func (tx *overlayRwTx) ExistsInShard(shardId types.ShardId, tableName ShardedTableName, key []byte) (bool, error) {
+	return tx.Exists(ShardTableName(tableName, shardId), key)
+}
+
+func (tx *overlayRwTx) GetFromShard(shardId types.ShardId, tableName ShardedTableName, key []byte) ([]byte, error) {
+	return tx.Get(ShardTableName(tableName, shardId), key)
+}
+
+func (tx *overlayRwTx) RangeByShard(
+	shardId types.ShardId, tableName ShardedTableName, from []byte, to []byte,
+) (Iter, error) {
+	return tx.Range(ShardTableName(tableName, shardId), from, to)
+}
+
+func (tx *overlayRwTx) PutToShard(shardId types.ShardId, tableName ShardedTableName, key, value []byte) error {
+	return tx.Put(ShardTableName(tableName, shardId), key, value)
+}
+
+func (tx *overlayRwTx) DeleteFromShard(shardId types.ShardId, tableName ShardedTableName, key []byte) error {
+	return tx.Delete(ShardTableName(tableName, shardId), key)
+}
+
+func (tx *overlayRwTx) Commit() error {
+	tx.Rollback()
+	return ErrReadOnly
+}
+
+func (tx *overlayRwTx) CommitWithTs() (Timestamp, error) {
+	tx.Rollback()
+	return 0, ErrReadOnly
+}
+
+type overlayRecord struct {
+	key   []byte
+	value []byte
+	err   error
+}
+
+// overlayIter merges the changes of the transaction with the stored records, both are sorted by key.
+type overlayIter struct {
+	base    Iter
+	stored  *overlayRecord
+	changes []overlayRecord
+}
+
+// skip drops the stored record replaced by the change and the deleted keys
+// until the next record to return is found.
+func (it *overlayIter) skip() {
+	for {
+		if it.stored == nil && it.base.HasNext() {
+			key, value, err := it.base.Next()
+			it.stored = &overlayRecord{key: key, value: value, err: err}
+		}
+		if len(it.changes) == 0 {
+			return
+		}
+		change := it.changes[0]
+		if it.stored != nil && it.stored.err == nil {
+			switch cmp := bytes.Compare(it.stored.key, change.key); {
+			case cmp < 0:
+				return
+			case cmp == 0:
+				it.stored = nil
+			}
+		}
+		if change.value != nil {
+			return
+		}
+		it.changes = it.changes[1:]
+	}
+}
+
+func (it *overlayIter) HasNext() bool {
+	it.skip()
+	return it.stored != nil || len(it.changes) > 0
+}
+
+func (it *overlayIter) Next() ([]byte, []byte, error) {
+	it.skip()
+	if it.stored != nil && (len(it.changes) == 0 || it.stored.err != nil ||
+		bytes.Compare(it.stored.key, it.changes[0].key) < 0) {
+		record := it.stored
+		it.stored = nil
+		return record.key, record.value, record.err
+	}
+	if len(it.changes) == 0 {
+		return nil, nil, ErrKeyNotFound
+	}
+	record := it.changes[0]
+	it.changes = it.changes[1:]
+	return record.key, bytes.Clone(record.value), nil
+}
+
+func (it *overlayIter) Close() {
+	it.base.Close()
+}
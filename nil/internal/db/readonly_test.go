@@ -0,0 +1,80 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlyBadgerDb(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	path := t.TempDir()
+
+	database, err := NewBadgerDb(path)
+	require.NoError(t, err)
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	for _, key := range []string{"a", "c", "e", "g"} {
+		require.NoError(t, tx.Put("tbl", []byte(key), []byte("stored-"+key)))
+	}
+	require.NoError(t, tx.Put("other", []byte("b"), []byte("other")))
+	require.NoError(t, tx.Commit())
+	database.Close()
+
+	readOnly, err := NewReadOnlyBadgerDb(path)
+	require.NoError(t, err)
+
+	rwTx, err := readOnly.CreateRwTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, rwTx.Put("tbl", []byte("b"), []byte("new-b")))
+	require.NoError(t, rwTx.Put("tbl", []byte("c"), []byte("new-c")))
+	require.NoError(t, rwTx.Delete("tbl", []byte("e")))
+	require.NoError(t, rwTx.Put("tbl", []byte("h"), nil))
+
+	value, err := rwTx.Get("tbl", []byte("c"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new-c"), value)
+	_, err = rwTx.Get("tbl", []byte("e"))
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	exists, err := rwTx.Exists("tbl", []byte("h"))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	rangeKeys := func(from, to []byte) []string {
+		t.Helper()
+		iter, err := rwTx.Range("tbl", from, to)
+		require.NoError(t, err)
+		defer iter.Close()
+		var res []string
+		for iter.HasNext() {
+			key, value, err := iter.Next()
+			require.NoError(t, err)
+			res = append(res, string(key)+"="+string(value))
+		}
+		return res
+	}
+	assert.Equal(t, []string{"a=stored-a", "b=new-b", "c=new-c", "g=stored-g", "h="}, rangeKeys(nil, nil))
+	assert.Equal(t, []string{"b=new-b", "c=new-c", "g=stored-g"}, rangeKeys([]byte("b"), []byte("g")))
+
+	require.ErrorIs(t, rwTx.Commit(), ErrReadOnly)
+	rwTx.Rollback()
+	require.ErrorIs(t, readOnly.DropAll(), ErrReadOnly)
+	readOnly.Close()
+
+	// The stored data is intact
+	database, err = NewBadgerDb(path)
+	require.NoError(t, err)
+	defer database.Close()
+	roTx, err := database.CreateRoTx(ctx)
+	require.NoError(t, err)
+	defer roTx.Rollback()
+	value, err = roTx.Get("tbl", []byte("e"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("stored-e"), value)
+	exists, err = roTx.Exists("tbl", []byte("b"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -16,6 +16,8 @@ const (
 	blockTimestampTable  = ShardedTableName("BlockTimestamp")
 	codeTable            = ShardedTableName("Code")
 	shardBlocksTrieTable = ShardedTableName("ShardBlocksTrie")
+	stateDiffTable       = ShardedTableName("StateDiff")
+	resourceUsageTable   = ShardedTableName("ResourceUsage")
 
 	ContractTrieTable                                = ShardedTableName("ContractTrie")
 	StorageTrieTable                                 = ShardedTableName("StorageTrie")
@@ -28,11 +30,13 @@ const (
 	BlockHashAndInTransactionIndexByTransactionHash  = ShardedTableName("BlockHashAndInTransactionIndexByTransactionHash")
 	BlockHashAndOutTransactionIndexByTransactionHash = ShardedTableName("BlockHashAndOutTransactionIndexByTransactionHash")
 	AsyncCallContextTable                            = ShardedTableName("AsyncCallContext")
+	ScheduledCallTrieTable                           = ShardedTableName("ScheduledCallTrie")
 
-	collatorStateTable          = TableName("CollatorState")
-	errorByTransactionHashTable = TableName("ErrorByTransactionHash")
-	schemeVersionTable          = TableName("SchemeVersion")
-	LastBlockTable              = TableName("LastBlock")
+	collatorStateTable               = TableName("CollatorState")
+	errorByTransactionHashTable      = TableName("ErrorByTransactionHash")
+	revertDataByTransactionHashTable = TableName("RevertDataByTransactionHash")
+	schemeVersionTable               = TableName("SchemeVersion")
+	LastBlockTable                   = TableName("LastBlock")
 )
 
 func ShardTableName(tableName ShardedTableName, shardId types.ShardId) TableName {
@@ -30,6 +30,9 @@ func NewDbMock(dbImpl DB) *DBMock {
 		LogGCFunc: func(ctx context.Context, discardRation float64, gcFrequency time.Duration) error {
 			return dbImpl.LogGC(ctx, discardRation, gcFrequency)
 		},
+		RunGCFunc: func(ctx context.Context, discardRation float64) error {
+			return dbImpl.RunGC(ctx, discardRation)
+		},
 		FetchFunc: func(ctx context.Context, reader io.Reader) error {
 			return dbImpl.Fetch(ctx, reader)
 		},
@@ -3,6 +3,7 @@ package execution
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
@@ -117,6 +118,23 @@ func NewAccountState(es IExecutionState, addr types.Address, account *types.Smar
 	return accountState, nil
 }
 
+// moveTo binds the account to another execution state, e.g. when the changes of a speculative execution are adopted.
+// The tries keep their roots, so the unchanged data is read from the transaction of the new state.
+func (as *AccountState) moveTo(es IExecutionState) {
+	shardId := as.address.ShardId()
+	storageRoot := as.StorageTree.RootHash()
+	tokenRoot := as.TokenTree.RootHash()
+	asyncContextRoot := as.AsyncContextTree.RootHash()
+
+	as.db = es
+	as.StorageTree = NewDbStorageTrie(es.GetRwTx(), shardId)
+	as.StorageTree.SetRootHash(storageRoot)
+	as.TokenTree = NewDbTokenTrie(es.GetRwTx(), shardId)
+	as.TokenTree.SetRootHash(tokenRoot)
+	as.AsyncContextTree = NewDbAsyncContextTrie(es.GetRwTx(), shardId)
+	as.AsyncContextTree.SetRootHash(asyncContextRoot)
+}
+
 func (as *AccountState) empty() bool {
 	return as.Seqno == 0 && as.Balance.IsZero() && len(as.Code) == 0
 }
@@ -255,12 +273,20 @@ func (as *AccountState) SetAsyncContext(index types.TransactionIndex, ctx *types
 }
 
 func (as *AccountState) GetAndRemoveAsyncContext(index types.TransactionIndex) (*types.AsyncContext, error) {
+	// The context could be already removed by a response in this block, e.g. the failure response of a timeout.
+	if slices.Contains(as.AsyncContextRemoved, index) {
+		return nil, db.ErrKeyNotFound
+	}
 	ctx, exists := as.AsyncContext[index]
 	if exists {
 		return ctx, nil
 	}
+	ctx, err := as.AsyncContextTree.Fetch(index)
+	if err != nil {
+		return nil, err
+	}
 	as.AsyncContextRemoved = append(as.AsyncContextRemoved, index)
-	return as.AsyncContextTree.Fetch(index)
+	return ctx, nil
 }
 
 func (as *AccountState) setCode(codeHash common.Hash, code []byte) {
@@ -0,0 +1,78 @@
+package execution
+
+import (
+	"errors"
+	"math"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/holiman/uint256"
+)
+
+// ValidateBatchTransaction checks that the data of a batched transaction is a valid batch payload.
+// The calls of a batch don't transfer value or tokens, so the transaction must not have them.
+func ValidateBatchTransaction(transaction *types.Transaction) types.ExecError {
+	check.PanicIfNot(transaction.IsBatch())
+
+	if !transaction.Value.IsZero() || len(transaction.Token) > 0 {
+		return types.NewVerboseError(types.ErrorInvalidPayload, "batched transaction can't transfer value or tokens")
+	}
+
+	if _, err := types.ParseBatchPayload(transaction.Data); err != nil {
+		return types.KeepOrWrapError(types.ErrorInvalidPayload, err)
+	}
+	return nil
+}
+
+// handleBatchTransaction executes the calls of a batched transaction one by one sharing the gas of the transaction.
+// The calls transfer no value, the transactions with value or tokens are rejected by ValidateBatchTransaction.
+// A failed call is reverted by the EVM. If the batch is atomic, the whole transaction is reverted as well
+// and the error of the call becomes the error of the transaction.
+func (es *ExecutionState) handleBatchTransaction(caller vm.ContractRef, transaction *types.Transaction) *ExecutionResult {
+	gas := es.txnFeeCredit.ToGas(es.GasPrice)
+
+	payload, err := types.ParseBatchPayload(transaction.Data)
+	if err != nil {
+		return NewExecutionResult().
+			SetError(types.KeepOrWrapError(types.ErrorInvalidPayload, err)).
+			SetUsed(0, es.GasPrice)
+	}
+
+	res := NewExecutionResult().SetUsed(0, es.GasPrice)
+	res.BatchReceipts = make([]*types.BatchCallReceipt, 0, len(payload.Calls))
+	for _, call := range payload.Calls {
+		es.evm.DebugInfo = nil
+		left := gas - res.GasUsed
+		ret, leftOver, err := es.evm.Call(caller, transaction.To, call.Data, left.Uint64(), uint256.NewInt(0))
+		used := left - types.Gas(leftOver)
+		res.AddUsed(used)
+
+		receipt := &types.BatchCallReceipt{
+			Success: err == nil,
+			Status:  types.ErrorSuccess,
+			GasUsed: used,
+		}
+		res.BatchReceipts = append(res.BatchReceipts, receipt)
+		if err == nil {
+			res.SetReturnData(ret)
+			continue
+		}
+
+		var callErr types.ExecError
+		if !errors.As(err, &callErr) {
+			return res.SetFatal(err)
+		}
+		receipt.Status = callErr.Code()
+		if es.evm.DebugInfo != nil {
+			check.PanicIfNot(es.evm.DebugInfo.Pc <= math.MaxUint32)
+			receipt.FailedPc = uint32(es.evm.DebugInfo.Pc)
+		}
+
+		if payload.Atomic {
+			es.RevertToSnapshot(es.revertId)
+			return res.SetError(callErr).SetReturnData(ret).SetDebugInfo(es.evm.DebugInfo)
+		}
+	}
+	return res
+}
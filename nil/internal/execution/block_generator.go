@@ -3,7 +3,10 @@ package execution
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/assert"
@@ -21,6 +24,12 @@ type BlockGeneratorParams struct {
 	TraceEVM         bool
 	MainKeysPath     string
 	DisableConsensus bool
+	// RecordStateDiff enables writing the state diff of every generated block
+	RecordStateDiff bool
+	// RecordResourceUsage enables writing the gas and storage used by the contracts in every generated block
+	RecordResourceUsage bool
+	// ParallelExecution enables the optimistic parallel execution of the transactions of a block, see parallel.go
+	ParallelExecution bool
 }
 
 func NewBlockGeneratorParams(shardId types.ShardId, nShards uint32) BlockGeneratorParams {
@@ -70,6 +79,8 @@ func NewBlockGenerator(ctx context.Context, params BlockGeneratorParams, txFabri
 		return nil, err
 	}
 	executionState.TraceVm = params.TraceEVM
+	executionState.RecordStateDiff = params.RecordStateDiff
+	executionState.RecordResourceUsage = params.RecordResourceUsage
 
 	const mhName = "github.com/NilFoundation/nil/nil/internal/execution"
 	mh, err := NewMetricsHandler(mhName, params.ShardId)
@@ -215,22 +226,22 @@ func (g *BlockGenerator) prepareExecutionState(proposal *Proposal, gasPrices []t
 		return err
 	}
 
-	if err := g.updateGasPrices(gasPrices); err != nil {
-		return fmt.Errorf("failed to update gas prices: %w", err)
-	}
-
 	g.executionState.MainChainHash = proposal.MainChainHash
 
-	for _, txn := range proposal.InternalTxns {
-		if err := g.handleTxn(txn); err != nil {
-			return err
-		}
+	// The rules are selected by the height, so replayed blocks are executed by the rules they were produced with.
+	if err := g.executionState.UpdateFork(proposal.PrevBlockId + 1); err != nil {
+		return fmt.Errorf("failed to select fork: %w", err)
 	}
 
-	for _, txn := range proposal.ExternalTxns {
-		if err := g.handleTxn(txn); err != nil {
-			return err
-		}
+	if err := g.updateGasPrices(gasPrices); err != nil {
+		return fmt.Errorf("failed to update gas prices: %w", err)
+	}
+
+	txns := make([]*types.Transaction, 0, len(proposal.InternalTxns)+len(proposal.ExternalTxns))
+	txns = append(txns, proposal.InternalTxns...)
+	txns = append(txns, proposal.ExternalTxns...)
+	if err := g.handleTxns(txns); err != nil {
+		return err
 	}
 
 	for _, txn := range proposal.ForwardTxns {
@@ -245,38 +256,63 @@ func (g *BlockGenerator) prepareExecutionState(proposal *Proposal, gasPrices []t
 	return nil
 }
 
-func (g *BlockGenerator) handleTxn(txn *types.Transaction) error {
-	if txn.IsDeploy() {
-		g.counters.DeployTransactions++
+func (g *BlockGenerator) handleTxns(txns []*types.Transaction) error {
+	if g.canExecuteInParallel(txns) {
+		return g.handleTxnsInParallel(txns)
 	}
-	if txn.IsExecution() {
-		g.counters.ExecTransactions++
+	for _, txn := range txns {
+		if err := g.handleTxn(txn); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+func (g *BlockGenerator) handleTxn(txn *types.Transaction) error {
+	g.executionState.AddInTransaction(txn)
+	return g.addTxnResult(txn, g.executeTxn(g.executionState, txn))
+}
+
+// executeTxn executes the transaction against the given state. The transaction must be already added to the state.
+func (g *BlockGenerator) executeTxn(es *ExecutionState, txn *types.Transaction) *ExecutionResult {
 	var txnHash common.Hash
 	if assert.Enable {
 		txnHash = txn.Hash()
 	}
 
 	var res *ExecutionResult
-	g.executionState.AddInTransaction(txn)
 	if txn.IsInternal() {
-		res = g.handleInternalInTransaction(txn)
-		g.counters.InternalTransactions++
+		res = g.handleInternalInTransaction(es, txn)
 	} else {
-		res = g.handleExternalTransaction(txn)
-		g.counters.ExternalTransactions++
+		res = g.handleExternalTransaction(es, txn)
 	}
 
 	if assert.Enable {
 		check.PanicIfNotf(txnHash == txn.Hash(), "Transaction hash changed during execution")
 	}
+	return res
+}
+
+// addTxnResult adds the receipt of the current inbound transaction and updates the counters.
+func (g *BlockGenerator) addTxnResult(txn *types.Transaction, res *ExecutionResult) error {
+	if txn.IsDeploy() {
+		g.counters.DeployTransactions++
+	}
+	if txn.IsExecution() {
+		g.counters.ExecTransactions++
+	}
+	if txn.IsInternal() {
+		g.counters.InternalTransactions++
+	} else {
+		g.counters.ExternalTransactions++
+	}
 
 	if res.FatalError != nil {
 		return res.FatalError
 	}
 	g.addReceipt(res)
 	g.counters.CoinsUsed = g.counters.CoinsUsed.Add(res.CoinsUsed())
+	g.counters.GasUsed += res.GasUsed
 
 	return nil
 }
@@ -288,6 +324,46 @@ func (g *BlockGenerator) BuildBlock(proposal *Proposal, gasPrices []types.Uint25
 	return g.executionState.BuildBlock(proposal.PrevBlockId + 1)
 }
 
+// AccountDiff is the state of an account after the built block compared to the expected one.
+// A nil state means that the account doesn't exist.
+type AccountDiff struct {
+	Address  types.Address        `json:"address"`
+	Expected *types.SmartContract `json:"expected"`
+	Actual   *types.SmartContract `json:"actual"`
+}
+
+// DiffAccounts compares the accounts touched by the built block with their state in the contract trie
+// with the given root (e.g. the SmartContractsRoot of the original block when a block is re-executed).
+// Only the accounts which differ are returned.
+func (g *BlockGenerator) DiffAccounts(expectedRoot common.Hash) ([]AccountDiff, error) {
+	expectedTree := NewDbContractTrieReader(g.rwTx, g.params.ShardId)
+	expectedTree.SetRootHash(expectedRoot)
+
+	notFoundAsNil := func(contract *types.SmartContract, err error) (*types.SmartContract, error) {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return contract, err
+	}
+
+	var diffs []AccountDiff
+	for addr := range g.executionState.Accounts {
+		expected, err := notFoundAsNil(expectedTree.Fetch(addr.Hash()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read expected state of %s: %w", addr, err)
+		}
+		actual, err := notFoundAsNil(g.executionState.ContractTree.Fetch(addr.Hash()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state of %s: %w", addr, err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			diffs = append(diffs, AccountDiff{Address: addr, Expected: expected, Actual: actual})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Address.Hex() < diffs[j].Address.Hex() })
+	return diffs, nil
+}
+
 func (g *BlockGenerator) GenerateBlock(proposal *Proposal, params *types.ConsensusParams) (*BlockGenerationResult, error) {
 	g.mh.StartProcessingMeasurement(g.ctx, g.executionState.GasPrice, proposal.PrevBlockId+1)
 	defer func() { g.mh.EndProcessingMeasurement(g.ctx, g.counters) }()
@@ -313,27 +389,33 @@ func ValidateInternalTransaction(transaction *types.Transaction) error {
 	return nil
 }
 
-func (g *BlockGenerator) handleInternalInTransaction(txn *types.Transaction) *ExecutionResult {
+func (g *BlockGenerator) handleInternalInTransaction(es *ExecutionState, txn *types.Transaction) *ExecutionResult {
 	if err := ValidateInternalTransaction(txn); err != nil {
 		g.logger.Warn().Err(err).Msg("Invalid internal transaction")
 		return NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorValidation, err))
 	}
+	if txn.IsScheduled() {
+		if err := es.ConsumeScheduledCall(txn); err != nil {
+			g.logger.Warn().Err(err).Msg("Invalid scheduled call")
+			return NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorValidation, err))
+		}
+	}
 
-	return g.executionState.HandleTransaction(g.ctx, txn, NewTransactionPayer(txn, g.executionState))
+	return es.HandleTransaction(g.ctx, txn, NewTransactionPayer(txn, es))
 }
 
-func (g *BlockGenerator) handleExternalTransaction(txn *types.Transaction) *ExecutionResult {
-	verifyResult := ValidateExternalTransaction(g.executionState, txn)
+func (g *BlockGenerator) handleExternalTransaction(es *ExecutionState, txn *types.Transaction) *ExecutionResult {
+	verifyResult := ValidateExternalTransaction(es, txn)
 	if verifyResult.Failed() {
 		g.logger.Error().Err(verifyResult.Error).Msg("External transaction validation failed.")
 		return verifyResult
 	}
 
-	acc, err := g.executionState.GetAccount(txn.To)
+	payer, err := NewExternalTransactionPayer(es, txn)
 	// Validation cached the account.
 	check.PanicIfErr(err)
 
-	res := g.executionState.HandleTransaction(g.ctx, txn, NewAccountPayer(acc, txn))
+	res := es.HandleTransaction(g.ctx, txn, payer)
 	res.AddUsed(verifyResult.GasUsed)
 	return res
 }
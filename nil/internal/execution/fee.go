@@ -10,7 +10,7 @@ import (
 var lock sync.Mutex
 
 func (es *ExecutionState) UpdateBaseFee(prevBlock *types.Block) error {
-	es.BaseFee = calculateBaseFee(prevBlock.BaseFee, prevBlock.GasUsed)
+	es.BaseFee = es.Rules().BaseFee(prevBlock.BaseFee, prevBlock.GasUsed)
 	if es.BaseFee.Cmp(prevBlock.BaseFee) != 0 {
 		logger.Debug().
 			Stringer("Old", prevBlock.BaseFee).
@@ -0,0 +1,121 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/params"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// Rules holds the execution rules of a fork which are implemented outside the VM.
+// Opcodes and precompiled contracts of the fork are selected by the VM itself.
+type Rules struct {
+	// BaseFee calculates the base fee of a block from the base fee and the gas used by the previous block.
+	BaseFee func(prevBaseFee types.Value, prevGasUsed types.Gas) types.Value
+
+	// ValidateExternalTransaction checks whether the external transaction can be included in a block.
+	ValidateExternalTransaction func(es *ExecutionState, txn *types.Transaction) *ExecutionResult
+}
+
+// forkRules maps every known fork to its rules.
+// A new fork copies the rules of the previous one and replaces the changed functions,
+// the functions of the previous forks are never modified to keep historical blocks replayable.
+var forkRules = map[params.Fork]*Rules{
+	params.ForkGenesis: {
+		BaseFee:                     calculateBaseFee,
+		ValidateExternalTransaction: validateExternalTransaction,
+	},
+	params.ForkPaymasters: {
+		BaseFee:                     calculateBaseFee,
+		ValidateExternalTransaction: validateSponsoredExternalTransaction,
+	},
+	params.ForkBatchTransactions: {
+		BaseFee:                     calculateBaseFee,
+		ValidateExternalTransaction: validateBatchedExternalTransaction,
+	},
+	params.ForkScheduledCalls: {
+		BaseFee:                     calculateBaseFee,
+		ValidateExternalTransaction: validateBatchedExternalTransaction,
+	},
+	params.ForkAsyncTimeouts: {
+		BaseFee:                     calculateBaseFee,
+		ValidateExternalTransaction: validateBatchedExternalTransaction,
+	},
+	params.ForkConsensusMessageDomain: {
+		BaseFee:                     calculateBaseFee,
+		ValidateExternalTransaction: validateBatchedExternalTransaction,
+	},
+}
+
+// RulesForFork returns the rules of the fork. The fork must be known to the node,
+// which is checked when the fork is resolved from the config, see config.GetActiveFork.
+// The VM selects the opcodes and the precompiled contracts of the fork under the same assumption.
+func RulesForFork(fork params.Fork) *Rules {
+	rules, ok := forkRules[fork]
+	check.PanicIfNotf(ok, "no execution rules for fork %s", fork)
+	return rules
+}
+
+// Rules returns the rules of the fork active for the block being built.
+func (es *ExecutionState) Rules() *Rules {
+	return RulesForFork(es.Fork)
+}
+
+// UpdateFork selects the fork for the block with the given id from the fork schedule of the config
+// and recalculates the base fee by the formula of the fork.
+// The fork is activated by the height of the main shard: the block itself for the main shard
+// and the block referenced by MainChainHash for the other shards.
+func (es *ExecutionState) UpdateFork(blockId types.BlockNumber) error {
+	fork := params.ForkGenesis
+	// The stub accessor is used when the config is not needed, e.g. for the zerostate of the shards.
+	if _, isStub := es.configAccessor.(*config.ConfigAccessorStub); es.configAccessor != nil && !isStub {
+		mainShardHeight, err := es.mainShardHeight(blockId)
+		if err != nil {
+			return err
+		}
+		if fork, err = config.GetActiveFork(es.configAccessor, mainShardHeight); err != nil {
+			return err
+		}
+	}
+	if fork != es.Fork {
+		logger.Debug().
+			Stringer(logging.FieldShardId, es.ShardId).
+			Stringer(logging.FieldBlockNumber, blockId).
+			Stringer("fork", fork).
+			Msg("Fork selected")
+	}
+	es.Fork = fork
+
+	prevBlock, err := es.shardAccessor.GetBlock().ByHash(es.PrevBlock)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return es.UpdateBaseFee(prevBlock.Block())
+}
+
+// mainShardHeight returns the height of the main shard the fork is selected by.
+// The main chain block must be known: falling back to the local head of the main shard
+// would make the rules of the replayed blocks depend on the state of the node.
+func (es *ExecutionState) mainShardHeight(blockId types.BlockNumber) (types.BlockNumber, error) {
+	if es.ShardId.IsMainShard() {
+		return blockId, nil
+	}
+	// Only the blocks produced before the main chain is started don't reference it
+	if es.MainChainHash.Empty() {
+		return 0, nil
+	}
+
+	mainBlock, err := db.ReadBlock(es.tx, types.MainShardId, es.MainChainHash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read main chain block %s to select the fork: %w", es.MainChainHash, err)
+	}
+	return mainBlock.Id, nil
+}
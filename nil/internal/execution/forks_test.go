@@ -0,0 +1,114 @@
+package execution
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/params"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForkSchedule(t *testing.T) {
+	t.Parallel()
+
+	forks := &config.ParamForks{Schedule: []config.ForkActivation{
+		{Fork: uint32(params.ForkGenesis), Height: 0},
+		{Fork: uint32(params.LatestFork) + 1, Height: 10},
+	}}
+	require.NoError(t, forks.Validate())
+	require.Equal(t, params.ForkGenesis, forks.ActiveFork(9))
+	require.Equal(t, params.LatestFork+1, forks.ActiveFork(10))
+	require.Equal(t, params.ForkGenesis, new(config.ParamForks).ActiveFork(100))
+
+	require.ErrorContains(t, (&config.ParamForks{Schedule: []config.ForkActivation{
+		{Fork: 2, Height: 0},
+		{Fork: 1, Height: 10},
+	}}).Validate(), "fork 1 is scheduled after fork 2")
+	require.ErrorContains(t, (&config.ParamForks{Schedule: []config.ForkActivation{
+		{Fork: 1, Height: 10},
+		{Fork: 2, Height: 5},
+	}}).Validate(), "before fork 1")
+}
+
+func TestKnownForks(t *testing.T) {
+	t.Parallel()
+
+	for fork := params.ForkGenesis; fork.IsKnown(); fork++ {
+		require.NotNil(t, RulesForFork(fork), fork)
+		require.NotEqual(t, fmt.Sprintf("fork#%d", fork), fork.String())
+	}
+
+	zeroState, err := CreateDefaultZeroStateConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, params.LatestFork, zeroState.ConfigParams.Forks.ActiveFork(0))
+}
+
+func TestUpdateFork(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	configAccessor, err := config.NewConfigAccessorTx(tx, nil)
+	require.NoError(t, err)
+
+	t.Run("NoSchedule", func(t *testing.T) {
+		fork, err := config.GetActiveFork(configAccessor, 100)
+		require.NoError(t, err)
+		require.Equal(t, params.ForkGenesis, fork)
+	})
+
+	state, err := NewExecutionState(tx, types.MainShardId, StateParams{ConfigAccessor: configAccessor})
+	require.NoError(t, err)
+
+	zeroState, err := ParseZeroStateConfig(fmt.Sprintf(`
+config:
+  forks:
+    schedule:
+    - fork: %d
+      height: 10
+`, params.LatestFork+1))
+	require.NoError(t, err)
+	require.NoError(t, state.GenerateZeroState(zeroState))
+	require.Equal(t, params.ForkGenesis, state.Fork)
+
+	require.NoError(t, state.UpdateFork(9))
+	require.Equal(t, params.ForkGenesis, state.Fork)
+
+	blockContext, err := NewEVMBlockContext(state)
+	require.NoError(t, err)
+	require.Equal(t, params.ForkGenesis, blockContext.Fork)
+
+	// The node doesn't know the rules of the scheduled fork, so it must not produce or replay the block
+	require.ErrorContains(t, state.UpdateFork(10), "upgrade the node")
+
+	t.Run("Shard", func(t *testing.T) {
+		shardState, err := NewExecutionState(tx, types.BaseShardId, StateParams{ConfigAccessor: configAccessor})
+		require.NoError(t, err)
+
+		// The fork of a shard block is selected by its main chain block, which must be known
+		shardState.MainChainHash = common.HexToHash("0x01")
+		require.ErrorIs(t, shardState.UpdateFork(100), db.ErrKeyNotFound)
+
+		writeMainBlock := func(id types.BlockNumber) common.Hash {
+			mainBlock := &types.Block{BlockData: types.BlockData{Id: id}}
+			hash := mainBlock.Hash(types.MainShardId)
+			require.NoError(t, db.WriteBlock(tx, types.MainShardId, hash, mainBlock))
+			return hash
+		}
+
+		shardState.MainChainHash = writeMainBlock(9)
+		require.NoError(t, shardState.UpdateFork(100))
+		require.Equal(t, params.ForkGenesis, shardState.Fork)
+
+		shardState.MainChainHash = writeMainBlock(10)
+		require.ErrorContains(t, shardState.UpdateFork(100), "upgrade the node")
+	})
+}
@@ -25,6 +25,7 @@ type IRevertableExecutionState interface {
 	revertTransientStorageChange(addr types.Address, key common.Hash, prevValue common.Hash)
 	revertOutTransactionsChange(index int, txnHash common.Hash)
 	revertAsyncContextChange(addr types.Address, requestId types.TransactionIndex)
+	revertScheduledCallChange(id common.Hash, prev *types.ScheduledCall, existed bool)
 }
 
 // JournalEntry is a modification entry in the state change journal that can be
@@ -134,6 +135,11 @@ type (
 		account   *types.Address
 		requestId types.TransactionIndex
 	}
+	scheduledCallChange struct {
+		id      common.Hash
+		prev    *types.ScheduledCall
+		existed bool
+	}
 )
 
 func (ch createObjectChange) revert(s IRevertableExecutionState) {
@@ -192,6 +198,10 @@ func (ch asyncContextChange) revert(s IRevertableExecutionState) {
 	s.revertAsyncContextChange(*ch.account, ch.requestId)
 }
 
+func (ch scheduledCallChange) revert(s IRevertableExecutionState) {
+	s.revertScheduledCallChange(ch.id, ch.prev, ch.existed)
+}
+
 type ExecutionStateRevertableWrapper struct {
 	es *ExecutionState
 }
@@ -305,3 +315,13 @@ func (w *ExecutionStateRevertableWrapper) revertAsyncContextChange(addr types.Ad
 		delete(account.AsyncContext, requestId)
 	}
 }
+
+func (w *ExecutionStateRevertableWrapper) revertScheduledCallChange(
+	id common.Hash, prev *types.ScheduledCall, existed bool,
+) {
+	if existed {
+		w.es.scheduledCalls[id] = prev
+	} else {
+		delete(w.es.scheduledCalls, id)
+	}
+}
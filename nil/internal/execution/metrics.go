@@ -16,6 +16,7 @@ type MetricsHandler struct {
 
 	// Histograms
 	coinsUsedHistogram telemetry.Histogram
+	gasUsedHistogram   telemetry.Histogram
 
 	// Counters
 	internalTxnCounter telemetry.Counter
@@ -34,6 +35,7 @@ type BlockGeneratorCounters struct {
 	DeployTransactions   int64
 	ExecTransactions     int64
 	CoinsUsed            types.Value
+	GasUsed              types.Gas
 }
 
 func NewBlockGeneratorCounters() *BlockGeneratorCounters {
@@ -72,6 +74,11 @@ func (mh *MetricsHandler) initMetrics(meter metric.Meter) error {
 		return err
 	}
 
+	mh.gasUsedHistogram, err = meter.Int64Histogram("gas_used", metric.WithDescription("Gas used per block"))
+	if err != nil {
+		return err
+	}
+
 	// Initialize counters
 	mh.internalTxnCounter, err = meter.Int64Counter("internal_transactions_processed")
 	if err != nil {
@@ -120,4 +127,5 @@ func (mh *MetricsHandler) EndProcessingMeasurement(ctx context.Context, counters
 	mh.deployTxnCounter.Add(ctx, counters.DeployTransactions, mh.option)
 	mh.execTxnCounter.Add(ctx, counters.ExecTransactions, mh.option)
 	mh.coinsUsedHistogram.Record(ctx, int64(counters.CoinsUsed.Uint64()), mh.option)
+	mh.gasUsedHistogram.Record(ctx, int64(counters.GasUsed), mh.option)
 }
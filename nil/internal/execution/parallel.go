@@ -0,0 +1,302 @@
+package execution
+
+import (
+	"maps"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// The transactions of a block can be executed optimistically in parallel.
+// First, every transaction is speculatively executed by a pool of workers against its own copy of the state
+// at the start of the block. The accounts accessed and changed by the transaction are recorded.
+// Then the speculations are validated in the block order. A speculation is valid if none of the accounts
+// it accessed were changed by the preceding transactions, in this case its changes are adopted as is.
+// Otherwise, the transaction is re-executed against the state of the block just like in the sequential execution.
+// So the result is always the same as the result of the sequential execution.
+
+// accountAccess holds the fields of an account, which are changed without journal entries,
+// at the first access to the account.
+type accountAccess struct {
+	requestId            uint64
+	asyncContextsRemoved int
+}
+
+// speculation is the result of the speculative execution of a transaction.
+type speculation struct {
+	state *ExecutionState
+	res   *ExecutionResult
+	// written holds the accounts changed by the transaction
+	written map[types.Address]struct{}
+
+	// The values of the state fields, which are carried over from the previous transactions, at the start.
+	refund       uint64
+	wasAwaitCall bool
+}
+
+func (es *ExecutionState) recordAccountAccess(addr types.Address, acc *AccountState) {
+	if _, ok := es.accessedAccounts[addr]; ok {
+		return
+	}
+	var access accountAccess
+	if acc != nil {
+		access.requestId = acc.requestId
+		access.asyncContextsRemoved = len(acc.AsyncContextRemoved)
+	}
+	es.accessedAccounts[addr] = access
+}
+
+// writtenAccounts returns the accounts changed since the given journal length.
+// The accesses to the accounts must be recorded during the whole period.
+func (es *ExecutionState) writtenAccounts(journalStart int) map[types.Address]struct{} {
+	written := make(map[types.Address]struct{})
+	for _, entry := range es.journal.entries[journalStart:] {
+		if addr, ok := journalEntryAccount(entry); ok {
+			written[addr] = struct{}{}
+		}
+	}
+	for addr, access := range es.accessedAccounts {
+		acc, ok := es.Accounts[addr]
+		if ok && (acc.requestId != access.requestId || len(acc.AsyncContextRemoved) != access.asyncContextsRemoved) {
+			written[addr] = struct{}{}
+		}
+	}
+	return written
+}
+
+// journalEntryAccount returns the account changed by the journal entry, if any.
+func journalEntryAccount(entry JournalEntry) (types.Address, bool) {
+	switch e := entry.(type) {
+	case createObjectChange:
+		return *e.account, true
+	case createContractChange:
+		return e.account, true
+	case selfDestructChange:
+		return *e.account, true
+	case balanceChange:
+		return *e.account, true
+	case tokenChange:
+		return *e.account, true
+	case seqnoChange:
+		return *e.account, true
+	case extSeqnoChange:
+		return *e.account, true
+	case storageChange:
+		return *e.account, true
+	case codeChange:
+		return *e.account, true
+	case transientStorageChange:
+		return *e.account, true
+	case asyncContextChange:
+		return *e.account, true
+	}
+	return types.Address{}, false
+}
+
+// newSpeculativeState creates a state for the speculative execution of a transaction.
+// The state es must not have changes yet, so that both states read the accounts from the same contract trie.
+func (es *ExecutionState) newSpeculativeState(tx db.RoTx) (*ExecutionState, error) {
+	rwTx := &db.RwWrapper{RoTx: tx}
+	res := &ExecutionState{
+		tx:               rwTx,
+		PrevBlock:        es.PrevBlock,
+		MainChainHash:    es.MainChainHash,
+		ShardId:          es.ShardId,
+		ChildChainBlocks: map[types.ShardId]common.Hash{},
+		Accounts:         map[types.Address]*AccountState{},
+		OutTransactions:  map[common.Hash][]*types.OutboundTransaction{},
+		Logs:             map[common.Hash][]*types.Log{},
+		DebugLogs:        map[common.Hash][]*types.DebugLog{},
+		Errors:           map[common.Hash]error{},
+		RevertData:       map[common.Hash][]byte{},
+		scheduledCalls:   map[common.Hash]*types.ScheduledCall{},
+
+		journal:          newJournal(),
+		transientStorage: newTransientStorage(),
+		refund:           es.refund,
+		wasAwaitCall:     es.wasAwaitCall,
+		accessedAccounts: map[types.Address]accountAccess{},
+
+		shardAccessor:  NewStateAccessor().Access(rwTx, es.ShardId),
+		configAccessor: es.configAccessor,
+
+		BaseFee:  es.BaseFee,
+		GasPrice: es.GasPrice,
+		Fork:     es.Fork,
+		TraceVm:  es.TraceVm,
+	}
+
+	return res, res.initTries()
+}
+
+// isValid reports whether the speculation can be adopted by the state es
+// after the preceding transactions changed the written accounts.
+func (s *speculation) isValid(es *ExecutionState, txn *types.Transaction, written map[types.Address]struct{}) bool {
+	if s == nil || s.res.IsFatal() {
+		return false
+	}
+	if s.res.Error != nil {
+		switch s.res.Error.Code() {
+		case types.ErrorPanicDuringExecution:
+			return false
+		case types.ErrorForwardingFailed:
+			// The failed forwarding reverts the state to the last snapshot of the block.
+			return false
+		}
+	}
+	// The flag is only checked by requests and responses.
+	if (txn.IsRequest() || txn.IsResponse()) && s.wasAwaitCall != es.wasAwaitCall {
+		return false
+	}
+	// The scheduled calls aren't tracked per key, so the transactions using them are re-executed.
+	if s.state.scheduledCallsAccessed {
+		return false
+	}
+	for addr := range s.state.accessedAccounts {
+		if _, ok := written[addr]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// adopt applies the changes of the valid speculation to the state. The transaction must be already added to the state.
+func (es *ExecutionState) adopt(s *speculation) {
+	spec := s.state
+	for addr := range s.written {
+		if acc, ok := spec.Accounts[addr]; ok {
+			acc.moveTo(es)
+			es.Accounts[addr] = acc
+		}
+		if storage, ok := spec.transientStorage[addr]; ok {
+			es.transientStorage[addr] = storage
+		}
+	}
+	es.journal.entries = append(es.journal.entries, spec.journal.entries...)
+
+	txnHash := es.InTransactionHash
+	if txns, ok := spec.OutTransactions[txnHash]; ok {
+		es.OutTransactions[txnHash] = txns
+	}
+	if logs, ok := spec.Logs[txnHash]; ok {
+		es.Logs[txnHash] = logs
+	}
+	if logs, ok := spec.DebugLogs[txnHash]; ok {
+		es.DebugLogs[txnHash] = logs
+	}
+
+	es.GasUsed += spec.GasUsed
+	es.GasPrice = spec.GasPrice
+	es.txnFeeCredit = spec.txnFeeCredit
+	// Only the change of the counter is applied, it doesn't affect the execution of the other transactions.
+	es.refund += spec.refund - s.refund
+	es.wasAwaitCall = es.wasAwaitCall || spec.wasAwaitCall
+}
+
+// canExecuteInParallel reports whether the transactions can be executed in parallel.
+// The transactions of the main shard can change the config, so they are always executed sequentially.
+func (g *BlockGenerator) canExecuteInParallel(txns []*types.Transaction) bool {
+	es := g.executionState
+	return g.params.ParallelExecution &&
+		!g.params.ShardId.IsMainShard() &&
+		len(txns) > 1 &&
+		len(es.Accounts) == 0 &&
+		len(es.transientStorage) == 0 &&
+		len(es.InTransactions) == 0
+}
+
+func (g *BlockGenerator) handleTxnsInParallel(txns []*types.Transaction) error {
+	specs := g.speculate(txns)
+
+	es := g.executionState
+	written := make(map[types.Address]struct{})
+	reExecuted := 0
+	for i, txn := range txns {
+		var res *ExecutionResult
+		if s := specs[i]; s.isValid(es, txn, written) {
+			es.AddInTransaction(txn)
+			es.adopt(s)
+			maps.Copy(written, s.written)
+			res = s.res
+		} else {
+			reExecuted++
+			journalStart := es.journal.length()
+			es.accessedAccounts = make(map[types.Address]accountAccess)
+			es.AddInTransaction(txn)
+			res = g.executeTxn(es, txn)
+			maps.Copy(written, es.writtenAccounts(journalStart))
+			es.accessedAccounts = nil
+		}
+
+		if err := g.addTxnResult(txn, res); err != nil {
+			return err
+		}
+	}
+
+	g.logger.Debug().
+		Int("txns", len(txns)).
+		Int("reExecuted", reExecuted).
+		Msg("Executed transactions in parallel")
+	return nil
+}
+
+// speculate executes every transaction against its own copy of the state by a pool of workers.
+// A nil speculation means that the transaction failed to be executed speculatively.
+func (g *BlockGenerator) speculate(txns []*types.Transaction) []*speculation {
+	specs := make([]*speculation, len(txns))
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(txns)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			tx, err := g.txFabric.CreateRoTx(g.ctx)
+			if err != nil {
+				g.logger.Warn().Err(err).Msg("Failed to create transaction for speculative execution")
+				return
+			}
+			defer tx.Rollback()
+
+			for i := int(next.Add(1) - 1); i < len(txns); i = int(next.Add(1) - 1) {
+				specs[i] = g.speculateTxn(tx, txns[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	return specs
+}
+
+func (g *BlockGenerator) speculateTxn(tx db.RoTx, txn *types.Transaction) (s *speculation) {
+	defer func() {
+		if r := recover(); r != nil {
+			g.logger.Debug().
+				Stringer(logging.FieldTransactionHash, txn.Hash()).
+				Msgf("Speculative execution panicked: %v", r)
+			s = nil
+		}
+	}()
+
+	es, err := g.executionState.newSpeculativeState(tx)
+	if err != nil {
+		g.logger.Debug().Err(err).Msg("Failed to create state for speculative execution")
+		return nil
+	}
+
+	s = &speculation{
+		state:        es,
+		refund:       es.refund,
+		wasAwaitCall: es.wasAwaitCall,
+	}
+	es.AddInTransaction(txn)
+	s.res = g.executeTxn(es, txn)
+	s.written = es.writtenAccounts(0)
+	return s
+}
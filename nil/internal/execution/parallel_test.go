@@ -0,0 +1,227 @@
+package execution
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+// counterCode deploys a contract which on every call increments the counter in slot 0,
+// increments the transient counter and stores it in slot 1 and emits a log.
+var counterCode = hexutil.MustDecode("0x" +
+	// copy the runtime code to the memory and return it
+	"601c80600b6000396000f3" +
+	// sstore(0, sload(0) + 1)
+	"600054600101600055" +
+	// t := tload(0) + 1; tstore(0, t); sstore(1, t)
+	"60005c60010180" + "60005d600155" +
+	// log0(0, 0); stop
+	"60006000a0" + "00")
+
+// executionDiff generates the same blocks by the sequential and the parallel execution
+// and checks that the results are the same.
+type executionDiff struct {
+	shardId types.ShardId
+
+	sequential db.DB
+	parallel   db.DB
+
+	mainChainHash common.Hash
+	prevBlockId   types.BlockNumber
+	prevBlockHash common.Hash
+}
+
+func newExecutionDiff(t *testing.T, shardId types.ShardId) *executionDiff {
+	t.Helper()
+
+	d := &executionDiff{shardId: shardId}
+	for _, database := range []*db.DB{&d.sequential, &d.parallel} {
+		var err error
+		*database, err = db.NewBadgerDbInMemory()
+		require.NoError(t, err)
+		t.Cleanup((*database).Close)
+
+		d.mainChainHash = d.generateZeroState(t, *database, types.MainShardId)
+		d.prevBlockHash = d.generateZeroState(t, *database, shardId)
+	}
+	return d
+}
+
+func (d *executionDiff) generateZeroState(t *testing.T, database db.DB, shardId types.ShardId) common.Hash {
+	t.Helper()
+
+	g, err := NewBlockGenerator(t.Context(), NewBlockGeneratorParams(shardId, 2), database, nil)
+	require.NoError(t, err)
+	defer g.Rollback()
+
+	block, err := g.GenerateZeroState(&ZeroStateConfig{
+		ConfigParams: ConfigParams{
+			GasPrice: config.ParamGasPrice{
+				Shards: []types.Uint256{*types.NewUint256(10), *types.NewUint256(10)},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return block.Hash(shardId)
+}
+
+func (d *executionDiff) generate(t *testing.T, database db.DB, parallel bool, proposal *Proposal) (*types.Block, *types.BlockStateDiff) {
+	t.Helper()
+
+	tx, err := database.CreateRoTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	prevBlock, err := db.ReadBlock(tx, d.shardId, d.prevBlockHash)
+	require.NoError(t, err)
+
+	params := NewBlockGeneratorParams(d.shardId, 2)
+	params.RecordStateDiff = true
+	params.ParallelExecution = parallel
+	g, err := NewBlockGenerator(t.Context(), params, database, prevBlock)
+	require.NoError(t, err)
+	defer g.Rollback()
+
+	res, err := g.GenerateBlock(proposal, &types.ConsensusParams{})
+	require.NoError(t, err)
+
+	tx, err = database.CreateRoTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	diff, err := db.ReadStateDiff(tx, d.shardId, res.BlockHash)
+	require.NoError(t, err)
+
+	return res.Block, diff
+}
+
+// generateBlock generates the next block from the transactions and returns its state diff.
+func (d *executionDiff) generateBlock(t *testing.T, txns ...*types.Transaction) *types.BlockStateDiff {
+	t.Helper()
+
+	proposal := &Proposal{
+		PrevBlockId:   d.prevBlockId,
+		PrevBlockHash: d.prevBlockHash,
+		MainChainHash: d.mainChainHash,
+		InternalTxns:  txns,
+	}
+	expectedBlock, expectedDiff := d.generate(t, d.sequential, false, proposal)
+	block, diff := d.generate(t, d.parallel, true, proposal)
+
+	require.Equal(t, expectedDiff, diff)
+	require.Equal(t, expectedBlock, block)
+
+	d.prevBlockId++
+	d.prevBlockHash = block.Hash(d.shardId)
+	return diff
+}
+
+type txnBuilder struct {
+	shardId types.ShardId
+	sender  types.Address
+	seqno   types.Seqno
+}
+
+func (b *txnBuilder) next(to types.Address, value uint64) *types.Transaction {
+	b.seqno++
+	return &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			Flags:        types.NewTransactionFlags(types.TransactionFlagInternal),
+			To:           to,
+			Seqno:        b.seqno,
+			FeeCredit:    types.GasToValue(1_000_000),
+			MaxFeePerGas: types.MaxFeePerGasDefault,
+		},
+		From:     b.sender,
+		RefundTo: b.sender,
+		Value:    types.NewValueFromUint64(value),
+	}
+}
+
+func (b *txnBuilder) deploy(salt uint64) *types.Transaction {
+	b.seqno++
+	txn := NewDeployTransaction(types.BuildDeployPayload(counterCode, common.IntToHash(int(salt))),
+		b.shardId, b.sender, b.seqno, types.NewValueFromUint64(1000))
+	txn.RefundTo = b.sender
+	return txn
+}
+
+func findAccountDiff(t *testing.T, diff []types.AccountStateDiff, addr types.Address) types.AccountStateDiff {
+	t.Helper()
+
+	for _, acc := range diff {
+		if acc.Address == addr {
+			return acc
+		}
+	}
+	require.Failf(t, "account is not changed", "%s", addr)
+	return types.AccountStateDiff{}
+}
+
+func TestParallelExecutionMatchesSequential(t *testing.T) {
+	t.Parallel()
+
+	shardId := types.ShardId(1)
+	d := newExecutionDiff(t, shardId)
+	b := &txnBuilder{shardId: shardId, sender: types.GenerateRandomAddress(shardId)}
+
+	const nCounters = 8
+	counters := make([]types.Address, nCounters)
+	deploys := make([]*types.Transaction, 0, nCounters+1)
+	for i := range nCounters {
+		txn := b.deploy(uint64(i))
+		counters[i] = txn.To
+		deploys = append(deploys, txn)
+	}
+	// The second deployment to the same address conflicts with the first one
+	deploys = append(deploys, b.deploy(0))
+	diff := d.generateBlock(t, deploys...)
+	for _, addr := range counters {
+		require.True(t, findAccountDiff(t, diff.Accounts, addr).Created)
+	}
+
+	t.Run("Conflicts", func(t *testing.T) {
+		recipient := types.GenerateRandomAddress(shardId)
+		txns := []*types.Transaction{
+			b.next(counters[0], 0),
+			b.next(counters[1], 0),
+			b.next(counters[0], 0),
+			b.next(recipient, 10),
+			b.next(counters[2], 5),
+			b.next(counters[0], 0),
+			b.next(recipient, 20),
+			b.next(counters[3], 0),
+		}
+		diff := d.generateBlock(t, txns...)
+
+		counter := findAccountDiff(t, diff.Accounts, counters[0])
+		require.Equal(t, []types.StorageDiff{
+			{Key: common.IntToHash(0), Before: common.EmptyHash, After: common.IntToHash(3)},
+			// The transient storage isn't cleared between the transactions of a block
+			{Key: common.IntToHash(1), Before: common.EmptyHash, After: common.IntToHash(3)},
+		}, counter.Storage)
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		rnd := rand.New(rand.NewPCG(1, 2))
+		recipients := []types.Address{
+			types.GenerateRandomAddress(shardId),
+			types.GenerateRandomAddress(shardId),
+		}
+		for range 5 {
+			txns := make([]*types.Transaction, 0, 50)
+			for range cap(txns) {
+				if rnd.IntN(4) == 0 {
+					txns = append(txns, b.next(recipients[rnd.IntN(len(recipients))], rnd.Uint64N(100)))
+				} else {
+					txns = append(txns, b.next(counters[rnd.IntN(nCounters)], 0))
+				}
+			}
+			d.generateBlock(t, txns...)
+		}
+	})
+}
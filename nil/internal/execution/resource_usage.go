@@ -0,0 +1,83 @@
+package execution
+
+import (
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// transactionSelector returns the selector of the method called by the transaction
+// or nil if the transaction doesn't call a method.
+func transactionSelector(txn *types.Transaction) types.Code {
+	if !txn.IsExecution() || txn.IsResponse() || txn.IsBounce() || txn.IsRefund() || txn.IsBatch() || len(txn.Data) < 4 {
+		return nil
+	}
+	return txn.Data[:4]
+}
+
+// beginTransactionResourceUsage marks the start of the changes of the current inbound transaction.
+func (es *ExecutionState) beginTransactionResourceUsage() {
+	if es.RecordResourceUsage {
+		es.txnResourceUsageStart = es.journal.length()
+	}
+}
+
+// endTransactionResourceUsage adds the gas used by the current inbound transaction to its recipient
+// and the storage slots created and deleted by the transaction to their contracts.
+func (es *ExecutionState) endTransactionResourceUsage(gasUsed types.Gas) {
+	if !es.RecordResourceUsage {
+		return
+	}
+
+	get := func(addr types.Address) *types.ContractResourceUsage {
+		usage, ok := es.resourceUsage[addr]
+		if !ok {
+			usage = &types.ContractResourceUsage{Address: addr}
+			es.resourceUsage[addr] = usage
+		}
+		return usage
+	}
+
+	txn := es.GetInTransaction()
+	usage := get(txn.To)
+	usage.Transactions++
+	usage.GasUsed += gasUsed
+	usage.AddSelector(transactionSelector(txn), 1, gasUsed)
+
+	// The journal keeps the previous values, so the value before the first entry of a slot
+	// is the value before the transaction.
+	type slot struct {
+		addr types.Address
+		key  common.Hash
+	}
+	before := make(map[slot]common.Hash)
+	for _, entry := range es.journal.entries[es.txnResourceUsageStart:] {
+		if e, ok := entry.(storageChange); ok {
+			s := slot{addr: *e.account, key: e.key}
+			if _, ok := before[s]; !ok {
+				before[s] = e.prevvalue
+			}
+		}
+	}
+	for s, prev := range before {
+		acc, ok := es.Accounts[s.addr]
+		if !ok {
+			continue
+		}
+		switch after := acc.State[s.key]; {
+		case prev.Empty() && !after.Empty():
+			get(s.addr).SlotsCreated++
+		case !prev.Empty() && after.Empty():
+			get(s.addr).SlotsDeleted++
+		}
+	}
+}
+
+// BlockResourceUsage returns the usage of the resources by the contracts in the block so far.
+func (es *ExecutionState) BlockResourceUsage() *types.ResourceUsage {
+	res := &types.ResourceUsage{Contracts: make([]*types.ContractResourceUsage, 0, len(es.resourceUsage))}
+	for _, usage := range es.resourceUsage {
+		res.Contracts = append(res.Contracts, usage)
+	}
+	res.Sort()
+	return res
+}
@@ -0,0 +1,107 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceUsage(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	configAccessor, err := config.NewConfigAccessorTx(tx, nil)
+	require.NoError(t, err)
+
+	shardId := types.BaseShardId
+	addrA := types.GenerateRandomAddress(shardId)
+	addrB := types.GenerateRandomAddress(shardId)
+	key1, key2, key3 := common.IntToHash(1), common.IntToHash(2), common.IntToHash(3)
+
+	state, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: configAccessor})
+	require.NoError(t, err)
+	require.NoError(t, state.CreateAccount(addrA))
+	require.NoError(t, state.SetState(addrA, key1, common.IntToHash(1)))
+	prevBlock, err := state.Commit(0, nil)
+	require.NoError(t, err)
+
+	_, err = db.ReadResourceUsage(tx, shardId, prevBlock.BlockHash)
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+	state, err = NewExecutionState(tx, shardId, StateParams{Block: prevBlock.Block, ConfigAccessor: configAccessor})
+	require.NoError(t, err)
+	state.RecordResourceUsage = true
+
+	call := func(to types.Address, seqno types.Seqno, data []byte, gas types.Gas, change func()) {
+		t.Helper()
+
+		txn := types.NewEmptyTransaction()
+		txn.To = to
+		txn.Seqno = seqno
+		txn.Data = data
+		state.AddInTransaction(txn)
+		change()
+		state.AddReceipt(NewExecutionResult().SetUsed(gas, types.DefaultGasPrice))
+	}
+
+	call(addrA, 1, []byte{1, 2, 3, 4, 5}, 100, func() {
+		require.NoError(t, state.SetState(addrA, key1, common.EmptyHash))
+		require.NoError(t, state.SetState(addrA, key2, common.IntToHash(2)))
+		// Slots created and deleted by the same transaction are not counted
+		require.NoError(t, state.SetState(addrA, key3, common.IntToHash(3)))
+		require.NoError(t, state.SetState(addrA, key3, common.EmptyHash))
+	})
+	call(addrA, 2, []byte{1, 2, 3, 4}, 50, func() {
+		// Reverted changes are not counted
+		snapshot := state.Snapshot()
+		require.NoError(t, state.SetState(addrA, key3, common.IntToHash(3)))
+		state.RevertToSnapshot(snapshot)
+	})
+	// The slots are counted for the changed contract, the gas for the recipient
+	call(addrB, 3, nil, 400, func() {
+		require.NoError(t, state.SetState(addrA, key3, common.IntToHash(3)))
+	})
+
+	blockRes, err := state.Commit(1, nil)
+	require.NoError(t, err)
+
+	usage, err := db.ReadResourceUsage(tx, shardId, blockRes.BlockHash)
+	require.NoError(t, err)
+
+	contractA := &types.ContractResourceUsage{
+		Address:      addrA,
+		Transactions: 2,
+		GasUsed:      150,
+		SlotsCreated: 2,
+		SlotsDeleted: 1,
+		Selectors: []*types.SelectorUsage{
+			{Selector: types.Code{1, 2, 3, 4}, Transactions: 2, GasUsed: 150},
+		},
+	}
+	contractB := &types.ContractResourceUsage{
+		Address:      addrB,
+		Transactions: 1,
+		GasUsed:      400,
+		Selectors: []*types.SelectorUsage{
+			{Selector: types.Code{}, Transactions: 1, GasUsed: 400},
+		},
+	}
+	require.Equal(t, []*types.ContractResourceUsage{contractB, contractA}, usage.Contracts)
+
+	usage.Merge(usage)
+	require.Len(t, usage.Contracts, 2)
+	require.Equal(t, addrB, usage.Contracts[0].Address)
+	require.Equal(t, types.Gas(800), usage.Contracts[0].GasUsed)
+	require.Equal(t, uint64(4), usage.Contracts[1].Transactions)
+	require.Equal(t, uint64(4), usage.Contracts[1].SlotsCreated)
+	require.Equal(t, types.Gas(300), usage.Contracts[1].Selectors[0].GasUsed)
+}
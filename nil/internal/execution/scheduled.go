@@ -0,0 +1,300 @@
+package execution
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+)
+
+// Contracts can schedule calls to the contracts of their shard to be executed in a future block.
+// A scheduled call is an internal transaction with the Scheduled flag, which fee credit and value are withdrawn
+// from the owner when the call is scheduled. The calls are stored in the scheduled call trie of the shard
+// by the hashes of their transactions. The proposer includes the due calls ahead of the transactions from the pool,
+// and the execution of such transaction removes the call from the trie, so it can be executed only once.
+// The owner can cancel the call before it's executed and get the withdrawn funds back.
+//
+// The calls are ordered in the trie by the position of the chain they're due at, see scheduledCallKey,
+// so that the proposer finds the due calls without scanning the whole trie. The same trie maps the ids of the calls
+// to their keys. All keys are 32 bytes long, so that the trie doesn't hash them and keeps their order.
+
+// scheduledCallClock is the position of the chain the call is ordered by. It's the first byte of the key of the call,
+// while the keys of the ids start with scheduledCallIdPrefix.
+type scheduledCallClock byte
+
+const (
+	scheduledCallIdPrefix byte = 0
+
+	scheduledCallByBlock          scheduledCallClock = 1
+	scheduledCallByTime           scheduledCallClock = 2
+	scheduledCallByMainShardBlock scheduledCallClock = 3
+)
+
+// scheduledCallKey returns the key of the call: the clock, the big-endian bound of the call by the clock
+// and the prefix of the id. The clock is the height of the main shard if it's bounded, the time if only it's bounded
+// and the block number otherwise. The other bounds are checked when the call is reached by the clock.
+func scheduledCallKey(id common.Hash, call *types.ScheduledCall) common.Hash {
+	clock, bound := scheduledCallByBlock, uint64(call.BlockId)
+	switch {
+	case call.MainShardBlockId != 0:
+		clock, bound = scheduledCallByMainShardBlock, uint64(call.MainShardBlockId)
+	case call.BlockId == 0 && call.Timestamp != 0:
+		clock, bound = scheduledCallByTime, call.Timestamp
+	}
+
+	var key common.Hash
+	key[0] = byte(clock)
+	binary.BigEndian.PutUint64(key[1:9], bound)
+	copy(key[9:], id[:])
+	return key
+}
+
+// scheduledCallIdKey returns the key of the id of the call.
+func scheduledCallIdKey(id common.Hash) common.Hash {
+	id[0] = scheduledCallIdPrefix
+	return id
+}
+
+// GetScheduledCall returns the scheduled call with the given id or nil if there is no such call.
+func (es *ExecutionState) GetScheduledCall(id common.Hash) (*types.ScheduledCall, error) {
+	es.scheduledCallsAccessed = true
+	if call, ok := es.scheduledCalls[id]; ok {
+		return call, nil
+	}
+	key, err := es.scheduledCallIds.Fetch(scheduledCallIdKey(id))
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return es.ScheduledCallTree.Fetch(*key)
+}
+
+func (es *ExecutionState) setScheduledCall(id common.Hash, call *types.ScheduledCall) {
+	es.scheduledCallsAccessed = true
+	prev, existed := es.scheduledCalls[id]
+	es.journal.append(scheduledCallChange{id: id, prev: prev, existed: existed})
+	es.scheduledCalls[id] = call
+}
+
+// ScheduleCall registers the call to be executed in the first block not earlier than the given block number and time.
+// The fee credit and the value of the call must be already withdrawn from the caller. It returns the id of the call.
+func (es *ExecutionState) ScheduleCall(
+	caller types.Address, payload *types.InternalTransactionPayload, blockId types.BlockNumber, timestamp uint64,
+) (common.Hash, error) {
+	if payload.To.ShardId() != es.ShardId {
+		return common.EmptyHash, types.NewError(types.ErrorScheduledCallToOtherShard)
+	}
+
+	// Unlike outbound transactions, the seqno is always increased, so that the ids of the calls are unique.
+	seqno, err := es.GetSeqno(caller)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	if seqno+1 < seqno {
+		return common.EmptyHash, vm.ErrNonceUintOverflow
+	}
+	if err := es.SetSeqno(caller, seqno+1); err != nil {
+		return common.EmptyHash, err
+	}
+
+	txn := payload.ToTransaction(caller, seqno)
+	txn.Flags.SetBit(types.TransactionFlagScheduled)
+	txn.MaxPriorityFeePerGas = es.GetInTransaction().MaxPriorityFeePerGas
+	txn.MaxFeePerGas = es.GetInTransaction().MaxFeePerGas
+
+	id := txn.Hash()
+	es.setScheduledCall(id, &types.ScheduledCall{
+		Transaction: txn,
+		BlockId:     blockId,
+		Timestamp:   timestamp,
+	})
+
+	logger.Debug().
+		Stringer("id", id).
+		Stringer("owner", caller).
+		Uint64("blockId", blockId.Uint64()).
+		Uint64("timestamp", timestamp).
+		Msg("Scheduled call")
+
+	return id, nil
+}
+
+// CancelScheduledCall removes the call owned by the caller, see types.ScheduledCall.Owner. It returns the removed call,
+// so that the withdrawn funds can be returned to the caller.
+func (es *ExecutionState) CancelScheduledCall(caller types.Address, id common.Hash) (*types.ScheduledCall, error) {
+	call, err := es.GetScheduledCall(id)
+	if err != nil {
+		return nil, err
+	}
+	if call == nil || call.Owner() != caller {
+		return nil, types.NewError(types.ErrorScheduledCallNotFound)
+	}
+	es.setScheduledCall(id, nil)
+	return call, nil
+}
+
+// ConsumeScheduledCall removes the call executed by the transaction. The call must be due in the current block.
+func (es *ExecutionState) ConsumeScheduledCall(txn *types.Transaction) error {
+	id := txn.Hash()
+	call, err := es.GetScheduledCall(id)
+	if err != nil {
+		return err
+	}
+	if call == nil {
+		return types.NewError(types.ErrorScheduledCallNotFound)
+	}
+
+	blockId, timestamp, mainShardBlockId, err := es.currentBlockPosition()
+	if err != nil {
+		return err
+	}
+	if !call.IsDue(blockId, timestamp, mainShardBlockId) {
+		return types.NewError(types.ErrorScheduledCallNotDue)
+	}
+
+	es.setScheduledCall(id, nil)
+	return nil
+}
+
+// DueScheduledCalls returns up to limit transactions of the calls which are due in the current block.
+// Only the calls scheduled in the previous blocks and not changed in the current one are considered.
+func (es *ExecutionState) DueScheduledCalls(limit int) ([]*types.Transaction, error) {
+	blockId, timestamp, mainShardBlockId, err := es.currentBlockPosition()
+	if err != nil {
+		return nil, err
+	}
+
+	clocks := []struct {
+		clock scheduledCallClock
+		now   uint64
+	}{
+		{scheduledCallByBlock, uint64(blockId)},
+		{scheduledCallByTime, timestamp},
+		{scheduledCallByMainShardBlock, uint64(mainShardBlockId)},
+	}
+
+	var res []*types.Transaction
+	for _, c := range clocks {
+		for key, value := range es.ScheduledCallTree.IterateFrom([]byte{byte(c.clock)}) {
+			if len(res) >= limit {
+				return res, nil
+			}
+			if key[0] != byte(c.clock) || binary.BigEndian.Uint64(key[1:9]) > c.now {
+				break
+			}
+
+			call := &types.ScheduledCall{}
+			if err := call.UnmarshalSSZ(value); err != nil {
+				return nil, err
+			}
+			if _, ok := es.scheduledCalls[call.Transaction.Hash()]; ok {
+				continue
+			}
+			if call.IsDue(blockId, timestamp, mainShardBlockId) {
+				res = append(res, call.Transaction)
+			}
+		}
+	}
+	return res, nil
+}
+
+// currentBlockPosition returns the number and the time of the current block as they are seen by the EVM
+// and the height of the main shard seen by the block.
+func (es *ExecutionState) currentBlockPosition() (types.BlockNumber, uint64, types.BlockNumber, error) {
+	blockContext, err := NewEVMBlockContext(es)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	blockId := types.BlockNumber(blockContext.BlockNumber)
+	mainShardBlockId, err := es.mainShardHeight(blockId)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return blockId, blockContext.Time, mainShardBlockId, nil
+}
+
+func (es *ExecutionState) commitScheduledCalls() error {
+	for id, call := range es.scheduledCalls {
+		idKey := scheduledCallIdKey(id)
+
+		// The call could be scheduled and removed in the same block, so it may be missing in the trie
+		key, err := es.scheduledCallIds.Fetch(idKey)
+		switch {
+		case err == nil:
+			if err := es.ScheduledCallTree.Delete(*key); err != nil {
+				return err
+			}
+			if err := es.scheduledCallIds.Delete(idKey); err != nil {
+				return err
+			}
+		case !errors.Is(err, db.ErrKeyNotFound):
+			return err
+		}
+
+		if call != nil {
+			key := scheduledCallKey(id, call)
+			if err := es.ScheduledCallTree.Update(key, call); err != nil {
+				return err
+			}
+			if err := es.scheduledCallIds.Update(idKey, &key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// scheduleRequestTimeout schedules the failure response to the request, which is executed
+// if the response doesn't arrive until the main shard grows by the given number of blocks.
+// The response doesn't return the value of the request, because the request can still be executed.
+// It's sent on behalf of the callee, but only the caller can cancel it. It returns the id of the scheduled response.
+func (es *ExecutionState) scheduleRequestTimeout(request *types.Transaction, timeout uint64) (common.Hash, error) {
+	_, _, mainShardBlockId, err := es.currentBlockPosition()
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	if uint64(mainShardBlockId)+timeout < timeout {
+		return common.EmptyHash, types.NewError(types.ErrorAsyncCallTimeoutTooBig)
+	}
+
+	data, err := (&types.AsyncResponsePayload{Success: false}).MarshalSSZ()
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	payload := &types.InternalTransactionPayload{
+		Kind:        types.ResponseTransactionKind,
+		FeeCredit:   types.NewZeroValue(),
+		ForwardKind: types.ForwardKindNone,
+		To:          request.From,
+		RefundTo:    request.RefundTo,
+		BounceTo:    request.From,
+		Value:       types.NewZeroValue(),
+		Data:        data,
+		RequestId:   request.RequestId,
+	}
+	txn := payload.ToTransaction(request.To, request.Seqno)
+	txn.Flags.SetBit(types.TransactionFlagScheduled)
+	txn.MaxPriorityFeePerGas = request.MaxPriorityFeePerGas
+	txn.MaxFeePerGas = request.MaxFeePerGas
+	txn.RequestChain = request.RequestChain
+
+	id := txn.Hash()
+	es.setScheduledCall(id, &types.ScheduledCall{
+		Transaction:      txn,
+		MainShardBlockId: mainShardBlockId + types.BlockNumber(timeout),
+	})
+
+	logger.Debug().
+		Stringer("id", id).
+		Stringer("caller", request.From).
+		Uint64("requestId", request.RequestId).
+		Uint64("mainShardBlockId", uint64(mainShardBlockId)+timeout).
+		Msg("Scheduled request timeout")
+
+	return id, nil
+}
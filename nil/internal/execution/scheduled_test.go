@@ -0,0 +1,328 @@
+package execution
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledCalls(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	configAccessor, err := config.NewConfigAccessorTx(tx, nil)
+	require.NoError(t, err)
+
+	shardId := types.BaseShardId
+	owner := types.GenerateRandomAddress(shardId)
+	stranger := types.GenerateRandomAddress(shardId)
+	payload := &types.InternalTransactionPayload{
+		Kind:      types.ExecutionTransactionKind,
+		FeeCredit: types.NewValueFromUint64(1000),
+		To:        types.GenerateRandomAddress(shardId),
+		RefundTo:  owner,
+		BounceTo:  owner,
+		Value:     types.NewValueFromUint64(5),
+	}
+
+	newState := func(prev *BlockGenerationResult) *ExecutionState {
+		t.Helper()
+
+		params := StateParams{ConfigAccessor: configAccessor}
+		if prev != nil {
+			params.Block = prev.Block
+		}
+		state, err := NewExecutionState(tx, shardId, params)
+		require.NoError(t, err)
+		state.AddInTransaction(types.NewEmptyTransaction())
+		return state
+	}
+
+	state := newState(nil)
+	require.NoError(t, state.CreateAccount(owner))
+
+	otherShard := *payload
+	otherShard.To = types.GenerateRandomAddress(shardId + 1)
+	_, err = state.ScheduleCall(owner, &otherShard, 2, 0)
+	require.Equal(t, types.ErrorScheduledCallToOtherShard, types.GetErrorCode(err))
+
+	id, err := state.ScheduleCall(owner, payload, 2, 0)
+	require.NoError(t, err)
+	cancelled, err := state.ScheduleCall(owner, payload, 2, 0)
+	require.NoError(t, err)
+	require.NotEqual(t, id, cancelled)
+
+	_, err = state.CancelScheduledCall(stranger, cancelled)
+	require.Equal(t, types.ErrorScheduledCallNotFound, types.GetErrorCode(err))
+	call, err := state.CancelScheduledCall(owner, cancelled)
+	require.NoError(t, err)
+	require.Equal(t, payload.Value, call.Transaction.Value)
+
+	state.AddReceipt(NewExecutionResult())
+	block0, err := state.Commit(0, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, common.EmptyHash, block0.Block.ScheduledCallsRoot)
+
+	// The call isn't due in block 1
+	state = newState(block0)
+	due, err := state.DueScheduledCalls(10)
+	require.NoError(t, err)
+	require.Empty(t, due)
+
+	call, err = state.GetScheduledCall(id)
+	require.NoError(t, err)
+	require.NotNil(t, call)
+	require.True(t, call.Transaction.IsScheduled())
+	require.Equal(t, types.ErrorScheduledCallNotDue, types.GetErrorCode(state.ConsumeScheduledCall(call.Transaction)))
+
+	cancelledCall, err := state.GetScheduledCall(cancelled)
+	require.NoError(t, err)
+	require.Nil(t, cancelledCall)
+
+	state.AddReceipt(NewExecutionResult())
+	block1, err := state.Commit(1, nil)
+	require.NoError(t, err)
+	require.Equal(t, block0.Block.ScheduledCallsRoot, block1.Block.ScheduledCallsRoot)
+
+	// The call is due in block 2 and is executed only once
+	state = newState(block1)
+	due, err = state.DueScheduledCalls(10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, id, due[0].Hash())
+
+	require.NoError(t, state.ConsumeScheduledCall(due[0]))
+	require.Equal(t, types.ErrorScheduledCallNotFound, types.GetErrorCode(state.ConsumeScheduledCall(due[0])))
+	due, err = state.DueScheduledCalls(10)
+	require.NoError(t, err)
+	require.Empty(t, due)
+
+	state.AddReceipt(NewExecutionResult())
+	block2, err := state.Commit(2, nil)
+	require.NoError(t, err)
+
+	// The trie is empty again, so the block keeps the legacy encoding
+	require.Equal(t, common.EmptyHash, block2.Block.ScheduledCallsRoot)
+
+	state = newState(block2)
+	call, err = state.GetScheduledCall(id)
+	require.NoError(t, err)
+	require.Nil(t, call)
+}
+
+func TestDueScheduledCalls(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	configAccessor, err := config.NewConfigAccessorTx(tx, nil)
+	require.NoError(t, err)
+
+	shardId := types.BaseShardId
+	owner := types.GenerateRandomAddress(shardId)
+	payload := &types.InternalTransactionPayload{
+		Kind:      types.ExecutionTransactionKind,
+		FeeCredit: types.NewValueFromUint64(1000),
+		To:        types.GenerateRandomAddress(shardId),
+		RefundTo:  owner,
+		BounceTo:  owner,
+		Value:     types.NewZeroValue(),
+	}
+
+	state, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: configAccessor})
+	require.NoError(t, err)
+	state.AddInTransaction(types.NewEmptyTransaction())
+	require.NoError(t, state.CreateAccount(owner))
+
+	// The calls are ordered by the block number or by the time
+	byBlock := make([]common.Hash, 3)
+	for i := range byBlock {
+		byBlock[i], err = state.ScheduleCall(owner, payload, types.BlockNumber(3-i), 0)
+		require.NoError(t, err)
+	}
+	byTime, err := state.ScheduleCall(owner, payload, 0, 1)
+	require.NoError(t, err)
+	later, err := state.ScheduleCall(owner, payload, 0, math.MaxUint64)
+	require.NoError(t, err)
+	// The call by the block number isn't due until the time comes
+	notYet, err := state.ScheduleCall(owner, payload, 1, math.MaxUint64)
+	require.NoError(t, err)
+
+	state.AddReceipt(NewExecutionResult())
+	block, err := state.Commit(0, nil)
+	require.NoError(t, err)
+
+	// The calls by the block number are due in block 4
+	for i := range types.BlockNumber(3) {
+		state, err = NewExecutionState(tx, shardId, StateParams{Block: block.Block, ConfigAccessor: configAccessor})
+		require.NoError(t, err)
+		block, err = state.Commit(i+1, nil)
+		require.NoError(t, err)
+	}
+	state, err = NewExecutionState(tx, shardId, StateParams{Block: block.Block, ConfigAccessor: configAccessor})
+	require.NoError(t, err)
+
+	ids := func(txns []*types.Transaction) []common.Hash {
+		res := make([]common.Hash, len(txns))
+		for i, txn := range txns {
+			res[i] = txn.Hash()
+		}
+		return res
+	}
+
+	due, err := state.DueScheduledCalls(10)
+	require.NoError(t, err)
+	require.Equal(t, []common.Hash{byBlock[2], byBlock[1], byBlock[0], byTime}, ids(due))
+	require.NotContains(t, ids(due), later)
+	require.NotContains(t, ids(due), notYet)
+
+	due, err = state.DueScheduledCalls(2)
+	require.NoError(t, err)
+	require.Equal(t, []common.Hash{byBlock[2], byBlock[1]}, ids(due))
+
+	// The calls executed in the block aren't returned again
+	require.NoError(t, state.ConsumeScheduledCall(due[0]))
+	due, err = state.DueScheduledCalls(10)
+	require.NoError(t, err)
+	require.Equal(t, []common.Hash{byBlock[1], byBlock[0], byTime}, ids(due))
+}
+
+func TestRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	configAccessor, err := config.NewConfigAccessorTx(tx, nil)
+	require.NoError(t, err)
+
+	// The main shard grows by two blocks
+	var mainBlock *BlockGenerationResult
+	for i := range types.BlockNumber(3) {
+		params := StateParams{ConfigAccessor: configAccessor}
+		if mainBlock != nil {
+			params.Block = mainBlock.Block
+		}
+		state, err := NewExecutionState(tx, types.MainShardId, params)
+		require.NoError(t, err)
+		mainBlock, err = state.Commit(i, nil)
+		require.NoError(t, err)
+	}
+
+	shardId := types.BaseShardId
+	caller := types.GenerateRandomAddress(shardId)
+	payload := &types.InternalTransactionPayload{
+		Kind:           types.ExecutionTransactionKind,
+		FeeCredit:      types.NewZeroValue(),
+		ForwardKind:    types.ForwardKindRemaining,
+		To:             types.GenerateRandomAddress(shardId + 1),
+		RefundTo:       caller,
+		BounceTo:       caller,
+		Value:          types.NewZeroValue(),
+		RequestContext: []byte{1, 2, 3, 4},
+	}
+
+	state, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: configAccessor})
+	require.NoError(t, err)
+	state.AddInTransaction(types.NewEmptyTransaction())
+	require.NoError(t, state.CreateAccount(caller))
+
+	// The main shard is at height 0 for the shard at the moment of the requests
+	timedOut, err := state.AddOutRequestTransaction(caller, payload, 1000, false, 2)
+	require.NoError(t, err)
+	answered, err := state.AddOutRequestTransaction(caller, payload, 1000, false, 2)
+	require.NoError(t, err)
+	noTimeout, err := state.AddOutRequestTransaction(caller, payload, 1000, false, 0)
+	require.NoError(t, err)
+
+	acc, err := state.GetAccount(caller)
+	require.NoError(t, err)
+	require.False(t, acc.AsyncContext[types.TransactionIndex(timedOut.RequestId)].TimeoutCallId.Empty())
+	require.True(t, acc.AsyncContext[types.TransactionIndex(noTimeout.RequestId)].TimeoutCallId.Empty())
+
+	// The failure response is sent on behalf of the callee, but only the caller can cancel it
+	timeoutId := acc.AsyncContext[types.TransactionIndex(answered.RequestId)].TimeoutCallId
+	_, err = state.CancelScheduledCall(payload.To, timeoutId)
+	require.Equal(t, types.ErrorScheduledCallNotFound, types.GetErrorCode(err))
+	call, err := state.CancelScheduledCall(caller, timeoutId)
+	require.NoError(t, err)
+	require.Equal(t, payload.To, call.Transaction.From)
+
+	state.AddReceipt(NewExecutionResult())
+	block0, err := state.Commit(0, nil)
+	require.NoError(t, err)
+
+	response := func(request *types.Transaction) *types.Transaction {
+		data, err := (&types.AsyncResponsePayload{Success: true}).MarshalSSZ()
+		require.NoError(t, err)
+		return (&types.InternalTransactionPayload{
+			Kind:      types.ResponseTransactionKind,
+			FeeCredit: types.NewZeroValue(),
+			To:        caller,
+			Value:     types.NewZeroValue(),
+			Data:      data,
+			RequestId: request.RequestId,
+		}).ToTransaction(request.To, 0)
+	}
+
+	// The timeouts are not due until the main shard reaches the deadline
+	state, err = NewExecutionState(tx, shardId, StateParams{Block: block0.Block, ConfigAccessor: configAccessor})
+	require.NoError(t, err)
+	due, err := state.DueScheduledCalls(10)
+	require.NoError(t, err)
+	require.Empty(t, due)
+
+	// The response in time cancels the timeout
+	state.AddInTransaction(types.NewEmptyTransaction())
+	_, _, res := state.TryProcessResponse(response(answered))
+	require.Nil(t, res)
+	state.AddReceipt(NewExecutionResult())
+	block1, err := state.Commit(1, nil)
+	require.NoError(t, err)
+
+	state, err = NewExecutionState(tx, shardId, StateParams{Block: block1.Block, ConfigAccessor: configAccessor})
+	require.NoError(t, err)
+	state.MainChainHash = mainBlock.BlockHash
+	due, err = state.DueScheduledCalls(10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+
+	// The failure response releases the context of the request
+	failure := due[0]
+	require.True(t, failure.IsResponse())
+	require.True(t, failure.IsScheduled())
+	require.NoError(t, failure.VerifyFlags())
+	require.Equal(t, timedOut.RequestId, failure.RequestId)
+	require.Equal(t, caller, failure.To)
+	require.NoError(t, state.ConsumeScheduledCall(failure))
+
+	state.AddInTransaction(failure)
+	_, _, res = state.TryProcessResponse(failure)
+	require.Nil(t, res)
+
+	// The response after the timeout fails
+	_, _, res = state.TryProcessResponse(response(timedOut))
+	require.NotNil(t, res)
+	require.Equal(t, types.ErrorAsyncContextNotFound, res.Error.Code())
+
+	state.AddReceipt(NewExecutionResult())
+	_, err = state.Commit(2, nil)
+	require.NoError(t, err)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/tracing"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/internal/vm"
@@ -32,6 +33,12 @@ const TraceBlocksEnabled = false
 
 const ExternalTransactionVerificationMaxGas = types.Gas(100_000)
 
+// PaymasterVerificationMaxGas is the gas budget of the `verifyPaymaster` call.
+const PaymasterVerificationMaxGas = types.Gas(100_000)
+
+// maxRevertDataSize limits the size of the revert data kept for the failed transactions.
+const maxRevertDataSize = 1024
+
 var blocksTracer *BlocksTracer
 
 type Storage map[common.Hash]common.Hash
@@ -52,6 +59,7 @@ type ExecutionState struct {
 	InTransactionTree  *TransactionTrie
 	OutTransactionTree *TransactionTrie
 	ReceiptTree        *ReceiptTrie
+	ScheduledCallTree  *ScheduledCallTrie
 	PrevBlock          common.Hash
 	MainChainHash      common.Hash
 	ShardId            types.ShardId
@@ -59,6 +67,9 @@ type ExecutionState struct {
 	GasPrice           types.Value // Current gas price including priority fee
 	BaseFee            types.Value
 
+	// Fork selects the execution rules of the block, see UpdateFork
+	Fork params.Fork
+
 	InTransactionHash common.Hash
 	Logs              map[common.Hash][]*types.Log
 	DebugLogs         map[common.Hash][]*types.DebugLog
@@ -72,6 +83,8 @@ type ExecutionState struct {
 
 	Receipts []*types.Receipt
 	Errors   map[common.Hash]error
+	// RevertData holds the revert data of the failed transactions, which is decoded by the RPC with the ABI
+	RevertData map[common.Hash][]byte
 
 	GasUsed types.Gas
 
@@ -91,6 +104,18 @@ type ExecutionState struct {
 	// If true, log every instruction execution.
 	TraceVm bool
 
+	// If true, the changes of the state are recorded and written along with the block, see BlockStateDiff.
+	RecordStateDiff bool
+	// txnStateDiffStart is the journal length at the start of the current inbound transaction
+	txnStateDiffStart int
+	txnStateDiffs     []types.TransactionStateDiff
+
+	// If true, the gas and storage used by the contracts are recorded and written along with the block,
+	// see BlockResourceUsage.
+	RecordResourceUsage   bool
+	txnResourceUsageStart int
+	resourceUsage         map[types.Address]*types.ContractResourceUsage
+
 	shardAccessor *shardAccessor
 
 	// Pointer to currently executed VM
@@ -99,6 +124,16 @@ type ExecutionState struct {
 	// wasAwaitCall is true if the VM execution ended with sending a awaitCall transaction
 	wasAwaitCall bool
 
+	// accessedAccounts collects the accounts accessed by a transaction if it is not nil, see parallel.go
+	accessedAccounts map[types.Address]accountAccess
+
+	// scheduledCallIds maps the ids of the scheduled calls to their keys in ScheduledCallTree, see scheduled.go
+	scheduledCallIds *ScheduledCallIdTrie
+	// scheduledCalls holds the scheduled calls changed in the block by id, a nil value means that the call is removed
+	scheduledCalls map[common.Hash]*types.ScheduledCall
+	// scheduledCallsAccessed is true if the scheduled calls were read or changed, see parallel.go
+	scheduledCallsAccessed bool
+
 	configAccessor config.ConfigAccessor
 
 	// txnFeeCredit holds the total fee credit for the inbound transaction. It can be changed during execution, thus we
@@ -117,6 +152,7 @@ type ExecutionResult struct {
 	GasPrice       types.Value
 	CoinsForwarded types.Value
 	DebugInfo      *vm.DebugInfo
+	BatchReceipts  []*types.BatchCallReceipt
 }
 
 func NewExecutionResult() *ExecutionResult {
@@ -227,6 +263,7 @@ func NewEVMBlockContext(es *ExecutionState) (*vm.BlockContext, error) {
 		BaseFee:     big.NewInt(10),
 		BlobBaseFee: big.NewInt(10),
 		Time:        time,
+		Fork:        es.Fork,
 	}, nil
 }
 
@@ -250,6 +287,7 @@ func NewExecutionState(tx any, shardId types.ShardId, params StateParams) (*Exec
 	var baseFeePerGas types.Value
 	var prevBlockHash common.Hash
 	if params.Block != nil {
+		// The fork of the new block is not known yet, so the base fee is recalculated by UpdateFork
 		baseFeePerGas = calculateBaseFee(params.Block.BaseFee, params.Block.GasUsed)
 		prevBlockHash = params.Block.Hash(shardId)
 	}
@@ -264,6 +302,9 @@ func NewExecutionState(tx any, shardId types.ShardId, params StateParams) (*Exec
 		Logs:             map[common.Hash][]*types.Log{},
 		DebugLogs:        map[common.Hash][]*types.DebugLog{},
 		Errors:           map[common.Hash]error{},
+		RevertData:       map[common.Hash][]byte{},
+		scheduledCalls:   map[common.Hash]*types.ScheduledCall{},
+		resourceUsage:    map[types.Address]*types.ContractResourceUsage{},
 
 		journal:          newJournal(),
 		transientStorage: newTransientStorage(),
@@ -290,8 +331,10 @@ func (es *ExecutionState) initTries() error {
 	es.InTransactionTree = NewDbTransactionTrie(es.tx, es.ShardId)
 	es.OutTransactionTree = NewDbTransactionTrie(es.tx, es.ShardId)
 	es.ReceiptTree = NewDbReceiptTrie(es.tx, es.ShardId)
+	es.ScheduledCallTree, es.scheduledCallIds = NewDbScheduledCallTries(es.tx, es.ShardId)
 	if err == nil {
 		es.ContractTree.SetRootHash(data.Block().SmartContractsRoot)
+		es.ScheduledCallTree.SetRootHash(data.Block().ScheduledCallsRoot)
 	}
 
 	return nil
@@ -315,6 +358,14 @@ func (es *ExecutionState) GetAccountReader(addr types.Address) (*AccountStateRea
 }
 
 func (es *ExecutionState) GetAccount(addr types.Address) (*AccountState, error) {
+	acc, err := es.getAccount(addr)
+	if err == nil && es.accessedAccounts != nil {
+		es.recordAccountAccess(addr, acc)
+	}
+	return acc, err
+}
+
+func (es *ExecutionState) getAccount(addr types.Address) (*AccountState, error) {
 	acc, ok := es.Accounts[addr]
 	if ok {
 		return acc, nil
@@ -729,6 +780,8 @@ func (es *ExecutionState) AddInTransaction(transaction *types.Transaction) commo
 	es.InTransactions = append(es.InTransactions, common.CopyPtr(transaction))
 	es.InTransactionHash = transaction.Hash()
 	es.InTransactionHashes = append(es.InTransactionHashes, es.InTransactionHash)
+	es.beginTransactionStateDiff()
+	es.beginTransactionResourceUsage()
 	return es.InTransactionHash
 }
 
@@ -771,6 +824,7 @@ func (es *ExecutionState) AddOutRequestTransaction(
 	payload *types.InternalTransactionPayload,
 	responseProcessingGas types.Gas,
 	isAwait bool,
+	timeout uint64,
 ) (*types.Transaction, error) {
 	txn, err := es.AddOutTransaction(caller, payload)
 	if err != nil {
@@ -782,6 +836,13 @@ func (es *ExecutionState) AddOutRequestTransaction(
 
 	txn.RequestId = acc.FetchRequestId()
 
+	var timeoutCallId common.Hash
+	if timeout != 0 {
+		if timeoutCallId, err = es.scheduleRequestTimeout(txn, timeout); err != nil {
+			return nil, err
+		}
+	}
+
 	// Only await calls should inherit the request chain from the inbound transaction.
 	if isAwait {
 		// If an inbound transaction is also a request, we need to add a new record to the request chain.
@@ -800,6 +861,13 @@ func (es *ExecutionState) AddOutRequestTransaction(
 		}
 
 		es.wasAwaitCall = true
+		if timeout != 0 {
+			// The context is completed with the VM state by SaveVmState.
+			acc.SetAsyncContext(types.TransactionIndex(txn.RequestId), &types.AsyncContext{
+				IsAwait:       true,
+				TimeoutCallId: timeoutCallId,
+			})
+		}
 		// Stop vm execution and save its state after the current instruction (call of precompile) is finished.
 		es.evm.StopAndDumpState(responseProcessingGas)
 	} else {
@@ -807,6 +875,7 @@ func (es *ExecutionState) AddOutRequestTransaction(
 			IsAwait:               false,
 			Data:                  payload.RequestContext,
 			ResponseProcessingGas: responseProcessingGas,
+			TimeoutCallId:         timeoutCallId,
 		})
 	}
 
@@ -1102,9 +1171,17 @@ func (es *ExecutionState) TryProcessResponse(transaction *types.Transaction) ([]
 	key := make([]byte, 8)
 	binary.BigEndian.PutUint64(key, transaction.RequestId)
 	asyncContext, err := acc.GetAndRemoveAsyncContext(types.TransactionIndex(transaction.RequestId))
+	if errors.Is(err, db.ErrKeyNotFound) {
+		// The request has timed out, and the failure response has been already processed.
+		return nil, nil, NewExecutionResult().SetError(types.NewError(types.ErrorAsyncContextNotFound))
+	}
 	if err != nil {
 		return nil, nil, NewExecutionResult().SetFatal(fmt.Errorf("failed to get async context: %w", err))
 	}
+	if !asyncContext.TimeoutCallId.Empty() {
+		// The response has arrived in time, or it's the failure response itself.
+		es.setScheduledCall(asyncContext.TimeoutCallId, nil)
+	}
 
 	responsePayload := new(types.AsyncResponsePayload)
 	if err := responsePayload.UnmarshalSSZ(transaction.Data); err != nil {
@@ -1179,6 +1256,10 @@ func (es *ExecutionState) handleExecutionTransaction(_ context.Context, transact
 
 	es.revertId = es.Snapshot()
 
+	if transaction.IsBatch() {
+		return es.handleBatchTransaction(caller, transaction)
+	}
+
 	es.evm.SetTokenTransfer(transaction.Token)
 	gas := es.txnFeeCredit.ToGas(es.GasPrice)
 	ret, leftOver, err := es.evm.Call(caller, addr, callData, gas.Uint64(), transaction.Value.Int())
@@ -1224,16 +1305,22 @@ func (es *ExecutionState) AddReceipt(execResult *ExecutionResult) {
 		Logs:            es.Logs[es.InTransactionHash],
 		DebugLogs:       es.DebugLogs[es.InTransactionHash],
 		ContractAddress: es.GetInTransaction().To,
+		BatchReceipts:   execResult.BatchReceipts,
 	}
 
 	if execResult.Failed() {
 		es.Errors[es.InTransactionHash] = execResult.Error
+		if len(execResult.ReturnData) > 0 {
+			es.RevertData[es.InTransactionHash] = execResult.ReturnData[:min(len(execResult.ReturnData), maxRevertDataSize)]
+		}
 		if execResult.DebugInfo != nil {
 			check.PanicIfNot(execResult.DebugInfo.Pc <= math.MaxUint32)
 			r.FailedPc = uint32(execResult.DebugInfo.Pc)
 		}
 	}
 	es.Receipts = append(es.Receipts, r)
+	es.endTransactionStateDiff()
+	es.endTransactionResourceUsage(execResult.GasUsed)
 }
 
 func GetOutTransactions(es *ExecutionState) []*types.Transaction {
@@ -1351,6 +1438,10 @@ func (es *ExecutionState) BuildBlock(blockId types.BlockNumber) (*BlockGeneratio
 		}
 	}
 
+	if err := es.commitScheduledCalls(); err != nil {
+		return nil, fmt.Errorf("failed to update scheduled calls trie: %w", err)
+	}
+
 	configRoot := common.EmptyHash
 	var configParams map[string][]byte
 	if es.ShardId.IsMainShard() {
@@ -1378,6 +1469,7 @@ func (es *ExecutionState) BuildBlock(blockId types.BlockNumber) (*BlockGeneratio
 			InTransactionsRoot:  es.InTransactionTree.RootHash(),
 			OutTransactionsRoot: es.OutTransactionTree.RootHash(),
 			ConfigRoot:          configRoot,
+			ScheduledCallsRoot:  es.ScheduledCallTree.RootHash(),
 			OutTransactionsNum:  types.TransactionIndex(len(outTxnKeys)),
 			ReceiptsRoot:        es.ReceiptTree.RootHash(),
 			ChildBlocksRootHash: treeShardsRootHash,
@@ -1422,12 +1514,29 @@ func (es *ExecutionState) CommitBlock(block *types.Block, params *types.Consensu
 			return err
 		}
 	}
+	for k, v := range es.RevertData {
+		if err := db.WriteRevertData(es.tx, k, v); err != nil {
+			return err
+		}
+	}
 
 	blockHash := block.Hash(es.ShardId)
 	if err := db.WriteBlock(es.tx, es.ShardId, blockHash, block); err != nil {
 		return err
 	}
 
+	if es.RecordStateDiff {
+		if err := db.WriteStateDiff(es.tx, es.ShardId, blockHash, es.BlockStateDiff()); err != nil {
+			return fmt.Errorf("failed to write state diff: %w", err)
+		}
+	}
+
+	if es.RecordResourceUsage {
+		if err := db.WriteResourceUsage(es.tx, es.ShardId, blockHash, es.BlockResourceUsage()); err != nil {
+			return fmt.Errorf("failed to write resource usage: %w", err)
+		}
+	}
+
 	logger.Trace().
 		Stringer(logging.FieldShardId, es.ShardId).
 		Stringer(logging.FieldBlockNumber, block.Id).
@@ -1522,7 +1631,9 @@ func (es *ExecutionState) GetShardID() types.ShardId {
 	return es.ShardId
 }
 
-func (es *ExecutionState) CallVerifyExternal(transaction *types.Transaction, account *AccountState) *ExecutionResult {
+// CallVerifyExternal calls `verifyExternal` of the account to check the signature of the external transaction.
+// The verification is paid by the payer: the account itself or the paymaster of the transaction.
+func (es *ExecutionState) CallVerifyExternal(transaction *types.Transaction, account, payer *AccountState) *ExecutionResult {
 	methodSignature := "verifyExternal(uint256,bytes)"
 	methodSelector := crypto.Keccak256([]byte(methodSignature))[:4]
 	argSpec := vm.VerifySignatureArgs()[1:] // skip first arg (pubkey)
@@ -1535,20 +1646,65 @@ func (es *ExecutionState) CallVerifyExternal(transaction *types.Transaction, acc
 		logger.Error().Err(err).Msg("failed to pack arguments")
 		return NewExecutionResult().SetFatal(err)
 	}
+	calldata := append(methodSelector, argData...) //nolint:gocritic
+
+	return es.callVerification(transaction, account, payer, calldata, ExternalTransactionVerificationMaxGas,
+		types.ErrorExternalVerificationFailed, tracing.BalanceDecreaseVerifyExternal)
+}
 
+// CallVerifyPaymaster calls `verifyPaymaster` of the paymaster to check that it agrees to pay the fees
+// of the external transaction. The paymaster receives the recipient, the signing hash and the fee credit
+// of the transaction and pays for the verification itself.
+func (es *ExecutionState) CallVerifyPaymaster(transaction *types.Transaction, paymaster *AccountState) *ExecutionResult {
+	hash, err := transaction.SigningHash()
+	if err != nil {
+		return NewExecutionResult().SetFatal(fmt.Errorf("transaction.SigningHash() failed: %w", err))
+	}
+	methodSelector := crypto.Keccak256([]byte("verifyPaymaster(address,uint256,uint256)"))[:4]
+	argData, err := verifyPaymasterArgs().Pack(transaction.To, hash.Big(), transaction.FeeCredit.ToBig())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to pack arguments")
+		return NewExecutionResult().SetFatal(err)
+	}
+	calldata := append(methodSelector, argData...) //nolint:gocritic
+
+	return es.callVerification(transaction, paymaster, paymaster, calldata, PaymasterVerificationMaxGas,
+		types.ErrorPaymasterVerificationFailed, tracing.BalanceDecreaseVerifyPaymaster)
+}
+
+func verifyPaymasterArgs() abi.Arguments {
+	// arguments: address account, uint256 hash, uint256 feeCredit
+	// returns: bool approved
+	addressTy, _ := abi.NewType("address", "", nil)
+	uint256Ty, _ := abi.NewType("uint256", "", nil)
+	return abi.Arguments{
+		abi.Argument{Name: "account", Type: addressTy},
+		abi.Argument{Name: "hash", Type: uint256Ty},
+		abi.Argument{Name: "feeCredit", Type: uint256Ty},
+	}
+}
+
+// callVerification makes a static call of the verification method of the account,
+// which must return true, and charges the payer for the gas spent.
+func (es *ExecutionState) callVerification(
+	transaction *types.Transaction,
+	account, payer *AccountState,
+	calldata []byte,
+	maxGas types.Gas,
+	failureCode types.ErrorCode,
+	reason tracing.BalanceChangeReason,
+) *ExecutionResult {
 	if err := es.updateGasPrice(transaction); err != nil {
 		return NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorBaseFeeTooHigh, err))
 	}
 
-	calldata := append(methodSelector, argData...) //nolint:gocritic
-
 	if err := es.newVm(transaction.IsInternal(), transaction.From, nil); err != nil {
 		return NewExecutionResult().SetFatal(fmt.Errorf("newVm failed: %w", err))
 	}
 	defer es.resetVm()
 
-	gasCreditLimit := ExternalTransactionVerificationMaxGas
-	gasAvailable := account.Balance.ToGas(es.GasPrice)
+	gasCreditLimit := maxGas
+	gasAvailable := payer.Balance.ToGas(es.GasPrice)
 
 	if gasAvailable.Lt(gasCreditLimit) {
 		gasCreditLimit = gasAvailable
@@ -1556,21 +1712,21 @@ func (es *ExecutionState) CallVerifyExternal(transaction *types.Transaction, acc
 
 	ret, leftOverGas, err := es.evm.StaticCall((vm.AccountRef)(account.address), account.address, calldata, gasCreditLimit.Uint64())
 	if err != nil {
-		if types.IsOutOfGasError(err) && gasCreditLimit.Lt(ExternalTransactionVerificationMaxGas) {
-			// This condition means that account has not enough balance even to execute the verification.
+		if types.IsOutOfGasError(err) && gasCreditLimit.Lt(maxGas) {
+			// This condition means that payer has not enough balance even to execute the verification.
 			// So it will be clearer to return `InsufficientBalance` error instead of `OutOfGas`.
 			return NewExecutionResult().SetError(types.NewError(types.ErrorInsufficientBalance))
 		}
-		txnErr := types.KeepOrWrapError(types.ErrorExternalVerificationFailed, err)
+		txnErr := types.KeepOrWrapError(failureCode, err)
 		return NewExecutionResult().SetError(txnErr)
 	}
 	if !bytes.Equal(ret, common.LeftPadBytes([]byte{1}, 32)) {
-		return NewExecutionResult().SetError(types.NewError(types.ErrorExternalVerificationFailed))
+		return NewExecutionResult().SetError(types.NewError(failureCode))
 	}
 	res := NewExecutionResult()
 	spentGas := gasCreditLimit.Sub(types.Gas(leftOverGas))
 	res.SetUsed(spentGas, es.GasPrice)
-	check.PanicIfErr(account.SubBalance(res.CoinsUsed(), tracing.BalanceDecreaseVerifyExternal))
+	check.PanicIfErr(payer.SubBalance(res.CoinsUsed(), reason))
 	return res
 }
 
@@ -1689,11 +1845,15 @@ func (es *ExecutionState) SaveVmState(state *types.EvmState, continuationGasCred
 
 	logger.Debug().Int("size", len(data)).Msg("Save vm state")
 
-	acc.SetAsyncContext(types.TransactionIndex(outTxn.RequestId), &types.AsyncContext{
+	asyncContext := &types.AsyncContext{
 		IsAwait:               true,
 		Data:                  data,
 		ResponseProcessingGas: continuationGasCredit,
-	})
+	}
+	if prev, ok := acc.AsyncContext[types.TransactionIndex(outTxn.RequestId)]; ok {
+		asyncContext.TimeoutCallId = prev.TimeoutCallId
+	}
+	acc.SetAsyncContext(types.TransactionIndex(outTxn.RequestId), asyncContext)
 	return nil
 }
 
@@ -0,0 +1,174 @@
+package execution
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// accountChanges holds the values of an account before the first change found in the journal.
+// A nil field means that the corresponding value wasn't changed.
+type accountChanges struct {
+	created  bool
+	balance  *types.Value
+	seqno    *types.Seqno
+	extSeqno *types.Seqno
+	codeHash *common.Hash
+	storage  map[common.Hash]common.Hash
+	tokens   map[types.TokenId]types.Value
+}
+
+func setOnce[T any](dst **T, value T) {
+	if *dst == nil {
+		*dst = &value
+	}
+}
+
+// collectStateDiff builds the diff of the accounts changed by the journal entries.
+// The journal keeps the previous values, so the value before the first entry of a key is the value
+// before the changes and the current value of the account is the value after them.
+func (es *ExecutionState) collectStateDiff(entries []JournalEntry) []types.AccountStateDiff {
+	changes := make(map[types.Address]*accountChanges)
+	get := func(addr types.Address) *accountChanges {
+		ch, ok := changes[addr]
+		if !ok {
+			ch = &accountChanges{
+				storage: make(map[common.Hash]common.Hash),
+				tokens:  make(map[types.TokenId]types.Value),
+			}
+			changes[addr] = ch
+		}
+		return ch
+	}
+
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case createObjectChange:
+			get(*e.account).created = true
+		case balanceChange:
+			setOnce(&get(*e.account).balance, e.prev)
+		case selfDestructChange:
+			setOnce(&get(*e.account).balance, e.prevbalance)
+		case seqnoChange:
+			setOnce(&get(*e.account).seqno, e.prev)
+		case extSeqnoChange:
+			setOnce(&get(*e.account).extSeqno, e.prev)
+		case codeChange:
+			setOnce(&get(*e.account).codeHash, common.BytesToHash(e.prevhash))
+		case storageChange:
+			ch := get(*e.account)
+			if _, ok := ch.storage[e.key]; !ok {
+				ch.storage[e.key] = e.prevvalue
+			}
+		case tokenChange:
+			ch := get(*e.account)
+			if _, ok := ch.tokens[e.id]; !ok {
+				ch.tokens[e.id] = e.prev
+			}
+		}
+	}
+
+	res := make([]types.AccountStateDiff, 0, len(changes))
+	for addr, ch := range changes {
+		acc, ok := es.Accounts[addr]
+		if !ok {
+			continue
+		}
+		if diff, changed := makeAccountStateDiff(addr, acc, ch); changed {
+			res = append(res, diff)
+		}
+	}
+	slices.SortFunc(res, func(a, b types.AccountStateDiff) int {
+		return bytes.Compare(a.Address.Bytes(), b.Address.Bytes())
+	})
+	return res
+}
+
+func makeAccountStateDiff(addr types.Address, acc *AccountState, ch *accountChanges) (types.AccountStateDiff, bool) {
+	diff := types.AccountStateDiff{
+		Address:        addr,
+		Created:        ch.created,
+		BalanceBefore:  acc.Balance,
+		BalanceAfter:   acc.Balance,
+		SeqnoBefore:    acc.Seqno,
+		SeqnoAfter:     acc.Seqno,
+		ExtSeqnoBefore: acc.ExtSeqno,
+		ExtSeqnoAfter:  acc.ExtSeqno,
+		CodeHashBefore: acc.CodeHash,
+		CodeHashAfter:  acc.CodeHash,
+	}
+	if ch.created {
+		// A new account has no state before the changes
+		diff.BalanceBefore = types.NewZeroValue()
+		diff.SeqnoBefore = 0
+		diff.ExtSeqnoBefore = 0
+		diff.CodeHashBefore = common.EmptyHash
+	}
+	if ch.balance != nil {
+		diff.BalanceBefore = *ch.balance
+	}
+	if ch.seqno != nil {
+		diff.SeqnoBefore = *ch.seqno
+	}
+	if ch.extSeqno != nil {
+		diff.ExtSeqnoBefore = *ch.extSeqno
+	}
+	if ch.codeHash != nil {
+		diff.CodeHashBefore = *ch.codeHash
+	}
+
+	for key, before := range ch.storage {
+		if after := acc.State[key]; after != before {
+			diff.Storage = append(diff.Storage, types.StorageDiff{Key: key, Before: before, After: after})
+		}
+	}
+	slices.SortFunc(diff.Storage, func(a, b types.StorageDiff) int {
+		return bytes.Compare(a.Key.Bytes(), b.Key.Bytes())
+	})
+
+	for id, before := range ch.tokens {
+		if after := acc.Tokens[id]; after.Cmp(before) != 0 {
+			diff.Tokens = append(diff.Tokens, types.TokenDiff{Token: id, Before: before, After: after})
+		}
+	}
+	slices.SortFunc(diff.Tokens, func(a, b types.TokenDiff) int {
+		return bytes.Compare(a.Token[:], b.Token[:])
+	})
+
+	changed := ch.created ||
+		diff.BalanceBefore.Cmp(diff.BalanceAfter) != 0 ||
+		diff.SeqnoBefore != diff.SeqnoAfter ||
+		diff.ExtSeqnoBefore != diff.ExtSeqnoAfter ||
+		diff.CodeHashBefore != diff.CodeHashAfter ||
+		len(diff.Storage) > 0 ||
+		len(diff.Tokens) > 0
+	return diff, changed
+}
+
+// beginTransactionStateDiff marks the start of the changes of the current inbound transaction.
+func (es *ExecutionState) beginTransactionStateDiff() {
+	if es.RecordStateDiff {
+		es.txnStateDiffStart = es.journal.length()
+	}
+}
+
+// endTransactionStateDiff records the changes of the current inbound transaction.
+func (es *ExecutionState) endTransactionStateDiff() {
+	if !es.RecordStateDiff {
+		return
+	}
+	es.txnStateDiffs = append(es.txnStateDiffs, types.TransactionStateDiff{
+		TxnHash:  es.InTransactionHash,
+		Accounts: es.collectStateDiff(es.journal.entries[es.txnStateDiffStart:]),
+	})
+}
+
+// BlockStateDiff returns the changes made by the block so far.
+func (es *ExecutionState) BlockStateDiff() *types.BlockStateDiff {
+	return &types.BlockStateDiff{
+		Accounts:     es.collectStateDiff(es.journal.entries),
+		Transactions: es.txnStateDiffs,
+	}
+}
@@ -0,0 +1,108 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDiff(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	configAccessor, err := config.NewConfigAccessorTx(tx, nil)
+	require.NoError(t, err)
+
+	shardId := types.BaseShardId
+	addrA := types.GenerateRandomAddress(shardId)
+	addrB := types.GenerateRandomAddress(shardId)
+	key1, key2 := common.IntToHash(1), common.IntToHash(2)
+
+	state, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: configAccessor})
+	require.NoError(t, err)
+	require.NoError(t, state.CreateAccount(addrA))
+	require.NoError(t, state.SetBalance(addrA, types.NewValueFromUint64(100)))
+	require.NoError(t, state.SetState(addrA, key1, common.IntToHash(1)))
+	prevBlock, err := state.Commit(0, nil)
+	require.NoError(t, err)
+
+	_, err = db.ReadStateDiff(tx, shardId, prevBlock.BlockHash)
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+	state, err = NewExecutionState(tx, shardId, StateParams{Block: prevBlock.Block, ConfigAccessor: configAccessor})
+	require.NoError(t, err)
+	state.RecordStateDiff = true
+
+	txn1 := types.NewEmptyTransaction()
+	txn1.Seqno = 1
+	state.AddInTransaction(txn1)
+	require.NoError(t, state.SetBalance(addrA, types.NewValueFromUint64(150)))
+	require.NoError(t, state.SetState(addrA, key1, common.IntToHash(5)))
+	// Changed and restored slots are not a part of the diff
+	require.NoError(t, state.SetState(addrA, key2, common.IntToHash(7)))
+	require.NoError(t, state.SetState(addrA, key2, common.EmptyHash))
+	require.NoError(t, state.CreateAccount(addrB))
+	require.NoError(t, state.SetBalance(addrB, types.NewValueFromUint64(10)))
+	state.AddReceipt(NewExecutionResult())
+
+	txn2 := types.NewEmptyTransaction()
+	txn2.Seqno = 2
+	state.AddInTransaction(txn2)
+	require.NoError(t, state.SetBalance(addrA, types.NewValueFromUint64(120)))
+	require.NoError(t, state.SetSeqno(addrA, 1))
+	// Reverted changes are not a part of the diff
+	snapshot := state.Snapshot()
+	require.NoError(t, state.SetState(addrA, key1, common.IntToHash(9)))
+	state.RevertToSnapshot(snapshot)
+	state.AddReceipt(NewExecutionResult())
+
+	blockRes, err := state.Commit(1, nil)
+	require.NoError(t, err)
+
+	diff, err := db.ReadStateDiff(tx, shardId, blockRes.BlockHash)
+	require.NoError(t, err)
+
+	// The decoded diff has empty lists instead of nil ones
+	accountA := func(balanceBefore, balanceAfter uint64, seqnoAfter types.Seqno, storage []types.StorageDiff) types.AccountStateDiff {
+		return types.AccountStateDiff{
+			Address:       addrA,
+			BalanceBefore: types.NewValueFromUint64(balanceBefore),
+			BalanceAfter:  types.NewValueFromUint64(balanceAfter),
+			SeqnoAfter:    seqnoAfter,
+			Storage:       storage,
+			Tokens:        []types.TokenDiff{},
+		}
+	}
+	accountB := types.AccountStateDiff{
+		Address:       addrB,
+		Created:       true,
+		BalanceBefore: types.NewValueFromUint64(0),
+		BalanceAfter:  types.NewValueFromUint64(10),
+		Storage:       []types.StorageDiff{},
+		Tokens:        []types.TokenDiff{},
+	}
+	storage := []types.StorageDiff{{Key: key1, Before: common.IntToHash(1), After: common.IntToHash(5)}}
+
+	sorted := func(accounts ...types.AccountStateDiff) []types.AccountStateDiff {
+		if accounts[0].Address.Hex() > accounts[len(accounts)-1].Address.Hex() {
+			accounts[0], accounts[len(accounts)-1] = accounts[len(accounts)-1], accounts[0]
+		}
+		return accounts
+	}
+
+	require.Equal(t, sorted(accountA(100, 120, 1, storage), accountB), diff.Accounts)
+	require.Len(t, diff.Transactions, 2)
+	require.Equal(t, txn1.Hash(), diff.Transactions[0].TxnHash)
+	require.Equal(t, sorted(accountA(100, 150, 0, storage), accountB), diff.Transactions[0].Accounts)
+	require.Equal(t, txn2.Hash(), diff.Transactions[1].TxnHash)
+	require.Equal(t, []types.AccountStateDiff{accountA(150, 120, 1, []types.StorageDiff{})}, diff.Transactions[1].Accounts)
+}
@@ -49,6 +49,7 @@ func GenerateZeroState(t *testing.T, shardId types.ShardId, txFabric db.DB) *typ
 		GasPrice: config.ParamGasPrice{
 			Shards: []types.Uint256{*types.NewUint256(10), *types.NewUint256(10), *types.NewUint256(10)},
 		},
+		Forks: LatestForkSchedule(),
 	}
 	block, err := g.GenerateZeroState(zerostateCfg)
 	require.NoError(t, err)
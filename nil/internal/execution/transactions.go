@@ -5,6 +5,7 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/tracing"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/internal/vm"
@@ -107,7 +108,7 @@ func (a accountPayer) AddBalance(amount types.Value) error {
 }
 
 func (a accountPayer) String() string {
-	return fmt.Sprintf("account %v", a.transaction.From.Hex())
+	return fmt.Sprintf("account %v", a.account.address.Hex())
 }
 
 func buyGas(payer Payer, transaction *types.Transaction) error {
@@ -160,7 +161,7 @@ func validateExternalDeployTransaction(es *ExecutionState, transaction *types.Tr
 	return NewExecutionResult()
 }
 
-func validateExternalExecutionTransaction(es *ExecutionState, transaction *types.Transaction) *ExecutionResult {
+func validateExternalExecutionTransaction(es *ExecutionState, transaction *types.Transaction, payer *AccountState) *ExecutionResult {
 	check.PanicIfNot(transaction.IsExecution())
 
 	to := transaction.To
@@ -180,10 +181,77 @@ func validateExternalExecutionTransaction(es *ExecutionState, transaction *types
 		return NewExecutionResult().SetError(types.NewWrapError(types.ErrorSeqnoGap, err))
 	}
 
-	return es.CallVerifyExternal(transaction, account)
+	return es.CallVerifyExternal(transaction, account, payer)
 }
 
+// getPaymaster returns the paymaster of the external transaction.
+func getPaymaster(es *ExecutionState, transaction *types.Transaction) (*AccountState, *ExecutionResult) {
+	if transaction.Paymaster.ShardId() != transaction.To.ShardId() {
+		return nil, NewExecutionResult().SetError(types.NewError(types.ErrorPaymasterInOtherShard))
+	}
+	if exists, err := es.ContractExists(transaction.Paymaster); err != nil {
+		return nil, NewExecutionResult().SetFatal(err)
+	} else if !exists {
+		return nil, NewExecutionResult().SetError(types.NewError(types.ErrorPaymasterDoesNotExist))
+	}
+	paymaster, err := es.GetAccount(transaction.Paymaster)
+	if err != nil {
+		return nil, NewExecutionResult().SetFatal(err)
+	}
+	return paymaster, nil
+}
+
+// NewExternalTransactionPayer returns the payer of the fees of the validated external transaction:
+// the paymaster if the transaction has one, otherwise the recipient.
+func NewExternalTransactionPayer(es *ExecutionState, transaction *types.Transaction) (Payer, error) {
+	addr := transaction.To
+	if transaction.HasPaymaster() {
+		if es.Fork < params.ForkPaymasters {
+			return nil, forkNotActive("paymasters", params.ForkPaymasters).Error
+		}
+		addr = transaction.Paymaster
+	}
+	acc, err := es.GetAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil {
+		return nil, fmt.Errorf("payer %s of external transaction does not exist", addr)
+	}
+	return NewAccountPayer(acc, transaction), nil
+}
+
+// ValidateExternalTransaction checks the external transaction by the rules of the active fork.
 func ValidateExternalTransaction(es *ExecutionState, transaction *types.Transaction) *ExecutionResult {
+	return es.Rules().ValidateExternalTransaction(es, transaction)
+}
+
+// forkNotActive is the result of the validation of a transaction using the feature of a later fork.
+func forkNotActive(feature string, fork params.Fork) *ExecutionResult {
+	return NewExecutionResult().SetError(types.NewVerboseError(types.ErrorForkNotActive,
+		fmt.Sprintf("%s are supported since fork %s", feature, fork)))
+}
+
+// validateExternalTransaction is the validation of the genesis fork: the fees are always paid by the recipient.
+func validateExternalTransaction(es *ExecutionState, transaction *types.Transaction) *ExecutionResult {
+	if transaction.HasPaymaster() {
+		return forkNotActive("paymasters", params.ForkPaymasters)
+	}
+	return validateSponsoredExternalTransaction(es, transaction)
+}
+
+// validateSponsoredExternalTransaction is the validation of ForkPaymasters:
+// the fees are paid by the paymaster if the transaction names one.
+func validateSponsoredExternalTransaction(es *ExecutionState, transaction *types.Transaction) *ExecutionResult {
+	if transaction.IsBatch() {
+		return forkNotActive("batched transactions", params.ForkBatchTransactions)
+	}
+	return validateBatchedExternalTransaction(es, transaction)
+}
+
+// validateBatchedExternalTransaction is the validation of ForkBatchTransactions:
+// the data of batched transactions must be a valid batch.
+func validateBatchedExternalTransaction(es *ExecutionState, transaction *types.Transaction) *ExecutionResult {
 	check.PanicIfNot(transaction.IsExternal())
 
 	if transaction.ChainId != types.DefaultChainId {
@@ -195,18 +263,49 @@ func ValidateExternalTransaction(es *ExecutionState, transaction *types.Transact
 		return NewExecutionResult().SetError(types.NewError(types.ErrorMaxFeePerGasIsZero))
 	}
 
-	if account, err := es.GetAccount(transaction.To); err != nil {
+	account, err := es.GetAccount(transaction.To)
+	if err != nil {
 		return NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorNoAccount, err))
 	} else if account == nil {
 		return NewExecutionResult().SetError(types.NewError(types.ErrorDestinationContractDoesNotExist))
 	}
 
-	switch {
-	case transaction.IsDeploy():
-		return validateExternalDeployTransaction(es, transaction)
-	case transaction.IsRefund():
+	if transaction.IsRefund() {
 		return NewExecutionResult().SetError(types.NewError(types.ErrorRefundTransactionIsNotAllowedInExternalTransactions))
-	default:
-		return validateExternalExecutionTransaction(es, transaction)
 	}
+
+	if !transaction.HasPaymaster() {
+		return validateExternalTransactionByKind(es, transaction, account)
+	}
+
+	// The paymaster approves the transaction first and then pays for all the verifications.
+	// If the transaction is rejected, nothing is charged.
+	paymaster, res := getPaymaster(es, transaction)
+	if res != nil {
+		return res
+	}
+	snapshot := es.Snapshot()
+	paymasterRes := es.CallVerifyPaymaster(transaction, paymaster)
+	if paymasterRes.Failed() {
+		return paymasterRes
+	}
+	res = validateExternalTransactionByKind(es, transaction, paymaster)
+	if res.Failed() {
+		es.RevertToSnapshot(snapshot)
+		return res
+	}
+	res.GasPrice = paymasterRes.GasPrice
+	return res.AddUsed(paymasterRes.GasUsed)
+}
+
+func validateExternalTransactionByKind(es *ExecutionState, transaction *types.Transaction, payer *AccountState) *ExecutionResult {
+	if transaction.IsDeploy() {
+		return validateExternalDeployTransaction(es, transaction)
+	}
+	if transaction.IsBatch() {
+		if err := ValidateBatchTransaction(transaction); err != nil {
+			return NewExecutionResult().SetError(err)
+		}
+	}
+	return validateExternalExecutionTransaction(es, transaction, payer)
 }
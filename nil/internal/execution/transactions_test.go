@@ -8,6 +8,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/stretchr/testify/suite"
 )
@@ -132,6 +133,199 @@ func (s *TransactionsSuite) TestValidateExternalTransaction() {
 	})
 }
 
+func (s *TransactionsSuite) TestValidateSponsoredExternalTransaction() {
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	es, err := NewExecutionState(tx, types.BaseShardId, StateParams{
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	s.Require().NoError(err)
+	es.BaseFee = types.DefaultGasPrice
+	es.GasPrice = es.BaseFee
+
+	// contracts that always return "true" and "false"
+	approveCode := hexutil.FromHex("600160005260206000f3")
+	rejectCode := hexutil.FromHex("600060005260206000f3")
+	balance := types.NewValueFromUint64(10_000_000_000_000_000)
+
+	txn := types.NewEmptyTransaction()
+	txn.To = types.GenerateRandomAddress(types.BaseShardId)
+	txn.Data = []byte("hello")
+	txn.MaxFeePerGas = types.MaxFeePerGasDefault
+	s.Require().NoError(es.CreateAccount(txn.To))
+	s.Require().NoError(es.SetCode(txn.To, approveCode))
+
+	validate := func() types.ExecError {
+		res := ValidateExternalTransaction(es, txn)
+		s.Require().False(res.IsFatal())
+		if res.Failed() {
+			return res.Error
+		}
+		return nil
+	}
+	paymasterBalance := func() types.Value {
+		acc, err := es.GetAccount(txn.Paymaster)
+		s.Require().NoError(err)
+		return acc.Balance
+	}
+
+	s.Run("GenesisFork", func() {
+		txn.Paymaster = types.GenerateRandomAddress(types.BaseShardId)
+		defer func() {
+			txn.Paymaster = types.EmptyAddress
+		}()
+
+		s.Require().Equal(params.ForkGenesis, es.Fork)
+		s.Require().Equal(types.ErrorForkNotActive, validate().Code())
+	})
+	es.Fork = params.ForkPaymasters
+
+	s.Run("NoPaymaster", func() {
+		s.Require().Equal(types.ErrorInsufficientBalance, validate().Code())
+	})
+
+	s.Run("PaymasterInOtherShard", func() {
+		txn.Paymaster = types.GenerateRandomAddress(types.MainShardId)
+		s.Require().Equal(types.ErrorPaymasterInOtherShard, validate().Code())
+	})
+
+	s.Run("PaymasterDoesNotExist", func() {
+		txn.Paymaster = types.GenerateRandomAddress(types.BaseShardId)
+		s.Require().Equal(types.ErrorPaymasterDoesNotExist, validate().Code())
+
+		s.Require().NoError(es.CreateAccount(txn.Paymaster))
+		s.Require().NoError(es.SetCode(txn.Paymaster, rejectCode))
+		s.Require().NoError(es.SetBalance(txn.Paymaster, balance))
+	})
+
+	s.Run("PaymasterRejects", func() {
+		s.Require().Equal(types.ErrorPaymasterVerificationFailed, validate().Code())
+		s.Require().Equal(balance, paymasterBalance())
+
+		s.Require().NoError(es.SetCode(txn.Paymaster, approveCode))
+	})
+
+	s.Run("AccountRejects", func() {
+		s.Require().NoError(es.SetCode(txn.To, rejectCode))
+		defer func() {
+			s.Require().NoError(es.SetCode(txn.To, approveCode))
+		}()
+
+		s.Require().Equal(types.ErrorExternalVerificationFailed, validate().Code())
+		// The approval of the paymaster is reverted
+		s.Require().Equal(balance, paymasterBalance())
+	})
+
+	s.Run("Ok", func() {
+		s.Require().NoError(validate())
+		s.Require().Negative(paymasterBalance().Cmp(balance))
+
+		acc, err := es.GetAccount(txn.To)
+		s.Require().NoError(err)
+		s.Require().True(acc.Balance.IsZero())
+
+		payer, err := NewExternalTransactionPayer(es, txn)
+		s.Require().NoError(err)
+		s.Require().Equal("account "+txn.Paymaster.Hex(), payer.String())
+	})
+}
+
+func (s *TransactionsSuite) TestBatchTransaction() {
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	es, err := NewExecutionState(tx, types.BaseShardId, StateParams{
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	s.Require().NoError(err)
+	es.BaseFee = types.DefaultGasPrice
+	es.GasPrice = es.BaseFee
+
+	// contract that reverts on empty calldata and stores the first word of calldata to slot 0 otherwise
+	addr := types.GenerateRandomAddress(types.BaseShardId)
+	s.Require().NoError(es.CreateAccount(addr))
+	s.Require().NoError(es.SetCode(addr, hexutil.FromHex("3660095760006000fd5b60003560005500")))
+
+	word := func(v byte) types.Code {
+		w := make(types.Code, 32)
+		w[31] = v
+		return w
+	}
+	slot := func() byte {
+		v, err := es.GetState(addr, common.EmptyHash)
+		s.Require().NoError(err)
+		return v[31]
+	}
+	newTxn := func(payload *types.BatchPayload) *types.Transaction {
+		data, err := payload.Bytes()
+		s.Require().NoError(err)
+		txn := types.NewEmptyTransaction()
+		txn.Flags = types.NewTransactionFlags(types.TransactionFlagBatch)
+		txn.To = addr
+		txn.From = addr
+		txn.Data = data
+		txn.FeeCredit = toGasCredit(1_000_000)
+		txn.MaxFeePerGas = types.MaxFeePerGasDefault
+		return txn
+	}
+
+	s.Run("InvalidPayload", func() {
+		txn := newTxn(types.NewBatchPayload(true))
+		s.Require().Equal(types.ErrorInvalidPayload, ValidateBatchTransaction(txn).Code())
+	})
+
+	s.Run("Value", func() {
+		txn := newTxn(types.NewBatchPayload(true, word(1)))
+		txn.Value = types.NewValueFromUint64(1)
+		s.Require().Equal(types.ErrorInvalidPayload, ValidateBatchTransaction(txn).Code())
+
+		txn.Value = types.NewZeroValue()
+		txn.Token = []types.TokenBalance{{Token: types.TokenId(addr), Balance: types.NewValueFromUint64(1)}}
+		s.Require().Equal(types.ErrorInvalidPayload, ValidateBatchTransaction(txn).Code())
+	})
+
+	s.Run("ForkNotActive", func() {
+		txn := newTxn(types.NewBatchPayload(true, word(1)))
+		fork := es.Fork
+		es.Fork = params.ForkPaymasters
+		defer func() {
+			es.Fork = fork
+		}()
+		s.Require().Equal(types.ErrorForkNotActive, ValidateExternalTransaction(es, txn).Error.Code())
+	})
+
+	s.Run("Atomic", func() {
+		txn := newTxn(types.NewBatchPayload(true, word(1), nil, word(2)))
+		s.Require().NoError(ValidateBatchTransaction(txn))
+
+		res := es.HandleTransaction(s.ctx, txn, dummyPayer{})
+		s.Require().False(res.IsFatal())
+		s.Require().Equal(types.ErrorExecutionReverted, res.Error.Code())
+		s.Require().Len(res.BatchReceipts, 2)
+		s.True(res.BatchReceipts[0].Success)
+		s.False(res.BatchReceipts[1].Success)
+		s.Equal(types.ErrorExecutionReverted, res.BatchReceipts[1].Status)
+		s.Zero(slot())
+	})
+
+	s.Run("BestEffort", func() {
+		txn := newTxn(types.NewBatchPayload(false, word(1), nil, word(2)))
+
+		res := es.HandleTransaction(s.ctx, txn, dummyPayer{})
+		s.Require().False(res.Failed())
+		s.Require().Len(res.BatchReceipts, 3)
+		s.True(res.BatchReceipts[0].Success)
+		s.False(res.BatchReceipts[1].Success)
+		s.True(res.BatchReceipts[2].Success)
+		s.Equal(res.GasUsed,
+			res.BatchReceipts[0].GasUsed+res.BatchReceipts[1].GasUsed+res.BatchReceipts[2].GasUsed)
+		s.EqualValues(2, slot())
+	})
+}
+
 func (s *TransactionsSuite) TestValidateDeployTransaction() {
 	txn := types.NewEmptyTransaction()
 	txn.Data = types.Code("no-salt")
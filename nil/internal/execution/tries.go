@@ -34,21 +34,25 @@ type BaseMPT[K any, V any, VPtr MPTValue[V]] struct {
 }
 
 type (
-	ContractTrie     = BaseMPT[common.Hash, types.SmartContract, *types.SmartContract]
-	TransactionTrie  = BaseMPT[types.TransactionIndex, types.Transaction, *types.Transaction]
-	ReceiptTrie      = BaseMPT[types.TransactionIndex, types.Receipt, *types.Receipt]
-	StorageTrie      = BaseMPT[common.Hash, types.Uint256, *types.Uint256]
-	TokenTrie        = BaseMPT[types.TokenId, types.Value, *types.Value]
-	ShardBlocksTrie  = BaseMPT[types.ShardId, common.Hash, *common.Hash]
-	AsyncContextTrie = BaseMPT[types.TransactionIndex, types.AsyncContext, *types.AsyncContext]
-
-	ContractTrieReader     = BaseMPTReader[common.Hash, types.SmartContract, *types.SmartContract]
-	TransactionTrieReader  = BaseMPTReader[types.TransactionIndex, types.Transaction, *types.Transaction]
-	ReceiptTrieReader      = BaseMPTReader[types.TransactionIndex, types.Receipt, *types.Receipt]
-	StorageTrieReader      = BaseMPTReader[common.Hash, types.Uint256, *types.Uint256]
-	TokenTrieReader        = BaseMPTReader[types.TokenId, types.Value, *types.Value]
-	ShardBlocksTrieReader  = BaseMPTReader[types.ShardId, common.Hash, *common.Hash]
-	AsyncContextTrieReader = BaseMPTReader[types.TransactionIndex, types.AsyncContext, *types.AsyncContext]
+	ContractTrie        = BaseMPT[common.Hash, types.SmartContract, *types.SmartContract]
+	TransactionTrie     = BaseMPT[types.TransactionIndex, types.Transaction, *types.Transaction]
+	ReceiptTrie         = BaseMPT[types.TransactionIndex, types.Receipt, *types.Receipt]
+	StorageTrie         = BaseMPT[common.Hash, types.Uint256, *types.Uint256]
+	TokenTrie           = BaseMPT[types.TokenId, types.Value, *types.Value]
+	ShardBlocksTrie     = BaseMPT[types.ShardId, common.Hash, *common.Hash]
+	AsyncContextTrie    = BaseMPT[types.TransactionIndex, types.AsyncContext, *types.AsyncContext]
+	ScheduledCallTrie   = BaseMPT[common.Hash, types.ScheduledCall, *types.ScheduledCall]
+	ScheduledCallIdTrie = BaseMPT[common.Hash, common.Hash, *common.Hash]
+
+	ContractTrieReader        = BaseMPTReader[common.Hash, types.SmartContract, *types.SmartContract]
+	TransactionTrieReader     = BaseMPTReader[types.TransactionIndex, types.Transaction, *types.Transaction]
+	ReceiptTrieReader         = BaseMPTReader[types.TransactionIndex, types.Receipt, *types.Receipt]
+	StorageTrieReader         = BaseMPTReader[common.Hash, types.Uint256, *types.Uint256]
+	TokenTrieReader           = BaseMPTReader[types.TokenId, types.Value, *types.Value]
+	ShardBlocksTrieReader     = BaseMPTReader[types.ShardId, common.Hash, *common.Hash]
+	AsyncContextTrieReader    = BaseMPTReader[types.TransactionIndex, types.AsyncContext, *types.AsyncContext]
+	ScheduledCallTrieReader   = BaseMPTReader[common.Hash, types.ScheduledCall, *types.ScheduledCall]
+	ScheduledCallIdTrieReader = BaseMPTReader[common.Hash, common.Hash, *common.Hash]
 )
 
 func NewContractTrieReader(parent *mpt.Reader) *ContractTrieReader {
@@ -91,6 +95,22 @@ func NewAsyncContextTrieReader(parent *mpt.Reader) *AsyncContextTrieReader {
 	}
 }
 
+func NewScheduledCallTrieReader(parent *mpt.Reader) *ScheduledCallTrieReader {
+	return &ScheduledCallTrieReader{
+		parent,
+		func(k common.Hash) []byte { return k.Bytes() },
+		func(bs []byte) (common.Hash, error) { return common.BytesToHash(bs), nil },
+	}
+}
+
+func NewScheduledCallIdTrieReader(parent *mpt.Reader) *ScheduledCallIdTrieReader {
+	return &ScheduledCallIdTrieReader{
+		parent,
+		func(k common.Hash) []byte { return k.Bytes() },
+		func(bs []byte) (common.Hash, error) { return common.BytesToHash(bs), nil },
+	}
+}
+
 func NewTokenTrieReader(parent *mpt.Reader) *TokenTrieReader {
 	return &TokenTrieReader{
 		parent,
@@ -146,6 +166,20 @@ func NewAsyncContextTrie(parent *mpt.MerklePatriciaTrie) *AsyncContextTrie {
 	}
 }
 
+func NewScheduledCallTrie(parent *mpt.MerklePatriciaTrie) *ScheduledCallTrie {
+	return &ScheduledCallTrie{
+		BaseMPTReader: NewScheduledCallTrieReader(parent.Reader),
+		rwTrie:        parent,
+	}
+}
+
+func NewScheduledCallIdTrie(parent *mpt.MerklePatriciaTrie) *ScheduledCallIdTrie {
+	return &ScheduledCallIdTrie{
+		BaseMPTReader: NewScheduledCallIdTrieReader(parent.Reader),
+		rwTrie:        parent,
+	}
+}
+
 func NewTokenTrie(parent *mpt.MerklePatriciaTrie) *TokenTrie {
 	return &TokenTrie{
 		BaseMPTReader: NewTokenTrieReader(parent.Reader),
@@ -180,6 +214,10 @@ func NewDbAsyncContextTrieReader(tx db.RoTx, shardId types.ShardId) *AsyncContex
 	return NewAsyncContextTrieReader(mpt.NewDbReader(tx, shardId, db.AsyncCallContextTable))
 }
 
+func NewDbScheduledCallTrieReader(tx db.RoTx, shardId types.ShardId) *ScheduledCallTrieReader {
+	return NewScheduledCallTrieReader(mpt.NewDbReader(tx, shardId, db.ScheduledCallTrieTable))
+}
+
 func NewDbTokenTrieReader(tx db.RoTx, shardId types.ShardId) *TokenTrieReader {
 	return NewTokenTrieReader(mpt.NewDbReader(tx, shardId, db.TokenTrieTable))
 }
@@ -208,6 +246,13 @@ func NewDbAsyncContextTrie(tx db.RwTx, shardId types.ShardId) *AsyncContextTrie
 	return NewAsyncContextTrie(mpt.NewDbMPT(tx, shardId, db.AsyncCallContextTable))
 }
 
+// NewDbScheduledCallTries returns the views of the trie of the scheduled calls: the calls by their keys
+// and the keys by the ids of the calls.
+func NewDbScheduledCallTries(tx db.RwTx, shardId types.ShardId) (*ScheduledCallTrie, *ScheduledCallIdTrie) {
+	trie := mpt.NewDbMPT(tx, shardId, db.ScheduledCallTrieTable)
+	return NewScheduledCallTrie(trie), NewScheduledCallIdTrie(trie)
+}
+
 func NewDbTokenTrie(tx db.RwTx, shardId types.ShardId) *TokenTrie {
 	return NewTokenTrie(mpt.NewDbMPT(tx, shardId, db.TokenTrieTable))
 }
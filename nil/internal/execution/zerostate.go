@@ -11,6 +11,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	nilcrypto "github.com/NilFoundation/nil/nil/internal/crypto"
+	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"gopkg.in/yaml.v3"
@@ -33,6 +34,7 @@ type MainKeys struct {
 type ConfigParams struct {
 	Validators config.ParamValidators `yaml:"validators,omitempty"`
 	GasPrice   config.ParamGasPrice   `yaml:"gasPrice"`
+	Forks      config.ParamForks      `yaml:"forks,omitempty"`
 }
 
 type ZeroStateConfig struct {
@@ -101,10 +103,17 @@ contracts:
 		return nil, err
 	}
 	zeroStateConfig.MainPublicKey = mainPublicKey
+	zeroStateConfig.ConfigParams.Forks = LatestForkSchedule()
 
 	return zeroStateConfig, nil
 }
 
+// LatestForkSchedule activates the latest fork from the zerostate. It's used for new networks,
+// the existing ones keep their schedule and have to be upgraded by changing the config.
+func LatestForkSchedule() config.ParamForks {
+	return config.ParamForks{Schedule: []config.ForkActivation{{Fork: uint32(params.LatestFork), Height: 0}}}
+}
+
 func (cfg *ZeroStateConfig) GetValidators() []config.ListValidators {
 	return cfg.ConfigParams.Validators.Validators
 }
@@ -181,6 +190,18 @@ func (es *ExecutionState) GenerateZeroState(stateConfig *ZeroStateConfig) error
 		if err != nil {
 			return err
 		}
+		// Without the schedule the genesis rules are always active, and the param isn't written,
+		// so the genesis of the configs without forks stays the same
+		if len(stateConfig.ConfigParams.Forks.Schedule) != 0 {
+			err = config.SetParamForks(cfgAccessor, &stateConfig.ConfigParams.Forks)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := es.UpdateFork(0); err != nil {
+		return err
 	}
 
 	if len(stateConfig.ConfigParams.GasPrice.Shards) != 0 {
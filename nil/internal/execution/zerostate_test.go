@@ -244,6 +244,39 @@ contracts:
 	require.Nil(t, smartAccount)
 }
 
+func TestZeroStateWithoutForks(t *testing.T) {
+	t.Parallel()
+
+	generate := func(forks config.ParamForks) *types.Block {
+		t.Helper()
+
+		database, err := db.NewBadgerDbInMemory()
+		require.NoError(t, err)
+		t.Cleanup(database.Close)
+
+		g, err := NewBlockGenerator(t.Context(), NewBlockGeneratorParams(types.MainShardId, 2), database, nil)
+		require.NoError(t, err)
+		defer g.Rollback()
+
+		block, err := g.GenerateZeroState(&ZeroStateConfig{ConfigParams: ConfigParams{
+			GasPrice: config.ParamGasPrice{Shards: []types.Uint256{*types.NewUint256(10), *types.NewUint256(10)}},
+			Forks:    forks,
+		}})
+		require.NoError(t, err)
+		return block
+	}
+
+	// The genesis of the config without forks is the same as before the fork schedule was introduced
+	configRoot := common.HexToHash("0x12dfc97daa9b11d628b986839a9c34584af264b9369bc5ee4f10b37a96e4f4e4")
+	block := generate(config.ParamForks{})
+	require.Equal(t, configRoot, block.ConfigRoot)
+	require.Equal(t, common.HexToHash("0x00004be481d504918e417f321d37d2cd821397cb25e8136eb58d109568a2f372"),
+		block.Hash(types.MainShardId))
+
+	block = generate(LatestForkSchedule())
+	require.NotEqual(t, configRoot, block.ConfigRoot)
+}
+
 func TestSuiteZeroState(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,232 @@
+package keystore
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+const keyFileExt = ".json"
+
+var (
+	ErrAccountNotFound = errors.New("account not found")
+	ErrAccountExists   = errors.New("account already exists")
+	ErrInvalidName     = errors.New("invalid account name")
+
+	nameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+)
+
+// Keystore keeps named private keys in a directory, one file per key,
+// encrypted with a passphrase in the Ethereum v3 keystore format (scrypt and AES-128-CTR).
+type Keystore struct {
+	dir     string
+	scryptN int
+	scryptP int
+}
+
+// New returns a keystore storing the keys in the directory.
+func New(dir string) *Keystore {
+	return &Keystore{
+		dir:     dir,
+		scryptN: keystore.StandardScryptN,
+		scryptP: keystore.StandardScryptP,
+	}
+}
+
+// NewLight returns a keystore using the scrypt parameters requiring less memory and CPU time,
+// which is useful for tests.
+func NewLight(dir string) *Keystore {
+	ks := New(dir)
+	ks.scryptN = keystore.LightScryptN
+	ks.scryptP = keystore.LightScryptP
+	return ks
+}
+
+func (ks *Keystore) Dir() string {
+	return ks.dir
+}
+
+func (ks *Keystore) path(name string) (string, error) {
+	if !nameRegexp.MatchString(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return filepath.Join(ks.dir, name+keyFileExt), nil
+}
+
+// Import encrypts the key with the passphrase and stores it under the name.
+func (ks *Keystore) Import(name string, key *ecdsa.PrivateKey, passphrase string) error {
+	path, err := ks.path(name)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+	data, err := keystore.EncryptKey(&keystore.Key{
+		Id:         id,
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, passphrase, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(ks.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create keystore directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%w: %s", ErrAccountExists, name)
+		}
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(data)
+	return err
+}
+
+// Load decrypts the key stored under the name.
+func (ks *Keystore) Load(name string, passphrase string) (*ecdsa.PrivateKey, error) {
+	path, err := ks.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, name)
+		}
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt account %s: %w", name, err)
+	}
+	return key.PrivateKey, nil
+}
+
+// Delete removes the key stored under the name.
+func (ks *Keystore) Delete(name string) error {
+	path, err := ks.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrAccountNotFound, name)
+		}
+		return err
+	}
+	return nil
+}
+
+// List returns the sorted names of the stored keys.
+func (ks *Keystore) List() ([]string, error) {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), keyFileExt)
+		if entry.IsDir() || !ok || !nameRegexp.MatchString(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// PassphraseFunc returns the passphrase unlocking the account.
+type PassphraseFunc func(name string) (string, error)
+
+// Signer signs with a key from the keystore.
+// The key is decrypted on the first use, so the passphrase is requested only if something has to be signed.
+type Signer struct {
+	ks         *Keystore
+	name       string
+	passphrase PassphraseFunc
+
+	mu  sync.Mutex
+	key *ecdsa.PrivateKey
+}
+
+var _ client.Signer = (*Signer)(nil)
+
+func NewSigner(ks *Keystore, name string, passphrase PassphraseFunc) *Signer {
+	return &Signer{
+		ks:         ks,
+		name:       name,
+		passphrase: passphrase,
+	}
+}
+
+func (s *Signer) Name() string {
+	return s.name
+}
+
+func (s *Signer) unlock() (*ecdsa.PrivateKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key != nil {
+		return s.key, nil
+	}
+
+	passphrase, err := s.passphrase(s.name)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.ks.Load(s.name, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	s.key = key
+	return key, nil
+}
+
+func (s *Signer) PublicKey(context.Context) (*ecdsa.PublicKey, error) {
+	key, err := s.unlock()
+	if err != nil {
+		return nil, err
+	}
+	return &key.PublicKey, nil
+}
+
+func (s *Signer) Sign(_ context.Context, hash common.Hash) (types.Signature, error) {
+	key, err := s.unlock()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		return nil, err
+	}
+	return types.Signature(sig), nil
+}
@@ -0,0 +1,118 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystore(t *testing.T) {
+	t.Parallel()
+
+	ks := NewLight(filepath.Join(t.TempDir(), "keystore"))
+
+	names, err := ks.List()
+	require.NoError(t, err)
+	require.Empty(t, names)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	require.NoError(t, ks.Import("main", key, "secret"))
+	require.ErrorIs(t, ks.Import("main", key, "secret"), ErrAccountExists)
+	require.ErrorIs(t, ks.Import("../main", key, "secret"), ErrInvalidName)
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	require.NoError(t, ks.Import("dev-1", other, ""))
+
+	names, err = ks.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"dev-1", "main"}, names)
+
+	t.Run("Load", func(t *testing.T) {
+		t.Parallel()
+
+		loaded, err := ks.Load("main", "secret")
+		require.NoError(t, err)
+		require.Equal(t, key.D, loaded.D)
+
+		_, err = ks.Load("main", "wrong")
+		require.Error(t, err)
+
+		_, err = ks.Load("unknown", "secret")
+		require.ErrorIs(t, err, ErrAccountNotFound)
+	})
+
+	t.Run("EthereumCompatibility", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := os.ReadFile(filepath.Join(ks.Dir(), "main.json"))
+		require.NoError(t, err)
+		require.NotContains(t, string(data), hex.EncodeToString(crypto.FromECDSA(key)))
+
+		decrypted, err := keystore.DecryptKey(data, "secret")
+		require.NoError(t, err)
+		require.Equal(t, key.D, decrypted.PrivateKey.D)
+		require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), decrypted.Address)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		ks := NewLight(t.TempDir())
+		require.NoError(t, ks.Import("tmp", key, ""))
+		require.NoError(t, ks.Delete("tmp"))
+		require.ErrorIs(t, ks.Delete("tmp"), ErrAccountNotFound)
+	})
+}
+
+func TestSigner(t *testing.T) {
+	t.Parallel()
+
+	ks := NewLight(t.TempDir())
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	require.NoError(t, ks.Import("main", key, "secret"))
+
+	requests := 0
+	signer := NewSigner(ks, "main", func(name string) (string, error) {
+		requests++
+		require.Equal(t, "main", name)
+		return "secret", nil
+	})
+	require.Zero(t, requests)
+
+	pub, err := signer.PublicKey(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, key.PublicKey, *pub)
+
+	txn := &types.ExternalTransaction{
+		To:    types.GenerateRandomAddress(types.BaseShardId),
+		Data:  types.Code{1, 2, 3},
+		Seqno: 1,
+	}
+	require.NoError(t, client.SignExternalTransaction(t.Context(), txn, signer))
+	require.Equal(t, 1, requests)
+
+	expected := *txn
+	require.NoError(t, expected.Sign(key))
+	require.Equal(t, expected.AuthData, txn.AuthData)
+
+	t.Run("WrongPassphrase", func(t *testing.T) {
+		t.Parallel()
+
+		signer := NewSigner(ks, "main", func(string) (string, error) {
+			return "wrong", nil
+		})
+		_, err := signer.Sign(t.Context(), common.EmptyHash)
+		require.Error(t, err)
+	})
+}
@@ -4,41 +4,68 @@ import (
 	"iter"
 )
 
+// Iterate returns the entries of the trie in the order of their keys.
 func (m *Reader) Iterate() iter.Seq2[[]byte, []byte] {
+	return m.IterateFrom(nil)
+}
+
+// IterateFrom returns the entries of the trie with the keys not less than start in the order of the keys.
+// Note that the keys longer than maxRawKeyLen are hashed, so the order makes sense only for shorter keys.
+func (m *Reader) IterateFrom(start []byte) iter.Seq2[[]byte, []byte] {
 	type Yield = func([]byte, []byte) bool
+	startPath := newPath(start, false)
 	return func(yield Yield) {
-		var iter func(ref Reference, path *Path)
-		iter = func(ref Reference, path *Path) {
+		// iter returns false if the iteration is stopped.
+		// bounded is true if the path of the parent node is a prefix of the start path.
+		var iter func(ref Reference, path *Path, bounded bool) bool
+		iter = func(ref Reference, path *Path, bounded bool) bool {
 			node, err := m.getNode(ref)
 			if err != nil {
-				return
+				return true
 			}
 			npath := node.Path()
 			if npath != nil {
 				path = path.Combine(npath)
 			}
+			if bounded {
+				cmp := comparePrefix(path, startPath)
+				if cmp < 0 {
+					return true
+				}
+				bounded = cmp == 0 && path.Size() < startPath.Size()
+			}
 			data := node.Data()
-			if len(data) > 0 {
+			if len(data) > 0 && !bounded {
 				// note: even though we access path.Data directly here is ok
 				// cause every key in the mpt is []byte, i.e. it consists of even number of nibbles
 				if !yield(path.Data, data) {
-					return
+					return false
 				}
 			}
 			switch node := node.(type) {
 			case *BranchNode:
 				for i, br := range node.Branches {
-					if len(br) > 0 {
-						iter(br, path.Combine(newPath([]byte{byte(i)}, true)))
+					if len(br) > 0 && !iter(br, path.Combine(newPath([]byte{byte(i)}, true)), bounded) {
+						return false
 					}
 				}
-				return
 			case *ExtensionNode:
-				iter(node.NextRef, path)
+				return iter(node.NextRef, path, bounded)
 			}
+			return true
 		}
 		if m.root.IsValid() {
-			iter(m.root, newPath(nil, false))
+			iter(m.root, newPath(nil, false), !startPath.Empty())
+		}
+	}
+}
+
+// comparePrefix compares the nibbles of the paths up to the length of the shorter one.
+func comparePrefix(path, other *Path) int {
+	for i := range min(path.Size(), other.Size()) {
+		if d := path.At(i) - other.At(i); d != 0 {
+			return d
 		}
 	}
+	return 0
 }
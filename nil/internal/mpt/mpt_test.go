@@ -119,6 +119,28 @@ func TestIterate(t *testing.T) {
 		i += 1
 	}
 	require.Len(t, keys, i)
+
+	iterateFrom := func(start string, limit int) []string {
+		t.Helper()
+
+		var res []string
+		for k := range trie.IterateFrom([]byte(start)) {
+			if len(res) == limit {
+				break
+			}
+			res = append(res, string(k))
+		}
+		return res
+	}
+	require.Equal(t, []string{"do", "dog", "doge", "horse"}, iterateFrom("", 10))
+	require.Equal(t, []string{"do", "dog", "doge", "horse"}, iterateFrom("d", 10))
+	require.Equal(t, []string{"dog", "doge", "horse"}, iterateFrom("dof", 10))
+	require.Equal(t, []string{"doge", "horse"}, iterateFrom("doga", 10))
+	require.Equal(t, []string{"horse"}, iterateFrom("e", 10))
+	require.Empty(t, iterateFrom("i", 10))
+
+	// The iteration can be stopped in the middle of a branch
+	require.Equal(t, []string{"dog"}, iterateFrom("dog", 1))
 }
 
 func TestInsertGetLots(t *testing.T) {
@@ -9,11 +9,13 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/network/internal"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/peerstore"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/metric"
 )
 
 type Manager struct {
@@ -23,7 +25,8 @@ type Manager struct {
 	pubSub *PubSub
 	dht    *DHT
 
-	meter telemetry.Meter
+	meter      telemetry.Meter
+	peersGauge metric.Registration
 
 	logger zerolog.Logger
 }
@@ -70,14 +73,32 @@ func newManagerFromHost(ctx context.Context, conf *Config, h host.Host) (*Manage
 		return nil, err
 	}
 
-	return &Manager{
+	m := &Manager{
 		ctx:    ctx,
 		host:   h,
 		pubSub: ps,
 		dht:    dht,
 		meter:  telemetry.NewMeter("github.com/NilFoundation/nil/nil/internal/network"),
 		logger: logger,
-	}, nil
+	}
+	if err := m.registerPeersGauge(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Manager) registerPeersGauge() error {
+	peers, err := m.meter.Int64ObservableGauge("peers", metric.WithDescription("Number of connected peers"))
+	if err != nil {
+		return err
+	}
+
+	option := metric.WithAttributes(telattr.P2PIdentity(m.host.ID()))
+	m.peersGauge, err = m.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(peers, int64(len(m.host.Network().Peers())), option)
+		return nil
+	}, peers)
+	return err
 }
 
 func NewManager(ctx context.Context, conf *Config) (*Manager, error) {
@@ -161,6 +182,10 @@ func (m *Manager) Connect(ctx context.Context, addr AddrInfo) (PeerID, error) {
 }
 
 func (m *Manager) Close() {
+	if err := m.peersGauge.Unregister(); err != nil {
+		m.logError(err, "Error unregistering peers gauge")
+	}
+
 	if m.dht != nil {
 		if err := m.dht.Close(); err != nil {
 			m.logError(err, "Error closing DHT")
@@ -0,0 +1,49 @@
+package params
+
+import "fmt"
+
+// Fork identifies a set of execution rules: opcodes, precompiles, fee formulas and transaction validation.
+// Forks are activated by the height of the main shard according to the schedule stored in the config
+// (see config.ParamForks), so historical blocks are always replayed with the rules they were produced with.
+type Fork uint32
+
+const (
+	// ForkGenesis is the rule set the network is launched with.
+	ForkGenesis Fork = iota
+	// ForkPaymasters lets external transactions name a paymaster which pays their fees.
+	ForkPaymasters
+	// ForkBatchTransactions lets external transactions execute a batch of calls with a receipt per call.
+	ForkBatchTransactions
+	// ForkScheduledCalls lets contracts schedule calls to be executed by the collator in a later block.
+	ForkScheduledCalls
+	// ForkAsyncTimeouts lets contracts set a timeout of async requests, which fail if the response doesn't arrive in time.
+	ForkAsyncTimeouts
+	// ForkConsensusMessageDomain prefixes the signed hash of a consensus message with a domain,
+	// so the signature of a message can't be obtained as a committed seal and vice versa.
+	// The validators of a shard have to upgrade their nodes and remote signers before the activation.
+	ForkConsensusMessageDomain
+
+	// LatestFork is the newest fork supported by this node.
+	LatestFork = ForkConsensusMessageDomain
+)
+
+var forkNames = map[Fork]string{
+	ForkGenesis:                "genesis",
+	ForkPaymasters:             "paymasters",
+	ForkBatchTransactions:      "batch-transactions",
+	ForkScheduledCalls:         "scheduled-calls",
+	ForkAsyncTimeouts:          "async-timeouts",
+	ForkConsensusMessageDomain: "consensus-message-domain",
+}
+
+// IsKnown returns true if the node implements the rules of the fork.
+func (f Fork) IsKnown() bool {
+	return f <= LatestFork
+}
+
+func (f Fork) String() string {
+	if name, ok := forkNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("fork#%d", uint32(f))
+}
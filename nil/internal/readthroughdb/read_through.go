@@ -188,6 +188,10 @@ func (db *ReadThroughDb) LogGC(ctx context.Context, discardRation float64, gcFre
 	return db.db.LogGC(ctx, discardRation, gcFrequency)
 }
 
+func (db *ReadThroughDb) RunGC(ctx context.Context, discardRation float64) error {
+	return db.db.RunGC(ctx, discardRation)
+}
+
 func NewReadThroughDb(client client.DbClient, baseDb db.DB) (db.DB, error) {
 	db := &ReadThroughDb{
 		client: client,
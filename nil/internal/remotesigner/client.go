@@ -0,0 +1,247 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// Client calls the remote signer over a unix socket or HTTPS.
+type Client struct {
+	client    http.Client
+	endpoint  string
+	authToken string
+}
+
+// NewClient returns a client of the signer at the endpoint, which is either
+// "unix:///path/to/socket", "tcp://host:port" or an HTTPS URL.
+// The signer is called over TCP with TLS and the auth token from the transport config only.
+func NewClient(endpoint string, transport *TransportConfig) (*Client, error) {
+	token, err := transport.authToken()
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		client:    http.Client{Timeout: defaultRequestTimeout},
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		authToken: token,
+	}
+
+	if socketPath, ok := strings.CutPrefix(endpoint, "unix://"); ok {
+		c.endpoint = "http://unix"
+		c.client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		}
+		return c, nil
+	}
+
+	if address, ok := strings.CutPrefix(endpoint, "tcp://"); ok {
+		c.endpoint = "https://" + address
+	}
+	if !strings.HasPrefix(c.endpoint, "https://") || token == "" {
+		return nil, fmt.Errorf("%w: the signer at %s must be called over HTTPS with an auth token",
+			ErrInsecureTransport, endpoint)
+	}
+	tlsConfig, err := transport.clientTLS()
+	if err != nil {
+		return nil, err
+	}
+	c.client.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	return c, nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.authToken != "" {
+		request.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+	return request, nil
+}
+
+func (c *Client) call(ctx context.Context, method, path string, req, resp any) error {
+	var body io.Reader
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	request, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(response.Body)
+		switch response.StatusCode {
+		case http.StatusPreconditionFailed:
+			return fmt.Errorf("%w: %s", ErrSlashingProtection, data)
+		case http.StatusForbidden:
+			return fmt.Errorf("%w: %s", ErrTransactionPolicy, data)
+		}
+		return fmt.Errorf("remote signer responded with %s: %s", response.Status, data)
+	}
+	return json.NewDecoder(response.Body).Decode(resp)
+}
+
+// Upcheck checks that the signer is available.
+func (c *Client) Upcheck(ctx context.Context) error {
+	request, err := c.newRequest(ctx, http.MethodGet, upcheckPath, nil)
+	if err != nil {
+		return err
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote signer responded with %s", response.Status)
+	}
+	return nil
+}
+
+// BlsSigner returns the signer with the public key. If the key is empty, the signer must hold exactly one key.
+func (c *Client) BlsSigner(ctx context.Context, publicKey []byte) (*RemoteBlsSigner, error) {
+	var keys []hexutil.Bytes
+	if err := c.call(ctx, http.MethodGet, blsPublicKeysPath, nil, &keys); err != nil {
+		return nil, err
+	}
+
+	key, err := selectKey(keys, publicKey, func(k hexutil.Bytes, b []byte) bool {
+		return bytes.Equal(k, b)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to select BLS key: %w", err)
+	}
+	return &RemoteBlsSigner{client: c, publicKey: key}, nil
+}
+
+// EcdsaSigner returns the signer with the address. If the address is empty, the signer must hold exactly one key.
+func (c *Client) EcdsaSigner(ctx context.Context, address ethcommon.Address) (*RemoteEcdsaSigner, error) {
+	var addresses []ethcommon.Address
+	if err := c.call(ctx, http.MethodGet, secp256k1AddressPath, nil, &addresses); err != nil {
+		return nil, err
+	}
+
+	var wanted []byte
+	if address != (ethcommon.Address{}) {
+		wanted = address.Bytes()
+	}
+	selected, err := selectKey(addresses, wanted, func(a ethcommon.Address, b []byte) bool {
+		return bytes.Equal(a.Bytes(), b)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to select secp256k1 key: %w", err)
+	}
+	return &RemoteEcdsaSigner{client: c, address: selected}, nil
+}
+
+func selectKey[K any](keys []K, wanted []byte, equal func(K, []byte) bool) (K, error) {
+	var empty K
+	if len(wanted) == 0 {
+		if len(keys) != 1 {
+			return empty, fmt.Errorf("the key is not specified and the signer holds %d keys", len(keys))
+		}
+		return keys[0], nil
+	}
+	for _, key := range keys {
+		if equal(key, wanted) {
+			return key, nil
+		}
+	}
+	return empty, errors.New("the key is not held by the signer")
+}
+
+// RemoteBlsSigner signs consensus data with the validator key held by the remote signer.
+type RemoteBlsSigner struct {
+	client    *Client
+	publicKey hexutil.Bytes
+}
+
+var _ BlsSigner = (*RemoteBlsSigner)(nil)
+
+func (s *RemoteBlsSigner) PublicKey() []byte {
+	return s.publicKey
+}
+
+func (s *RemoteBlsSigner) SignBls(ctx context.Context, req *BlsSigningRequest) (types.BlsSignature, error) {
+	var resp signatureResponse
+	if err := s.client.call(ctx, http.MethodPost, blsSignPath+s.publicKey.String(), req, &resp); err != nil {
+		return nil, err
+	}
+	return types.BlsSignature(resp.Signature), nil
+}
+
+// RemoteEcdsaSigner signs L1 transactions with the key held by the remote signer.
+type RemoteEcdsaSigner struct {
+	client  *Client
+	address ethcommon.Address
+}
+
+var _ EcdsaSigner = (*RemoteEcdsaSigner)(nil)
+
+func (s *RemoteEcdsaSigner) Address() ethcommon.Address {
+	return s.address
+}
+
+// SignTransaction sends the transaction without the blob sidecar, which isn't signed, and attaches it to the result.
+func (s *RemoteEcdsaSigner) SignTransaction(
+	ctx context.Context, txn *ethtypes.Transaction, chainID *big.Int,
+) (*ethtypes.Transaction, error) {
+	data, err := txn.WithoutBlobTxSidecar().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var resp transactionResponse
+	req := &transactionSigningRequest{ChainId: (*hexutil.Big)(chainID), Transaction: data}
+	if err := s.client.call(ctx, http.MethodPost, secp256k1SignPath+s.address.Hex(), req, &resp); err != nil {
+		return nil, err
+	}
+
+	signed := &ethtypes.Transaction{}
+	if err := signed.UnmarshalBinary(resp.Transaction); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+	// The signer must not substitute the transaction
+	signer := ethtypes.LatestSignerForChainID(chainID)
+	if signer.Hash(signed) != signer.Hash(txn) {
+		return nil, errors.New("the remote signer returned another transaction")
+	}
+	if from, err := ethtypes.Sender(signer, signed); err != nil || from != s.address {
+		return nil, fmt.Errorf("the remote signer returned a transaction not signed by %s", s.address)
+	}
+	if sidecar := txn.BlobTxSidecar(); sidecar != nil {
+		signed = signed.WithBlobTxSidecar(sidecar)
+	}
+	return signed, nil
+}
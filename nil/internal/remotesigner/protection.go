@@ -0,0 +1,163 @@
+package remotesigner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// DefaultProtectionRetention is the number of the latest heights whose signed proposals are kept.
+const DefaultProtectionRetention = 1024
+
+var ErrSlashingProtection = errors.New("refused by slashing protection")
+
+type signedProposal struct {
+	Height       uint64        `json:"height"`
+	Round        uint64        `json:"round"`
+	ProposalHash hexutil.Bytes `json:"proposalHash"`
+}
+
+// protectionRecords are the proposals signed by a key in a shard.
+type protectionRecords struct {
+	// MinHeight is the lowest height which can still be signed, the records below it are pruned.
+	MinHeight uint64            `json:"minHeight"`
+	Proposals []*signedProposal `json:"proposals"`
+}
+
+// SlashingProtection remembers the proposals signed by every key in every shard and refuses to sign
+// a different proposal at the same height and round. The records are persisted to a file
+// before the signature is returned, so the protection holds across restarts of the signer.
+type SlashingProtection struct {
+	path      string
+	retention uint64
+
+	mu   sync.Mutex
+	keys map[string]map[types.ShardId]*protectionRecords
+}
+
+// NewSlashingProtection loads the records from the file, it is created on the first signature.
+// If the path is empty, the records are kept in memory only.
+func NewSlashingProtection(path string, retention uint64) (*SlashingProtection, error) {
+	p := &SlashingProtection{
+		path:      path,
+		retention: retention,
+		keys:      make(map[string]map[types.ShardId]*protectionRecords),
+	}
+	if path == "" {
+		return p, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &p.keys); err != nil {
+		return nil, fmt.Errorf("failed to decode slashing protection records %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// CheckProposal records the proposal signed by the key in the shard.
+// It fails if another proposal was signed at the same height and round or the height is already pruned.
+func (p *SlashingProtection) CheckProposal(
+	pubKey []byte, shardId types.ShardId, height, round uint64, proposalHash []byte,
+) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := hexutil.Encode(pubKey)
+	shards, ok := p.keys[key]
+	if !ok {
+		shards = make(map[types.ShardId]*protectionRecords)
+		p.keys[key] = shards
+	}
+	records, ok := shards[shardId]
+	if !ok {
+		records = &protectionRecords{}
+	}
+
+	if height < records.MinHeight {
+		return fmt.Errorf("%w: height %d is below the lowest protected height %d in shard %d",
+			ErrSlashingProtection, height, records.MinHeight, shardId)
+	}
+
+	maxHeight := height
+	for _, proposal := range records.Proposals {
+		if proposal.Height == height && proposal.Round == round {
+			if bytes.Equal(proposal.ProposalHash, proposalHash) {
+				return nil
+			}
+			return fmt.Errorf(
+				"%w: proposal %x is already signed in shard %d at height %d and round %d, refusing to sign %x",
+				ErrSlashingProtection, []byte(proposal.ProposalHash), shardId, height, round, proposalHash)
+		}
+		maxHeight = max(maxHeight, proposal.Height)
+	}
+
+	updated := &protectionRecords{MinHeight: records.MinHeight}
+	if maxHeight >= p.retention {
+		updated.MinHeight = max(updated.MinHeight, maxHeight-p.retention+1)
+	}
+	for _, proposal := range records.Proposals {
+		if proposal.Height >= updated.MinHeight {
+			updated.Proposals = append(updated.Proposals, proposal)
+		}
+	}
+	updated.Proposals = append(updated.Proposals, &signedProposal{
+		Height:       height,
+		Round:        round,
+		ProposalHash: bytes.Clone(proposalHash),
+	})
+
+	shards[shardId] = updated
+	if err := p.persist(); err != nil {
+		if ok {
+			shards[shardId] = records
+		} else {
+			delete(shards, shardId)
+		}
+		return fmt.Errorf("failed to persist slashing protection records: %w", err)
+	}
+	return nil
+}
+
+func (p *SlashingProtection) persist() error {
+	if p.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(p.keys)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it, so that the records are never left partially written.
+	tmp, err := os.CreateTemp(filepath.Dir(p.path), filepath.Base(p.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p.path)
+}
@@ -0,0 +1,94 @@
+package remotesigner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlashingProtection(t *testing.T) {
+	t.Parallel()
+
+	pubKey := []byte{1, 2, 3}
+	shardId := types.ShardId(1)
+	hashA := []byte{0xa}
+	hashB := []byte{0xb}
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := NewSlashingProtection("", DefaultProtectionRetention)
+		require.NoError(t, err)
+
+		require.NoError(t, p.CheckProposal(pubKey, shardId, 10, 0, hashA))
+		require.NoError(t, p.CheckProposal(pubKey, shardId, 10, 0, hashA))
+		require.ErrorIs(t, p.CheckProposal(pubKey, shardId, 10, 0, hashB), ErrSlashingProtection)
+
+		// Other rounds, heights and keys are independent
+		require.NoError(t, p.CheckProposal(pubKey, shardId, 10, 1, hashB))
+		require.NoError(t, p.CheckProposal(pubKey, shardId, 11, 0, hashB))
+		require.NoError(t, p.CheckProposal([]byte{4, 5, 6}, shardId, 10, 0, hashB))
+	})
+
+	t.Run("Shards", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "protection.json")
+		p, err := NewSlashingProtection(path, 2)
+		require.NoError(t, err)
+
+		// The same key validates two shards at the same height
+		otherShardId := types.ShardId(2)
+		require.NoError(t, p.CheckProposal(pubKey, shardId, 10, 0, hashA))
+		require.NoError(t, p.CheckProposal(pubKey, otherShardId, 10, 0, hashB))
+		require.ErrorIs(t, p.CheckProposal(pubKey, shardId, 10, 0, hashB), ErrSlashingProtection)
+		require.ErrorIs(t, p.CheckProposal(pubKey, otherShardId, 10, 0, hashA), ErrSlashingProtection)
+
+		// The heights are pruned per shard
+		require.NoError(t, p.CheckProposal(pubKey, shardId, 20, 0, hashA))
+		require.NoError(t, p.CheckProposal(pubKey, otherShardId, 11, 0, hashA))
+
+		reloaded, err := NewSlashingProtection(path, 2)
+		require.NoError(t, err)
+		require.ErrorIs(t, reloaded.CheckProposal(pubKey, otherShardId, 10, 0, hashA), ErrSlashingProtection)
+		require.NoError(t, reloaded.CheckProposal(pubKey, otherShardId, 10, 0, hashB))
+		require.ErrorIs(t, reloaded.CheckProposal(pubKey, shardId, 10, 0, hashA), ErrSlashingProtection)
+	})
+
+	t.Run("Persistence", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "protection.json")
+		p, err := NewSlashingProtection(path, DefaultProtectionRetention)
+		require.NoError(t, err)
+		require.NoError(t, p.CheckProposal(pubKey, shardId, 10, 0, hashA))
+
+		reloaded, err := NewSlashingProtection(path, DefaultProtectionRetention)
+		require.NoError(t, err)
+		require.NoError(t, reloaded.CheckProposal(pubKey, shardId, 10, 0, hashA))
+		require.ErrorIs(t, reloaded.CheckProposal(pubKey, shardId, 10, 0, hashB), ErrSlashingProtection)
+	})
+
+	t.Run("Pruning", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := NewSlashingProtection("", 2)
+		require.NoError(t, err)
+
+		require.NoError(t, p.CheckProposal(pubKey, shardId, 1, 0, hashA))
+		require.NoError(t, p.CheckProposal(pubKey, shardId, 2, 0, hashA))
+		require.NoError(t, p.CheckProposal(pubKey, shardId, 3, 0, hashA))
+		require.Len(t, p.keys, 1)
+		for _, shards := range p.keys {
+			require.Len(t, shards, 1)
+			require.Len(t, shards[shardId].Proposals, 2)
+			require.Equal(t, uint64(2), shards[shardId].MinHeight)
+		}
+
+		// The pruned height can't be signed anymore
+		require.ErrorIs(t, p.CheckProposal(pubKey, shardId, 1, 0, hashB), ErrSlashingProtection)
+		require.ErrorIs(t, p.CheckProposal(pubKey, shardId, 3, 0, hashB), ErrSlashingProtection)
+	})
+}
@@ -0,0 +1,245 @@
+package remotesigner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+)
+
+// The handles of the signing protocol, modeled after Web3Signer. The keys are identified
+// by the hex-encoded BLS public keys and the secp256k1 addresses respectively.
+const (
+	upcheckPath           = "/upcheck"
+	blsPublicKeysPath     = "/api/v1/bls/publicKeys"
+	blsSignPath           = "/api/v1/bls/sign/"
+	secp256k1AddressPath  = "/api/v1/secp256k1/addresses"
+	secp256k1SignPath     = "/api/v1/secp256k1/sign/"
+	defaultRequestTimeout = 10 * time.Second
+)
+
+type signatureResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// transactionSigningRequest carries the unsigned transaction in the binary encoding without the blob sidecar.
+type transactionSigningRequest struct {
+	ChainId     *hexutil.Big  `json:"chainId"`
+	Transaction hexutil.Bytes `json:"transaction"`
+}
+
+type transactionResponse struct {
+	Transaction hexutil.Bytes `json:"transaction"`
+}
+
+// Server is the reference implementation of the remote signer.
+// The BLS signatures are checked by the slashing protection if it is set,
+// the transactions are checked by the transaction policy if it is set.
+type Server struct {
+	mux         *http.ServeMux
+	blsSigners  map[string]*LocalBlsSigner
+	ecdsaSigner map[ethcommon.Address]*LocalEcdsaSigner
+	transport   *TransportConfig
+	logger      zerolog.Logger
+}
+
+func NewServer(
+	blsKeys []bls.PrivateKey,
+	ecdsaKeys []*ecdsa.PrivateKey,
+	protection *SlashingProtection,
+	policy *TransactionPolicy,
+	transport *TransportConfig,
+	logger zerolog.Logger,
+) *Server {
+	s := &Server{
+		mux:         http.NewServeMux(),
+		blsSigners:  make(map[string]*LocalBlsSigner, len(blsKeys)),
+		ecdsaSigner: make(map[ethcommon.Address]*LocalEcdsaSigner, len(ecdsaKeys)),
+		transport:   transport,
+		logger:      logger,
+	}
+	for _, key := range blsKeys {
+		signer := NewLocalBlsSigner(key, protection)
+		s.blsSigners[hexutil.Encode(signer.PublicKey())] = signer
+	}
+	for _, key := range ecdsaKeys {
+		signer := NewLocalEcdsaSigner(key, policy)
+		s.ecdsaSigner[signer.Address()] = signer
+	}
+
+	s.mux.HandleFunc("GET "+upcheckPath, s.upcheck)
+	s.mux.HandleFunc("GET "+blsPublicKeysPath, s.blsPublicKeys)
+	s.mux.HandleFunc("POST "+blsSignPath+"{key}", s.blsSign)
+	s.mux.HandleFunc("GET "+secp256k1AddressPath, s.secp256k1Addresses)
+	s.mux.HandleFunc("POST "+secp256k1SignPath+"{address}", s.secp256k1Sign)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Serve listens on the endpoint until the context is done.
+// The endpoint is either "unix:///path/to/socket" or "tcp://host:port".
+// Serving over TCP requires TLS and the auth token in the transport config.
+func (s *Server) Serve(ctx context.Context, endpoint string) error {
+	token, err := s.transport.authToken()
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := s.transport.serverTLS()
+	if err != nil {
+		return err
+	}
+
+	var listener net.Listener
+	if socketPath, ok := strings.CutPrefix(endpoint, "unix://"); ok {
+		// Remove the socket left by a previous run, otherwise listening fails.
+		if err := os.Remove(socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if listener, err = net.Listen("unix", socketPath); err != nil {
+			return err
+		}
+		// Only the user running the signer may connect to the socket
+		if err := os.Chmod(socketPath, 0o600); err != nil {
+			listener.Close()
+			return err
+		}
+	} else {
+		if tlsConfig == nil || token == "" {
+			return fmt.Errorf("%w: serving over TCP requires a TLS certificate and an auth token", ErrInsecureTransport)
+		}
+		if listener, err = net.Listen("tcp", strings.TrimPrefix(endpoint, "tcp://")); err != nil {
+			return err
+		}
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	srv := http.Server{Handler: withAuth(token, s), ReadHeaderTimeout: defaultRequestTimeout}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second) //nolint:contextcheck
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx) //nolint:contextcheck
+	}()
+
+	s.logger.Info().Msgf("Serving remote signer at `%s`", endpoint)
+	if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) upcheck(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte("OK"))
+}
+
+func (s *Server) blsPublicKeys(w http.ResponseWriter, _ *http.Request) {
+	keys := make([]string, 0, len(s.blsSigners))
+	for key := range s.blsSigners {
+		keys = append(keys, key)
+	}
+	s.writeJSON(w, keys)
+}
+
+func (s *Server) blsSign(w http.ResponseWriter, r *http.Request) {
+	signer, ok := s.blsSigners[strings.ToLower(r.PathValue("key"))]
+	if !ok {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("unknown public key %s", r.PathValue("key")))
+		return
+	}
+
+	var req BlsSigningRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sig, err := signer.SignBls(r.Context(), &req)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("kind", string(req.Kind)).Msg("Refused to sign")
+		s.writeError(w, statusOf(err), err)
+		return
+	}
+	s.writeJSON(w, signatureResponse{Signature: hexutil.Bytes(sig)})
+}
+
+func (s *Server) secp256k1Addresses(w http.ResponseWriter, _ *http.Request) {
+	addresses := make([]ethcommon.Address, 0, len(s.ecdsaSigner))
+	for address := range s.ecdsaSigner {
+		addresses = append(addresses, address)
+	}
+	s.writeJSON(w, addresses)
+}
+
+func (s *Server) secp256k1Sign(w http.ResponseWriter, r *http.Request) {
+	signer, ok := s.ecdsaSigner[ethcommon.HexToAddress(r.PathValue("address"))]
+	if !ok {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("unknown address %s", r.PathValue("address")))
+		return
+	}
+
+	var req transactionSigningRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	txn := &ethtypes.Transaction{}
+	if err := txn.UnmarshalBinary(req.Transaction); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("%w: failed to decode transaction: %w", errInvalidRequest, err))
+		return
+	}
+
+	signed, err := signer.SignTransaction(r.Context(), txn, req.ChainId.ToInt())
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Refused to sign")
+		s.writeError(w, statusOf(err), err)
+		return
+	}
+	data, err := signed.MarshalBinary()
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, transactionResponse{Transaction: data})
+}
+
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrSlashingProtection):
+		// The same status is used by Web3Signer for the slashing protection refusals.
+		return http.StatusPreconditionFailed
+	case errors.Is(err, ErrTransactionPolicy):
+		return http.StatusForbidden
+	case errors.Is(err, errInvalidRequest):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to write response")
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, code int, err error) {
+	w.WriteHeader(code)
+	_, _ = fmt.Fprintf(w, "error: %s", err.Error())
+}
@@ -0,0 +1,292 @@
+package remotesigner
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	protoIBFT "github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func prepareMessage(t *testing.T, height, round uint64, proposalHash []byte) []byte {
+	t.Helper()
+
+	data, err := proto.Marshal(&protoIBFT.IbftMessage{
+		View: &protoIBFT.View{Height: height, Round: round},
+		Type: protoIBFT.MessageType_PREPARE,
+		Payload: &protoIBFT.IbftMessage_PrepareData{
+			PrepareData: &protoIBFT.PrepareMessage{ProposalHash: proposalHash},
+		},
+	})
+	require.NoError(t, err)
+	return data
+}
+
+func TestRemoteSigner(t *testing.T) {
+	t.Parallel()
+
+	blsKey := bls.NewRandomKey()
+	ecdsaKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	protection, err := NewSlashingProtection("", DefaultProtectionRetention)
+	require.NoError(t, err)
+	chainID := big.NewInt(11155111)
+	rollupAddress := ethcommon.HexToAddress("0x5678")
+	policy := &TransactionPolicy{ChainID: chainID, Destinations: []ethcommon.Address{rollupAddress}}
+	server := NewServer([]bls.PrivateKey{blsKey}, []*ecdsa.PrivateKey{ecdsaKey}, protection, policy, nil,
+		logging.NewLogger("remote_signer_test"))
+
+	// Keep the socket path short, it is limited to 108 bytes
+	dir, err := os.MkdirTemp("", "signer")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	endpoint := "unix://" + filepath.Join(dir, "signer.sock")
+
+	ctx := t.Context()
+	go func() {
+		if err := server.Serve(ctx, endpoint); err != nil {
+			t.Errorf("failed to serve: %s", err)
+		}
+	}()
+
+	client, err := NewClient(endpoint, nil)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return client.Upcheck(ctx) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	t.Run("Bls", func(t *testing.T) {
+		t.Parallel()
+
+		signer, err := client.BlsSigner(ctx, nil)
+		require.NoError(t, err)
+		publicKey, err := blsKey.PublicKey().Marshal()
+		require.NoError(t, err)
+		require.Equal(t, publicKey, signer.PublicKey())
+
+		hashA := common.HexToHash("0x0a").Bytes()
+		hashB := common.HexToHash("0x0b").Bytes()
+
+		data := prepareMessage(t, 5, 0, hashA)
+		sig, err := signer.SignBls(ctx, &BlsSigningRequest{
+			Kind: BlsSigningMessage, ShardId: 1, Data: data, MessageDomain: true,
+		})
+		require.NoError(t, err)
+		signature, err := bls.SignatureFromBytes(sig)
+		require.NoError(t, err)
+		require.NoError(t, signature.Verify(blsKey.PublicKey(), MessageSigningData(data, true)))
+		require.Error(t, signature.Verify(blsKey.PublicKey(), common.PoseidonHash(data).Bytes()))
+
+		// Before the fork activation the hash of the message is signed without the domain
+		legacy := prepareMessage(t, 4, 0, hashA)
+		sig, err = signer.SignBls(ctx, &BlsSigningRequest{Kind: BlsSigningMessage, ShardId: 1, Data: legacy})
+		require.NoError(t, err)
+		signature, err = bls.SignatureFromBytes(sig)
+		require.NoError(t, err)
+		require.NoError(t, signature.Verify(blsKey.PublicKey(), common.PoseidonHash(legacy).Bytes()))
+		require.Equal(t, common.PoseidonHash(legacy).Bytes(), MessageSigningData(legacy, false))
+
+		seal := &BlsSigningRequest{Kind: BlsSigningSeal, ShardId: 1, Height: 5, Round: 0, Data: hashA}
+		sig, err = signer.SignBls(ctx, seal)
+		require.NoError(t, err)
+		signature, err = bls.SignatureFromBytes(sig)
+		require.NoError(t, err)
+		require.NoError(t, signature.Verify(blsKey.PublicKey(), hashA))
+
+		// A different proposal at the same height and round is refused
+		_, err = signer.SignBls(ctx, &BlsSigningRequest{
+			Kind: BlsSigningSeal, ShardId: 1, Height: 5, Round: 0, Data: hashB,
+		})
+		require.ErrorIs(t, err, ErrSlashingProtection)
+		conflicting := prepareMessage(t, 5, 0, hashB)
+		_, err = signer.SignBls(ctx, &BlsSigningRequest{
+			Kind: BlsSigningMessage, ShardId: 1, Data: conflicting, MessageDomain: true,
+		})
+		require.ErrorIs(t, err, ErrSlashingProtection)
+
+		// The conflicting message can't be signed as a seal at an unused height once the domain is used
+		for _, data := range [][]byte{MessageSigningData(conflicting, true), common.PoseidonHash(conflicting).Bytes()} {
+			sig, err = signer.SignBls(ctx, &BlsSigningRequest{
+				Kind: BlsSigningSeal, ShardId: 1, Height: 100, Round: 0, Data: data,
+			})
+			if err != nil {
+				require.ErrorContains(t, err, "not a proposal hash")
+				continue
+			}
+			signature, err = bls.SignatureFromBytes(sig)
+			require.NoError(t, err)
+			require.Error(t, signature.Verify(blsKey.PublicKey(), MessageSigningData(conflicting, true)))
+		}
+
+		// Another shard has its own heights
+		_, err = signer.SignBls(ctx, &BlsSigningRequest{
+			Kind: BlsSigningSeal, ShardId: 2, Height: 5, Round: 0, Data: hashB,
+		})
+		require.NoError(t, err)
+
+		_, err = signer.SignBls(ctx, &BlsSigningRequest{Kind: BlsSigningMessage, Data: []byte{0xff, 0xff}})
+		require.Error(t, err)
+
+		_, err = client.BlsSigner(ctx, []byte{1, 2, 3})
+		require.Error(t, err)
+	})
+
+	t.Run("Ecdsa", func(t *testing.T) {
+		t.Parallel()
+
+		signer, err := client.EcdsaSigner(ctx, ethcommon.Address{})
+		require.NoError(t, err)
+		address := crypto.PubkeyToAddress(ecdsaKey.PublicKey)
+		require.Equal(t, address, signer.Address())
+
+		txn := ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     1,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(2),
+			Gas:       21000,
+			To:        &rollupAddress,
+			Value:     big.NewInt(0),
+		})
+		signed, err := signer.SignTransaction(ctx, txn, chainID)
+		require.NoError(t, err)
+		from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(chainID), signed)
+		require.NoError(t, err)
+		require.Equal(t, address, from)
+		require.Equal(t, txn.Nonce(), signed.Nonce())
+
+		// The transactions to other contracts or chains are refused
+		otherAddress := ethcommon.HexToAddress("0x1234")
+		_, err = signer.SignTransaction(ctx, ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			ChainID: chainID,
+			To:      &otherAddress,
+		}), chainID)
+		require.ErrorIs(t, err, ErrTransactionPolicy)
+
+		otherChainID := big.NewInt(1)
+		_, err = signer.SignTransaction(ctx, ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			ChainID: otherChainID,
+			To:      &rollupAddress,
+		}), otherChainID)
+		require.ErrorIs(t, err, ErrTransactionPolicy)
+
+		// The chain ID of the transaction must match the requested one
+		_, err = signer.SignTransaction(ctx, ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			ChainID: otherChainID,
+			To:      &rollupAddress,
+		}), chainID)
+		require.Error(t, err)
+
+		_, err = client.EcdsaSigner(ctx, ethcommon.HexToAddress("0x1234"))
+		require.Error(t, err)
+	})
+}
+
+// writeCertificate writes a self-signed certificate of 127.0.0.1 and its key to the dir.
+func writeCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "nil_signer"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certFile, keyFile
+}
+
+func TestRemoteSignerTcp(t *testing.T) {
+	t.Parallel()
+
+	blsKey := bls.NewRandomKey()
+	logger := logging.NewLogger("remote_signer_test")
+	ctx := t.Context()
+
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir)
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+	wrongTokenFile := filepath.Join(dir, "wrong-token")
+	require.NoError(t, os.WriteFile(wrongTokenFile, []byte("wrong"), 0o600))
+
+	t.Run("Insecure", func(t *testing.T) {
+		t.Parallel()
+
+		server := NewServer([]bls.PrivateKey{blsKey}, nil, nil, nil, &TransportConfig{AuthTokenFile: tokenFile}, logger)
+		require.ErrorIs(t, server.Serve(ctx, "tcp://127.0.0.1:0"), ErrInsecureTransport)
+
+		_, err := NewClient("tcp://127.0.0.1:1234", nil)
+		require.ErrorIs(t, err, ErrInsecureTransport)
+		_, err = NewClient("http://127.0.0.1:1234", &TransportConfig{AuthTokenFile: tokenFile})
+		require.ErrorIs(t, err, ErrInsecureTransport)
+	})
+
+	t.Run("Tls", func(t *testing.T) {
+		t.Parallel()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		endpoint := "tcp://" + listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		server := NewServer([]bls.PrivateKey{blsKey}, nil, nil, nil, &TransportConfig{
+			TLSCertFile:   certFile,
+			TLSKeyFile:    keyFile,
+			AuthTokenFile: tokenFile,
+		}, logger)
+		go func() {
+			if err := server.Serve(ctx, endpoint); err != nil {
+				t.Errorf("failed to serve: %s", err)
+			}
+		}()
+
+		client, err := NewClient(endpoint, &TransportConfig{TLSCAFile: certFile, AuthTokenFile: tokenFile})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return client.Upcheck(ctx) == nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		_, err = client.BlsSigner(ctx, nil)
+		require.NoError(t, err)
+
+		unauthorized, err := NewClient(endpoint, &TransportConfig{TLSCAFile: certFile, AuthTokenFile: wrongTokenFile})
+		require.NoError(t, err)
+		_, err = unauthorized.BlsSigner(ctx, nil)
+		require.ErrorContains(t, err, "401")
+	})
+}
@@ -0,0 +1,230 @@
+package remotesigner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	protoIBFT "github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/protobuf/proto"
+)
+
+type BlsSigningKind string
+
+const (
+	// BlsSigningMessage is the signing of a serialized consensus message, see MessageSigningData.
+	BlsSigningMessage BlsSigningKind = "message"
+	// BlsSigningSeal is the signing of a committed seal, the data is the proposal hash which is signed as is.
+	BlsSigningSeal BlsSigningKind = "seal"
+)
+
+// messageDomain prefixes the hash of a consensus message in the signed data since params.ForkConsensusMessageDomain.
+// Seals sign bare proposal hashes, so a seal can't be requested for the data of a message and vice versa.
+var messageDomain = []byte("nil/ibft/message:")
+
+// MessageSigningData returns the data signed for the serialized consensus message.
+// Before the activation of params.ForkConsensusMessageDomain the hash of the message is signed without the domain.
+func MessageSigningData(data []byte, domain bool) []byte {
+	hash := common.PoseidonHash(data).Bytes()
+	if !domain {
+		return hash
+	}
+	return append(slices.Clone(messageDomain), hash...)
+}
+
+// BlsSigningRequest is the request to sign consensus data with the validator key.
+// The shard is set for all requests, since the same key validates several shards with independent heights.
+// The height and round are set for the seals only, for the messages they are taken from the message itself.
+// MessageDomain is set for the messages at the heights where params.ForkConsensusMessageDomain is active,
+// the requests of the nodes which don't know the fork are signed without the domain.
+type BlsSigningRequest struct {
+	Kind          BlsSigningKind `json:"kind"`
+	ShardId       types.ShardId  `json:"shardId"`
+	Height        uint64         `json:"height,omitempty"`
+	Round         uint64         `json:"round,omitempty"`
+	Data          hexutil.Bytes  `json:"data"`
+	MessageDomain bool           `json:"messageDomain,omitempty"`
+}
+
+// BlsSigner signs consensus data with the validator key, which can be held by a remote signer.
+type BlsSigner interface {
+	PublicKey() []byte
+	SignBls(ctx context.Context, req *BlsSigningRequest) (types.BlsSignature, error)
+}
+
+// EcdsaSigner signs L1 transactions with a secp256k1 key, which can be held by a remote signer.
+type EcdsaSigner interface {
+	Address() ethcommon.Address
+	// SignTransaction returns the transaction signed for the chain.
+	SignTransaction(ctx context.Context, txn *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error)
+}
+
+// TransactionPolicy restricts the transactions signed with the secp256k1 keys
+// to the chain and the contracts of the rollup.
+type TransactionPolicy struct {
+	ChainID      *big.Int
+	Destinations []ethcommon.Address
+}
+
+var (
+	errInvalidRequest = errors.New("invalid signing request")
+
+	ErrTransactionPolicy = errors.New("refused by transaction policy")
+)
+
+// check returns an error if the transaction is not allowed to be signed for the chain.
+func (p *TransactionPolicy) check(txn *ethtypes.Transaction, chainID *big.Int) error {
+	if chainID == nil || chainID.Sign() <= 0 {
+		return fmt.Errorf("%w: chain ID is not set", errInvalidRequest)
+	}
+	if txn.Type() != ethtypes.LegacyTxType && txn.ChainId().Cmp(chainID) != 0 {
+		return fmt.Errorf("%w: transaction chain ID %s differs from %s", errInvalidRequest, txn.ChainId(), chainID)
+	}
+	if p == nil {
+		return nil
+	}
+	if chainID.Cmp(p.ChainID) != 0 {
+		return fmt.Errorf("%w: chain ID %s is not allowed", ErrTransactionPolicy, chainID)
+	}
+	if txn.To() == nil || !slices.Contains(p.Destinations, *txn.To()) {
+		return fmt.Errorf("%w: destination %v is not allowed", ErrTransactionPolicy, txn.To())
+	}
+	return nil
+}
+
+// signedProposal returns the height, round and hash of the proposal attested by the signature.
+// Signatures which don't attest to any proposal, e.g. round changes, return ok == false.
+func (req *BlsSigningRequest) signedProposal() (height, round uint64, proposalHash []byte, ok bool, err error) {
+	switch req.Kind {
+	case BlsSigningSeal:
+		if err := req.checkSeal(); err != nil {
+			return 0, 0, nil, false, err
+		}
+		return req.Height, req.Round, req.Data, true, nil
+	case BlsSigningMessage:
+		msg := &protoIBFT.IbftMessage{}
+		if err := proto.Unmarshal(req.Data, msg); err != nil {
+			return 0, 0, nil, false, fmt.Errorf("%w: failed to decode message: %w", errInvalidRequest, err)
+		}
+		switch msg.GetType() {
+		case protoIBFT.MessageType_PREPREPARE:
+			proposalHash = msg.GetPreprepareData().GetProposalHash()
+		case protoIBFT.MessageType_PREPARE:
+			proposalHash = msg.GetPrepareData().GetProposalHash()
+		case protoIBFT.MessageType_COMMIT:
+			proposalHash = msg.GetCommitData().GetProposalHash()
+		default:
+			return 0, 0, nil, false, nil
+		}
+		return msg.GetView().GetHeight(), msg.GetView().GetRound(), proposalHash, true, nil
+	default:
+		return 0, 0, nil, false, fmt.Errorf("%w: unknown kind %q", errInvalidRequest, req.Kind)
+	}
+}
+
+// checkSeal returns an error if the seal data is not a proposal hash.
+func (req *BlsSigningRequest) checkSeal() error {
+	if len(req.Data) != common.HashSize {
+		return fmt.Errorf("%w: seal data is %d bytes, not a proposal hash", errInvalidRequest, len(req.Data))
+	}
+	return nil
+}
+
+// LocalBlsSigner signs with the key held in memory.
+type LocalBlsSigner struct {
+	key        bls.PrivateKey
+	publicKey  []byte
+	protection *SlashingProtection
+}
+
+var _ BlsSigner = (*LocalBlsSigner)(nil)
+
+// NewLocalBlsSigner returns a signer with the key. If the protection is nil, all requests are signed.
+func NewLocalBlsSigner(key bls.PrivateKey, protection *SlashingProtection) *LocalBlsSigner {
+	publicKey, err := key.PublicKey().Marshal()
+	check.PanicIfErr(err)
+	return &LocalBlsSigner{
+		key:        key,
+		publicKey:  publicKey,
+		protection: protection,
+	}
+}
+
+func (s *LocalBlsSigner) PublicKey() []byte {
+	return s.publicKey
+}
+
+func (s *LocalBlsSigner) SignBls(_ context.Context, req *BlsSigningRequest) (types.BlsSignature, error) {
+	if s.protection != nil {
+		height, round, proposalHash, ok, err := req.signedProposal()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if err := s.protection.CheckProposal(s.publicKey, req.ShardId, height, round, proposalHash); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var data []byte
+	switch req.Kind {
+	case BlsSigningMessage:
+		data = MessageSigningData(req.Data, req.MessageDomain)
+	case BlsSigningSeal:
+		if err := req.checkSeal(); err != nil {
+			return nil, err
+		}
+		data = req.Data
+	default:
+		return nil, fmt.Errorf("%w: unknown kind %q", errInvalidRequest, req.Kind)
+	}
+
+	sig, err := s.key.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Marshal()
+}
+
+// LocalEcdsaSigner signs with the key held in memory.
+type LocalEcdsaSigner struct {
+	key     *ecdsa.PrivateKey
+	address ethcommon.Address
+	policy  *TransactionPolicy
+}
+
+var _ EcdsaSigner = (*LocalEcdsaSigner)(nil)
+
+// NewLocalEcdsaSigner returns a signer with the key. If the policy is nil, transactions to any destination are signed.
+func NewLocalEcdsaSigner(key *ecdsa.PrivateKey, policy *TransactionPolicy) *LocalEcdsaSigner {
+	return &LocalEcdsaSigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+		policy:  policy,
+	}
+}
+
+func (s *LocalEcdsaSigner) Address() ethcommon.Address {
+	return s.address
+}
+
+func (s *LocalEcdsaSigner) SignTransaction(
+	_ context.Context, txn *ethtypes.Transaction, chainID *big.Int,
+) (*ethtypes.Transaction, error) {
+	if err := s.policy.check(txn, chainID); err != nil {
+		return nil, err
+	}
+	return ethtypes.SignTx(txn, ethtypes.LatestSignerForChainID(chainID), s.key)
+}
@@ -0,0 +1,88 @@
+package remotesigner
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var ErrInsecureTransport = errors.New("insecure transport")
+
+// TransportConfig secures the TCP transport of the signer. The requests over TCP are served over TLS
+// and authenticated with the bearer token. The unix socket is protected by the file permissions,
+// the token is checked there as well if it is set.
+type TransportConfig struct {
+	// TLSCertFile and TLSKeyFile are the certificate and the key of the server.
+	TLSCertFile string `yaml:"tlsCertFile,omitempty"`
+	TLSKeyFile  string `yaml:"tlsKeyFile,omitempty"`
+	// TLSCAFile is the certificate authority the client verifies the server with, the system pool is used if empty.
+	TLSCAFile string `yaml:"tlsCaFile,omitempty"`
+	// AuthTokenFile is the file with the bearer token the clients are authenticated with.
+	AuthTokenFile string `yaml:"authTokenFile,omitempty"`
+}
+
+func (c *TransportConfig) authToken() (string, error) {
+	if c == nil || c.AuthTokenFile == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(c.AuthTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the auth token: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("the auth token file %s is empty", c.AuthTokenFile)
+	}
+	return token, nil
+}
+
+func (c *TransportConfig) serverTLS() (*tls.Config, error) {
+	if c == nil || c.TLSCertFile == "" || c.TLSKeyFile == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS certificate: %w", err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+func (c *TransportConfig) clientTLS() (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS13}
+	if c == nil || c.TLSCAFile == "" {
+		return config, nil
+	}
+	data, err := os.ReadFile(c.TLSCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the TLS certificate authority: %w", err)
+	}
+	config.RootCAs = x509.NewCertPool()
+	if !config.RootCAs.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", c.TLSCAFile)
+	}
+	return config, nil
+}
+
+// withAuth rejects the requests without the bearer token. The upcheck is available to everyone.
+func withAuth(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != upcheckPath &&
+			subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "error: unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
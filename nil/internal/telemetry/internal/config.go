@@ -4,4 +4,8 @@ type Config struct {
 	ServiceName string `yaml:"serviceName,omitempty"`
 
 	ExportMetrics bool `yaml:"exportMetrics,omitempty"`
+
+	// PrometheusEndpoint is the address to serve metrics for scraping at the /metrics path.
+	// The endpoint is not started if the value is empty.
+	PrometheusEndpoint string `yaml:"prometheusEndpoint,omitempty"`
 }
@@ -12,27 +12,53 @@ import (
 
 const metricExportInterval = 10 * time.Second
 
+var metricsServer *prometheusServer
+
 func InitMetrics(ctx context.Context, config *Config) error {
-	if config == nil || !config.ExportMetrics {
+	if config == nil || (!config.ExportMetrics && config.PrometheusEndpoint == "") {
 		// no metrics
 		return nil
 	}
 
-	exporter, err := newMetricGrpcExporter(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to initialize exporter: %w", err)
+	var readers []sdkmetric.Reader
+	if config.ExportMetrics {
+		exporter, err := newMetricGrpcExporter(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to initialize exporter: %w", err)
+		}
+		readers = append(readers, sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(metricExportInterval)))
 	}
 
-	mp, err := newMeterProvider(exporter, config)
+	var server *prometheusServer
+	if config.PrometheusEndpoint != "" {
+		reader, srv, err := newPrometheusReader(config.PrometheusEndpoint)
+		if err != nil {
+			return fmt.Errorf("failed to initialize prometheus endpoint: %w", err)
+		}
+		readers = append(readers, reader)
+		server = srv
+	}
+
+	mp, err := newMeterProvider(readers, config)
 	if err != nil {
 		return fmt.Errorf("failed to initialize metric provider: %w", err)
 	}
 
 	otel.SetMeterProvider(mp)
+
+	if server != nil {
+		server.Start()
+		metricsServer = server
+	}
 	return nil
 }
 
 func ShutdownMetrics(ctx context.Context) {
+	if metricsServer != nil {
+		metricsServer.Shutdown(context.WithoutCancel(ctx))
+		metricsServer = nil
+	}
+
 	mp, ok := otel.GetMeterProvider().(*sdkmetric.MeterProvider)
 	if !ok {
 		// mb metrics were not initialized
@@ -46,15 +72,15 @@ func newMetricGrpcExporter(ctx context.Context) (sdkmetric.Exporter, error) {
 	return otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithInsecure())
 }
 
-func newMeterProvider(exporter sdkmetric.Exporter, config *Config) (*sdkmetric.MeterProvider, error) {
+func newMeterProvider(readers []sdkmetric.Reader, config *Config) (*sdkmetric.MeterProvider, error) {
 	res, err := NewResource(config)
 	if err != nil {
 		return nil, err
 	}
 
-	return sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter,
-			sdkmetric.WithInterval(metricExportInterval))),
-		sdkmetric.WithResource(res),
-	), nil
+	options := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	for _, reader := range readers {
+		options = append(options, sdkmetric.WithReader(reader))
+	}
+	return sdkmetric.NewMeterProvider(options...), nil
 }
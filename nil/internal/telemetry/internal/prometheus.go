@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const (
+	MetricsPath = "/metrics"
+
+	readHeaderTimeout = 5 * time.Second
+)
+
+var logger = logging.NewLogger("telemetry")
+
+// prometheusServer serves metrics collected by the meter provider for scraping
+type prometheusServer struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// newPrometheusReader creates a reader collecting metrics on scrape requests
+// along with the server exposing them at the given endpoint.
+func newPrometheusReader(endpoint string) (sdkmetric.Reader, *prometheusServer, error) {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collectors.NewGoCollector()); err != nil {
+		return nil, nil, err
+	}
+	if err := registry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		return nil, nil, err
+	}
+
+	reader, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to listen on %s: %w", endpoint, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return reader, &prometheusServer{
+		server:   &http.Server{Handler: mux, ReadHeaderTimeout: readHeaderTimeout},
+		listener: listener,
+	}, nil
+}
+
+func (s *prometheusServer) Start() {
+	logger.Info().Msgf("Serving metrics at http://%s%s", s.listener.Addr(), MetricsPath)
+	go func() {
+		if err := s.server.Serve(s.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error().Err(err).Msg("Metrics server failed")
+		}
+	}()
+}
+
+func (s *prometheusServer) Shutdown(ctx context.Context) {
+	// nothing to do with the error
+	_ = s.server.Shutdown(ctx)
+}
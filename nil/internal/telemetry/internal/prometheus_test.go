@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestPrometheusEndpoint(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	reader, server, err := newPrometheusReader("127.0.0.1:0")
+	require.NoError(t, err)
+	server.Start()
+	defer server.Shutdown(ctx)
+
+	mp, err := newMeterProvider([]sdkmetric.Reader{reader}, &Config{ServiceName: "test"})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, mp.Shutdown(ctx)) }()
+
+	counter, err := mp.Meter("test").Int64Counter("test_requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 3)
+
+	resp, err := http.Get("http://" + server.listener.Addr().String() + MetricsPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "test_requests_total")
+	require.Contains(t, string(body), "go_goroutines", "runtime metrics are collected as well")
+}
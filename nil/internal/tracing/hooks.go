@@ -255,6 +255,10 @@ const (
 
 	// BalanceResponseTransactionSupplement is decreased when there is not enough gas for response transaction.
 	BalanceResponseTransactionSupplement BalanceChangeReason = 18
+
+	// BalanceDecreaseVerifyPaymaster is decreased when the paymaster approves external transaction
+	// via verifyPaymaster contract call.
+	BalanceDecreaseVerifyPaymaster BalanceChangeReason = 19
 )
 
 // generate fmt.Stringer implementation for BalanceChangeReason
@@ -298,6 +302,8 @@ func (bcr BalanceChangeReason) String() string {
 		return "BalanceDecreaseVerifyExternal"
 	case BalanceResponseTransactionSupplement:
 		return "BalanceResponseTransactionSupplement"
+	case BalanceDecreaseVerifyPaymaster:
+		return "BalanceDecreaseVerifyPaymaster"
 	default:
 		return fmt.Sprintf("Unknown BalanceChangeReason: %d", bcr)
 	}
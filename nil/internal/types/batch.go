@@ -0,0 +1,55 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BatchCall is a call of a batched external transaction.
+type BatchCall struct {
+	Data Code `json:"data" ssz-max:"24576"`
+}
+
+// BatchPayload is the data of a batched external transaction: the calls to the recipient which are executed
+// in order after a single verification of the transaction.
+// If the batch is atomic, the failure of any call reverts the whole transaction. Otherwise, the failed calls
+// are reverted alone and the rest are executed.
+type BatchPayload struct {
+	Atomic bool         `json:"atomic"`
+	Calls  []*BatchCall `json:"calls" ssz-max:"64"`
+}
+
+// BatchCallReceipt is the result of a call of a batched external transaction.
+// It's linked to the receipt of the transaction by its index in the batch.
+type BatchCallReceipt struct {
+	Success  bool      `json:"success"`
+	Status   ErrorCode `json:"status"`
+	GasUsed  Gas       `json:"gasUsed"`
+	FailedPc uint32    `json:"failedPc"`
+}
+
+func NewBatchPayload(atomic bool, calls ...Code) *BatchPayload {
+	p := &BatchPayload{Atomic: atomic, Calls: make([]*BatchCall, len(calls))}
+	for i, data := range calls {
+		p.Calls[i] = &BatchCall{Data: data}
+	}
+	return p
+}
+
+// ParseBatchPayload decodes the data of a batched transaction. The batch must have at least one call.
+func ParseBatchPayload(data []byte) (*BatchPayload, error) {
+	p := &BatchPayload{}
+	if err := p.UnmarshalSSZ(data); err != nil {
+		return nil, fmt.Errorf("invalid batch payload: %w", err)
+	}
+	if len(p.Calls) == 0 {
+		return nil, errors.New("batch has no calls")
+	}
+	return p, nil
+}
+
+func (p *BatchPayload) Bytes() (Code, error) {
+	return p.MarshalSSZ()
+}
+
+//go:generate go run github.com/NilFoundation/fastssz/sszgen --path batch.go -include ../../common/length.go,code.go,gas.go,exec_errors.go --objs BatchCall,BatchPayload,BatchCallReceipt
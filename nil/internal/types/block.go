@@ -48,6 +48,9 @@ type BlockData struct {
 	BaseFee             Value            `json:"gasPrice" ch:"gas_price"`
 	GasUsed             Gas              `json:"gasUsed" ch:"gas_used"`
 	L1BlockNumber       uint64           `json:"l1BlockNumber" ch:"l1_block_number"`
+	// ScheduledCallsRoot is the root of the trie of calls scheduled by the contracts of the shard.
+	// It's encoded only if it's set, see blockDataV1.
+	ScheduledCallsRoot common.Hash `json:"scheduledCallsRoot" ch:"scheduled_calls_root" ssz:"-"`
 }
 
 type ConsensusParams struct {
@@ -90,6 +93,126 @@ var (
 	_ fastssz.Unmarshaler = new(Block)
 )
 
+// blockDataV0 is the layout of the blocks without scheduled calls.
+type blockDataV0 struct {
+	BlockData
+}
+
+// blockDataV1 is the layout of the blocks with scheduled calls.
+type blockDataV1 struct {
+	BlockData
+	ScheduledCallsRoot common.Hash
+}
+
+func (b *BlockData) layout() sszLayout {
+	if b.ScheduledCallsRoot.Empty() {
+		return &blockDataV0{BlockData: *b}
+	}
+	return &blockDataV1{BlockData: *b, ScheduledCallsRoot: b.ScheduledCallsRoot}
+}
+
+func (b *BlockData) MarshalSSZ() ([]byte, error) {
+	return fastssz.MarshalSSZ(b)
+}
+
+func (b *BlockData) MarshalSSZTo(buf []byte) ([]byte, error) {
+	return b.layout().MarshalSSZTo(buf)
+}
+
+func (b *BlockData) SizeSSZ() int {
+	return b.layout().SizeSSZ()
+}
+
+func (b *BlockData) UnmarshalSSZ(buf []byte) error {
+	var v0 blockDataV0
+	var v1 blockDataV1
+	extended, err := unmarshalLayout(buf, &v1, &v0)
+	if err != nil {
+		return err
+	}
+	if extended {
+		*b = v1.BlockData
+		b.ScheduledCallsRoot = v1.ScheduledCallsRoot
+	} else {
+		*b = v0.BlockData
+	}
+	return nil
+}
+
+func (b *BlockData) HashTreeRoot() ([32]byte, error) {
+	return fastssz.HashWithDefaultHasher(b)
+}
+
+func (b *BlockData) HashTreeRootWith(hh fastssz.HashWalker) error {
+	return b.layout().HashTreeRootWith(hh)
+}
+
+func (b *BlockData) GetTree() (*fastssz.Node, error) {
+	return fastssz.ProofTree(b)
+}
+
+// blockV0 is the layout of the blocks without scheduled calls.
+type blockV0 struct {
+	Block
+}
+
+// blockV1 is the layout of the blocks with scheduled calls.
+type blockV1 struct {
+	Block
+	ScheduledCallsRoot common.Hash
+}
+
+func (b *Block) layout() sszLayout {
+	// The generated codec initializes the signature on the copy of the block in SizeSSZ, but not in MarshalSSZTo
+	if b.Signature == nil {
+		b.Signature = new(BlsAggregateSignature)
+	}
+	if b.ScheduledCallsRoot.Empty() {
+		return &blockV0{Block: *b}
+	}
+	return &blockV1{Block: *b, ScheduledCallsRoot: b.ScheduledCallsRoot}
+}
+
+func (b *Block) MarshalSSZ() ([]byte, error) {
+	return fastssz.MarshalSSZ(b)
+}
+
+func (b *Block) MarshalSSZTo(buf []byte) ([]byte, error) {
+	return b.layout().MarshalSSZTo(buf)
+}
+
+func (b *Block) SizeSSZ() int {
+	return b.layout().SizeSSZ()
+}
+
+func (b *Block) UnmarshalSSZ(buf []byte) error {
+	var v0 blockV0
+	var v1 blockV1
+	extended, err := unmarshalLayout(buf, &v1, &v0)
+	if err != nil {
+		return err
+	}
+	if extended {
+		*b = v1.Block
+		b.ScheduledCallsRoot = v1.ScheduledCallsRoot
+	} else {
+		*b = v0.Block
+	}
+	return nil
+}
+
+func (b *Block) HashTreeRoot() ([32]byte, error) {
+	return fastssz.HashWithDefaultHasher(b)
+}
+
+func (b *Block) HashTreeRootWith(hh fastssz.HashWalker) error {
+	return b.layout().HashTreeRootWith(hh)
+}
+
+func (b *Block) GetTree() (*fastssz.Node, error) {
+	return fastssz.ProofTree(b)
+}
+
 func (b *Block) Hash(shardId ShardId) common.Hash {
 	return ToShardedHash(common.MustPoseidonSSZ(&b.BlockData), shardId)
 }
@@ -177,4 +300,4 @@ func (b *Block) VerifySignature(pubkeys []bls.PublicKey, shardId ShardId) error
 
 const InvalidDbTimestamp uint64 = math.MaxUint64
 
-//go:generate go run github.com/NilFoundation/fastssz/sszgen --path block.go -include ../../common/hexutil/bytes.go,../../common/length.go,signature.go,address.go,code.go,shard.go,bloom.go,log.go,value.go,transaction.go,gas.go,../../common/hash.go --objs BlockData,Block
+//go:generate go run github.com/NilFoundation/fastssz/sszgen --path block.go -include ../../common/hexutil/bytes.go,../../common/length.go,signature.go,address.go,code.go,shard.go,bloom.go,log.go,value.go,transaction.go,gas.go,../../common/hash.go --objs blockDataV0,blockDataV1,blockV0,blockV1
@@ -182,6 +182,24 @@ const (
 	ErrorBaseFeeTooHigh
 	// ErrorMaxFeePerGasIsZero is returned when the MaxFeePerGas is zero. It is not allowed to have zero MaxFeePerGas.
 	ErrorMaxFeePerGasIsZero
+	// ErrorPaymasterDoesNotExist is returned when the paymaster of the external transaction does not exist.
+	ErrorPaymasterDoesNotExist
+	// ErrorPaymasterInOtherShard is returned when the paymaster is not in the shard of the transaction recipient.
+	ErrorPaymasterInOtherShard
+	// ErrorPaymasterVerificationFailed is returned when the paymaster doesn't approve the external transaction.
+	ErrorPaymasterVerificationFailed
+	// ErrorScheduledCallToOtherShard is returned when a call is scheduled to a contract in another shard.
+	ErrorScheduledCallToOtherShard
+	// ErrorScheduledCallNotFound is returned when the scheduled call doesn't exist or belongs to another contract.
+	ErrorScheduledCallNotFound
+	// ErrorScheduledCallNotDue is returned when the scheduled call is executed before its block or time.
+	ErrorScheduledCallNotDue
+	// ErrorAsyncCallTimeoutTooBig is returned when the deadline of an async request overflows.
+	ErrorAsyncCallTimeoutTooBig
+	// ErrorAsyncContextNotFound is returned when the response arrives after the request has timed out.
+	ErrorAsyncContextNotFound
+	// ErrorForkNotActive is returned when the transaction uses a feature of the fork which is not active yet.
+	ErrorForkNotActive
 )
 
 type ExecError interface {
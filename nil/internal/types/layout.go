@@ -0,0 +1,26 @@
+package types
+
+import ssz "github.com/NilFoundation/fastssz"
+
+// Fields added to the structs stored in the tries can't change the encoding of the existing records:
+// their hashes are part of the trie roots and the block hashes. Such fields are skipped by the generated
+// codec of the struct (`ssz:"-"`) and the struct is encoded by one of two layouts with generated codecs:
+// the legacy one if the new fields are empty and the extended one with the new fields appended otherwise.
+
+// sszLayout is the generated codec of a layout.
+type sszLayout interface {
+	ssz.Marshaler
+	ssz.Unmarshaler
+	ssz.HashRoot
+}
+
+// unmarshalLayout decodes the data by the extended layout and falls back to the legacy one.
+// Returns true if the data has the extended layout.
+// The extended layout is tried first: its decoder always rejects the legacy data, which has a smaller fixed part,
+// while the legacy decoder would ignore the appended fields of the extended data.
+func unmarshalLayout(buf []byte, extended, legacy ssz.Unmarshaler) (bool, error) {
+	if err := extended.UnmarshalSSZ(buf); err == nil {
+		return true, nil
+	}
+	return false, legacy.UnmarshalSSZ(buf)
+}
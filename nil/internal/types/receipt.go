@@ -1,6 +1,7 @@
 package types
 
 import (
+	ssz "github.com/NilFoundation/fastssz"
 	"github.com/NilFoundation/nil/nil/common"
 )
 
@@ -19,10 +20,78 @@ type Receipt struct {
 
 	TxnHash         common.Hash `json:"transactionHash"`
 	ContractAddress Address     `json:"contractAddress"`
+
+	// BatchReceipts holds the results of the calls of a batched transaction in the order of the calls.
+	// They're encoded only if they're set, see receiptV1.
+	BatchReceipts []*BatchCallReceipt `json:"batchReceipts,omitempty" ssz:"-"`
 }
 
 func (r *Receipt) Hash() common.Hash {
 	return ToShardedHash(common.MustPoseidonSSZ(r), ShardIdFromHash(r.TxnHash))
 }
 
-//go:generate go run github.com/NilFoundation/fastssz/sszgen --path receipt.go -include ../../common/hexutil/bytes.go,../../common/length.go,address.go,gas.go,value.go,block.go,bloom.go,log.go,transaction.go,exec_errors.go,../../common/hash.go,uint256.go --objs Receipt
+// Limits of the fields of Receipt, they must match its ssz tags.
+const (
+	ReceiptMaxLogsSize      = 1000
+	ReceiptMaxDebugLogsSize = 1000
+)
+
+// receiptV0 is the layout of the receipts of the transactions which are not batched.
+type receiptV0 struct {
+	Receipt
+}
+
+// receiptV1 is the layout of the receipts of the batched transactions.
+type receiptV1 struct {
+	Receipt
+	BatchReceipts []*BatchCallReceipt `ssz-max:"64"`
+}
+
+func (r *Receipt) layout() sszLayout {
+	if len(r.BatchReceipts) == 0 {
+		return &receiptV0{Receipt: *r}
+	}
+	return &receiptV1{Receipt: *r, BatchReceipts: r.BatchReceipts}
+}
+
+func (r *Receipt) MarshalSSZ() ([]byte, error) {
+	return ssz.MarshalSSZ(r)
+}
+
+func (r *Receipt) MarshalSSZTo(buf []byte) ([]byte, error) {
+	return r.layout().MarshalSSZTo(buf)
+}
+
+func (r *Receipt) SizeSSZ() int {
+	return r.layout().SizeSSZ()
+}
+
+func (r *Receipt) UnmarshalSSZ(buf []byte) error {
+	var v0 receiptV0
+	var v1 receiptV1
+	extended, err := unmarshalLayout(buf, &v1, &v0)
+	if err != nil {
+		return err
+	}
+	if extended {
+		*r = v1.Receipt
+		r.BatchReceipts = v1.BatchReceipts
+	} else {
+		*r = v0.Receipt
+	}
+	return nil
+}
+
+func (r *Receipt) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(r)
+}
+
+func (r *Receipt) HashTreeRootWith(hh ssz.HashWalker) error {
+	return r.layout().HashTreeRootWith(hh)
+}
+
+func (r *Receipt) GetTree() (*ssz.Node, error) {
+	return ssz.ProofTree(r)
+}
+
+//go:generate go run github.com/NilFoundation/fastssz/sszgen --path receipt.go -include ../../common/hexutil/bytes.go,../../common/length.go,address.go,gas.go,value.go,block.go,bloom.go,log.go,transaction.go,batch.go,exec_errors.go,../../common/hash.go,uint256.go --objs receiptV0,receiptV1
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+)
+
+// SelectorUsage is the gas used by the transactions calling a method of a contract.
+// The selector is empty for the deploys and the transactions without a call, e.g. transfers and responses.
+type SelectorUsage struct {
+	Selector     Code   `json:"selector" ssz-max:"4"`
+	Transactions uint64 `json:"transactions"`
+	GasUsed      Gas    `json:"gasUsed"`
+}
+
+// ContractResourceUsage is the gas used by the inbound transactions of a contract
+// and the storage slots created and deleted in the contract.
+type ContractResourceUsage struct {
+	Address      Address          `json:"address"`
+	Transactions uint64           `json:"transactions"`
+	GasUsed      Gas              `json:"gasUsed"`
+	SlotsCreated uint64           `json:"slotsCreated"`
+	SlotsDeleted uint64           `json:"slotsDeleted"`
+	Selectors    []*SelectorUsage `json:"selectors" ssz-max:"10000"`
+}
+
+// ResourceUsage is the usage of the resources of a shard by its contracts in a block or a range of blocks.
+// The contracts are sorted by the used gas in the descending order.
+type ResourceUsage struct {
+	Contracts []*ContractResourceUsage `json:"contracts" ssz-max:"100000"`
+}
+
+// Merge adds the usage of other blocks to the usage.
+func (u *ResourceUsage) Merge(other *ResourceUsage) {
+	contracts := make(map[Address]*ContractResourceUsage, len(u.Contracts))
+	for _, c := range u.Contracts {
+		contracts[c.Address] = c
+	}
+	for _, oc := range other.Contracts {
+		c, ok := contracts[oc.Address]
+		if !ok {
+			c = &ContractResourceUsage{Address: oc.Address}
+			contracts[oc.Address] = c
+			u.Contracts = append(u.Contracts, c)
+		}
+		c.Transactions += oc.Transactions
+		c.GasUsed += oc.GasUsed
+		c.SlotsCreated += oc.SlotsCreated
+		c.SlotsDeleted += oc.SlotsDeleted
+		for _, os := range oc.Selectors {
+			c.AddSelector(os.Selector, os.Transactions, os.GasUsed)
+		}
+	}
+	u.Sort()
+}
+
+// AddSelector adds the usage of the method with the given selector.
+func (c *ContractResourceUsage) AddSelector(selector Code, transactions uint64, gasUsed Gas) {
+	for _, s := range c.Selectors {
+		if bytes.Equal(s.Selector, selector) {
+			s.Transactions += transactions
+			s.GasUsed += gasUsed
+			return
+		}
+	}
+	c.Selectors = append(c.Selectors, &SelectorUsage{
+		Selector:     slices.Clone(selector),
+		Transactions: transactions,
+		GasUsed:      gasUsed,
+	})
+}
+
+// Sort orders the contracts and their selectors by the used gas in the descending order.
+// The ties are broken by the addresses and the selectors, so that the order is deterministic.
+func (u *ResourceUsage) Sort() {
+	for _, c := range u.Contracts {
+		slices.SortFunc(c.Selectors, func(a, b *SelectorUsage) int {
+			if r := cmp.Compare(b.GasUsed, a.GasUsed); r != 0 {
+				return r
+			}
+			return bytes.Compare(a.Selector, b.Selector)
+		})
+	}
+	slices.SortFunc(u.Contracts, func(a, b *ContractResourceUsage) int {
+		if r := cmp.Compare(b.GasUsed, a.GasUsed); r != 0 {
+			return r
+		}
+		return bytes.Compare(a.Address.Bytes(), b.Address.Bytes())
+	})
+}
+
+//go:generate go run github.com/NilFoundation/fastssz/sszgen --path resource_usage.go -include ../../common/length.go,address.go,code.go,gas.go --objs SelectorUsage,ContractResourceUsage,ResourceUsage
@@ -0,0 +1,31 @@
+package types
+
+// ScheduledCall is an internal transaction registered by a contract to be executed by the collator
+// in the first block which number and time are not less than the given ones.
+// MainShardBlockId bounds the height of the main shard seen by the block. It's used for the timeouts
+// of async requests, which are measured in the blocks of the main shard to be the same for all shards.
+// The key of the call in the trie of the shard is the hash of the transaction.
+type ScheduledCall struct {
+	Transaction      *Transaction `json:"transaction"`
+	BlockId          BlockNumber  `json:"blockId"`
+	Timestamp        uint64       `json:"timestamp"`
+	MainShardBlockId BlockNumber  `json:"mainShardBlockId"`
+}
+
+// IsDue reports whether the call can be executed in the block with the given number and time
+// which sees the given height of the main shard.
+func (c *ScheduledCall) IsDue(blockId BlockNumber, timestamp uint64, mainShardBlockId BlockNumber) bool {
+	return blockId >= c.BlockId && timestamp >= c.Timestamp && mainShardBlockId >= c.MainShardBlockId
+}
+
+// Owner returns the address which can cancel the call: the contract which has scheduled it, or the requester
+// for the failure response scheduled on the timeout of its request. The response is sent on behalf of the callee,
+// which must not be able to cancel it. The contracts can schedule only the execution transactions.
+func (c *ScheduledCall) Owner() Address {
+	if c.Transaction.IsResponse() {
+		return c.Transaction.To
+	}
+	return c.Transaction.From
+}
+
+//go:generate go run github.com/NilFoundation/fastssz/sszgen --path scheduled.go -include ../../common/length.go,address.go,gas.go,value.go,code.go,shard.go,bloom.go,log.go,../../common/hash.go,signature.go,account.go,bitflags.go,transaction.go,block.go --objs ScheduledCall
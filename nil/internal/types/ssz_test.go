@@ -42,6 +42,44 @@ func TestSszBlock(t *testing.T) {
 	require.Equal(t, common.BytesToHash(h2), common.BytesToHash(h[:]))
 }
 
+func TestSszBlockScheduledCallsRoot(t *testing.T) {
+	t.Parallel()
+
+	block := Block{
+		BlockData: BlockData{
+			Id:        1,
+			PrevBlock: common.Hash{0x01},
+		},
+	}
+	legacy, err := block.MarshalSSZ()
+	require.NoError(t, err)
+	legacyHash := block.Hash(BaseShardId)
+
+	// The root is appended to the legacy layout
+	block.ScheduledCallsRoot = common.Hash{0x02}
+	encoded, err := block.MarshalSSZ()
+	require.NoError(t, err)
+	require.Len(t, encoded, len(legacy)+common.HashSize)
+	require.NotEqual(t, legacyHash, block.Hash(BaseShardId))
+
+	var block2 Block
+	require.NoError(t, block2.UnmarshalSSZ(encoded))
+	require.Equal(t, block.ScheduledCallsRoot, block2.ScheduledCallsRoot)
+	require.Equal(t, block.PrevBlock, block2.PrevBlock)
+	require.Equal(t, block.Hash(BaseShardId), block2.Hash(BaseShardId))
+
+	require.NoError(t, block2.UnmarshalSSZ(legacy))
+	require.True(t, block2.ScheduledCallsRoot.Empty())
+	require.Equal(t, legacyHash, block2.Hash(BaseShardId))
+
+	data, err := block.BlockData.MarshalSSZ()
+	require.NoError(t, err)
+	var blockData BlockData
+	require.NoError(t, blockData.UnmarshalSSZ(data))
+	require.Equal(t, block.ScheduledCallsRoot, blockData.ScheduledCallsRoot)
+	require.Equal(t, block.PrevBlock, blockData.PrevBlock)
+}
+
 func TestSszTransaction(t *testing.T) {
 	t.Parallel()
 
@@ -76,6 +114,108 @@ func TestSszTransaction(t *testing.T) {
 	require.Equal(t, h2, h)
 }
 
+func TestSszTransactionPaymaster(t *testing.T) {
+	t.Parallel()
+
+	transaction := Transaction{
+		TransactionDigest: TransactionDigest{
+			Data:  Code{0x00000001},
+			Seqno: 567,
+		},
+		Value:     NewValueFromUint64(1234),
+		Signature: Signature{0x02},
+	}
+	legacy, err := transaction.MarshalSSZ()
+	require.NoError(t, err)
+
+	// The paymaster is appended to the legacy layout
+	transaction.Paymaster = HexToAddress("9405832983856CB0CF6CD570F071122F1BEA2F21")
+	encoded, err := transaction.MarshalSSZ()
+	require.NoError(t, err)
+	require.Len(t, encoded, len(legacy)+AddrSize)
+
+	var transaction2 Transaction
+	require.NoError(t, transaction2.UnmarshalSSZ(encoded))
+	require.Equal(t, transaction.Paymaster, transaction2.Paymaster)
+	require.Equal(t, transaction.Signature, transaction2.Signature)
+	require.Equal(t, transaction.Hash(), transaction2.Hash())
+
+	require.NoError(t, transaction2.UnmarshalSSZ(legacy))
+	require.True(t, transaction2.Paymaster.IsEmpty())
+	require.Equal(t, transaction.Signature, transaction2.Signature)
+
+	external := ExternalTransaction{
+		Data:      Code{0x00000001},
+		AuthData:  Signature{0x02},
+		Paymaster: transaction.Paymaster,
+	}
+	encoded, err = external.MarshalSSZ()
+	require.NoError(t, err)
+
+	var external2 ExternalTransaction
+	require.NoError(t, external2.UnmarshalSSZ(encoded))
+	require.Equal(t, external.Paymaster, external2.Paymaster)
+	require.Equal(t, external.AuthData, external2.AuthData)
+	require.Equal(t, external.Hash(), external2.Hash())
+}
+
+func TestSszReceiptBatchReceipts(t *testing.T) {
+	t.Parallel()
+
+	receipt := Receipt{
+		Success: true,
+		GasUsed: 100,
+		TxnHash: common.HexToHash("0x01"),
+	}
+	legacy, err := receipt.MarshalSSZ()
+	require.NoError(t, err)
+	legacyHash := receipt.Hash()
+
+	// The batch receipts are appended to the legacy layout
+	receipt.BatchReceipts = []*BatchCallReceipt{{Success: true, GasUsed: 40}, {Status: ErrorExecutionReverted}}
+	encoded, err := receipt.MarshalSSZ()
+	require.NoError(t, err)
+	require.Greater(t, len(encoded), len(legacy))
+	require.NotEqual(t, legacyHash, receipt.Hash())
+
+	var receipt2 Receipt
+	require.NoError(t, receipt2.UnmarshalSSZ(encoded))
+	require.Equal(t, receipt.BatchReceipts, receipt2.BatchReceipts)
+	require.Equal(t, receipt.Hash(), receipt2.Hash())
+
+	var receipt3 Receipt
+	require.NoError(t, receipt3.UnmarshalSSZ(legacy))
+	require.Empty(t, receipt3.BatchReceipts)
+	require.Equal(t, legacyHash, receipt3.Hash())
+}
+
+func TestSszAsyncContextTimeout(t *testing.T) {
+	t.Parallel()
+
+	asyncContext := AsyncContext{
+		IsAwait:               true,
+		Data:                  []byte{1, 2, 3},
+		ResponseProcessingGas: 100,
+	}
+	legacy, err := asyncContext.MarshalSSZ()
+	require.NoError(t, err)
+
+	// The timeout call id is appended to the legacy layout
+	asyncContext.TimeoutCallId = common.Hash{0x01}
+	encoded, err := asyncContext.MarshalSSZ()
+	require.NoError(t, err)
+	require.Len(t, encoded, len(legacy)+common.HashSize)
+
+	var asyncContext2 AsyncContext
+	require.NoError(t, asyncContext2.UnmarshalSSZ(encoded))
+	require.Equal(t, asyncContext, asyncContext2)
+
+	var asyncContext3 AsyncContext
+	require.NoError(t, asyncContext3.UnmarshalSSZ(legacy))
+	require.True(t, asyncContext3.TimeoutCallId.Empty())
+	require.Equal(t, asyncContext.Data, asyncContext3.Data)
+}
+
 func TestSszSmc(t *testing.T) {
 	t.Parallel()
 
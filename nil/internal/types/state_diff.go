@@ -0,0 +1,54 @@
+package types
+
+import (
+	"github.com/NilFoundation/nil/nil/common"
+)
+
+// StorageDiff is a storage slot changed by a block or a transaction.
+type StorageDiff struct {
+	Key    common.Hash `json:"key"`
+	Before common.Hash `json:"before"`
+	After  common.Hash `json:"after"`
+}
+
+// TokenDiff is a token balance changed by a block or a transaction.
+type TokenDiff struct {
+	Token  TokenId `json:"id" ssz-size:"20"`
+	Before Value   `json:"before" ssz-size:"32"`
+	After  Value   `json:"after" ssz-size:"32"`
+}
+
+// AccountStateDiff is the change of an account. Fields which weren't changed have equal before and after values;
+// storage slots and tokens which weren't changed are omitted.
+type AccountStateDiff struct {
+	Address Address `json:"address"`
+	// Created is true if the account didn't exist before.
+	Created bool `json:"created"`
+
+	BalanceBefore  Value       `json:"balanceBefore" ssz-size:"32"`
+	BalanceAfter   Value       `json:"balanceAfter" ssz-size:"32"`
+	SeqnoBefore    Seqno       `json:"seqnoBefore"`
+	SeqnoAfter     Seqno       `json:"seqnoAfter"`
+	ExtSeqnoBefore Seqno       `json:"extSeqnoBefore"`
+	ExtSeqnoAfter  Seqno       `json:"extSeqnoAfter"`
+	CodeHashBefore common.Hash `json:"codeHashBefore"`
+	CodeHashAfter  common.Hash `json:"codeHashAfter"`
+
+	Storage []StorageDiff `json:"storage,omitempty" ssz-max:"100000"`
+	Tokens  []TokenDiff   `json:"tokens,omitempty" ssz-max:"10000"`
+}
+
+// TransactionStateDiff is the change of the state made by an inbound transaction.
+type TransactionStateDiff struct {
+	TxnHash  common.Hash        `json:"txnHash"`
+	Accounts []AccountStateDiff `json:"accounts" ssz-max:"10000"`
+}
+
+// BlockStateDiff is the change of the shard state made by a block.
+// Accounts is the cumulative change, Transactions are the changes of the inbound transactions in the block order.
+type BlockStateDiff struct {
+	Accounts     []AccountStateDiff     `json:"accounts" ssz-max:"100000"`
+	Transactions []TransactionStateDiff `json:"transactions" ssz-max:"100000"`
+}
+
+//go:generate go run github.com/NilFoundation/fastssz/sszgen --path state_diff.go -include ../../common/length.go,address.go,value.go,transaction.go,account.go,uint256.go,../../common/hash.go --objs StorageDiff,TokenDiff,AccountStateDiff,TransactionStateDiff,BlockStateDiff
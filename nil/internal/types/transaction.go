@@ -20,6 +20,7 @@ const (
 	DeployTransactionKind
 	RefundTransactionKind
 	ResponseTransactionKind
+	BatchTransactionKind
 )
 
 func (k TransactionKind) String() string {
@@ -32,6 +33,8 @@ func (k TransactionKind) String() string {
 		return "RefundTransactionKind"
 	case ResponseTransactionKind:
 		return "ResponseTransactionKind"
+	case BatchTransactionKind:
+		return "BatchTransactionKind"
 	}
 	panic("unknown TransactionKind")
 }
@@ -46,6 +49,8 @@ func (k *TransactionKind) Set(input string) error {
 		*k = RefundTransactionKind
 	case "response", "ResponseTransactionKind":
 		*k = ResponseTransactionKind
+	case "batch", "BatchTransactionKind":
+		*k = BatchTransactionKind
 	default:
 		return fmt.Errorf("unknown TransactionKind: %s", input)
 	}
@@ -102,6 +107,8 @@ const (
 	TransactionFlagRefund
 	TransactionFlagBounce
 	TransactionFlagResponse
+	TransactionFlagBatch
+	TransactionFlagScheduled
 )
 
 type ForwardKind uint64
@@ -172,6 +179,10 @@ type Transaction struct {
 
 	// This field should always be at the end of the structure for easy signing
 	Signature Signature `json:"signature,omitempty" ch:"signature" ssz-max:"256"`
+
+	// Paymaster is the contract which pays the fees of the external transaction instead of its recipient.
+	// It's encoded only if it's set, see transactionV1.
+	Paymaster Address `json:"paymaster,omitempty" ch:"paymaster" ssz:"-"`
 }
 
 type OutboundTransaction struct {
@@ -189,6 +200,15 @@ type ExternalTransaction struct {
 	Seqno                Seqno           `json:"seqno,omitempty" ch:"seqno"`
 	Data                 Code            `json:"data,omitempty" ch:"data" ssz-max:"24576"`
 	AuthData             Signature       `json:"authData,omitempty" ch:"auth_data" ssz-max:"256"`
+	// Paymaster is encoded only if it's set, see externalTransactionV1.
+	Paymaster Address `json:"paymaster,omitempty" ch:"paymaster" ssz:"-"`
+}
+
+// SponsoredTransactionDigest is signed instead of TransactionDigest if the transaction names a paymaster,
+// so that the paymaster can't be replaced without the consent of the sender.
+type SponsoredTransactionDigest struct {
+	TransactionDigest
+	Paymaster Address `json:"paymaster" ch:"paymaster"`
 }
 
 type InternalTransactionPayload struct {
@@ -242,10 +262,193 @@ type AsyncResponsePayload struct {
 
 // AsyncContext contains context of the request. For await requests it contains VM state, which will be restored upon
 // the response. For callback requests it contains captured variables(not implemented yet).
+// If the request has a timeout, TimeoutCallId is the id of the scheduled failure response, which is cancelled
+// when the response arrives in time. It's encoded only if it's set, see asyncContextV1.
 type AsyncContext struct {
-	IsAwait               bool   `json:"isAwait"`
-	Data                  []byte `ssz-max:"10000000" json:"data"`
-	ResponseProcessingGas Gas    `json:"gas"`
+	IsAwait               bool        `json:"isAwait"`
+	Data                  []byte      `ssz-max:"10000000" json:"data"`
+	ResponseProcessingGas Gas         `json:"gas"`
+	TimeoutCallId         common.Hash `json:"timeoutCallId" ssz:"-"`
+}
+
+// asyncContextV0 is the layout of the contexts of the requests without a timeout.
+type asyncContextV0 struct {
+	AsyncContext
+}
+
+// asyncContextV1 is the layout of the contexts of the requests with a timeout.
+type asyncContextV1 struct {
+	AsyncContext
+	TimeoutCallId common.Hash
+}
+
+func (m *AsyncContext) layout() sszLayout {
+	if m.TimeoutCallId.Empty() {
+		return &asyncContextV0{AsyncContext: *m}
+	}
+	return &asyncContextV1{AsyncContext: *m, TimeoutCallId: m.TimeoutCallId}
+}
+
+func (m *AsyncContext) MarshalSSZ() ([]byte, error) {
+	return ssz.MarshalSSZ(m)
+}
+
+func (m *AsyncContext) MarshalSSZTo(buf []byte) ([]byte, error) {
+	return m.layout().MarshalSSZTo(buf)
+}
+
+func (m *AsyncContext) SizeSSZ() int {
+	return m.layout().SizeSSZ()
+}
+
+func (m *AsyncContext) UnmarshalSSZ(buf []byte) error {
+	var v0 asyncContextV0
+	var v1 asyncContextV1
+	extended, err := unmarshalLayout(buf, &v1, &v0)
+	if err != nil {
+		return err
+	}
+	if extended {
+		*m = v1.AsyncContext
+		m.TimeoutCallId = v1.TimeoutCallId
+	} else {
+		*m = v0.AsyncContext
+	}
+	return nil
+}
+
+func (m *AsyncContext) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(m)
+}
+
+func (m *AsyncContext) HashTreeRootWith(hh ssz.HashWalker) error {
+	return m.layout().HashTreeRootWith(hh)
+}
+
+func (m *AsyncContext) GetTree() (*ssz.Node, error) {
+	return ssz.ProofTree(m)
+}
+
+// Limits of the fields of Transaction, they must match its ssz tags.
+const (
+	TransactionMaxDataSize  = 24576
+	TransactionMaxTokenSize = 256
+)
+
+// transactionV0 is the layout of the transactions without a paymaster.
+type transactionV0 struct {
+	Transaction
+}
+
+// transactionV1 is the layout of the transactions with a paymaster.
+type transactionV1 struct {
+	Transaction
+	Paymaster Address
+}
+
+func (m *Transaction) layout() sszLayout {
+	if !m.HasPaymaster() {
+		return &transactionV0{Transaction: *m}
+	}
+	return &transactionV1{Transaction: *m, Paymaster: m.Paymaster}
+}
+
+func (m *Transaction) MarshalSSZ() ([]byte, error) {
+	return ssz.MarshalSSZ(m)
+}
+
+func (m *Transaction) MarshalSSZTo(buf []byte) ([]byte, error) {
+	return m.layout().MarshalSSZTo(buf)
+}
+
+func (m *Transaction) SizeSSZ() int {
+	return m.layout().SizeSSZ()
+}
+
+func (m *Transaction) UnmarshalSSZ(buf []byte) error {
+	var v0 transactionV0
+	var v1 transactionV1
+	extended, err := unmarshalLayout(buf, &v1, &v0)
+	if err != nil {
+		return err
+	}
+	if extended {
+		*m = v1.Transaction
+		m.Paymaster = v1.Paymaster
+	} else {
+		*m = v0.Transaction
+	}
+	return nil
+}
+
+func (m *Transaction) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(m)
+}
+
+func (m *Transaction) HashTreeRootWith(hh ssz.HashWalker) error {
+	return m.layout().HashTreeRootWith(hh)
+}
+
+func (m *Transaction) GetTree() (*ssz.Node, error) {
+	return ssz.ProofTree(m)
+}
+
+// externalTransactionV0 is the layout of the external transactions without a paymaster.
+type externalTransactionV0 struct {
+	ExternalTransaction
+}
+
+// externalTransactionV1 is the layout of the external transactions with a paymaster.
+type externalTransactionV1 struct {
+	ExternalTransaction
+	Paymaster Address
+}
+
+func (m *ExternalTransaction) layout() sszLayout {
+	if m.Paymaster.IsEmpty() {
+		return &externalTransactionV0{ExternalTransaction: *m}
+	}
+	return &externalTransactionV1{ExternalTransaction: *m, Paymaster: m.Paymaster}
+}
+
+func (m *ExternalTransaction) MarshalSSZ() ([]byte, error) {
+	return ssz.MarshalSSZ(m)
+}
+
+func (m *ExternalTransaction) MarshalSSZTo(buf []byte) ([]byte, error) {
+	return m.layout().MarshalSSZTo(buf)
+}
+
+func (m *ExternalTransaction) SizeSSZ() int {
+	return m.layout().SizeSSZ()
+}
+
+func (m *ExternalTransaction) UnmarshalSSZ(buf []byte) error {
+	var v0 externalTransactionV0
+	var v1 externalTransactionV1
+	extended, err := unmarshalLayout(buf, &v1, &v0)
+	if err != nil {
+		return err
+	}
+	if extended {
+		*m = v1.ExternalTransaction
+		m.Paymaster = v1.Paymaster
+	} else {
+		*m = v0.ExternalTransaction
+	}
+	return nil
+}
+
+func (m *ExternalTransaction) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(m)
+}
+
+func (m *ExternalTransaction) HashTreeRootWith(hh ssz.HashWalker) error {
+	return m.layout().HashTreeRootWith(hh)
+}
+
+func (m *ExternalTransaction) GetTree() (*ssz.Node, error) {
+	return ssz.ProofTree(m)
 }
 
 // interfaces
@@ -296,6 +499,8 @@ func (m *Transaction) toExternal() *ExternalTransaction {
 		kind = DeployTransactionKind
 	case m.IsRefund():
 		kind = RefundTransactionKind
+	case m.IsBatch():
+		kind = BatchTransactionKind
 	default:
 		kind = ExecutionTransactionKind
 	}
@@ -306,6 +511,7 @@ func (m *Transaction) toExternal() *ExternalTransaction {
 		ChainId:              m.ChainId,
 		Seqno:                m.Seqno,
 		Data:                 m.Data,
+		Paymaster:            m.Paymaster,
 		AuthData:             m.Signature,
 		MaxFeePerGas:         m.MaxFeePerGas,
 		MaxPriorityFeePerGas: m.MaxPriorityFeePerGas,
@@ -333,6 +539,13 @@ func (m *Transaction) VerifyFlags() error {
 	} else if m.IsRefund() || m.IsBounce() || m.IsRequestOrResponse() {
 		return errors.New("external transaction cannot be bounce, refund or async")
 	}
+	if m.IsBatch() && (m.IsInternal() || m.IsDeploy()) {
+		return errors.New("only external execution transaction can be batch")
+	}
+	// Scheduled responses are the failure responses to the timed out requests
+	if m.IsScheduled() && (!m.IsInternal() || m.IsDeploy() || m.IsRefund() || m.IsBounce() || m.IsRequest()) {
+		return errors.New("only internal execution transaction or response can be scheduled")
+	}
 	if m.To.ShardId().IsMainShard() && !m.From.ShardId().IsMainShard() {
 		return errors.New("transaction to main shard is not allowed from a regular shard")
 	}
@@ -363,6 +576,16 @@ func (m *Transaction) IsRefund() bool {
 	return m.Flags.IsRefund()
 }
 
+// IsScheduled returns true if the transaction executes a call scheduled by a contract.
+func (m *Transaction) IsScheduled() bool {
+	return m.Flags.IsScheduled()
+}
+
+// IsBatch returns true if the data of the transaction is a BatchPayload.
+func (m *Transaction) IsBatch() bool {
+	return m.Flags.IsBatch()
+}
+
 func (m *Transaction) IsResponse() bool {
 	return m.Flags.IsResponse()
 }
@@ -419,7 +642,7 @@ func (m *ExternalTransaction) Hash() common.Hash {
 }
 
 func (m *ExternalTransaction) SigningHash() (common.Hash, error) {
-	transactionDigest := TransactionDigest{
+	return signingHash(TransactionDigest{
 		Flags:                TransactionFlagsFromKind(false, m.Kind),
 		FeeCredit:            m.FeeCredit,
 		Seqno:                m.Seqno,
@@ -428,9 +651,14 @@ func (m *ExternalTransaction) SigningHash() (common.Hash, error) {
 		ChainId:              m.ChainId,
 		MaxPriorityFeePerGas: m.MaxPriorityFeePerGas,
 		MaxFeePerGas:         m.MaxFeePerGas,
-	}
+	}, m.Paymaster)
+}
 
-	return common.PoseidonSSZ(&transactionDigest)
+func signingHash(digest TransactionDigest, paymaster Address) (common.Hash, error) {
+	if paymaster.IsEmpty() {
+		return common.PoseidonSSZ(&digest)
+	}
+	return common.PoseidonSSZ(&SponsoredTransactionDigest{TransactionDigest: digest, Paymaster: paymaster})
 }
 
 func (m ExternalTransaction) ToTransaction() *Transaction {
@@ -446,12 +674,18 @@ func (m ExternalTransaction) ToTransaction() *Transaction {
 			MaxFeePerGas:         m.MaxFeePerGas,
 		},
 		From:      m.To,
+		Paymaster: m.Paymaster,
 		Signature: m.AuthData,
 	}
 }
 
 func (m *Transaction) SigningHash() (common.Hash, error) {
-	return common.PoseidonSSZ(&m.TransactionDigest)
+	return signingHash(m.TransactionDigest, m.Paymaster)
+}
+
+// HasPaymaster returns true if the fees of the transaction are paid by a paymaster.
+func (m *Transaction) HasPaymaster() bool {
+	return !m.Paymaster.IsEmpty()
 }
 
 func (m *ExternalTransaction) Sign(key *ecdsa.PrivateKey) error {
@@ -486,6 +720,12 @@ func TransactionFlagsFromKind(internal bool, kind TransactionKind) TransactionFl
 		flags = append(flags, TransactionFlagRefund)
 	case ResponseTransactionKind:
 		flags = append(flags, TransactionFlagResponse)
+	case BatchTransactionKind:
+		// Only external transactions are batched. The kind of internal transactions is set by contracts,
+		// it was ignored before the batches were introduced, so it doesn't change the transaction.
+		if !internal {
+			flags = append(flags, TransactionFlagBatch)
+		}
 	case ExecutionTransactionKind: // do nothing
 	}
 	return NewTransactionFlags(flags...)
@@ -510,6 +750,12 @@ func (m TransactionFlags) String() string {
 	if m.IsResponse() {
 		res += ", Response"
 	}
+	if m.IsBatch() {
+		res += ", Batch"
+	}
+	if m.IsScheduled() {
+		res += ", Scheduled"
+	}
 	return res
 }
 
@@ -532,6 +778,12 @@ func (m TransactionFlags) MarshalJSON() ([]byte, error) {
 	if m.IsResponse() {
 		res += ", \"Response\""
 	}
+	if m.IsBatch() {
+		res += ", \"Batch\""
+	}
+	if m.IsScheduled() {
+		res += ", \"Scheduled\""
+	}
 	return []byte(fmt.Sprintf("[%s]", res)), nil
 }
 
@@ -553,6 +805,10 @@ func (m *TransactionFlags) UnmarshalJSON(data []byte) error {
 			m.SetBit(TransactionFlagBounce)
 		case "Response":
 			m.SetBit(TransactionFlagResponse)
+		case "Batch":
+			m.SetBit(TransactionFlagBatch)
+		case "Scheduled":
+			m.SetBit(TransactionFlagScheduled)
 		}
 	}
 	return nil
@@ -578,4 +834,12 @@ func (m TransactionFlags) IsResponse() bool {
 	return m.GetBit(TransactionFlagResponse)
 }
 
-//go:generate go run github.com/NilFoundation/fastssz/sszgen --path transaction.go -include ../../common/length.go,address.go,gas.go,value.go,code.go,shard.go,bloom.go,log.go,../../common/hash.go,signature.go,account.go,bitflags.go --objs Transaction,ExternalTransaction,InternalTransactionPayload,TransactionDigest,TransactionFlags,EvmState,AsyncContext,AsyncResponsePayload
+func (m TransactionFlags) IsBatch() bool {
+	return m.GetBit(TransactionFlagBatch)
+}
+
+func (m TransactionFlags) IsScheduled() bool {
+	return m.GetBit(TransactionFlagScheduled)
+}
+
+//go:generate go run github.com/NilFoundation/fastssz/sszgen --path transaction.go -include ../../common/length.go,address.go,gas.go,value.go,code.go,shard.go,bloom.go,log.go,../../common/hash.go,signature.go,account.go,bitflags.go --objs transactionV0,transactionV1,externalTransactionV0,externalTransactionV1,InternalTransactionPayload,TransactionDigest,SponsoredTransactionDigest,TransactionFlags,EvmState,asyncContextV0,asyncContextV1,AsyncResponsePayload
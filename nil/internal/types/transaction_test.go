@@ -42,6 +42,36 @@ func TestTransactionSign(t *testing.T) {
 	assert.True(t, crypto.VerifySignature(pubBytes, h.Bytes(), txn.AuthData[:64]))
 }
 
+func TestSponsoredTransactionSigningHash(t *testing.T) {
+	t.Parallel()
+
+	txn := ExternalTransaction{
+		To:   HexToAddress("9405832983856CB0CF6CD570F071122F1BEA2F21"),
+		Data: Code("qwerty"),
+	}
+	unsponsored, err := txn.SigningHash()
+	require.NoError(t, err)
+
+	// The paymaster is signed, so it can't be replaced
+	txn.Paymaster = HexToAddress("9405832983856CB0CF6CD570F071122F1BEA2F22")
+	h, err := txn.SigningHash()
+	require.NoError(t, err)
+	assert.NotEqual(t, unsponsored, h)
+
+	txn.Paymaster = HexToAddress("9405832983856CB0CF6CD570F071122F1BEA2F23")
+	h2, err := txn.SigningHash()
+	require.NoError(t, err)
+	assert.NotEqual(t, h, h2)
+
+	// The paymaster survives the conversions
+	converted := txn.ToTransaction()
+	assert.True(t, converted.HasPaymaster())
+	h3, err := converted.SigningHash()
+	require.NoError(t, err)
+	assert.Equal(t, h2, h3)
+	assert.Equal(t, txn.Hash(), converted.Hash())
+}
+
 func TestTransactionFlagsJson(t *testing.T) {
 	t.Parallel()
 
@@ -64,3 +94,35 @@ func TestTransactionFlagsJson(t *testing.T) {
 	require.NoError(t, json.Unmarshal(data, &m2))
 	require.Equal(t, m, m2)
 }
+
+func TestBatchPayload(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseBatchPayload(nil)
+	require.Error(t, err)
+
+	empty, err := NewBatchPayload(true).Bytes()
+	require.NoError(t, err)
+	_, err = ParseBatchPayload(empty)
+	require.Error(t, err)
+
+	data, err := NewBatchPayload(false, Code("first"), Code("second")).Bytes()
+	require.NoError(t, err)
+	p, err := ParseBatchPayload(data)
+	require.NoError(t, err)
+	require.False(t, p.Atomic)
+	require.Len(t, p.Calls, 2)
+	require.Equal(t, Code("second"), p.Calls[1].Data)
+
+	txn := NewEmptyTransaction()
+	txn.Flags = TransactionFlagsFromKind(false, BatchTransactionKind)
+	txn.Data = data
+	require.True(t, txn.IsBatch())
+	require.NoError(t, txn.VerifyFlags())
+
+	txn.Flags.SetBit(TransactionFlagDeploy)
+	require.Error(t, txn.VerifyFlags())
+
+	// the kind of internal transactions is set by contracts, so it doesn't make them batches
+	require.False(t, TransactionFlagsFromKind(true, BatchTransactionKind).IsBatch())
+}
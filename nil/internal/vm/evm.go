@@ -19,7 +19,7 @@ type (
 )
 
 func (evm *EVM) precompile(addr types.Address) (PrecompiledContract, bool) {
-	precompiles := PrecompiledContractsPrague
+	precompiles := precompilesForFork(evm.Context.Fork)
 	p, ok := precompiles[addr]
 	return p, ok
 }
@@ -38,6 +38,9 @@ type BlockContext struct {
 	BaseFee     *big.Int      // Provides information for BASEFEE (0 if vm runs with NoBaseFee flag and 0 gas price)
 	BlobBaseFee *big.Int      // Provides information for BLOBBASEFEE (0 if vm runs with NoBaseFee flag and 0 blob gas price)
 	Random      *common.Hash  // Provides information for PREVRANDAO
+
+	// Fork selects the opcodes and the precompiled contracts
+	Fork params.Fork
 }
 
 // TxContext provides the EVM with information about a transaction.
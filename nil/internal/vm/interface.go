@@ -87,14 +87,27 @@ type StateDB interface {
 	// AddOutTransaction adds internal out transaction for current transaction
 	AddOutTransaction(caller types.Address, payload *types.InternalTransactionPayload) (*types.Transaction, error)
 
-	// AddOutRequestTransaction adds outbound request transaction for current transaction
+	// AddOutRequestTransaction adds outbound request transaction for current transaction.
+	// If timeout is not zero, the request fails after the given number of main shard blocks without response.
 	AddOutRequestTransaction(
 		caller types.Address,
 		payload *types.InternalTransactionPayload,
 		responseProcessingGas types.Gas,
 		isAwait bool,
+		timeout uint64,
 	) (*types.Transaction, error)
 
+	// ScheduleCall registers the internal transaction to be executed at or after the given block number and time
+	ScheduleCall(
+		caller types.Address,
+		payload *types.InternalTransactionPayload,
+		blockId types.BlockNumber,
+		timestamp uint64,
+	) (common.Hash, error)
+
+	// CancelScheduledCall removes the call scheduled by the caller and returns it
+	CancelScheduledCall(caller types.Address, id common.Hash) (*types.ScheduledCall, error)
+
 	// Get current transaction
 	GetInTransaction() *types.Transaction
 
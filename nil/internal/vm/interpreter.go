@@ -87,7 +87,7 @@ type EVMInterpreter struct {
 }
 
 func NewEVMInterpreter(evm *EVM, state *EvmRestoreData) *EVMInterpreter {
-	return &EVMInterpreter{evm: evm, table: &cancunInstructionSet, restoredState: state}
+	return &EVMInterpreter{evm: evm, table: instructionSetForFork(evm.Context.Fork), restoredState: state}
 }
 
 // Run loops and evaluates the contract's code with the given input data and returns
@@ -19,6 +19,7 @@ package vm
 import (
 	"fmt"
 
+	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/internal/params"
 )
 
@@ -46,6 +47,23 @@ type operation struct {
 
 var cancunInstructionSet = newCancunInstructionSet()
 
+// instructionSets maps every known fork to its opcodes.
+var instructionSets = map[params.Fork]*JumpTable{
+	params.ForkGenesis:                &cancunInstructionSet,
+	params.ForkPaymasters:             &cancunInstructionSet,
+	params.ForkBatchTransactions:      &cancunInstructionSet,
+	params.ForkScheduledCalls:         &cancunInstructionSet,
+	params.ForkAsyncTimeouts:          &cancunInstructionSet,
+	params.ForkConsensusMessageDomain: &cancunInstructionSet,
+}
+
+// instructionSetForFork returns the opcodes of the fork.
+func instructionSetForFork(fork params.Fork) *JumpTable {
+	table, ok := instructionSets[fork]
+	check.PanicIfNotf(ok, "no instruction set for fork %s", fork)
+	return table
+}
+
 // JumpTable contains the EVM opcodes supported at a given fork.
 type JumpTable [256]*operation
 
@@ -17,8 +17,10 @@
 package vm
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"math/big"
 	"reflect"
@@ -29,6 +31,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/abi"
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
+	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/tracing"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	eth_common "github.com/ethereum/go-ethereum/common"
@@ -99,8 +102,27 @@ var (
 	SendRequestAddress        = types.BytesToAddress([]byte{0xd8})
 	CheckIsResponseAddress    = types.BytesToAddress([]byte{0xd9})
 	LogAddress                = types.BytesToAddress([]byte{0xda})
+	ScheduleCallAddress       = types.BytesToAddress([]byte{0xdb})
+	CancelScheduledCallAddr   = types.BytesToAddress([]byte{0xdc})
 )
 
+// precompileSets maps every known fork to its precompiled contracts.
+var precompileSets = map[params.Fork]map[types.Address]PrecompiledContract{
+	params.ForkGenesis:                PrecompiledContractsPrague,
+	params.ForkPaymasters:             PrecompiledContractsPrague,
+	params.ForkBatchTransactions:      PrecompiledContractsPrague,
+	params.ForkScheduledCalls:         PrecompiledContractsScheduledCalls,
+	params.ForkAsyncTimeouts:          PrecompiledContractsAsyncTimeouts,
+	params.ForkConsensusMessageDomain: PrecompiledContractsAsyncTimeouts,
+}
+
+// precompilesForFork returns the precompiled contracts of the fork.
+func precompilesForFork(fork params.Fork) map[types.Address]PrecompiledContract {
+	precompiles, ok := precompileSets[fork]
+	check.PanicIfNotf(ok, "no precompiled contracts for fork %s", fork)
+	return precompiles
+}
+
 // PrecompiledContractsPrague contains the set of pre-compiled Ethereum
 // contracts used in the Prague release.
 var PrecompiledContractsPrague = map[types.Address]PrecompiledContract{
@@ -142,6 +164,30 @@ var PrecompiledContractsPrague = map[types.Address]PrecompiledContract{
 	LogAddress:                &emitLog{},
 }
 
+// PrecompiledContractsScheduledCalls adds the scheduling of calls to PrecompiledContractsPrague.
+var PrecompiledContractsScheduledCalls = extendPrecompiles(PrecompiledContractsPrague,
+	map[types.Address]PrecompiledContract{
+		ScheduleCallAddress:     &scheduleCall{},
+		CancelScheduledCallAddr: &cancelScheduledCall{},
+	})
+
+// PrecompiledContractsAsyncTimeouts adds the variants of the async request methods with a timeout
+// to PrecompiledContractsScheduledCalls.
+var PrecompiledContractsAsyncTimeouts = extendPrecompiles(PrecompiledContractsScheduledCalls,
+	map[types.Address]PrecompiledContract{
+		AwaitCallAddress:   &awaitCall{timeouts: true},
+		SendRequestAddress: &sendRequest{timeouts: true},
+	})
+
+// extendPrecompiles returns the precompiled contracts of the previous fork along with the added ones.
+func extendPrecompiles(
+	base map[types.Address]PrecompiledContract, added map[types.Address]PrecompiledContract,
+) map[types.Address]PrecompiledContract {
+	res := maps.Clone(base)
+	maps.Copy(res, added)
+	return res
+}
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 // It returns
 // - the returned bytes,
@@ -510,18 +556,53 @@ func estimateGasForAsyncRequest(input []byte, precompile string, argnum, argtota
 	return baseFee + responseProcessingGas.Uint64()
 }
 
-type awaitCall struct{}
+// asyncRequestMethodName returns the name of the method of an async request precompile called by the input:
+// the basic one or its variant with the timeout of the request if the timeouts are supported by the fork.
+// Before that the input is always decoded by the basic method.
+func asyncRequestMethodName(input []byte, methodName string, timeouts bool) (string, bool) {
+	withTimeout := methodName + "WithTimeout"
+	if timeouts && len(input) >= 4 && bytes.Equal(input[:4], getPrecompiledMethod(withTimeout).ID) {
+		return withTimeout, true
+	}
+	return methodName, false
+}
+
+// extractTimeoutParam removes the `timeout` argument of the variant of an async request method with the timeout
+// from the arguments. The timeout is measured in the blocks of the main shard, zero means no timeout.
+func extractTimeoutParam(args []any, withTimeout bool, index int, methodName string) ([]any, uint64, error) {
+	if !withTimeout {
+		return args, 0, nil
+	}
+	if len(args) <= index {
+		return nil, 0, types.NewVmError(types.ErrorPrecompileWrongNumberOfArguments)
+	}
+	timeout := extractUintParam(args[index], methodName, "timeout")
+	if !timeout.IsUint64() {
+		return nil, 0, types.NewVmError(types.ErrorAsyncCallTimeoutTooBig)
+	}
+	return append(args[:index:index], args[index+1:]...), timeout.Uint64(), nil
+}
+
+type awaitCall struct {
+	// timeouts enables the variant of the method with the timeout of the request
+	timeouts bool
+}
 
 var _ EvmAccessedPrecompiledContract = (*awaitCall)(nil)
 
 func (c *awaitCall) RequiredGas(input []byte, state StateDBReadOnly) (uint64, error) {
-	dst, err := extractDstAddress(input, "precompileAwaitCall", 0)
+	methodName, withTimeout := asyncRequestMethodName(input, "precompileAwaitCall", c.timeouts)
+	dst, err := extractDstAddress(input, methodName, 0)
 	if err != nil {
 		return math.MaxUint64, err
 	}
 	extraGas := GetExtraGasForOutboundTransaction(state, dst.ShardId())
 
-	return extraGas + estimateGasForAsyncRequest(input, "precompileAwaitCall", 1, 3), nil
+	argsNum := 3
+	if withTimeout {
+		argsNum++
+	}
+	return extraGas + estimateGasForAsyncRequest(input, methodName, 1, argsNum), nil
 }
 
 func (a *awaitCall) Run(evm *EVM, input []byte, value *uint256.Int, caller ContractRef) ([]byte, error) {
@@ -536,13 +617,20 @@ func (a *awaitCall) Run(evm *EVM, input []byte, value *uint256.Int, caller Contr
 		return nil, types.NewVmError(types.ErrorAwaitCallCalledFromNotTopLevel)
 	}
 
-	method := getPrecompiledMethod("precompileAwaitCall")
+	methodName, withTimeout := asyncRequestMethodName(input, "precompileAwaitCall", a.timeouts)
+	method := getPrecompiledMethod(methodName)
 
 	// Unpack arguments, skipping the first 4 bytes (function selector)
 	args, err := method.Inputs.Unpack(input[4:])
 	if err != nil {
 		return nil, types.NewVmVerboseError(types.ErrorAbiUnpackFailed, err.Error())
 	}
+
+	// Get optional `timeout` argument
+	args, timeout, err := extractTimeoutParam(args, withTimeout, 2, "awaitCall")
+	if err != nil {
+		return nil, err
+	}
 	if len(args) != 3 {
 		return nil, types.NewVmError(types.ErrorPrecompileWrongNumberOfArguments)
 	}
@@ -575,7 +663,7 @@ func (a *awaitCall) Run(evm *EVM, input []byte, value *uint256.Int, caller Contr
 
 	setRefundTo(&payload.RefundTo, state.GetInTransaction())
 
-	if _, err = state.AddOutRequestTransaction(caller.Address(), &payload, responseProcessingGas, true); err != nil {
+	if _, err = state.AddOutRequestTransaction(caller.Address(), &payload, responseProcessingGas, true, timeout); err != nil {
 		log.Logger.Error().Msgf("AddOutRequestTransaction failed: %s", err)
 		return nil, types.NewVmVerboseError(types.ErrorPrecompileStateDbReturnedError, err.Error())
 	}
@@ -583,18 +671,26 @@ func (a *awaitCall) Run(evm *EVM, input []byte, value *uint256.Int, caller Contr
 	return nil, nil
 }
 
-type sendRequest struct{}
+type sendRequest struct {
+	// timeouts enables the variant of the method with the timeout of the request
+	timeouts bool
+}
 
 var _ ReadWritePrecompiledContract = (*sendRequest)(nil)
 
 func (c *sendRequest) RequiredGas(input []byte, state StateDBReadOnly) (uint64, error) {
-	dst, err := extractDstAddress(input, "precompileSendRequest", 0)
+	methodName, withTimeout := asyncRequestMethodName(input, "precompileSendRequest", c.timeouts)
+	dst, err := extractDstAddress(input, methodName, 0)
 	if err != nil {
 		return math.MaxUint64, err
 	}
 	extraGas := GetExtraGasForOutboundTransaction(state, dst.ShardId())
 
-	return extraGas + estimateGasForAsyncRequest(input, "precompileSendRequest", 2, 5), nil
+	argsNum := 5
+	if withTimeout {
+		argsNum++
+	}
+	return extraGas + estimateGasForAsyncRequest(input, methodName, 2, argsNum), nil
 }
 
 func (a *sendRequest) Run(state StateDB, input []byte, value *uint256.Int, caller ContractRef) ([]byte, error) {
@@ -602,13 +698,20 @@ func (a *sendRequest) Run(state StateDB, input []byte, value *uint256.Int, calle
 		return nil, types.NewVmError(types.ErrorPrecompileTooShortCallData)
 	}
 
-	method := getPrecompiledMethod("precompileSendRequest")
+	methodName, withTimeout := asyncRequestMethodName(input, "precompileSendRequest", a.timeouts)
+	method := getPrecompiledMethod(methodName)
 
 	// Unpack arguments, skipping the first 4 bytes (function selector)
 	args, err := method.Inputs.Unpack(input[4:])
 	if err != nil {
 		return nil, types.NewVmVerboseError(types.ErrorAbiUnpackFailed, err.Error())
 	}
+
+	// Get optional `timeout` argument
+	args, timeout, err := extractTimeoutParam(args, withTimeout, 3, "sendRequest")
+	if err != nil {
+		return nil, err
+	}
 	if len(args) != 5 {
 		return nil, types.NewVmError(types.ErrorPrecompileWrongNumberOfArguments)
 	}
@@ -656,7 +759,7 @@ func (a *sendRequest) Run(state StateDB, input []byte, value *uint256.Int, calle
 
 	setRefundTo(&payload.RefundTo, state.GetInTransaction())
 
-	if _, err = state.AddOutRequestTransaction(caller.Address(), &payload, responseProcessingGas, false); err != nil {
+	if _, err = state.AddOutRequestTransaction(caller.Address(), &payload, responseProcessingGas, false, timeout); err != nil {
 		log.Logger.Error().Msgf("AddOutRequestTransaction failed: %s", err)
 		return nil, types.NewVmVerboseError(types.ErrorPrecompileStateDbReturnedError, err.Error())
 	}
@@ -1098,3 +1201,119 @@ func (e *emitLog) Run(state StateDB, input []byte, value *uint256.Int, caller Co
 
 	return res, nil
 }
+
+type scheduleCall struct{}
+
+var _ ReadWritePrecompiledContract = (*scheduleCall)(nil)
+
+func (c *scheduleCall) RequiredGas(input []byte, _ StateDBReadOnly) (uint64, error) {
+	// The call is stored in the trie of the shard until it's executed
+	return params.SstoreSetGas + params.TxDataNonZeroGasEIP2028*uint64(len(input)), nil
+}
+
+func (c *scheduleCall) Run(state StateDB, input []byte, value *uint256.Int, caller ContractRef) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, types.NewVmError(types.ErrorPrecompileTooShortCallData)
+	}
+
+	method := getPrecompiledMethod("precompileScheduleCall")
+
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, types.NewVmVerboseError(types.ErrorAbiUnpackFailed, err.Error())
+	}
+	if len(args) != 5 {
+		return nil, types.NewVmError(types.ErrorPrecompileWrongNumberOfArguments)
+	}
+
+	// Get `dst` argument
+	dst, ok := args[0].(types.Address)
+	check.PanicIfNotf(ok, "scheduleCall failed: dst argument is not an address")
+	if dst.ShardId() != caller.Address().ShardId() {
+		return nil, types.NewVmError(types.ErrorScheduledCallToOtherShard)
+	}
+
+	// Get `blockNumber` and `timestamp` arguments
+	blockNumber := extractUintParam(args[1], "scheduleCall", "blockNumber")
+	timestamp := extractUintParam(args[2], "scheduleCall", "timestamp")
+	if !blockNumber.IsUint64() || !timestamp.IsUint64() {
+		return nil, types.NewVmVerboseError(types.ErrorAbiUnpackFailed, "scheduleCall: block number or timestamp is too big")
+	}
+
+	// Get `feeCredit` argument, the rest of the value is sent with the call
+	feeCredit := extractUintParam(args[3], "scheduleCall", "feeCredit")
+	total := types.NewValue(value)
+	if total.Cmp(feeCredit) < 0 {
+		return nil, types.NewVmVerboseError(types.ErrorInsufficientFunds, "scheduleCall: value is less than fee credit")
+	}
+
+	// Get `callData` argument
+	callData := getBytesArgCopy(args[4], "scheduleCall", "callData")
+
+	if err := withdrawFunds(state, caller.Address(), total); err != nil {
+		return nil, err
+	}
+
+	payload := &types.InternalTransactionPayload{
+		Kind:        types.ExecutionTransactionKind,
+		FeeCredit:   feeCredit,
+		ForwardKind: types.ForwardKindNone,
+		To:          dst,
+		RefundTo:    caller.Address(),
+		BounceTo:    caller.Address(),
+		Value:       total.Sub(feeCredit),
+		Data:        callData,
+	}
+
+	id, err := state.ScheduleCall(
+		caller.Address(), payload, types.BlockNumber(blockNumber.Uint64()), timestamp.Uint64())
+	if err != nil {
+		return nil, types.KeepOrWrapError(types.ErrorPrecompileStateDbReturnedError, err)
+	}
+
+	return id.Bytes(), nil
+}
+
+type cancelScheduledCall struct{}
+
+var _ ReadWritePrecompiledContract = (*cancelScheduledCall)(nil)
+
+func (c *cancelScheduledCall) RequiredGas([]byte, StateDBReadOnly) (uint64, error) {
+	return params.SstoreResetGasEIP2200, nil
+}
+
+func (c *cancelScheduledCall) Run(state StateDB, input []byte, value *uint256.Int, caller ContractRef) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, types.NewVmError(types.ErrorPrecompileTooShortCallData)
+	}
+
+	method := getPrecompiledMethod("precompileCancelScheduledCall")
+
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, types.NewVmVerboseError(types.ErrorAbiUnpackFailed, err.Error())
+	}
+	if len(args) != 1 {
+		return nil, types.NewVmError(types.ErrorPrecompileWrongNumberOfArguments)
+	}
+
+	// Get `id` argument
+	id, ok := args[0].(*big.Int)
+	check.PanicIfNotf(ok, "cancelScheduledCall failed: id is not a big.Int: %v", args[0])
+
+	call, err := state.CancelScheduledCall(caller.Address(), common.BigToHash(id))
+	if err != nil {
+		return nil, types.KeepOrWrapError(types.ErrorPrecompileStateDbReturnedError, err)
+	}
+
+	// Return the withdrawn funds to the owner
+	refund := call.Transaction.FeeCredit.Add(call.Transaction.Value)
+	if err := state.AddBalance(caller.Address(), refund, tracing.BalanceIncreaseRefund); err != nil {
+		return nil, types.KeepOrWrapError(types.ErrorPrecompileStateDbReturnedError, err)
+	}
+
+	res := make([]byte, 32)
+	res[31] = 1
+
+	return res, nil
+}
@@ -0,0 +1,54 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// Client calls handles of the admin server over its unix socket.
+type Client struct {
+	client http.Client
+}
+
+func NewClient(socketPath string) *Client {
+	return &Client{
+		client: http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// Call requests the handle with the given parameters and copies the response body to out.
+// Responses with a non-OK status are returned as errors.
+func (c *Client) Call(ctx context.Context, handle string, params url.Values, out io.Writer) error {
+	target := "http://unix" + handle
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("admin server responded with %s: %s", response.Status, body)
+	}
+	_, err = io.Copy(out, response.Body)
+	return err
+}
@@ -1,6 +1,36 @@
 package admin
 
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// CollationController pauses and resumes block production of the shards collated by the node.
+type CollationController interface {
+	PauseCollation(shardId types.ShardId) error
+	ResumeCollation(shardId types.ShardId) error
+	// CollationStatus reports for every collated shard whether its collation is paused.
+	CollationStatus() map[types.ShardId]bool
+}
+
+// PeersReloader reconnects the node to its bootstrap peers.
+type PeersReloader interface {
+	// ReloadPeers replaces the bootstrap peers with the given ones (if any), connects to them
+	// and returns the resulting list.
+	ReloadPeers(ctx context.Context, peers network.AddrInfoSlice) (network.AddrInfoSlice, error)
+}
+
 type ServerConfig struct {
 	Enabled        bool
 	UnixSocketPath string
+
+	// Node controls. Handles of the controls which are not set are not served.
+	Collation CollationController
+	DB        db.DB
+	Peers     PeersReloader
+	// NodeConfig is the effective configuration of the node, it is shown with secrets redacted.
+	NodeConfig any
 }
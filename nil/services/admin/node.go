@@ -0,0 +1,239 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const defaultDiscardRatio = 0.5
+
+func (s *adminServer) registerNodeHandles() {
+	// GET http:/./pprof/goroutine?debug=1
+	s.mux.HandleFunc("/pprof/{profile}", s.profile)
+
+	if s.cfg.Collation != nil {
+		// GET http:/./collation/pause?shard=1 (all collated shards if the shard is not specified)
+		s.mux.HandleFunc("/collation/pause", s.pauseCollation)
+		s.mux.HandleFunc("/collation/resume", s.resumeCollation)
+		s.mux.HandleFunc("/collation/status", s.collationStatus)
+	}
+	if s.cfg.DB != nil {
+		// GET http:/./db/gc?discard_ratio=0.5
+		s.mux.HandleFunc("/db/gc", s.runGC)
+		// GET http:/./db/backup?path=/path/to/backup
+		s.mux.HandleFunc("/db/backup", s.backup)
+	}
+	if s.cfg.Peers != nil {
+		// GET http:/./peers/reload?peer=/ip4/127.0.0.1/tcp/3000/p2p/<id>&peer=...
+		s.mux.HandleFunc("/peers/reload", s.reloadPeers)
+	}
+	if s.cfg.NodeConfig != nil {
+		s.mux.HandleFunc("/config", s.showConfig)
+	}
+}
+
+// collatedShards returns the shard from the request or all collated shards if it's not specified.
+func (s *adminServer) collatedShards(r *http.Request) ([]types.ShardId, error) {
+	if shard := r.URL.Query().Get("shard"); shard != "" {
+		var shardId types.ShardId
+		if err := shardId.Set(shard); err != nil {
+			return nil, fmt.Errorf("invalid shard: %w", err)
+		}
+		return []types.ShardId{shardId}, nil
+	}
+
+	status := s.cfg.Collation.CollationStatus()
+	shards := make([]types.ShardId, 0, len(status))
+	for shardId := range status {
+		shards = append(shards, shardId)
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i] < shards[j] })
+	return shards, nil
+}
+
+func (s *adminServer) updateCollation(
+	w http.ResponseWriter, r *http.Request, action string, update func(types.ShardId) error,
+) {
+	shards, err := s.collatedShards(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	for _, shardId := range shards {
+		if err := update(shardId); err != nil {
+			s.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintf(w, "collation %s for shards %v", action, shards)
+}
+
+func (s *adminServer) pauseCollation(w http.ResponseWriter, r *http.Request) {
+	s.updateCollation(w, r, "paused", s.cfg.Collation.PauseCollation)
+}
+
+func (s *adminServer) resumeCollation(w http.ResponseWriter, r *http.Request) {
+	s.updateCollation(w, r, "resumed", s.cfg.Collation.ResumeCollation)
+}
+
+func (s *adminServer) collationStatus(w http.ResponseWriter, r *http.Request) {
+	paused := make(map[string]bool)
+	for shardId, isPaused := range s.cfg.Collation.CollationStatus() {
+		paused[shardId.String()] = isPaused
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(paused)
+}
+
+func (s *adminServer) runGC(w http.ResponseWriter, r *http.Request) {
+	discardRatio := defaultDiscardRatio
+	if value := r.URL.Query().Get("discard_ratio"); value != "" {
+		var err error
+		discardRatio, err = strconv.ParseFloat(value, 64)
+		if err != nil || discardRatio <= 0 || discardRatio >= 1 {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid discard ratio %q, expected a number in (0, 1)", value))
+			return
+		}
+	}
+
+	s.logger.Info().Float64("discardRatio", discardRatio).Msg("Running DB garbage collection...")
+	if err := s.cfg.DB.RunGC(r.Context(), discardRatio); err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, "garbage collection completed")
+}
+
+// backup writes a consistent snapshot of the whole database to the given path on the node's host.
+// The backup can be loaded into an empty database with db.DB.Fetch.
+func (s *adminServer) backup(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		s.writeError(w, http.StatusBadRequest, errors.New("path is not specified"))
+		return
+	}
+
+	size, err := s.writeBackup(r, path)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintf(w, "backup of %d bytes written to %s", size, path)
+}
+
+func (s *adminServer) writeBackup(r *http.Request, path string) (int64, error) {
+	if _, err := os.Stat(path); err == nil {
+		return 0, fmt.Errorf("%s already exists", path)
+	}
+
+	// Write to a temporary file first, so an interrupted backup is never mistaken for a complete one.
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmpPath)
+
+	s.logger.Info().Str("path", path).Msg("Writing DB backup...")
+	if err := s.cfg.DB.Stream(r.Context(), func([]byte) bool { return true }, file); err != nil {
+		file.Close()
+		return 0, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, err
+	}
+	s.logger.Info().Str("path", path).Int64("size", info.Size()).Msg("DB backup written")
+	return info.Size(), nil
+}
+
+// profile writes a runtime profile (e.g. goroutine or heap) in the format of runtime/pprof.
+func (s *adminServer) profile(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("profile")
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("unknown profile %q", name))
+		return
+	}
+
+	debug := 0
+	if value := r.URL.Query().Get("debug"); value != "" {
+		var err error
+		if debug, err = strconv.Atoi(value); err != nil {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid debug level: %w", err))
+			return
+		}
+	}
+
+	if debug == 0 {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	if err := profile.WriteTo(w, debug); err != nil {
+		s.logger.Error().Err(err).Str("profile", name).Msg("Failed to write profile")
+	}
+}
+
+func (s *adminServer) reloadPeers(w http.ResponseWriter, r *http.Request) {
+	var peers network.AddrInfoSlice
+	for _, value := range r.URL.Query()["peer"] {
+		var peer network.AddrInfo
+		if err := peer.Set(value); err != nil {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid peer %q: %w", value, err))
+			return
+		}
+		peers = append(peers, peer)
+	}
+
+	peers, err := s.cfg.Peers.ReloadPeers(r.Context(), peers)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	for _, peer := range peers {
+		_, _ = fmt.Fprintln(w, peer.String())
+	}
+}
+
+func (s *adminServer) showConfig(w http.ResponseWriter, r *http.Request) {
+	data, err := marshalRedacted(s.cfg.NodeConfig)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redactedValue = "<redacted>"
+
+// secretKeyMarkers are the parts of configuration keys which hold secrets, compared in lower case
+// with separators removed.
+var secretKeyMarkers = []string{"password", "secret", "token", "privatekey", "apikey"}
+
+// marshalRedacted encodes the value to YAML replacing values of the secret keys.
+func marshalRedacted(value any) ([]byte, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	redactNode(&root)
+	return yaml.Marshal(&root)
+}
+
+func redactNode(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if isSecretKey(key.Value) {
+				*value = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: redactedValue}
+				continue
+			}
+			redactNode(value)
+		}
+		return
+	}
+
+	for _, child := range node.Content {
+		redactNode(child)
+	}
+}
+
+func isSecretKey(key string) bool {
+	normalized := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key))
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(normalized, marker) {
+			return true
+		}
+	}
+	return false
+}
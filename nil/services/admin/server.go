@@ -33,6 +33,7 @@ func StartAdminServer(ctx context.Context, cfg *ServerConfig, logger zerolog.Log
 	// GET http:/./set_log_level?level=info
 	srv.mux.HandleFunc("/set_log_level", srv.setLogLevel)
 	srv.mux.HandleFunc("/ping", srv.ping)
+	srv.registerNodeHandles()
 
 	if err := srv.serve(ctx); err != nil {
 		return fmt.Errorf("error starting admin server: %w", err)
@@ -70,8 +71,7 @@ func (s *adminServer) setLogLevel(w http.ResponseWriter, r *http.Request) {
 	lvl := r.URL.Query().Get("level")
 	err := logging.TrySetupGlobalLevel(lvl)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		_, _ = fmt.Fprintf(w, "error: %s", err.Error())
+		s.writeError(w, http.StatusBadRequest, err)
 	} else {
 		w.WriteHeader(http.StatusOK)
 		_, _ = fmt.Fprintf(w, "set to %s", lvl)
@@ -81,3 +81,8 @@ func (s *adminServer) setLogLevel(w http.ResponseWriter, r *http.Request) {
 func (s *adminServer) ping(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
+
+func (s *adminServer) writeError(w http.ResponseWriter, code int, err error) {
+	w.WriteHeader(code)
+	_, _ = fmt.Fprintf(w, "error: %s", err.Error())
+}
@@ -3,12 +3,18 @@ package admin
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"maps"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 )
@@ -55,3 +61,147 @@ func TestAdminServer(t *testing.T) {
 
 	check(t, "invalid", http.StatusBadRequest, zerolog.WarnLevel)
 }
+
+type collationControllerMock struct {
+	paused map[types.ShardId]bool
+}
+
+func (c *collationControllerMock) PauseCollation(shardId types.ShardId) error {
+	return c.setPaused(shardId, true)
+}
+
+func (c *collationControllerMock) ResumeCollation(shardId types.ShardId) error {
+	return c.setPaused(shardId, false)
+}
+
+func (c *collationControllerMock) setPaused(shardId types.ShardId, paused bool) error {
+	if _, ok := c.paused[shardId]; !ok {
+		return fmt.Errorf("shard %s is not collated", shardId)
+	}
+	c.paused[shardId] = paused
+	return nil
+}
+
+func (c *collationControllerMock) CollationStatus() map[types.ShardId]bool {
+	return maps.Clone(c.paused)
+}
+
+func TestAdminServerNodeHandles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	database, err := db.NewBadgerDb(dir + "/database")
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, tx.Put("table", []byte("key"), []byte("value")))
+	require.NoError(t, tx.Commit())
+
+	collation := &collationControllerMock{paused: map[types.ShardId]bool{1: false, 2: false}}
+	nodeConfig := map[string]any{
+		"rpcPort": 8529,
+		"cometa":  map[string]any{"db-user": "default", "db-password": "qwerty"},
+	}
+
+	socketPath := dir + "/admin_socket"
+	cfg := &ServerConfig{
+		Enabled:        true,
+		UnixSocketPath: socketPath,
+		Collation:      collation,
+		DB:             database,
+		NodeConfig:     nodeConfig,
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go func() {
+		_ = StartAdminServer(ctx, cfg, logging.NewLogger("admin"))
+	}()
+
+	client := NewClient(socketPath)
+	call := func(handle string, params url.Values) (string, error) {
+		t.Helper()
+
+		var out bytes.Buffer
+		err := client.Call(ctx, handle, params, &out)
+		return out.String(), err
+	}
+
+	require.Eventually(t, func() bool {
+		_, err := call("/ping", nil)
+		return err == nil
+	}, 5*time.Second, 200*time.Millisecond)
+
+	t.Run("Collation", func(t *testing.T) {
+		_, err := call("/collation/pause", url.Values{"shard": {"1"}})
+		require.NoError(t, err)
+		require.Equal(t, map[types.ShardId]bool{1: true, 2: false}, collation.CollationStatus())
+
+		status, err := call("/collation/status", nil)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"1": true, "2": false}`, status)
+
+		_, err = call("/collation/pause", url.Values{"shard": {"3"}})
+		require.ErrorContains(t, err, "shard 3 is not collated")
+
+		_, err = call("/collation/resume", nil)
+		require.NoError(t, err)
+		require.Equal(t, map[types.ShardId]bool{1: false, 2: false}, collation.CollationStatus())
+	})
+
+	t.Run("GC", func(t *testing.T) {
+		_, err := call("/db/gc", nil)
+		require.NoError(t, err)
+
+		_, err = call("/db/gc", url.Values{"discard_ratio": {"2"}})
+		require.ErrorContains(t, err, "invalid discard ratio")
+	})
+
+	t.Run("Backup", func(t *testing.T) {
+		path := dir + "/backup"
+		_, err := call("/db/backup", url.Values{"path": {path}})
+		require.NoError(t, err)
+
+		restored, err := db.NewBadgerDbInMemory()
+		require.NoError(t, err)
+		defer restored.Close()
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		require.NoError(t, restored.Fetch(ctx, file))
+
+		tx, err := restored.CreateRoTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+		value, err := tx.Get("table", []byte("key"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("value"), value)
+
+		_, err = call("/db/backup", url.Values{"path": {path}})
+		require.ErrorContains(t, err, "already exists")
+	})
+
+	t.Run("Profile", func(t *testing.T) {
+		profile, err := call("/pprof/goroutine", url.Values{"debug": {"1"}})
+		require.NoError(t, err)
+		require.Contains(t, profile, "goroutine profile")
+
+		_, err = call("/pprof/unknown", nil)
+		require.ErrorContains(t, err, "unknown profile")
+	})
+
+	t.Run("Config", func(t *testing.T) {
+		config, err := call("/config", nil)
+		require.NoError(t, err)
+		require.Contains(t, config, "db-user: default")
+		require.Contains(t, config, "db-password: <redacted>")
+		require.NotContains(t, config, "qwerty")
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		_, err := call("/peers/reload", nil)
+		require.ErrorContains(t, err, "404")
+	})
+}
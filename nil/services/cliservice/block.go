@@ -30,7 +30,10 @@ func (s *Service) FetchBlock(shardId types.ShardId, blockId any) ([]byte, error)
 }
 
 // FetchDebugBlock fetches the block by number or hash with transactions related data.
-func (s *Service) FetchDebugBlock(shardId types.ShardId, blockId any, jsonOutput bool, fullOutput bool, noColor bool) ([]byte, error) {
+// If the decoder is set, the calldata, the events and the errors of the in transactions are decoded.
+func (s *Service) FetchDebugBlock(
+	shardId types.ShardId, blockId any, jsonOutput bool, fullOutput bool, noColor bool, decoder *AbiDecoder,
+) ([]byte, error) {
 	hexedBlock, err := s.client.GetDebugBlock(s.ctx, shardId, blockId, true)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to fetch block")
@@ -43,10 +46,15 @@ func (s *Service) FetchDebugBlock(shardId types.ShardId, blockId any, jsonOutput
 		return nil, err
 	}
 
+	var decoded map[common.Hash]*DecodedTransaction
+	if decoder != nil {
+		decoded = decoder.DecodeBlock(block)
+	}
+
 	if jsonOutput {
-		return s.debugBlockToJson(shardId, block)
+		return s.debugBlockToJson(shardId, block, decoded)
 	} else {
-		return s.debugBlockToText(shardId, block, !noColor, fullOutput)
+		return s.debugBlockToText(shardId, block, decoded, !noColor, fullOutput)
 	}
 }
 
@@ -62,7 +70,9 @@ func (m *transactionWithHash) MarshalJSON() ([]byte, error) {
 	}{&m.Transaction, m.Transaction.Hash()})
 }
 
-func (s *Service) debugBlockToJson(shardId types.ShardId, block *types.BlockWithExtractedData) ([]byte, error) {
+func (s *Service) debugBlockToJson(
+	shardId types.ShardId, block *types.BlockWithExtractedData, decoded map[common.Hash]*DecodedTransaction,
+) ([]byte, error) {
 	toWithHashTransactions := func(transactions []*types.Transaction) []transactionWithHash {
 		result := make([]transactionWithHash, 0, len(transactions))
 		for _, transaction := range transactions {
@@ -83,6 +93,8 @@ func (s *Service) debugBlockToJson(shardId types.ShardId, block *types.BlockWith
 		Errors          map[common.Hash]string `json:"errors,omitempty"`
 		Hash            common.Hash            `json:"hash"`
 		ShardId         types.ShardId          `json:"shardId"`
+
+		Decoded map[common.Hash]*DecodedTransaction `json:"decoded,omitempty"`
 	}{
 		block.Block,
 		block.ChildBlocks,
@@ -92,6 +104,7 @@ func (s *Service) debugBlockToJson(shardId types.ShardId, block *types.BlockWith
 		block.Errors,
 		block.Block.Hash(shardId),
 		shardId,
+		decoded,
 	}, "", "  ")
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to marshal block data to JSON")
@@ -100,7 +113,10 @@ func (s *Service) debugBlockToJson(shardId types.ShardId, block *types.BlockWith
 	return blockDataJSON, nil
 }
 
-func (s *Service) debugBlockToText(shardId types.ShardId, block *types.BlockWithExtractedData, useColor bool, full bool) ([]byte, error) {
+func (s *Service) debugBlockToText(
+	shardId types.ShardId, block *types.BlockWithExtractedData, decoded map[common.Hash]*DecodedTransaction,
+	useColor bool, full bool,
+) ([]byte, error) {
 	colors := map[string]string{
 		"blue":    "\033[94m",
 		"green":   "\033[32m",
@@ -119,6 +135,7 @@ func (s *Service) debugBlockToText(shardId types.ShardId, block *types.BlockWith
 	blockTemplate := `
 {{- $block := .block -}}
 {{- $color := .color -}}
+{{- $decoded := .decoded -}}
 Block #{{ .block.Id }} [{{ .color.bold }}{{ .block.Hash .shardId }}{{ .color.reset }}] @ {{ .shardId }} shard
   PrevBlock: {{ .block.PrevBlock }}
   ChildBlocksRootHash: {{ .block.ChildBlocksRootHash }}
@@ -132,7 +149,7 @@ Block #{{ .block.Id }} [{{ .color.bold }}{{ .block.Hash .shardId }}{{ .color.res
 {{ if len .block.InTransactions -}}
 ▼ InTransactions [{{ .block.InTransactionsRoot }}]:
   {{- range $index, $element := .block.InTransactions -}}
-    {{ template "transaction" dict "transaction" $element "index" $index "color" $color "block" $block }}
+    {{ template "transaction" dict "transaction" $element "index" $index "color" $color "block" $block "decoded" $decoded }}
   {{- end }}
 {{- else -}}
 ■ No in transactions [{{ .block.InTransactionsRoot }}]
@@ -140,7 +157,7 @@ Block #{{ .block.Id }} [{{ .color.bold }}{{ .block.Hash .shardId }}{{ .color.res
 {{ if len .block.OutTransactions -}}
 ▼ OutTransactions [{{ .block.OutTransactionsRoot }}]:
   {{- range $index, $element := .block.OutTransactions -}}
-    {{ template "transaction" dict "transaction" $element "index" $index "color" $color "block" $block }}
+    {{ template "transaction" dict "transaction" $element "index" $index "color" $color "block" $block "decoded" $decoded }}
   {{- end }}
 {{- else -}}
 ■ No out transactions [{{ .block.OutTransactionsRoot }}]
@@ -174,6 +191,9 @@ Block #{{ .block.Id }} [{{ .color.bold }}{{ .block.Hash .shardId }}{{ .color.res
     Flags: {{ .transaction.Flags }}
     RefundTo: {{ .transaction.RefundTo }}
     BounceTo: {{ .transaction.BounceTo }}
+    {{- if .transaction.HasPaymaster }}
+    Paymaster: {{ .transaction.Paymaster }}
+    {{- end }}
     Value: {{ .transaction.Value }}
     ChainId: {{ .transaction.ChainId }}
     Seqno: {{ .transaction.Seqno }}
@@ -182,7 +202,18 @@ Block #{{ .block.Id }} [{{ .color.bold }}{{ .block.Hash .shardId }}{{ .color.res
       {{ .Token }}: {{ .Balance }}
     {{- end }}{{ end }}
     Data: {{ formatData .transaction.Data }}{{ with .transaction.Signature }}
-    Signature: {{ . }}{{ end }}`
+    Signature: {{ . }}{{ end }}
+    {{- with index .decoded .transaction.Hash }}
+    {{- with .Call }}
+    {{ $color.yellow }}Call:{{ $color.reset }} {{ .String }}
+    {{- end }}
+    {{- range .Events }}
+    {{ $color.yellow }}Event:{{ $color.reset }} {{ .String }}
+    {{- end }}
+    {{- with .Error }}
+    {{ $color.yellow }}Decoded error: {{ $color.red }}{{ .String }}{{ $color.reset }}
+    {{- end }}
+    {{- end }}`
 
 	receiptTemplate := `
   [{{ .color.bold }}{{ .receipt.TxnHash }}{{ .color.reset }}]
@@ -191,6 +222,13 @@ Block #{{ .block.Id }} [{{ .color.bold }}{{ .block.Hash .shardId }}{{ .color.res
        {{- " " }}{{ .receipt.Status }}
        {{- .color.reset }}
      GasUsed: {{ .receipt.GasUsed }}
+     {{- $color := .color }}
+     {{- range $index, $call := .receipt.BatchReceipts }}
+     Call #{{ $index }}:
+       {{- if .Success }}{{ $color.green }}{{ else }}{{ $color.red }}{{ end }}
+       {{- " " }}{{ .Status }}
+       {{- $color.reset }} GasUsed: {{ .GasUsed }}
+     {{- end }}
      {{- /* */ -}}
 `
 
@@ -200,6 +238,7 @@ Block #{{ .block.Id }} [{{ .color.bold }}{{ .block.Hash .shardId }}{{ .color.res
 			"block":   block,
 			"shardId": shardId,
 			"color":   colors,
+			"decoded": decoded,
 		},
 		template.FuncMap{
 			"dict": func(values ...any) (map[string]any, error) {
@@ -144,7 +144,7 @@ func TestDebugBlockToText(t *testing.T) {
 	t.Run("FilledBlock", func(t *testing.T) {
 		t.Parallel()
 
-		text, err := s.debugBlockToText(types.ShardId(13), block, false, false)
+		text, err := s.debugBlockToText(types.ShardId(13), block, nil, false, false)
 		require.NoError(t, err)
 
 		expectedText := `Block #100500 [0x000d9bb830d574ddf2973a367f2fe9d899c7c523afb809a1a7d2480ab9bcd4cf] @ 13 shard
@@ -214,7 +214,7 @@ func TestDebugBlockToText(t *testing.T) {
 		emptyBlock.Receipts = nil
 		emptyBlock.Errors = nil
 
-		_, err := s.debugBlockToText(types.ShardId(13), &emptyBlock, true, false)
+		_, err := s.debugBlockToText(types.ShardId(13), &emptyBlock, nil, true, false)
 		require.NoError(t, err)
 	})
 }
@@ -1,6 +1,7 @@
 package cliservice
 
 import (
+	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/client/rpc"
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
@@ -51,9 +52,12 @@ func (s *Service) GetInfo(address types.Address) (string, string, error) {
 	s.logger.Info().Msgf("Address: %s", address)
 
 	var pub string
-	if s.privateKey != nil {
-		pubBytes := crypto.CompressPubkey(&s.privateKey.PublicKey)
-		pub = hexutil.Encode(pubBytes)
+	if s.signer != nil {
+		pubKey, err := s.signer.PublicKey(s.ctx)
+		if err != nil {
+			return "", "", err
+		}
+		pub = hexutil.Encode(crypto.CompressPubkey(pubKey))
 		s.logger.Info().Msgf("Public key: %s", pub)
 	}
 
@@ -79,7 +83,8 @@ func (s *Service) GetTokens(contractAddress types.Address) (types.TokensMap, err
 func (s *Service) RunContract(smartAccount types.Address, bytecode []byte, fee types.FeePack, value types.Value,
 	tokens []types.TokenBalance, contract types.Address,
 ) (common.Hash, error) {
-	txHash, err := s.client.SendTransactionViaSmartAccount(s.ctx, smartAccount, bytecode, fee, value, tokens, contract, s.privateKey)
+	txHash, err := client.SendTransactionViaSmartAccount(
+		s.ctx, s.client, smartAccount, bytecode, fee, value, tokens, contract, s.signer, false)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to send new transaction")
 		return common.EmptyHash, err
@@ -93,11 +98,12 @@ func (s *Service) RunContract(smartAccount types.Address, bytecode []byte, fee t
 
 // SendExternalTransaction runs bytecode on the specified contract address
 func (s *Service) SendExternalTransaction(bytecode []byte, contract types.Address, noSign bool) (common.Hash, error) {
-	pk := s.privateKey
+	signer := s.signer
 	if noSign {
-		pk = nil
+		signer = nil
 	}
-	txHash, err := s.client.SendExternalTransaction(s.ctx, types.Code(bytecode), contract, pk, types.NewFeePackFromGas(0))
+	txHash, err := client.SendExternalTransaction(
+		s.ctx, s.client, types.Code(bytecode), contract, signer, types.NewFeePackFromGas(0), false, false)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to send external transaction")
 		return common.EmptyHash, err
@@ -109,12 +115,51 @@ func (s *Service) SendExternalTransaction(bytecode []byte, contract types.Addres
 	return txHash, nil
 }
 
+// SendSponsoredExternalTransaction runs bytecode on the specified contract address, the fees are paid by the paymaster
+func (s *Service) SendSponsoredExternalTransaction(
+	bytecode []byte, contract, paymaster types.Address, noSign bool,
+) (common.Hash, error) {
+	signer := s.signer
+	if noSign {
+		signer = nil
+	}
+	txHash, err := client.SendSponsoredExternalTransaction(
+		s.ctx, s.client, types.Code(bytecode), contract, paymaster, signer, types.NewFeePackFromGas(0))
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to send sponsored external transaction")
+		return common.EmptyHash, err
+	}
+	s.logger.Info().
+		Stringer(logging.FieldShardId, contract.ShardId()).
+		Stringer(logging.FieldTransactionHash, txHash).
+		Stringer("paymaster", paymaster).
+		Send()
+	return txHash, nil
+}
+
+// RunBatch sends the calls via the smart account as a single batched transaction
+func (s *Service) RunBatch(
+	smartAccount types.Address, calls []client.SmartAccountCall, atomic bool, fee types.FeePack,
+) (common.Hash, error) {
+	txHash, err := client.SendBatchViaSmartAccount(s.ctx, s.client, smartAccount, calls, atomic, fee, s.signer)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to send batched transaction")
+		return common.EmptyHash, err
+	}
+	s.logger.Info().
+		Stringer(logging.FieldShardId, smartAccount.ShardId()).
+		Stringer(logging.FieldTransactionHash, txHash).
+		Int("calls", len(calls)).
+		Send()
+	return txHash, nil
+}
+
 // DeployContractViaSmartAccount deploys a new smart contract with the given bytecode via the smart account
 func (s *Service) DeployContractViaSmartAccount(shardId types.ShardId, smartAccount types.Address, deployPayload types.DeployPayload,
 	value types.Value,
 ) (common.Hash, types.Address, error) {
-	txHash, contractAddr, err := s.client.DeployContract(s.ctx, shardId, smartAccount, deployPayload, value,
-		types.NewFeePackFromGas(10_000_000), s.privateKey)
+	txHash, contractAddr, err := client.DeployContractViaSmartAccount(s.ctx, s.client, shardId, smartAccount, deployPayload,
+		value, types.NewFeePackFromGas(10_000_000), s.signer)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to send new transaction")
 		return common.EmptyHash, types.EmptyAddress, err
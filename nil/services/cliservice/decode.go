@@ -0,0 +1,327 @@
+package cliservice
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+// AbiDecoder decodes the calldata, the event logs and the revert data of the transactions.
+// The ABIs given on creation are tried first, then the ABI of the contract is fetched (e.g. from Cometa).
+type AbiDecoder struct {
+	abis    []abi.ABI
+	fetch   func(types.Address) (abi.ABI, error)
+	fetched map[types.Address]*abi.ABI
+}
+
+// NewAbiDecoder creates the decoder with the ABIs and the optional function fetching the ABI of a contract.
+func NewAbiDecoder(abis []abi.ABI, fetch func(types.Address) (abi.ABI, error)) *AbiDecoder {
+	return &AbiDecoder{
+		abis:    abis,
+		fetch:   fetch,
+		fetched: make(map[types.Address]*abi.ABI),
+	}
+}
+
+// DecodedArg is a decoded argument of a call, an event or an error.
+type DecodedArg struct {
+	Name  string `json:"name,omitempty"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// DecodedValue is a decoded call, event or error.
+type DecodedValue struct {
+	Name      string        `json:"name"`
+	Signature string        `json:"signature"`
+	Args      []*DecodedArg `json:"args,omitempty"`
+}
+
+func (v *DecodedValue) String() string {
+	args := make([]string, len(v.Args))
+	for i, arg := range v.Args {
+		if arg.Name != "" {
+			args[i] = fmt.Sprintf("%s=%v", arg.Name, arg.Value)
+		} else {
+			args[i] = fmt.Sprintf("%v", arg.Value)
+		}
+	}
+	return fmt.Sprintf("%s(%s)", v.Name, strings.Join(args, ", "))
+}
+
+// DecodedEvent is a decoded event log.
+type DecodedEvent struct {
+	Address types.Address `json:"address"`
+	DecodedValue
+}
+
+// DecodedTransaction holds the decoded inbound calldata, events and error of the transaction
+// and of the transactions spawned by it.
+type DecodedTransaction struct {
+	Hash            common.Hash           `json:"hash"`
+	Call            *DecodedValue         `json:"call,omitempty"`
+	Events          []*DecodedEvent       `json:"events,omitempty"`
+	Error           *DecodedValue         `json:"error,omitempty"`
+	OutTransactions []*DecodedTransaction `json:"outTransactions,omitempty"`
+}
+
+// lookup calls find with the known ABIs and then with the ABI of the contract
+// until it succeeds.
+func (d *AbiDecoder) lookup(address types.Address, find func(contractAbi *abi.ABI) bool) {
+	for i := range d.abis {
+		if find(&d.abis[i]) {
+			return
+		}
+	}
+	if contractAbi := d.contractAbi(address); contractAbi != nil {
+		find(contractAbi)
+	}
+}
+
+func (d *AbiDecoder) contractAbi(address types.Address) *abi.ABI {
+	if d.fetch == nil || address.IsEmpty() {
+		return nil
+	}
+	if contractAbi, ok := d.fetched[address]; ok {
+		return contractAbi
+	}
+	var res *abi.ABI
+	if contractAbi, err := d.fetch(address); err == nil {
+		res = &contractAbi
+	}
+	// The failures are cached as well, the contract is most likely not verified
+	d.fetched[address] = res
+	return res
+}
+
+// DecodeCall decodes the calldata of the call of the contract, it returns nil if no method matches.
+func (d *AbiDecoder) DecodeCall(to types.Address, data []byte) *DecodedValue {
+	if len(data) < 4 {
+		return nil
+	}
+	var res *DecodedValue
+	d.lookup(to, func(contractAbi *abi.ABI) bool {
+		method, err := contractAbi.MethodById(data)
+		if err != nil {
+			return false
+		}
+		values, err := method.Inputs.Unpack(data[4:])
+		if err != nil {
+			return false
+		}
+		res = decodedValue(method.Name, method.Sig, method.Inputs, values)
+		return true
+	})
+	return res
+}
+
+// DecodeLog decodes the event log, it returns nil if no event matches.
+func (d *AbiDecoder) DecodeLog(log *types.Log) *DecodedEvent {
+	if log == nil || len(log.Topics) == 0 {
+		return nil
+	}
+	var res *DecodedEvent
+	d.lookup(log.Address, func(contractAbi *abi.ABI) bool {
+		event, err := contractAbi.EventByID(log.Topics[0])
+		if err != nil {
+			return false
+		}
+		values, err := unpackEvent(event, log)
+		if err != nil {
+			return false
+		}
+		res = &DecodedEvent{
+			Address:      log.Address,
+			DecodedValue: *decodedValue(event.Name, event.Sig, event.Inputs, values),
+		}
+		return true
+	})
+	return res
+}
+
+// unpackEvent returns the values of the event arguments, the indexed ones are taken from the topics.
+// The indexed arguments of the dynamic types are returned as their hashes.
+func unpackEvent(event *abi.Event, log *types.Log) ([]any, error) {
+	nonIndexed, err := event.Inputs.NonIndexed().Unpack(log.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]any, 0, len(event.Inputs))
+	topics := log.Topics[1:]
+	for _, input := range event.Inputs {
+		if !input.Indexed {
+			values = append(values, nonIndexed[0])
+			nonIndexed = nonIndexed[1:]
+			continue
+		}
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("missing topic of the argument %q", input.Name)
+		}
+		if input.Type.T == abi.TupleTy {
+			values = append(values, topics[0])
+		} else {
+			topic := make(map[string]any, 1)
+			if err := abi.ParseTopicsIntoMap(topic, abi.Arguments{input}, topics[:1]); err != nil {
+				return nil, err
+			}
+			values = append(values, topic[input.Name])
+		}
+		topics = topics[1:]
+	}
+	return values, nil
+}
+
+// DecodeError decodes the revert data kept in the error message of the transaction
+// executed by the contract. It returns nil if there is no revert data or no error matches.
+func (d *AbiDecoder) DecodeError(address types.Address, message string) *DecodedValue {
+	data := abi.RevertDataFromReason(message)
+	if data == nil {
+		return nil
+	}
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return &DecodedValue{
+			Name:      "Error",
+			Signature: "Error(string)",
+			Args:      []*DecodedArg{{Type: "string", Value: reason}},
+		}
+	}
+
+	var res *DecodedValue
+	d.lookup(address, func(contractAbi *abi.ABI) bool {
+		errAbi, err := contractAbi.ErrorByID([4]byte(data[:4]))
+		if err != nil {
+			return false
+		}
+		// The revert data may be truncated, the error is reported without the arguments then
+		values, err := errAbi.Inputs.Unpack(data[4:])
+		if err != nil {
+			res = &DecodedValue{Name: errAbi.Name, Signature: errAbi.Sig}
+		} else {
+			res = decodedValue(errAbi.Name, errAbi.Sig, errAbi.Inputs, values)
+		}
+		return true
+	})
+	return res
+}
+
+// DecodeTransaction decodes the inbound calldata of the transaction.
+func (d *AbiDecoder) DecodeTransaction(txn *jsonrpc.RPCInTransaction) *DecodedTransaction {
+	res := &DecodedTransaction{Hash: txn.Hash}
+	if isCall(txn.Flags) {
+		res.Call = d.DecodeCall(txn.To, txn.Data)
+	}
+	return res
+}
+
+// isCall reports whether the data of the transaction is the calldata of a contract method.
+func isCall(flags types.TransactionFlags) bool {
+	return !flags.IsDeploy() && !flags.IsRefund() && !flags.IsBounce() && !flags.IsResponse() && !flags.IsBatch()
+}
+
+func decodedValue(name, signature string, inputs abi.Arguments, values []any) *DecodedValue {
+	res := &DecodedValue{
+		Name:      name,
+		Signature: signature,
+		Args:      make([]*DecodedArg, len(inputs)),
+	}
+	for i, input := range inputs {
+		res.Args[i] = &DecodedArg{
+			Name:  input.Name,
+			Type:  input.Type.String(),
+			Value: formatDecodedValue(values[i]),
+		}
+	}
+	return res
+}
+
+// formatDecodedValue makes the byte values hex encoded in the output.
+// The named types (e.g. the addresses) are marshaled on their own.
+func formatDecodedValue(value any) any {
+	if b, ok := value.([]byte); ok {
+		return hexutil.Bytes(b)
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Array && v.Type().Name() == "" && v.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return hexutil.Bytes(b)
+	}
+	return value
+}
+
+// DecodeReceipt decodes the receipt tree: the inbound calldata of the transactions, their events and errors.
+// The transactions are fetched to get their calldata.
+func (s *Service) DecodeReceipt(receipt *jsonrpc.RPCReceipt, decoder *AbiDecoder) (*DecodedTransaction, error) {
+	txn, err := s.client.GetInTransactionByHash(s.ctx, receipt.TxnHash)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to fetch transaction")
+		return nil, err
+	}
+
+	res := &DecodedTransaction{Hash: receipt.TxnHash}
+	if txn != nil {
+		res = decoder.DecodeTransaction(txn)
+	}
+	for _, log := range receipt.Logs {
+		if log == nil {
+			continue
+		}
+		if event := decoder.DecodeLog(log.Log); event != nil {
+			res.Events = append(res.Events, event)
+		}
+	}
+	if !receipt.Success {
+		res.Error = decoder.DecodeError(receipt.ContractAddress, receipt.ErrorMessage)
+	}
+
+	for _, outReceipt := range receipt.OutReceipts {
+		if outReceipt == nil {
+			continue
+		}
+		out, err := s.DecodeReceipt(outReceipt, decoder)
+		if err != nil {
+			return nil, err
+		}
+		res.OutTransactions = append(res.OutTransactions, out)
+	}
+	return res, nil
+}
+
+// DecodeBlock decodes the calldata, the events and the errors of the in transactions of the block.
+// Only the transactions with something decoded are returned.
+func (d *AbiDecoder) DecodeBlock(block *types.BlockWithExtractedData) map[common.Hash]*DecodedTransaction {
+	receipts := make(map[common.Hash]*types.Receipt, len(block.Receipts))
+	for _, receipt := range block.Receipts {
+		receipts[receipt.TxnHash] = receipt
+	}
+
+	res := make(map[common.Hash]*DecodedTransaction)
+	for _, txn := range block.InTransactions {
+		hash := txn.Hash()
+		decoded := &DecodedTransaction{Hash: hash}
+		if isCall(txn.Flags) {
+			decoded.Call = d.DecodeCall(txn.To, txn.Data)
+		}
+		if receipt, ok := receipts[hash]; ok {
+			for _, log := range receipt.Logs {
+				if event := d.DecodeLog(log); event != nil {
+					decoded.Events = append(decoded.Events, event)
+				}
+			}
+		}
+		if message, ok := block.Errors[hash]; ok {
+			decoded.Error = d.DecodeError(txn.To, message)
+		}
+		if decoded.Call != nil || len(decoded.Events) > 0 || decoded.Error != nil {
+			res[hash] = decoded
+		}
+	}
+	return res
+}
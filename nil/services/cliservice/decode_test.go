@@ -0,0 +1,181 @@
+package cliservice
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const decodeTestAbi = `[
+	{"type": "function", "name": "transfer", "stateMutability": "nonpayable", "outputs": [],
+		"inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}]},
+	{"type": "event", "name": "Transfer", "anonymous": false, "inputs": [
+		{"name": "from", "type": "address", "indexed": true},
+		{"name": "memo", "type": "string", "indexed": true},
+		{"name": "amount", "type": "uint256", "indexed": false}]},
+	{"type": "error", "name": "Insufficient", "inputs": [{"name": "balance", "type": "uint256"}]}
+]`
+
+func decodeTestContract(t *testing.T) abi.ABI {
+	t.Helper()
+
+	contractAbi, err := abi.JSON(strings.NewReader(decodeTestAbi))
+	require.NoError(t, err)
+	return contractAbi
+}
+
+func TestAbiDecoder(t *testing.T) {
+	t.Parallel()
+
+	contractAbi := decodeTestContract(t)
+	decoder := NewAbiDecoder([]abi.ABI{contractAbi}, nil)
+	to := types.HexToAddress("0x0001111111111111111111111111111111111111")
+
+	t.Run("Call", func(t *testing.T) {
+		t.Parallel()
+
+		calldata, err := contractAbi.Pack("transfer", to, big.NewInt(5))
+		require.NoError(t, err)
+
+		call := decoder.DecodeCall(to, calldata)
+		require.NotNil(t, call)
+		assert.Equal(t, "transfer", call.Name)
+		assert.Equal(t, "transfer(address,uint256)", call.Signature)
+		require.Len(t, call.Args, 2)
+		assert.Equal(t, to, call.Args[0].Value)
+		assert.Equal(t, "transfer(to="+to.Hex()+", amount=5)", call.String())
+
+		assert.Nil(t, decoder.DecodeCall(to, []byte{1, 2, 3, 4}))
+		assert.Nil(t, decoder.DecodeCall(to, nil))
+	})
+
+	t.Run("Log", func(t *testing.T) {
+		t.Parallel()
+
+		event := contractAbi.Events["Transfer"]
+		data, err := event.Inputs.NonIndexed().Pack(big.NewInt(7))
+		require.NoError(t, err)
+		memoHash := common.HexToHash("0x1234")
+		log := &types.Log{
+			Address: to,
+			Topics:  []common.Hash{event.ID, common.BytesToHash(to.Bytes()), memoHash},
+			Data:    data,
+		}
+
+		decoded := decoder.DecodeLog(log)
+		require.NotNil(t, decoded)
+		assert.Equal(t, to, decoded.Address)
+		assert.Equal(t, "Transfer", decoded.Name)
+		require.Len(t, decoded.Args, 3)
+		assert.Equal(t, to, decoded.Args[0].Value)
+		assert.Equal(t, memoHash, decoded.Args[1].Value)
+		assert.Equal(t, big.NewInt(7), decoded.Args[2].Value)
+
+		assert.Nil(t, decoder.DecodeLog(&types.Log{Topics: []common.Hash{common.HexToHash("0x01")}}))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		errAbi := contractAbi.Errors["Insufficient"]
+		args, err := errAbi.Inputs.Pack(big.NewInt(3))
+		require.NoError(t, err)
+		message := "ExecutionReverted: " + abi.RevertReason(append(errAbi.ID[:4:4], args...))
+
+		decoded := decoder.DecodeError(to, message)
+		require.NotNil(t, decoded)
+		assert.Equal(t, "Insufficient(balance=3)", decoded.String())
+
+		// The truncated revert data is decoded without the arguments
+		decoded = decoder.DecodeError(to, "ExecutionReverted: revert data: 0x"+errAbi.ID.Hex()[2:10])
+		require.NotNil(t, decoded)
+		assert.Equal(t, "Insufficient", decoded.Name)
+		assert.Empty(t, decoded.Args)
+
+		assert.Nil(t, decoder.DecodeError(to, "ExecutionReverted: Value must be non-zero"))
+		assert.Nil(t, decoder.DecodeError(to, "ExecutionReverted: revert data: 0x01020304"))
+	})
+}
+
+func TestAbiDecoderFetch(t *testing.T) {
+	t.Parallel()
+
+	contractAbi := decodeTestContract(t)
+	verified := types.HexToAddress("0x0001111111111111111111111111111111111111")
+	unknown := types.HexToAddress("0x0001222222222222222222222222222222222222")
+
+	fetched := make(map[types.Address]int)
+	decoder := NewAbiDecoder(nil, func(address types.Address) (abi.ABI, error) {
+		fetched[address]++
+		if address != verified {
+			return abi.ABI{}, errors.New("not found")
+		}
+		return contractAbi, nil
+	})
+
+	calldata, err := contractAbi.Pack("transfer", verified, big.NewInt(1))
+	require.NoError(t, err)
+
+	for range 2 {
+		require.NotNil(t, decoder.DecodeCall(verified, calldata))
+		require.Nil(t, decoder.DecodeCall(unknown, calldata))
+	}
+	// Both the found and the missing ABIs are fetched once
+	assert.Equal(t, map[types.Address]int{verified: 1, unknown: 1}, fetched)
+}
+
+func TestDecodeBlock(t *testing.T) {
+	t.Parallel()
+
+	contractAbi := decodeTestContract(t)
+	decoder := NewAbiDecoder([]abi.ABI{contractAbi}, nil)
+	to := types.HexToAddress("0x0001111111111111111111111111111111111111")
+
+	calldata, err := contractAbi.Pack("transfer", to, big.NewInt(5))
+	require.NoError(t, err)
+	call := &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			Flags: types.TransactionFlagsFromKind(true, types.ExecutionTransactionKind),
+			To:    to,
+			Data:  calldata,
+		},
+	}
+	deploy := &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			Flags: types.TransactionFlagsFromKind(true, types.DeployTransactionKind),
+			To:    to,
+			Data:  calldata,
+		},
+	}
+	errAbi := contractAbi.Errors["Insufficient"]
+	args, err := errAbi.Inputs.Pack(big.NewInt(3))
+	require.NoError(t, err)
+
+	block := &types.BlockWithExtractedData{
+		Block:          &types.Block{},
+		InTransactions: []*types.Transaction{call, deploy},
+		Receipts:       []*types.Receipt{{TxnHash: call.Hash()}, {TxnHash: deploy.Hash()}},
+		Errors: map[common.Hash]string{
+			call.Hash(): "ExecutionReverted: " + abi.RevertReason(append(errAbi.ID[:4:4], args...)),
+		},
+	}
+
+	decoded := decoder.DecodeBlock(block)
+	require.Len(t, decoded, 1)
+	require.Contains(t, decoded, call.Hash())
+	assert.Equal(t, "transfer", decoded[call.Hash()].Call.Name)
+	assert.Equal(t, "Insufficient", decoded[call.Hash()].Error.Name)
+
+	s := &Service{}
+	text, err := s.debugBlockToText(types.ShardId(1), block, decoded, false, false)
+	require.NoError(t, err)
+	assert.Contains(t, string(text), "Call: transfer(to="+to.Hex()+", amount=5)")
+	assert.Contains(t, string(text), "Decoded error: Insufficient(balance=3)")
+}
@@ -23,7 +23,11 @@ const (
 	ReceiptWaitTick = 200 * time.Millisecond
 )
 
-var ErrSmartAccountExists = errors.New("smart account already exists")
+var (
+	ErrSmartAccountExists = errors.New("smart account already exists")
+	// ErrTransactionFailed is returned if the transaction or a transaction spawned by it failed
+	ErrTransactionFailed = errors.New("transaction failed")
+)
 
 func collectFailedReceipts(dst []*jsonrpc.RPCReceipt, receipt *jsonrpc.RPCReceipt) []*jsonrpc.RPCReceipt {
 	if !receipt.Success {
@@ -180,7 +184,7 @@ func (s *Service) CreateSmartAccount(
 		return types.EmptyAddress, errors.New("deploy transaction processing failed")
 	}
 	if !res.AllSuccess() {
-		return types.EmptyAddress, fmt.Errorf("deploy transaction processing failed: %s", res.ErrorMessage)
+		return types.EmptyAddress, fmt.Errorf("deploy transaction processing failed: %w: %s", ErrTransactionFailed, res.ErrorMessage)
 	}
 	return addr, nil
 }
@@ -1,6 +1,10 @@
 package cliservice
 
 import (
+	"crypto/ecdsa"
+	"errors"
+
+	"github.com/NilFoundation/nil/nil/client"
 	nilcrypto "github.com/NilFoundation/nil/nil/internal/crypto"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -14,6 +18,7 @@ func (s *Service) GenerateNewKey() error {
 	}
 
 	s.privateKey = privateKey
+	s.signer = client.NewPrivateKeySigner(privateKey)
 	return nil
 }
 
@@ -25,6 +30,7 @@ func (s *Service) GenerateKeyFromHex(hexKey string) error {
 	}
 
 	s.privateKey = privateKey
+	s.signer = client.NewPrivateKeySigner(privateKey)
 	return nil
 }
 
@@ -33,6 +39,14 @@ func (s *Service) GetPrivateKey() string {
 	return nilcrypto.PrivateKeyToEthereumFormat(s.privateKey)
 }
 
+// GetPublicKey returns the public key of the account signing the transactions
+func (s *Service) GetPublicKey() (*ecdsa.PublicKey, error) {
+	if s.signer == nil {
+		return nil, errors.New("signer is not set")
+	}
+	return s.signer.PublicKey(s.ctx)
+}
+
 // GenerateNewKey generates a new private key
 func (s *Service) GenerateNewP2pKey() ([]byte, []byte, string, error) {
 	privateKey, err := network.GeneratePrivateKey()
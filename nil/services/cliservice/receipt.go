@@ -2,7 +2,10 @@ package cliservice
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
+	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 )
@@ -30,3 +33,60 @@ func (s *Service) FetchReceiptByHashJson(hash common.Hash) ([]byte, error) {
 	}
 	return receiptDataJSON, nil
 }
+
+// FetchDecodedReceiptByHashJson fetches the transaction receipt as a JSON string
+// with the decoded calldata, events and errors of the receipt tree in the "decoded" field
+func (s *Service) FetchDecodedReceiptByHashJson(hash common.Hash, decoder *AbiDecoder) ([]byte, error) {
+	receipt, err := s.FetchReceiptByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	var decoded *DecodedTransaction
+	if receipt != nil {
+		if decoded, err = s.DecodeReceipt(receipt, decoder); err != nil {
+			return nil, err
+		}
+	}
+	receiptDataJSON, err := json.MarshalIndent(struct {
+		*jsonrpc.RPCReceipt
+		Decoded *DecodedTransaction `json:"decoded,omitempty"`
+	}{receipt, decoded}, "", "  ")
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to marshal receipt data to JSON")
+		return nil, err
+	}
+	return receiptDataJSON, nil
+}
+
+// WaitForTransactionTree waits until the transaction and all transactions spawned by it are processed
+func (s *Service) WaitForTransactionTree(txnHash common.Hash) (*client.TransactionTree, error) {
+	tree, err := s.client.WaitForTransactionTree(s.ctx, txnHash, &client.WaitOptions{PollInterval: ReceiptWaitTick})
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Error during waiting for transaction tree")
+		return nil, err
+	}
+	return tree, nil
+}
+
+// TransactionTreeToString returns the summary of the processed transaction tree
+func TransactionTreeToString(tree *client.TransactionTree) string {
+	var sb strings.Builder
+	status := "success"
+	if !tree.Success {
+		status = "failed"
+	}
+	fmt.Fprintf(&sb, "Transactions: %d, %s\n", len(tree.Receipts), status)
+	fmt.Fprintf(&sb, "Gas used: %d\n", tree.GasUsed)
+	fmt.Fprintf(&sb, "Fees: %s\n", tree.Fees)
+	if failure := tree.FirstFailure; failure != nil {
+		fmt.Fprintf(&sb, "First failure: %s in shard %d, %s", failure.TxnHash, failure.ShardId, failure.Status)
+		if failure.ErrorMessage != "" {
+			fmt.Fprintf(&sb, ": %s", failure.ErrorMessage)
+		}
+		sb.WriteString("\n")
+	}
+	for _, bounced := range tree.Bounced {
+		fmt.Fprintf(&sb, "Bounced: %s to %s in %s\n", bounced.Value, bounced.To, bounced.TxnHash)
+	}
+	return sb.String()
+}
@@ -15,20 +15,27 @@ type Service struct {
 	ctx          context.Context
 	client       client.Client
 	privateKey   *ecdsa.PrivateKey
+	signer       client.Signer
 	logger       zerolog.Logger
 	faucetClient *faucet.Client
 }
 
 // NewService initializes a new Service with the given client
 func NewService(ctx context.Context, c client.Client, privateKey *ecdsa.PrivateKey, fc *faucet.Client) *Service {
-	s := &Service{
+	s := NewServiceWithSigner(ctx, c, client.NewPrivateKeySigner(privateKey), fc)
+
+	s.privateKey = privateKey
+
+	return s
+}
+
+// NewServiceWithSigner initializes a new Service signing the transactions with the given signer
+func NewServiceWithSigner(ctx context.Context, c client.Client, signer client.Signer, fc *faucet.Client) *Service {
+	return &Service{
 		ctx:          ctx,
 		client:       c,
+		signer:       signer,
 		faucetClient: fc,
 		logger:       logging.NewLogger("cliservice"),
 	}
-
-	s.privateKey = privateKey
-
-	return s
 }
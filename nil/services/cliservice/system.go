@@ -2,6 +2,7 @@ package cliservice
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
@@ -16,6 +17,22 @@ func ShardsToString(list []types.ShardId) string {
 	return str
 }
 
+func ResourceUsageToString(usage *types.ResourceUsage) string {
+	var sb strings.Builder
+	for _, c := range usage.Contracts {
+		fmt.Fprintf(&sb, "  * %s: gas %d, transactions %d, slots +%d/-%d\n",
+			c.Address, c.GasUsed, c.Transactions, c.SlotsCreated, c.SlotsDeleted)
+		for _, sel := range c.Selectors {
+			name := "<none>"
+			if len(sel.Selector) > 0 {
+				name = sel.Selector.Hex()
+			}
+			fmt.Fprintf(&sb, "      %s: gas %d, transactions %d\n", name, sel.GasUsed, sel.Transactions)
+		}
+	}
+	return sb.String()
+}
+
 func (s *Service) GetShards() ([]types.ShardId, error) {
 	list, err := s.client.GetShardIdList(s.ctx)
 	if err != nil {
@@ -46,3 +63,29 @@ func (s *Service) GetChainId() (types.ChainId, error) {
 	s.logger.Info().Msgf("ChainId: %d", value)
 	return value, nil
 }
+
+func (s *Service) GetLatestBlockNumber(shardId types.ShardId) (types.BlockNumber, error) {
+	block, err := s.client.GetBlock(s.ctx, shardId, "latest", false)
+	if err != nil {
+		return 0, err
+	}
+	return block.Number, nil
+}
+
+// GetTopContracts returns the contracts using the most gas in the given block range, limited to the given number.
+// The usage is available only for the blocks produced by the nodes with the resource usage recording enabled.
+func (s *Service) GetTopContracts(
+	shardId types.ShardId, fromBlock, toBlock types.BlockNumber, limit int,
+) (*types.ResourceUsage, error) {
+	usage, err := s.client.GetResourceUsage(s.ctx, shardId, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(usage.Contracts) > limit {
+		usage.Contracts = usage.Contracts[:limit]
+	}
+
+	s.logger.Info().Msgf("Top contracts of shard %d in blocks %d-%d:", shardId, fromBlock, toBlock)
+	s.logger.Info().Msg(ResourceUsageToString(usage))
+	return usage, nil
+}
@@ -1,6 +1,9 @@
 package cliservice
 
 import (
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/types"
@@ -12,16 +15,19 @@ func (s *Service) handleTokenTx(txHash common.Hash, contractAddr types.Address)
 		Stringer(logging.FieldTransactionHash, txHash).
 		Send()
 
-	_, err := s.WaitForReceipt(txHash)
+	receipt, err := s.WaitForReceipt(txHash)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to wait for token transaction receipt")
 		return err
 	}
+	if !receipt.AllSuccess() {
+		return fmt.Errorf("%w: %s %s", ErrTransactionFailed, txHash, receipt.ErrorMessage)
+	}
 	return nil
 }
 
 func (s *Service) TokenCreate(contractAddr types.Address, amount types.Value, name string) (*types.TokenId, error) {
-	txHash, err := s.client.SetTokenName(s.ctx, contractAddr, name, s.privateKey)
+	txHash, err := client.SetTokenName(s.ctx, s.client, contractAddr, name, s.signer)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to send setTokenName transaction")
 		return nil, err
@@ -30,7 +36,7 @@ func (s *Service) TokenCreate(contractAddr types.Address, amount types.Value, na
 		return nil, err
 	}
 
-	txHash, err = s.client.ChangeTokenAmount(s.ctx, contractAddr, amount, s.privateKey, true /* mint */)
+	txHash, err = client.ChangeTokenAmount(s.ctx, s.client, contractAddr, amount, s.signer, true /* mint */)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to send minToken transaction")
 		return nil, err
@@ -45,7 +51,7 @@ func (s *Service) TokenCreate(contractAddr types.Address, amount types.Value, na
 }
 
 func (s *Service) ChangeTokenAmount(contractAddr types.Address, amount types.Value, mint bool) (common.Hash, error) {
-	txHash, err := s.client.ChangeTokenAmount(s.ctx, contractAddr, amount, s.privateKey, mint)
+	txHash, err := client.ChangeTokenAmount(s.ctx, s.client, contractAddr, amount, s.signer, mint)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to send transaction for token amount change")
 		return common.EmptyHash, err
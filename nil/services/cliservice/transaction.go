@@ -25,6 +25,30 @@ func (s *Service) FetchTransactionByHashJson(hash common.Hash) ([]byte, error) {
 	return transactionDataJSON, nil
 }
 
+// FetchDecodedTransactionByHashJson fetches the transaction by hash
+// with its decoded calldata in the "decoded" field
+func (s *Service) FetchDecodedTransactionByHashJson(hash common.Hash, decoder *AbiDecoder) ([]byte, error) {
+	transactionData, err := s.FetchTransactionByHash(hash)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to fetch transaction")
+		return nil, err
+	}
+	var decoded *DecodedTransaction
+	if transactionData != nil {
+		decoded = decoder.DecodeTransaction(transactionData)
+	}
+
+	transactionDataJSON, err := json.MarshalIndent(struct {
+		*jsonrpc.RPCInTransaction
+		Decoded *DecodedTransaction `json:"decoded,omitempty"`
+	}{transactionData, decoded}, "", "  ")
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to marshal transaction data to JSON")
+		return nil, err
+	}
+	return transactionDataJSON, nil
+}
+
 func (s *Service) FetchTransactionByHash(hash common.Hash) (*jsonrpc.RPCInTransaction, error) {
 	return s.client.GetInTransactionByHash(s.ctx, hash)
 }
@@ -11,6 +11,7 @@ import (
 	"sort"
 
 	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/fabelx/go-solc-select/pkg/config"
 	"github.com/fabelx/go-solc-select/pkg/installer"
 	"github.com/fabelx/go-solc-select/pkg/versions"
@@ -45,6 +46,10 @@ type ContractData struct {
 	// Code holds runtime bytecode which is stored in blockchain.
 	Code []byte `json:"code,omitempty"`
 
+	// DeployedCode holds the runtime bytecode deployed at the registered address. Unlike Code, it includes
+	// the values of the immutables and the metadata of the deployed contract. Twin contracts are found by its hash.
+	DeployedCode []byte `json:"deployedCode,omitempty"`
+
 	// SourceFilesList holds a list of source files, ordered as referred to in debug entities like sourceMap.
 	// The file ID in sourceMap corresponds to the index in this array.
 	SourceFilesList []string `json:"sourceFilesList,omitempty"`
@@ -55,6 +60,22 @@ type ContractData struct {
 
 	// MethodIdentifiers holds a map of method identifiers: {signature -> methodId}. E.g. "test(uint256)": "29e99f07"
 	MethodIdentifiers map[string]string `json:"methodIdentifiers,omitempty"`
+
+	// ImmutableReferences holds the ranges of Code with the values of the immutable variables, sorted by start.
+	// The compiler leaves them zeroed, they are filled at the deployment.
+	ImmutableReferences []CodeRange `json:"immutableReferences,omitempty"`
+
+	// Match holds the result of the verification of the deployed code: "full" or "partial".
+	Match MatchStatus `json:"match,omitempty"`
+}
+
+// twinCode returns the code by which the twin contracts are found.
+// The compiled code is used for the contracts registered without the deployed code.
+func (c *ContractData) twinCode() types.Code {
+	if len(c.DeployedCode) != 0 {
+		return c.DeployedCode
+	}
+	return c.Code
 }
 
 func NewCompilerTask(inputJson string) (*CompilerTask, error) {
@@ -215,6 +236,12 @@ func CreateContractData(input *CompilerJsonInput, outputJson *CompilerJsonOutput
 	}
 	contractData.Abi = string(abiJson)
 	contractData.MethodIdentifiers = contractDescr.Evm.MethodIdentifiers
+	for _, refs := range contractDescr.Evm.DeployedBytecode.ImmutableReferences {
+		contractData.ImmutableReferences = append(contractData.ImmutableReferences, refs...)
+	}
+	sort.Slice(contractData.ImmutableReferences, func(i, j int) bool {
+		return contractData.ImmutableReferences[i].Start < contractData.ImmutableReferences[j].Start
+	})
 
 	return contractData, nil
 }
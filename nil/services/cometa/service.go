@@ -134,6 +134,8 @@ func (s *Service) Run(ctx context.Context, cfg *Config) error {
 	return s.startRpcServer(ctx, cfg.OwnEndpoint)
 }
 
+// RegisterContractData verifies the compiled contract against the code deployed at the address and stores it.
+// The match status and the deployed code are set in contractData, the compiled code is kept as is.
 func (s *Service) RegisterContractData(ctx context.Context, contractData *ContractData, address types.Address) error {
 	logger.Info().Msg("Register contract...")
 	code, err := s.client.GetCode(ctx, address, "latest")
@@ -144,15 +146,18 @@ func (s *Service) RegisterContractData(ctx context.Context, contractData *Contra
 		return fmt.Errorf("contract does not exist at address %s", address)
 	}
 
-	if !bytes.Equal(code, contractData.Code) {
-		return errors.New("compiled bytecode is not equal to the deployed one")
+	match, err := VerifyCode(code, contractData.Code, contractData.ImmutableReferences)
+	if err != nil {
+		return err
 	}
+	contractData.Match = match
+	contractData.DeployedCode = code
 
 	if err = s.storage.StoreContract(ctx, contractData, address); err != nil {
 		return err
 	}
 
-	logger.Info().Msg("Contract has been deployed.")
+	logger.Info().Str("match", string(match)).Msg("Contract has been deployed.")
 
 	return nil
 }
@@ -248,7 +253,7 @@ func (s *Service) FindContractWithSameCode(ctx context.Context, address types.Ad
 	if err != nil {
 		return nil, fmt.Errorf("failed to get contract by code hash: %w", err)
 	}
-	if !bytes.Equal(contractData.Code, code) {
+	if !bytes.Equal(contractData.twinCode(), code) {
 		return nil, errors.New("contract not found")
 	}
 	return contractData, nil
@@ -270,7 +275,23 @@ func (s *Service) GetLocationRaw(ctx context.Context, address types.Address, pc
 	return contract.GetLocationRaw(pc)
 }
 
+// GetAbi returns the ABI of the contract. The ABI of the implementation is returned for EIP-1967 proxies
+// if the implementation is registered.
 func (s *Service) GetAbi(ctx context.Context, address types.Address) (string, error) {
+	implementation, err := s.proxyImplementation(ctx, address)
+	if err != nil {
+		logger.Warn().Err(err).Stringer("address", address).Msg("Failed to check the proxy implementation")
+	}
+	if implementation != types.EmptyAddress {
+		if res, err := s.getAbi(ctx, implementation); err == nil {
+			return res, nil
+		}
+		logger.Debug().Stringer("implementation", implementation).Msg("Proxy implementation is not registered")
+	}
+	return s.getAbi(ctx, address)
+}
+
+func (s *Service) getAbi(ctx context.Context, address types.Address) (string, error) {
 	res, err := s.storage.GetAbi(ctx, address)
 	if err == nil {
 		return res, nil
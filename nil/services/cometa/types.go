@@ -97,13 +97,22 @@ type EvmOutput struct {
 }
 
 type CompilerOutputEvm struct {
-	Object              string            `json:"object,omitempty"`
-	Opcodes             string            `json:"opcodes,omitempty"`
-	SourceMap           string            `json:"sourceMap,omitempty"`
-	LinkReferences      any               `json:"linkReferences,omitempty"`
-	ImmutableReferences any               `json:"immutableReferences,omitempty"`
-	FunctionDebugData   FunctionDebugData `json:"functionDebugData"`
-	GeneratedSources    []GeneratedSource `json:"generatedSources,omitempty"`
+	Object              string              `json:"object,omitempty"`
+	Opcodes             string              `json:"opcodes,omitempty"`
+	SourceMap           string              `json:"sourceMap,omitempty"`
+	LinkReferences      any                 `json:"linkReferences,omitempty"`
+	ImmutableReferences ImmutableReferences `json:"immutableReferences,omitempty"`
+	FunctionDebugData   FunctionDebugData   `json:"functionDebugData"`
+	GeneratedSources    []GeneratedSource   `json:"generatedSources,omitempty"`
+}
+
+// ImmutableReferences holds the ranges of the code filled with the values of the immutable variables
+// at the deployment: {astId -> ranges}.
+type ImmutableReferences map[string][]CodeRange
+
+type CodeRange struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
 }
 
 type GeneratedSource struct {
@@ -226,6 +235,7 @@ func (t *CompilerTask) ToCompilerJsonInput() (*CompilerJsonInput, error) {
 				"evm.deployedBytecode.sourceMap",
 				"evm.deployedBytecode.generatedSources",
 				"evm.deployedBytecode.functionDebugData",
+				"evm.deployedBytecode.immutableReferences",
 				"evm.methodIdentifiers",
 			},
 		},
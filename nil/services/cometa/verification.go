@@ -0,0 +1,107 @@
+package cometa
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+type MatchStatus string
+
+const (
+	// FullMatch means that the deployed code is equal to the compiled one up to the values of the immutables.
+	FullMatch MatchStatus = "full"
+	// PartialMatch means that the codes differ only in the metadata appended by the compiler,
+	// e.g. because the source files had other paths.
+	PartialMatch MatchStatus = "partial"
+)
+
+var ErrCodeMismatch = errors.New("compiled bytecode is not equal to the deployed one")
+
+// Eip1967ImplementationSlot is the storage slot holding the implementation address of EIP-1967 proxies:
+// bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1).
+var Eip1967ImplementationSlot = common.HexToHash(
+	"0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// VerifyCode compares the deployed runtime code with the compiled one. The immutable ranges of the compiled code
+// are ignored. If the codes differ, they are compared without the CBOR-encoded metadata at the end of the code.
+func VerifyCode(deployed, compiled []byte, immutables []CodeRange) (MatchStatus, error) {
+	deployed = maskImmutables(deployed, immutables)
+	compiled = maskImmutables(compiled, immutables)
+	if bytes.Equal(deployed, compiled) {
+		return FullMatch, nil
+	}
+	if bytes.Equal(stripMetadata(deployed), stripMetadata(compiled)) {
+		return PartialMatch, nil
+	}
+	return "", ErrCodeMismatch
+}
+
+// maskImmutables returns the copy of the code with the immutable ranges zeroed.
+// The ranges out of the code are skipped, the codes do not match then anyway.
+func maskImmutables(code []byte, immutables []CodeRange) []byte {
+	if len(immutables) == 0 {
+		return code
+	}
+	masked := bytes.Clone(code)
+	for _, r := range immutables {
+		if r.Start < 0 || r.Length < 0 || r.Start+r.Length > len(masked) {
+			continue
+		}
+		clear(masked[r.Start : r.Start+r.Length])
+	}
+	return masked
+}
+
+// stripMetadata returns the code without the metadata appended by the compiler.
+// The metadata is a CBOR map followed by its length as a two-byte big-endian integer.
+// The code is returned as is if it does not end with the metadata.
+func stripMetadata(code []byte) []byte {
+	if len(code) < 2 {
+		return code
+	}
+	length := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	start := len(code) - 2 - length
+	if length == 0 || start < 0 {
+		return code
+	}
+	// The major type of CBOR maps is 5, i.e. the first byte is 0xa0-0xbf
+	if code[start]&0xe0 != 0xa0 {
+		return code
+	}
+	return code[:start]
+}
+
+// isEip1967Proxy reports whether the code uses the EIP-1967 implementation slot.
+// The compiler inlines the slot constant, so it is found in the code of such proxies.
+func isEip1967Proxy(code []byte) bool {
+	return bytes.Contains(code, Eip1967ImplementationSlot.Bytes())
+}
+
+// proxyImplementation returns the implementation address of the EIP-1967 proxy at the address.
+// The empty address is returned if the contract is not a proxy or the implementation is not set.
+func (s *Service) proxyImplementation(ctx context.Context, address types.Address) (types.Address, error) {
+	code, err := s.client.GetCode(ctx, address, "latest")
+	if err != nil {
+		return types.EmptyAddress, fmt.Errorf("failed to get code: %w", err)
+	}
+	if !isEip1967Proxy(code) {
+		return types.EmptyAddress, nil
+	}
+
+	contract, err := s.client.GetDebugContract(ctx, address, "latest")
+	if err != nil {
+		return types.EmptyAddress, fmt.Errorf("failed to get contract storage: %w", err)
+	}
+	value, ok := contract.Storage[Eip1967ImplementationSlot]
+	if !ok {
+		return types.EmptyAddress, nil
+	}
+	slot := value.Bytes32()
+	return types.BytesToAddress(slot[:]), nil
+}
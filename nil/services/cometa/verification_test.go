@@ -0,0 +1,165 @@
+package cometa
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runtime code with the immutable at [3, 5) and the metadata {"solc": 0x000818} at the end
+const (
+	testCode     = "0x600061000050" + "a164736f6c6343000818" + "000a"
+	testDeployed = "0x600061002a50" + "a164736f6c6343000818" + "000a"
+	testOtherMd  = "0x600061002a50" + "a164736f6c6343000819" + "000a"
+)
+
+func TestVerifyCode(t *testing.T) {
+	t.Parallel()
+
+	compiled := hexutil.MustDecode(testCode)
+	immutables := []CodeRange{{Start: 3, Length: 2}}
+
+	for name, test := range map[string]struct {
+		deployed   string
+		immutables []CodeRange
+		match      MatchStatus
+	}{
+		"Full": {
+			deployed: testCode,
+			match:    FullMatch,
+		},
+		"Immutables": {
+			deployed:   testDeployed,
+			immutables: immutables,
+			match:      FullMatch,
+		},
+		"Metadata": {
+			deployed:   testOtherMd,
+			immutables: immutables,
+			match:      PartialMatch,
+		},
+		"NoMetadata": {
+			deployed:   "0x600061002a50",
+			immutables: immutables,
+			match:      PartialMatch,
+		},
+		"UnmaskedImmutables": {
+			deployed: testDeployed,
+		},
+		"OtherCode": {
+			deployed:   "0x600161002a50" + "a164736f6c6343000818" + "000a",
+			immutables: immutables,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			match, err := VerifyCode(hexutil.MustDecode(test.deployed), compiled, test.immutables)
+			if test.match == "" {
+				require.ErrorIs(t, err, ErrCodeMismatch)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.match, match)
+		})
+	}
+}
+
+func TestStripMetadata(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, hexutil.MustDecode("0x600061000050"), stripMetadata(hexutil.MustDecode(testCode)))
+
+	// the length points outside the code
+	code := hexutil.MustDecode("0x6000ffff")
+	assert.Equal(t, code, stripMetadata(code))
+
+	// the data before the length is not a CBOR map
+	code = hexutil.MustDecode("0x6000600050010002")
+	assert.Equal(t, code, stripMetadata(code))
+}
+
+func TestProxyAbi(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var clientMock client.ClientMock
+	service, err := NewService(ctx, &Config{UseBadger: true, DbPath: t.TempDir() + "/cometa.db"}, &clientMock)
+	require.NoError(t, err)
+
+	proxy := types.HexToAddress("0x0001000000000000000000000000000000000001")
+	implementation := types.HexToAddress("0x0001000000000000000000000000000000000002")
+	implementationData := &ContractData{
+		Abi:      `[{"type":"function","name":"test"}]`,
+		Metadata: `{"compiler":{"version":"0.8.24"},"version":1}`,
+		Code:     hexutil.MustDecode(testCode),
+	}
+	require.NoError(t, service.storage.StoreContract(ctx, implementationData, implementation))
+
+	// PUSH32 slot SLOAD
+	proxyCode := append(append([]byte{0x7f}, Eip1967ImplementationSlot.Bytes()...), 0x54)
+	clientMock.GetCodeFunc = func(ctx context.Context, addr types.Address, blockId any) (types.Code, error) {
+		if addr == proxy {
+			return proxyCode, nil
+		}
+		return implementationData.Code, nil
+	}
+	clientMock.GetDebugContractFunc = func(
+		ctx context.Context, addr types.Address, blockId any,
+	) (*jsonrpc.DebugRPCContract, error) {
+		require.Equal(t, proxy, addr)
+		return &jsonrpc.DebugRPCContract{
+			Storage: map[common.Hash]types.Uint256{
+				Eip1967ImplementationSlot: *types.NewUint256FromBytes(implementation.Bytes()),
+			},
+		}, nil
+	}
+
+	res, err := service.GetAbi(ctx, proxy)
+	require.NoError(t, err)
+	assert.Equal(t, implementationData.Abi, res)
+
+	res, err = service.GetAbi(ctx, implementation)
+	require.NoError(t, err)
+	assert.Equal(t, implementationData.Abi, res)
+	assert.Len(t, clientMock.GetDebugContractCalls(), 1)
+}
+
+func TestRegisterContractWithImmutables(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var clientMock client.ClientMock
+	service, err := NewService(ctx, &Config{UseBadger: true, DbPath: t.TempDir() + "/cometa.db"}, &clientMock)
+	require.NoError(t, err)
+
+	address := types.HexToAddress("0x0001000000000000000000000000000000000001")
+	twin := types.HexToAddress("0x0001000000000000000000000000000000000002")
+	clientMock.GetCodeFunc = func(ctx context.Context, addr types.Address, blockId any) (types.Code, error) {
+		return hexutil.MustDecode(testDeployed), nil
+	}
+
+	contractData := &ContractData{
+		Code:                hexutil.MustDecode(testCode),
+		ImmutableReferences: []CodeRange{{Start: 3, Length: 2}},
+	}
+	require.NoError(t, service.RegisterContractData(ctx, contractData, address))
+
+	stored, err := service.storage.LoadContractData(ctx, address)
+	require.NoError(t, err)
+	assert.Equal(t, FullMatch, stored.Match)
+	assert.Equal(t, hexutil.MustDecode(testCode), stored.Code, "the compiled code is stored as is")
+	assert.Equal(t, hexutil.MustDecode(testDeployed), stored.DeployedCode)
+
+	// the twin is found by the deployed code
+	found, err := service.FindContractWithSameCode(ctx, twin)
+	require.NoError(t, err)
+	assert.Equal(t, stored, found)
+}
@@ -0,0 +1,96 @@
+package nilservice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/collate"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/admin"
+	"github.com/rs/zerolog"
+)
+
+func startAdminServer(
+	ctx context.Context, cfg *Config, database db.DB, networkManager *network.Manager,
+	collators map[types.ShardId]*collate.Scheduler,
+) error {
+	logger := logging.NewLogger("admin")
+	config := &admin.ServerConfig{
+		Enabled:        cfg.AdminSocketPath != "",
+		UnixSocketPath: cfg.AdminSocketPath,
+		DB:             database,
+		NodeConfig:     cfg,
+	}
+	if len(collators) > 0 {
+		config.Collation = collationControl(collators)
+	}
+	if networkManager != nil && cfg.Network != nil {
+		config.Peers = &peersReloader{cfg: cfg.Network, networkManager: networkManager, logger: logger}
+	}
+	return admin.StartAdminServer(ctx, config, logger)
+}
+
+// collationControl pauses and resumes the collators running in the node.
+type collationControl map[types.ShardId]*collate.Scheduler
+
+var _ admin.CollationController = collationControl(nil)
+
+func (c collationControl) collator(shardId types.ShardId) (*collate.Scheduler, error) {
+	collator, ok := c[shardId]
+	if !ok {
+		return nil, fmt.Errorf("shard %s is not collated by the node", shardId)
+	}
+	return collator, nil
+}
+
+func (c collationControl) PauseCollation(shardId types.ShardId) error {
+	collator, err := c.collator(shardId)
+	if err != nil {
+		return err
+	}
+	collator.Pause()
+	return nil
+}
+
+func (c collationControl) ResumeCollation(shardId types.ShardId) error {
+	collator, err := c.collator(shardId)
+	if err != nil {
+		return err
+	}
+	collator.Resume()
+	return nil
+}
+
+func (c collationControl) CollationStatus() map[types.ShardId]bool {
+	status := make(map[types.ShardId]bool, len(c))
+	for shardId, collator := range c {
+		status[shardId] = collator.IsPaused()
+	}
+	return status
+}
+
+// peersReloader reconnects the node to the DHT bootstrap peers.
+type peersReloader struct {
+	mu             sync.Mutex
+	cfg            *network.Config
+	networkManager *network.Manager
+	logger         zerolog.Logger
+}
+
+var _ admin.PeersReloader = (*peersReloader)(nil)
+
+func (r *peersReloader) ReloadPeers(ctx context.Context, peers network.AddrInfoSlice) (network.AddrInfoSlice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(peers) > 0 {
+		r.cfg.DHTBootstrapPeers = peers
+	}
+	r.logger.Info().Int("peers", len(r.cfg.DHTBootstrapPeers)).Msg("Reconnecting to bootstrap peers...")
+	network.ConnectToPeers(ctx, r.cfg.DHTBootstrapPeers, *r.networkManager, r.logger)
+	return r.cfg.DHTBootstrapPeers, nil
+}
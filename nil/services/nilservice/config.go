@@ -1,6 +1,7 @@
 package nilservice
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"slices"
@@ -12,10 +13,12 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/keys"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/remotesigner"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/cometa"
 	"github.com/NilFoundation/nil/nil/services/rollup"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 )
 
 var Logger = logging.NewLogger("config")
@@ -43,6 +46,9 @@ type Config struct {
 	RPCPort        int                   `yaml:"rpcPort,omitempty"`
 	BootstrapPeers network.AddrInfoSlice `yaml:"bootstrapPeers,omitempty"`
 
+	// RpcAccess restricts the API served to the clients, e.g. of a public RPC node
+	RpcAccess httpcfg.AccessCfg `yaml:"rpcAccess,omitempty"`
+
 	// Profiling
 	PprofPort int `yaml:"pprofPort,omitempty"`
 
@@ -52,12 +58,28 @@ type Config struct {
 	// RPC events log
 	LogClientRpcEvents bool `yaml:"logClientRpcEvents,omitempty"`
 
+	// RecordStateDiff enables storing the state diff of every block for debug_getStateDiff
+	RecordStateDiff bool `yaml:"recordStateDiff,omitempty"`
+
+	// RecordResourceUsage enables storing the gas and storage used by the contracts in every block
+	// for debug_getResourceUsage
+	RecordResourceUsage bool `yaml:"recordResourceUsage,omitempty"`
+
+	// ParallelExecution enables the optimistic parallel execution of the transactions within a block of a shard
+	ParallelExecution bool `yaml:"parallelExecution,omitempty"`
+
 	// Keys
 	MainKeysPath         string                     `yaml:"mainKeysPath,omitempty"`
 	NetworkKeysPath      string                     `yaml:"networkKeysPath,omitempty"`
 	ValidatorKeysPath    string                     `yaml:"validatorKeysPath,omitempty"`
 	ValidatorKeysManager *keys.ValidatorKeysManager `yaml:"-"`
 
+	// RemoteSigner is the endpoint of the signer holding the validator key,
+	// e.g. "unix:///run/nil/signer.sock". If it is set, the key is not loaded from ValidatorKeysPath.
+	RemoteSigner          string                       `yaml:"remoteSigner,omitempty"`
+	RemoteSignerTransport remotesigner.TransportConfig `yaml:"remoteSignerTransport,omitempty"`
+	ValidatorSigner       remotesigner.BlsSigner       `yaml:"-"`
+
 	// HttpUrl is calculated from RPCPort
 	HttpUrl string `yaml:"-"`
 
@@ -197,12 +219,48 @@ func (c *Config) LoadValidatorPrivateKey() (bls.PrivateKey, error) {
 	return c.ValidatorKeysManager.GetKey()
 }
 
+// LoadValidatorSigner sets the signer of the consensus messages, either remote or with the local validator key.
+func (c *Config) LoadValidatorSigner(ctx context.Context) error {
+	if c.ValidatorSigner != nil {
+		return nil
+	}
+
+	if c.RemoteSigner != "" {
+		client, err := remotesigner.NewClient(c.RemoteSigner, &c.RemoteSignerTransport)
+		if err != nil {
+			return err
+		}
+		signer, err := client.BlsSigner(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get the validator key from the remote signer: %w", err)
+		}
+		c.ValidatorSigner = signer
+		return nil
+	}
+
+	if err := c.LoadValidatorKeys(); err != nil {
+		return err
+	}
+	if c.ValidatorKeysManager == nil {
+		return errors.New("neither validator keys nor remote signer are configured")
+	}
+	key, err := c.ValidatorKeysManager.GetKey()
+	if err != nil {
+		return err
+	}
+	c.ValidatorSigner = remotesigner.NewLocalBlsSigner(key, nil)
+	return nil
+}
+
 func (c *Config) BlockGeneratorParams(shardId types.ShardId) execution.BlockGeneratorParams {
 	return execution.BlockGeneratorParams{
-		ShardId:          shardId,
-		NShards:          c.NShards,
-		TraceEVM:         c.TraceEVM,
-		MainKeysPath:     c.MainKeysPath,
-		DisableConsensus: c.DisableConsensus,
+		ShardId:             shardId,
+		NShards:             c.NShards,
+		TraceEVM:            c.TraceEVM,
+		MainKeysPath:        c.MainKeysPath,
+		DisableConsensus:    c.DisableConsensus,
+		RecordStateDiff:     c.RecordStateDiff,
+		ParallelExecution:   c.ParallelExecution,
+		RecordResourceUsage: c.RecordResourceUsage,
 	}
 }
@@ -22,7 +22,6 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/types"
-	"github.com/NilFoundation/nil/nil/services/admin"
 	"github.com/NilFoundation/nil/nil/services/cometa"
 	"github.com/NilFoundation/nil/nil/services/faucet"
 	"github.com/NilFoundation/nil/nil/services/rollup"
@@ -54,6 +53,7 @@ func startRpcServer(ctx context.Context, cfg *Config, rawApi rawapi.NodeApi, db
 		HTTPTimeouts:    httpcfg.DefaultHTTPTimeouts,
 		HttpCORSDomain:  []string{"*"},
 		KeepHeaders:     []string{"Client-Version", "Client-Type", "X-UID"},
+		Access:          cfg.RpcAccess,
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -117,14 +117,6 @@ func startRpcServer(ctx context.Context, cfg *Config, rawApi rawapi.NodeApi, db
 	return rpc.StartRpcServer(ctx, httpConfig, apiList, logger, nil)
 }
 
-func startAdminServer(ctx context.Context, cfg *Config) error {
-	config := &admin.ServerConfig{
-		Enabled:        cfg.AdminSocketPath != "",
-		UnixSocketPath: cfg.AdminSocketPath,
-	}
-	return admin.StartAdminServer(ctx, config, logging.NewLogger("admin"))
-}
-
 const defaultCollatorTickPeriodMs = 2000
 
 // used to access started service from outside of `Run` call
@@ -294,15 +286,13 @@ func (i *Node) Close(ctx context.Context) {
 	telemetry.Shutdown(ctx)
 }
 
-func runNormalOrCollatorsOnly(ctx context.Context, funcs []concurrent.Func, cfg *Config, database db.DB, networkManager *network.Manager, logger zerolog.Logger) ([]concurrent.Func, map[types.ShardId]txnpool.Pool, error) {
-	if err := cfg.LoadValidatorKeys(); err != nil {
+func runNormalOrCollatorsOnly(ctx context.Context, funcs []concurrent.Func, cfg *Config, database db.DB, networkManager *network.Manager, collators map[types.ShardId]*collate.Scheduler, logger zerolog.Logger) ([]concurrent.Func, map[types.ShardId]txnpool.Pool, error) {
+	if err := cfg.LoadValidatorSigner(ctx); err != nil {
 		return nil, nil, err
 	}
 
 	if !cfg.SplitShards && len(cfg.ZeroState.GetValidators()) == 0 {
-		if err := initDefaultValidator(cfg); err != nil {
-			return nil, nil, err
-		}
+		initDefaultValidator(cfg)
 	}
 
 	syncersResult, err := createSyncers("sync", cfg, networkManager, database, logger)
@@ -312,7 +302,7 @@ func runNormalOrCollatorsOnly(ctx context.Context, funcs []concurrent.Func, cfg
 	funcs = append(funcs, syncersResult.funcs...)
 
 	var shardFuncs []concurrent.Func
-	shardFuncs, txnPools, err := createShards(ctx, cfg, database, networkManager, syncersResult, logger)
+	shardFuncs, txnPools, err := createShards(ctx, cfg, database, networkManager, syncersResult, collators, logger)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create collators")
 		return nil, nil, err
@@ -355,6 +345,7 @@ func CreateNode(ctx context.Context, name string, cfg *Config, database db.DB, i
 	}
 
 	var txnPools map[types.ShardId]txnpool.Pool
+	collators := make(map[types.ShardId]*collate.Scheduler)
 	if cfg.Network != nil && cfg.RunMode != NormalRunMode {
 		cfg.Network.DHTMode = dht.ModeClient
 	}
@@ -367,7 +358,7 @@ func CreateNode(ctx context.Context, name string, cfg *Config, database db.DB, i
 	var syncersResult *syncersResult
 	switch cfg.RunMode {
 	case NormalRunMode, CollatorsOnlyRunMode:
-		funcs, txnPools, err = runNormalOrCollatorsOnly(ctx, funcs, cfg, database, networkManager, logger)
+		funcs, txnPools, err = runNormalOrCollatorsOnly(ctx, funcs, cfg, database, networkManager, collators, logger)
 		if err != nil {
 			return nil, err
 		}
@@ -418,7 +409,7 @@ func CreateNode(ctx context.Context, name string, cfg *Config, database db.DB, i
 	}
 
 	funcs = append(funcs, func(ctx context.Context) error {
-		if err := startAdminServer(ctx, cfg); err != nil {
+		if err := startAdminServer(ctx, cfg, database, networkManager, collators); err != nil {
 			logger.Error().Err(err).Msg("Admin server goroutine failed")
 			return err
 		}
@@ -522,23 +513,19 @@ func createNetworkManager(ctx context.Context, cfg *Config) (*network.Manager, e
 	return network.NewManager(ctx, cfg.Network)
 }
 
-func initDefaultValidator(cfg *Config) error {
-	pubkey, err := cfg.ValidatorKeysManager.GetPublicKey()
-	if err != nil {
-		return err
-	}
+func initDefaultValidator(cfg *Config) {
+	pubkey := cfg.ValidatorSigner.PublicKey()
 	validators := make([]config.ListValidators, cfg.NShards-1)
 	for i := range validators {
 		validators[i] = config.ListValidators{List: []config.ValidatorInfo{{PublicKey: config.Pubkey(pubkey)}}}
 	}
 	cfg.ZeroState.ConfigParams.Validators = config.ParamValidators{Validators: validators}
-	return nil
 }
 
 func createShards(
 	ctx context.Context, cfg *Config,
 	database db.DB, networkManager *network.Manager,
-	syncers *syncersResult, logger zerolog.Logger,
+	syncers *syncersResult, collators map[types.ShardId]*collate.Scheduler, logger zerolog.Logger,
 ) ([]concurrent.Func, map[types.ShardId]txnpool.Pool, error) {
 	collatorTickPeriod := time.Millisecond * time.Duration(cfg.CollatorTickPeriodMs)
 
@@ -559,22 +546,21 @@ func createShards(
 				return nil, nil, err
 			}
 
-			pKey, err := cfg.ValidatorKeysManager.GetKey()
-			if err != nil {
-				return nil, nil, err
-			}
-
 			collator := createActiveCollator(shardId, cfg, collatorTickPeriod, database, networkManager, txnPool)
 
-			consensus := ibft.NewConsensus(&ibft.ConsensusParams{
+			consensus, err := ibft.NewConsensus(&ibft.ConsensusParams{
 				ShardId:    shardId,
 				Db:         database,
 				Validator:  collator.Validator(),
 				NetManager: networkManager,
-				PrivateKey: pKey,
+				Signer:     cfg.ValidatorSigner,
 			})
+			if err != nil {
+				return nil, nil, err
+			}
 
 			pools[shardId] = txnPool
+			collators[shardId] = collator
 			funcs = append(funcs, func(ctx context.Context) error {
 				syncers.Wait() // Wait for syncers initialization
 				if err := consensus.Init(ctx); err != nil {
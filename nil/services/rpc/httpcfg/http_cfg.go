@@ -48,4 +48,51 @@ type HttpCfg struct {
 	RPCSlowLogThreshold time.Duration
 
 	KeepHeaders []string // List of headers to pass to the request handler
+
+	Access AccessCfg
+}
+
+// RateLimit is the token bucket limit of the requests, zero rate means no limit.
+type RateLimit struct {
+	// Rate is the number of requests per second
+	Rate float64 `yaml:"rate,omitempty"`
+	// Burst is the number of requests which can be served at once, at least one
+	Burst int `yaml:"burst,omitempty"`
+}
+
+func (l RateLimit) IsSet() bool {
+	return l.Rate > 0
+}
+
+// AccessCfg restricts the API served to the clients of a public node.
+// A client is identified by its API key or, if the key isn't sent, by its IP address.
+type AccessCfg struct {
+	// Namespaces is the list of served API namespaces, all public namespaces are served if it is empty
+	Namespaces []string `yaml:"namespaces,omitempty"`
+	// DisabledNamespaces are not served even if they are listed in Namespaces
+	DisabledNamespaces []string `yaml:"disabledNamespaces,omitempty"`
+	// AllowedMethods is the list of served methods, all methods of the served namespaces are allowed if it is empty
+	AllowedMethods []string `yaml:"allowedMethods,omitempty"`
+	// DisabledMethods are not served even if they are listed in AllowedMethods
+	DisabledMethods []string `yaml:"disabledMethods,omitempty"`
+
+	// MaxBatchSize is the maximum number of requests in a batch, zero means no limit
+	MaxBatchSize int `yaml:"maxBatchSize,omitempty"`
+	// MaxResponseSize is the maximum size of a response in bytes, zero means no limit
+	MaxResponseSize int `yaml:"maxResponseSize,omitempty"`
+
+	// RateLimit is applied to every IP address which doesn't send an API key
+	RateLimit RateLimit `yaml:"rateLimit,omitempty"`
+	// MethodRateLimits are applied to every client and method in addition to the client limit
+	MethodRateLimits map[string]RateLimit `yaml:"methodRateLimits,omitempty"`
+	// APIKeys maps the keys accepted in the API key header to the rate limits of their clients
+	APIKeys map[string]RateLimit `yaml:"apiKeys,omitempty"`
+	// RequireAPIKey rejects the requests without a valid API key
+	RequireAPIKey bool `yaml:"requireApiKey,omitempty"`
+	// ForwardedForHeader is the header with the client IP address set by a trusted reverse proxy,
+	// e.g. "X-Forwarded-For". The address of the connection is used if it is empty.
+	ForwardedForHeader string `yaml:"forwardedForHeader,omitempty"`
+	// TrustedProxies is the number of the trusted reverse proxies in front of the node, each of them
+	// appends the address of its peer to the header. Zero is treated as one.
+	TrustedProxies int `yaml:"trustedProxies,omitempty"`
 }
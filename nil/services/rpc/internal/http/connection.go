@@ -16,6 +16,9 @@ const (
 	niljsClientVersionPrefix = "niljs/"
 )
 
+// APIKeyHeader is the header with the API key of the client.
+const APIKeyHeader = "X-Api-Key"
+
 type (
 	remoteCtxKey    struct{}
 	schemeCtxKey    struct{}
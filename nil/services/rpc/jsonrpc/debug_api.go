@@ -17,8 +17,14 @@ type DebugAPI interface {
 	GetBlockByNumber(ctx context.Context, shardId types.ShardId, number transport.BlockNumber, withTransactions bool) (*DebugRPCBlock, error)
 	GetBlockByHash(ctx context.Context, hash common.Hash, withTransactions bool) (*DebugRPCBlock, error)
 	GetContract(ctx context.Context, contractAddr types.Address, blockNrOrHash transport.BlockNumberOrHash) (*DebugRPCContract, error)
+	GetStateDiff(ctx context.Context, shardId types.ShardId, blockNrOrHash transport.BlockNumberOrHash) (*types.BlockStateDiff, error)
+	GetTransactionStateDiff(ctx context.Context, hash common.Hash) (*types.TransactionStateDiff, error)
+	GetResourceUsage(ctx context.Context, shardId types.ShardId, fromBlock, toBlock types.BlockNumber) (*types.ResourceUsage, error)
 }
 
+// maxResourceUsageBlocks limits the number of blocks aggregated by a single debug_getResourceUsage call.
+const maxResourceUsageBlocks = 10_000
+
 type DebugAPIImpl struct {
 	logger zerolog.Logger
 	rawApi rawapi.NodeApi
@@ -94,3 +100,62 @@ func (api *DebugAPIImpl) GetContract(ctx context.Context, contractAddr types.Add
 		AsyncContext: contract.AsyncContext,
 	}, nil
 }
+
+// GetStateDiff implements debug_getStateDiff. Returns the accounts, storage slots, balances and tokens changed by the block.
+// The diff is available only for the blocks generated by a node run with state diff recording enabled.
+func (api *DebugAPIImpl) GetStateDiff(ctx context.Context, shardId types.ShardId, blockNrOrHash transport.BlockNumberOrHash) (*types.BlockStateDiff, error) {
+	data, err := api.rawApi.GetStateDiff(ctx, shardId, toBlockReference(blockNrOrHash))
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &types.BlockStateDiff{}
+	if err := diff.UnmarshalSSZ(data); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// GetTransactionStateDiff implements debug_getTransactionStateDiff. Returns the changes of the state made by the inbound transaction.
+func (api *DebugAPIImpl) GetTransactionStateDiff(ctx context.Context, hash common.Hash) (*types.TransactionStateDiff, error) {
+	data, err := api.rawApi.GetTransactionStateDiff(ctx, types.ShardIdFromHash(hash), hash)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &types.TransactionStateDiff{}
+	if err := diff.UnmarshalSSZ(data); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// GetResourceUsage implements debug_getResourceUsage. Returns the gas used by the contracts of the shard
+// per contract and per method selector, and the storage slots created and deleted in the contracts,
+// aggregated over the blocks from fromBlock to toBlock inclusive.
+// The usage is available only for the blocks generated by a node run with resource usage recording enabled.
+func (api *DebugAPIImpl) GetResourceUsage(
+	ctx context.Context, shardId types.ShardId, fromBlock, toBlock types.BlockNumber,
+) (*types.ResourceUsage, error) {
+	if fromBlock > toBlock {
+		return nil, fmt.Errorf("invalid block range: %d > %d", fromBlock, toBlock)
+	}
+	if toBlock-fromBlock >= maxResourceUsageBlocks {
+		return nil, fmt.Errorf("block range is too large, at most %d blocks are allowed", maxResourceUsageBlocks)
+	}
+
+	res := &types.ResourceUsage{Contracts: make([]*types.ContractResourceUsage, 0)}
+	for blockId := fromBlock; blockId <= toBlock; blockId++ {
+		data, err := api.rawApi.GetResourceUsage(ctx, shardId, rawapitypes.BlockNumberAsBlockReference(blockId))
+		if err != nil {
+			return nil, err
+		}
+
+		usage := &types.ResourceUsage{}
+		if err := usage.UnmarshalSSZ(data); err != nil {
+			return nil, err
+		}
+		res.Merge(usage)
+	}
+	return res, nil
+}
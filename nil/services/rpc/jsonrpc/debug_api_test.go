@@ -111,6 +111,7 @@ func TestDebugGetBlock(t *testing.T) {
 type SuiteDbgContracts struct {
 	SuiteAccountsBase
 	debugApi *DebugAPIImpl
+	txnHash  common.Hash
 }
 
 func (suite *SuiteDbgContracts) SetupSuite() {
@@ -129,10 +130,17 @@ func (suite *SuiteDbgContracts) SetupSuite() {
 	})
 	suite.Require().NoError(err)
 	es.BaseFee = types.DefaultGasPrice
+	es.RecordStateDiff = true
+	es.RecordResourceUsage = true
 
 	suite.smcAddr = types.GenerateRandomAddress(shardId)
 	suite.Require().NotEmpty(suite.smcAddr)
 
+	txn := types.NewEmptyTransaction()
+	txn.To = suite.smcAddr
+	txn.Data = []byte{0xde, 0xad, 0xbe, 0xef, 0x01}
+	suite.txnHash = es.AddInTransaction(txn)
+
 	suite.Require().NoError(es.CreateAccount(suite.smcAddr))
 	suite.Require().NoError(es.SetCode(suite.smcAddr, []byte("some code")))
 	suite.Require().NoError(es.SetState(suite.smcAddr, common.Hash{0x1}, common.IntToHash(2)))
@@ -140,6 +148,7 @@ func (suite *SuiteDbgContracts) SetupSuite() {
 
 	suite.Require().NoError(es.SetBalance(suite.smcAddr, types.NewValueFromUint64(1234)))
 	suite.Require().NoError(es.SetExtSeqno(suite.smcAddr, 567))
+	es.AddReceipt(execution.NewExecutionResult().SetUsed(1000, types.DefaultGasPrice))
 
 	blockRes, err := es.Commit(0, nil)
 	suite.Require().NoError(err)
@@ -204,6 +213,58 @@ func (suite *SuiteDbgContracts) TestGetContract() {
 	})
 }
 
+func (suite *SuiteDbgContracts) TestGetStateDiff() {
+	ctx := context.Background()
+
+	checkAccounts := func(accounts []types.AccountStateDiff) {
+		suite.Require().Len(accounts, 1)
+		acc := accounts[0]
+		suite.Equal(suite.smcAddr, acc.Address)
+		suite.True(acc.Created)
+		suite.Equal(types.NewValueFromUint64(1234), acc.BalanceAfter)
+		suite.Equal(types.Seqno(567), acc.ExtSeqnoAfter)
+		suite.Equal(types.Code("some code").Hash(), acc.CodeHashAfter)
+		suite.Equal([]types.StorageDiff{
+			{Key: common.Hash{0x1}, After: common.IntToHash(2)},
+			{Key: common.Hash{0x3}, After: common.IntToHash(4)},
+		}, acc.Storage)
+	}
+
+	suite.Run("block", func() {
+		diff, err := suite.debugApi.GetStateDiff(ctx, suite.smcAddr.ShardId(), transport.BlockNumberOrHash{BlockHash: &suite.blockHash})
+		suite.Require().NoError(err)
+		checkAccounts(diff.Accounts)
+		suite.Require().Len(diff.Transactions, 1)
+		suite.Equal(suite.txnHash, diff.Transactions[0].TxnHash)
+	})
+
+	suite.Run("transaction", func() {
+		diff, err := suite.debugApi.GetTransactionStateDiff(ctx, suite.txnHash)
+		suite.Require().NoError(err)
+		suite.Equal(suite.txnHash, diff.TxnHash)
+		checkAccounts(diff.Accounts)
+	})
+}
+
+func (suite *SuiteDbgContracts) TestGetResourceUsage() {
+	ctx := context.Background()
+
+	usage, err := suite.debugApi.GetResourceUsage(ctx, suite.smcAddr.ShardId(), 0, 0)
+	suite.Require().NoError(err)
+	suite.Equal([]*types.ContractResourceUsage{{
+		Address:      suite.smcAddr,
+		Transactions: 1,
+		GasUsed:      1000,
+		SlotsCreated: 2,
+		Selectors:    []*types.SelectorUsage{{Selector: types.Code{0xde, 0xad, 0xbe, 0xef}, Transactions: 1, GasUsed: 1000}},
+	}}, usage.Contracts)
+
+	_, err = suite.debugApi.GetResourceUsage(ctx, suite.smcAddr.ShardId(), 1, 0)
+	suite.Require().Error(err)
+	_, err = suite.debugApi.GetResourceUsage(ctx, suite.smcAddr.ShardId(), 0, 1)
+	suite.Require().Error(err)
+}
+
 func TestSuiteDbgContracts(t *testing.T) {
 	t.Parallel()
 
@@ -36,6 +36,7 @@ type (
 // @componentprop To to string true "The address where the transaction was sent."
 // @componentprop Value value string true "The transaction value."
 // @componentprop Token value array true "Token values."
+// @componentprop Paymaster paymaster string false "The contract which paid the fees of the external transaction."
 type RPCInTransaction struct {
 	Flags                types.TransactionFlags `json:"flags"`
 	Success              bool                   `json:"success"`
@@ -56,6 +57,7 @@ type RPCInTransaction struct {
 	Index                hexutil.Uint64         `json:"index"`
 	Value                types.Value            `json:"value"`
 	Token                []types.TokenBalance   `json:"token,omitempty"`
+	Paymaster            *types.Address         `json:"paymaster,omitempty"`
 	ChainID              types.ChainId          `json:"chainId,omitempty"`
 	Signature            types.Signature        `json:"signature"`
 }
@@ -226,6 +228,7 @@ func EncodeRawBlockWithExtractedData(block *types.RawBlockWithExtractedData) (*D
 // @componentprop Temporary temporary boolean false "The flag that shows whether the transaction is temporary."
 // @componentprop ErrorMessage errorTransaction string false "The error in case the transaction processing was unsuccessful."
 // @componentprop Flags flags string true "The array of transaction flags."
+// @componentprop BatchReceipts batchReceipts array false "Receipts of the calls of a batched transaction in the order of the calls."
 type RPCReceipt struct {
 	Flags           types.TransactionFlags `json:"flags"`
 	Success         bool                   `json:"success"`
@@ -248,6 +251,18 @@ type RPCReceipt struct {
 	ShardId         types.ShardId          `json:"shardId"`
 	Temporary       bool                   `json:"temporary,omitempty"`
 	ErrorMessage    string                 `json:"errorMessage,omitempty"`
+	BatchReceipts   []*RPCBatchCallReceipt `json:"batchReceipts,omitempty"`
+}
+
+// RPCBatchCallReceipt is the receipt of a call of a batched transaction.
+// It refers to the transaction by its hash and to the call by its index in the batch.
+type RPCBatchCallReceipt struct {
+	ParentTxnHash common.Hash `json:"parentTransactionHash"`
+	Index         uint32      `json:"index"`
+	Success       bool        `json:"success"`
+	Status        string      `json:"status"`
+	GasUsed       types.Gas   `json:"gasUsed"`
+	FailedPc      uint        `json:"failedPc"`
 }
 
 type RPCLog struct {
@@ -332,6 +347,9 @@ func NewRPCInTransaction(
 		ChainID:              transaction.ChainId,
 		Signature:            transaction.Signature,
 	}
+	if transaction.HasPaymaster() {
+		result.Paymaster = &transaction.Paymaster
+	}
 
 	return result, nil
 }
@@ -455,6 +473,17 @@ func NewRPCReceipt(info *rawapitypes.ReceiptInfo) (*RPCReceipt, error) {
 		IncludedInMain:  info.IncludedInMain,
 	}
 
+	for i, r := range receipt.BatchReceipts {
+		res.BatchReceipts = append(res.BatchReceipts, &RPCBatchCallReceipt{
+			ParentTxnHash: receipt.TxnHash,
+			Index:         uint32(i),
+			Success:       r.Success,
+			Status:        r.Status.String(),
+			GasUsed:       r.GasUsed,
+			FailedPc:      uint(r.FailedPc),
+		})
+	}
+
 	// Set only non-empty bloom
 	if len(receipt.Logs) > 0 {
 		res.Bloom = types.CreateBloom(types.Receipts{receipt}).Bytes()
@@ -129,6 +129,14 @@ func (api *ShardApiAccessor) GetBlockTransactionCount(ctx context.Context, block
 	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "GetBlockTransactionCount", blockReference)
 }
 
+func (api *ShardApiAccessor) GetStateDiff(ctx context.Context, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[sszx.SSZEncodedData](ctx, api, "GetStateDiff", blockReference)
+}
+
+func (api *ShardApiAccessor) GetResourceUsage(ctx context.Context, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[sszx.SSZEncodedData](ctx, api, "GetResourceUsage", blockReference)
+}
+
 func (api *ShardApiAccessor) GetBalance(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[types.Value](ctx, api, "GetBalance", address, blockReference)
 }
@@ -159,6 +167,10 @@ func (api *ShardApiAccessor) GetInTransactionReceipt(ctx context.Context, hash c
 	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ReceiptInfo](ctx, api, "GetInTransactionReceipt", hash)
 }
 
+func (api *ShardApiAccessor) GetTransactionStateDiff(ctx context.Context, hash common.Hash) (sszx.SSZEncodedData, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[sszx.SSZEncodedData](ctx, api, "GetTransactionStateDiff", hash)
+}
+
 func (api *ShardApiAccessor) GasPrice(ctx context.Context) (types.Value, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[types.Value](ctx, api, "GasPrice")
 }
@@ -122,8 +122,8 @@ func (api *LocalShardApi) getBlockByHash(tx db.RoTx, hash common.Hash, withTrans
 		}
 		for _, transaction := range transactions {
 			txnHash := transaction.Hash()
-			errMsg, err := db.ReadError(tx, txnHash)
-			if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+			errMsg, err := readError(tx, txnHash)
+			if err != nil {
 				return nil, err
 			}
 			if len(errMsg) > 0 {
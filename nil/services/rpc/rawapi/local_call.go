@@ -193,6 +193,9 @@ func (api *LocalShardApi) Call(
 		return nil, err
 	}
 	es.MainChainHash = mainBlockHash
+	if err := es.UpdateFork(block.Id + 1); err != nil {
+		return nil, err
+	}
 
 	if overrides != nil {
 		if err := overrides.Override(es); err != nil {
@@ -222,7 +225,9 @@ func (api *LocalShardApi) Call(
 		} else if toAs == nil {
 			return nil, rpctypes.ErrToAccNotFound
 		}
-		payer = execution.NewAccountPayer(toAs, txn)
+		if payer, err = execution.NewExternalTransactionPayer(es, txn); err != nil {
+			return nil, err
+		}
 	}
 
 	txnHash := es.AddInTransaction(txn)
@@ -236,7 +241,7 @@ func (api *LocalShardApi) Call(
 	}
 
 	if res.Failed() {
-		result.Error = res.GetError().Error()
+		result.Error = errorWithRevertReason(res.GetError().Error(), res.ReturnData)
 		return result, nil
 	}
 
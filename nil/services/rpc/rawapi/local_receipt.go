@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	fastssz "github.com/NilFoundation/fastssz"
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/abi"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/mpt"
@@ -79,8 +81,8 @@ func (api *LocalShardApi) GetInTransactionReceipt(ctx context.Context, hash comm
 		receipt = receiptWithError.Receipt
 		errMsg = receiptWithError.Error.Error()
 	} else {
-		errMsg, err = db.ReadError(tx, hash)
-		if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		errMsg, err = readError(tx, hash)
+		if err != nil {
 			return nil, err
 		}
 	}
@@ -168,3 +170,28 @@ func getBlockEntity[
 	root.SetRootHash(rootHash)
 	return mpt.GetEntity[T](root, entityKey)
 }
+
+// readError returns the error message of the transaction. The revert reason decoded from the revert data
+// is appended to the message, the custom errors are kept hex encoded to be decoded with the ABI by the clients.
+func readError(tx db.RoTx, txnHash common.Hash) (string, error) {
+	errMsg, err := db.ReadError(tx, txnHash)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	data, err := db.ReadRevertData(tx, txnHash)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return "", err
+	}
+	return errorWithRevertReason(errMsg, data), nil
+}
+
+func errorWithRevertReason(errMsg string, data []byte) string {
+	reason := abi.RevertReason(data)
+	if reason == "" || strings.Contains(errMsg, reason) {
+		return errMsg
+	}
+	return errMsg + ": " + reason
+}
@@ -0,0 +1,32 @@
+package rawapi
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorWithRevertReason(t *testing.T) {
+	t.Parallel()
+
+	// Error("revert reason") is already decoded by the execution
+	revert := hexutil.MustDecode("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000d" +
+		"72657665727420726561736f6e00000000000000000000000000000000000000")
+	require.Equal(t, "ExecutionReverted: revert reason",
+		errorWithRevertReason("ExecutionReverted: revert reason", revert))
+
+	// Panic(0x11) is decoded by the RPC only
+	panicData := hexutil.MustDecode("0x4e487b71" +
+		"0000000000000000000000000000000000000000000000000000000000000011")
+	require.Equal(t, "ExecutionReverted: arithmetic underflow or overflow",
+		errorWithRevertReason("ExecutionReverted", panicData))
+
+	// The custom errors are kept hex encoded
+	require.Equal(t, "ExecutionReverted: revert data: 0x01020304",
+		errorWithRevertReason("ExecutionReverted", []byte{1, 2, 3, 4}))
+
+	require.Equal(t, "OutOfGas", errorWithRevertReason("OutOfGas", nil))
+}
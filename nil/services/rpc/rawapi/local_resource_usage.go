@@ -0,0 +1,32 @@
+package rawapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+var ErrResourceUsageNotRecorded = errors.New(
+	"resource usage is not recorded for the block, the node must be run with --record-resource-usage")
+
+func (api *LocalShardApi) GetResourceUsage(ctx context.Context, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockHash, err := api.getBlockHashByReference(tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := db.ReadResourceUsageSSZ(tx, api.ShardId, blockHash)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, ErrResourceUsageNotRecorded
+	}
+	return usage, err
+}
@@ -0,0 +1,60 @@
+package rawapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+var ErrStateDiffNotRecorded = errors.New("state diff is not recorded for the block, the node must be run with --record-state-diff")
+
+func (api *LocalShardApi) GetStateDiff(ctx context.Context, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockHash, err := api.getBlockHashByReference(tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := db.ReadStateDiffSSZ(tx, api.ShardId, blockHash)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, ErrStateDiffNotRecorded
+	}
+	return diff, err
+}
+
+func (api *LocalShardApi) GetTransactionStateDiff(ctx context.Context, hash common.Hash) (sszx.SSZEncodedData, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, index, err := api.getBlockAndInTransactionIndexByTransactionHash(tx, api.ShardId, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := db.ReadStateDiff(tx, api.ShardId, index.BlockHash)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, ErrStateDiffNotRecorded
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if int(index.TransactionIndex) >= len(diff.Transactions) ||
+		diff.Transactions[index.TransactionIndex].TxnHash != hash {
+		return nil, fmt.Errorf("state diff of block %s doesn't contain transaction %s", index.BlockHash, hash)
+	}
+	return diff.Transactions[index.TransactionIndex].MarshalSSZ()
+}
@@ -94,6 +94,32 @@ func (api *NodeApiOverShardApis) GetBlockTransactionCount(ctx context.Context, s
 	return result, nil
 }
 
+func (api *NodeApiOverShardApis) GetStateDiff(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error) {
+	methodName := methodNameChecked("GetStateDiff")
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetStateDiff(ctx, blockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *NodeApiOverShardApis) GetResourceUsage(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error) {
+	methodName := methodNameChecked("GetResourceUsage")
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetResourceUsage(ctx, blockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *NodeApiOverShardApis) GetBalance(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error) {
 	methodName := methodNameChecked("GetBalance")
 	shardId := address.ShardId()
@@ -198,6 +224,19 @@ func (api *NodeApiOverShardApis) GetInTransactionReceipt(ctx context.Context, sh
 	return result, nil
 }
 
+func (api *NodeApiOverShardApis) GetTransactionStateDiff(ctx context.Context, shardId types.ShardId, hash common.Hash) (sszx.SSZEncodedData, error) {
+	methodName := methodNameChecked("GetTransactionStateDiff")
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetTransactionStateDiff(ctx, hash)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *NodeApiOverShardApis) GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error) {
 	methodName := methodNameChecked("GasPrice")
 	shardApi, ok := api.Apis[shardId]
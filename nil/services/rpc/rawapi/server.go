@@ -20,9 +20,12 @@ type NetworkTransportProtocolRo interface {
 	GetBlockHeader(request pb.BlockRequest) pb.RawBlockResponse
 	GetFullBlockData(request pb.BlockRequest) pb.RawFullBlockResponse
 	GetBlockTransactionCount(request pb.BlockRequest) pb.Uint64Response
+	GetStateDiff(request pb.BlockRequest) pb.RawBlockResponse
+	GetResourceUsage(request pb.BlockRequest) pb.RawBlockResponse
 
 	GetInTransaction(pb.TransactionRequest) pb.TransactionResponse
 	GetInTransactionReceipt(pb.Hash) pb.ReceiptResponse
+	GetTransactionStateDiff(pb.Hash) pb.RawBlockResponse
 
 	GetBalance(request pb.AccountRequest) pb.BalanceResponse
 	GetCode(request pb.AccountRequest) pb.CodeResponse
@@ -35,7 +35,8 @@ func StartRpcServer(
 		}
 	}
 
-	if err := transport.RegisterApisFromWhitelist(defaultAPIList, nil, srv, logger); err != nil {
+	srv.SetAccess(cfg.Access)
+	if err := transport.RegisterApisFromWhitelist(defaultAPIList, cfg.Access.Namespaces, srv, logger); err != nil {
 		return fmt.Errorf("could not start register RPC apis: %w", err)
 	}
 
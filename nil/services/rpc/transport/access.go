@@ -0,0 +1,190 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
+	nil_http "github.com/NilFoundation/nil/nil/services/rpc/internal/http"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// limiters of the clients which haven't sent requests for this time are dropped
+	limiterIdleTimeout = 10 * time.Minute
+	limiterSweepPeriod = time.Minute
+)
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// accessController enforces the access config of the server, it is shared by all connections.
+type accessController struct {
+	cfg                httpcfg.AccessCfg
+	allowedMethods     map[string]struct{}
+	disabledMethods    map[string]struct{}
+	disabledNamespaces map[string]struct{}
+
+	mu        sync.Mutex
+	limiters  map[string]*limiterEntry
+	lastSweep time.Time
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+func newAccessController(cfg httpcfg.AccessCfg) *accessController {
+	return &accessController{
+		cfg:                cfg,
+		allowedMethods:     toSet(cfg.AllowedMethods),
+		disabledMethods:    toSet(cfg.DisabledMethods),
+		disabledNamespaces: toSet(cfg.DisabledNamespaces),
+		limiters:           make(map[string]*limiterEntry),
+		lastSweep:          time.Now(),
+	}
+}
+
+// client returns the access of the client which sent the request.
+func (a *accessController) client(r *http.Request) (*clientAccess, error) {
+	if key := r.Header.Get(nil_http.APIKeyHeader); key != "" {
+		limit, ok := a.cfg.APIKeys[key]
+		if !ok {
+			return nil, &rejectedError{unauthorizedErrorCode, rejectUnauthorized, "invalid API key"}
+		}
+		return &clientAccess{controller: a, id: "key:" + key, limit: limit}, nil
+	}
+	if a.cfg.RequireAPIKey {
+		return nil, &rejectedError{
+			unauthorizedErrorCode, rejectUnauthorized, "API key is required in the " + nil_http.APIKeyHeader + " header",
+		}
+	}
+	return &clientAccess{controller: a, id: "ip:" + a.clientIP(r), limit: a.cfg.RateLimit}, nil
+}
+
+func (a *accessController) clientIP(r *http.Request) string {
+	if a.cfg.ForwardedForHeader != "" {
+		// Every proxy appends the address of its peer, so the entries on the left are set by the client
+		// and can be forged. The client is the entry appended by the farthest trusted proxy.
+		var addresses []string
+		for _, value := range r.Header.Values(a.cfg.ForwardedForHeader) {
+			for address := range strings.SplitSeq(value, ",") {
+				if address = strings.TrimSpace(address); address != "" {
+					addresses = append(addresses, address)
+				}
+			}
+		}
+		if len(addresses) > 0 {
+			hops := max(a.cfg.TrustedProxies, 1)
+			return addresses[max(len(addresses)-hops, 0)]
+		}
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+func (a *accessController) checkBatchSize(size int) error {
+	if a.cfg.MaxBatchSize > 0 && size > a.cfg.MaxBatchSize {
+		return &rejectedError{limitExceededErrorCode, rejectBatchTooLarge,
+			fmt.Sprintf("batch limit %d exceeded, requested batch of size %d", a.cfg.MaxBatchSize, size)}
+	}
+	return nil
+}
+
+func (a *accessController) checkResponseSize(size int) error {
+	if a.cfg.MaxResponseSize > 0 && size > a.cfg.MaxResponseSize {
+		return &rejectedError{limitExceededErrorCode, rejectResponseTooLarge,
+			fmt.Sprintf("response size %d exceeds the limit %d", size, a.cfg.MaxResponseSize)}
+	}
+	return nil
+}
+
+func (a *accessController) isMethodAllowed(method string) bool {
+	if _, ok := a.disabledMethods[method]; ok {
+		return false
+	}
+	namespace, _, _ := strings.Cut(method, serviceMethodSeparator)
+	if _, ok := a.disabledNamespaces[namespace]; ok {
+		return false
+	}
+	if len(a.allowedMethods) == 0 {
+		return true
+	}
+	_, ok := a.allowedMethods[method]
+	return ok
+}
+
+// take takes a token from the limiter with the key, the limiter is created on the first call.
+func (a *accessController) take(key string, limit httpcfg.RateLimit) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(a.lastSweep) > limiterSweepPeriod {
+		for k, entry := range a.limiters {
+			if now.Sub(entry.lastUsed) > limiterIdleTimeout {
+				delete(a.limiters, k)
+			}
+		}
+		a.lastSweep = now
+	}
+
+	entry, ok := a.limiters[key]
+	if !ok {
+		entry = &limiterEntry{limiter: rate.NewLimiter(rate.Limit(limit.Rate), max(limit.Burst, 1))}
+		a.limiters[key] = entry
+	}
+	entry.lastUsed = now
+	return entry.limiter.AllowN(now, 1)
+}
+
+// clientAccess checks the requests of a single client.
+type clientAccess struct {
+	controller *accessController
+	id         string
+	limit      httpcfg.RateLimit
+}
+
+// allow checks that the client may call the method now.
+func (c *clientAccess) allow(method string) error {
+	if !c.controller.isMethodAllowed(method) {
+		return &rejectedError{methodNotSupportedErrorCode, rejectMethodNotAllowed,
+			fmt.Sprintf("the method %s is not available", method)}
+	}
+
+	if c.limit.IsSet() && !c.controller.take(c.id, c.limit) {
+		return &rejectedError{limitExceededErrorCode, rejectRateLimited, "rate limit exceeded"}
+	}
+	if limit, ok := c.controller.cfg.MethodRateLimits[method]; ok && limit.IsSet() {
+		if !c.controller.take(c.id+"/"+method, limit) {
+			return &rejectedError{limitExceededErrorCode, rejectRateLimited, "rate limit exceeded for " + method}
+		}
+	}
+	return nil
+}
+
+func recordRejected(ctx context.Context, metrics *metricsHandler, reg *serviceRegistry, method string, err error) {
+	var rejected *rejectedError
+	if !errors.As(err, &rejected) {
+		return
+	}
+	if reg.callback(method) == nil {
+		// don't let arbitrary method names blow up the metric cardinality
+		method = "unknown"
+	}
+	metrics.RecordRejected(ctx, method, rejected.reason)
+}
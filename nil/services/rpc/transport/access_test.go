@@ -0,0 +1,242 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
+	nil_http "github.com/NilFoundation/nil/nil/services/rpc/internal/http"
+	"github.com/stretchr/testify/require"
+)
+
+type accessTestService struct{}
+
+func (s *accessTestService) Echo(_ context.Context, v string) (string, error) {
+	return v, nil
+}
+
+func (s *accessTestService) Large(_ context.Context) (string, error) {
+	return strings.Repeat("a", 1000), nil
+}
+
+type accessTestResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (r *accessTestResponse) code() int {
+	if r.Error == nil {
+		return 0
+	}
+	return r.Error.Code
+}
+
+func newAccessTestServer(t *testing.T, cfg httpcfg.AccessCfg) *Server {
+	t.Helper()
+
+	server := NewServer(false, false, logging.NewLogger("access_test"), 0, nil)
+	server.SetAccess(cfg)
+	require.NoError(t, server.RegisterName("test", &accessTestService{}))
+	require.NoError(t, server.RegisterName("db", &accessTestService{}))
+	return server
+}
+
+func serveAccessTest(t *testing.T, server *Server, body string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodPost, "http://node", strings.NewReader(body))
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	server.ServeSingleRequest(t.Context(), r, w)
+	return w
+}
+
+func callAccessTest(t *testing.T, server *Server, method string, headers map[string]string) *accessTestResponse {
+	t.Helper()
+
+	params := `[]`
+	if strings.HasSuffix(method, "echo") {
+		params = `["hi"]`
+	}
+	w := serveAccessTest(t, server, `{"jsonrpc":"2.0","id":1,"method":"`+method+`","params":`+params+`}`, headers)
+	var resp accessTestResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return &resp
+}
+
+func batchAccessTest(t *testing.T, server *Server, methods ...string) []accessTestResponse {
+	t.Helper()
+
+	calls := make([]string, len(methods))
+	for i, method := range methods {
+		calls[i] = `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":[]}`
+	}
+	w := serveAccessTest(t, server, "["+strings.Join(calls, ",")+"]", nil)
+	var resp []accessTestResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestAccessMethods(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		server := newAccessTestServer(t, httpcfg.AccessCfg{
+			DisabledNamespaces: []string{"db"},
+			DisabledMethods:    []string{"test_large"},
+		})
+		require.Zero(t, callAccessTest(t, server, "test_echo", nil).code())
+		require.Zero(t, callAccessTest(t, server, "rpc_modules", nil).code())
+		require.Equal(t, methodNotSupportedErrorCode, callAccessTest(t, server, "test_large", nil).code())
+		require.Equal(t, methodNotSupportedErrorCode, callAccessTest(t, server, "db_echo", nil).code())
+	})
+
+	t.Run("Allowed", func(t *testing.T) {
+		t.Parallel()
+
+		server := newAccessTestServer(t, httpcfg.AccessCfg{
+			AllowedMethods: []string{"test_echo"},
+		})
+		require.Zero(t, callAccessTest(t, server, "test_echo", nil).code())
+		require.Equal(t, methodNotSupportedErrorCode, callAccessTest(t, server, "test_large", nil).code())
+		require.Equal(t, methodNotSupportedErrorCode, callAccessTest(t, server, "db_echo", nil).code())
+	})
+}
+
+func TestAccessRateLimits(t *testing.T) {
+	t.Parallel()
+
+	// The rate is low enough for the bucket not to be refilled during the test
+	const rate = 0.0001
+
+	t.Run("PerIP", func(t *testing.T) {
+		t.Parallel()
+
+		server := newAccessTestServer(t, httpcfg.AccessCfg{
+			RateLimit:          httpcfg.RateLimit{Rate: rate, Burst: 2},
+			ForwardedForHeader: "X-Forwarded-For",
+		})
+		require.Zero(t, callAccessTest(t, server, "test_echo", nil).code())
+		require.Zero(t, callAccessTest(t, server, "test_large", nil).code())
+		require.Equal(t, limitExceededErrorCode, callAccessTest(t, server, "test_echo", nil).code())
+
+		other := map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.2"}
+		require.Zero(t, callAccessTest(t, server, "test_echo", other).code())
+		require.Zero(t, callAccessTest(t, server, "test_echo", other).code())
+
+		// The addresses prepended by the client don't change the client IP seen by the proxy
+		forged := map[string]string{"X-Forwarded-For": "10.0.0.4, 10.0.0.2"}
+		require.Equal(t, limitExceededErrorCode, callAccessTest(t, server, "test_echo", forged).code())
+	})
+
+	t.Run("TrustedProxies", func(t *testing.T) {
+		t.Parallel()
+
+		server := newAccessTestServer(t, httpcfg.AccessCfg{
+			RateLimit:          httpcfg.RateLimit{Rate: rate, Burst: 1},
+			ForwardedForHeader: "X-Forwarded-For",
+			TrustedProxies:     2,
+		})
+		require.Zero(t, callAccessTest(t, server, "test_echo",
+			map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.2, 10.0.1.1"}).code())
+		require.Equal(t, limitExceededErrorCode, callAccessTest(t, server, "test_echo",
+			map[string]string{"X-Forwarded-For": "10.0.0.4, 10.0.0.2, 10.0.1.1"}).code())
+		require.Zero(t, callAccessTest(t, server, "test_echo",
+			map[string]string{"X-Forwarded-For": "10.0.0.2, 10.0.0.3, 10.0.1.1"}).code())
+	})
+
+	t.Run("PerMethod", func(t *testing.T) {
+		t.Parallel()
+
+		server := newAccessTestServer(t, httpcfg.AccessCfg{
+			MethodRateLimits: map[string]httpcfg.RateLimit{"test_large": {Rate: rate, Burst: 1}},
+		})
+		require.Zero(t, callAccessTest(t, server, "test_large", nil).code())
+		require.Equal(t, limitExceededErrorCode, callAccessTest(t, server, "test_large", nil).code())
+		require.Zero(t, callAccessTest(t, server, "test_echo", nil).code())
+	})
+
+	t.Run("PerAPIKey", func(t *testing.T) {
+		t.Parallel()
+
+		server := newAccessTestServer(t, httpcfg.AccessCfg{
+			RateLimit: httpcfg.RateLimit{Rate: rate, Burst: 1},
+			APIKeys: map[string]httpcfg.RateLimit{
+				"unlimited": {},
+				"limited":   {Rate: rate, Burst: 1},
+			},
+		})
+		unlimited := map[string]string{nil_http.APIKeyHeader: "unlimited"}
+		limited := map[string]string{nil_http.APIKeyHeader: "limited"}
+		for range 3 {
+			require.Zero(t, callAccessTest(t, server, "test_echo", unlimited).code())
+		}
+		require.Zero(t, callAccessTest(t, server, "test_echo", limited).code())
+		require.Equal(t, limitExceededErrorCode, callAccessTest(t, server, "test_echo", limited).code())
+		require.Zero(t, callAccessTest(t, server, "test_echo", nil).code())
+	})
+}
+
+func TestAccessAPIKey(t *testing.T) {
+	t.Parallel()
+
+	server := newAccessTestServer(t, httpcfg.AccessCfg{
+		APIKeys:       map[string]httpcfg.RateLimit{"secret": {}},
+		RequireAPIKey: true,
+	})
+
+	w := serveAccessTest(t, server, `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["hi"]}`, nil)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	var resp accessTestResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, unauthorizedErrorCode, resp.code())
+
+	wrong := map[string]string{nil_http.APIKeyHeader: "wrong"}
+	require.Equal(t, unauthorizedErrorCode, callAccessTest(t, server, "test_echo", wrong).code())
+
+	valid := map[string]string{nil_http.APIKeyHeader: "secret"}
+	resp = *callAccessTest(t, server, "test_echo", valid)
+	require.Zero(t, resp.code())
+	require.JSONEq(t, `"hi"`, string(resp.Result))
+}
+
+func TestAccessSizeLimits(t *testing.T) {
+	t.Parallel()
+
+	server := newAccessTestServer(t, httpcfg.AccessCfg{
+		MaxBatchSize:    2,
+		MaxResponseSize: 500,
+	})
+
+	resp := batchAccessTest(t, server, "rpc_modules", "rpc_modules")
+	require.Len(t, resp, 2)
+	require.Zero(t, resp[0].code())
+	require.Zero(t, resp[1].code())
+
+	w := serveAccessTest(t, server, `[{"jsonrpc":"2.0","id":1,"method":"rpc_modules"},`+
+		`{"jsonrpc":"2.0","id":2,"method":"rpc_modules"},{"jsonrpc":"2.0","id":3,"method":"rpc_modules"}]`, nil)
+	var single accessTestResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &single))
+	require.Equal(t, limitExceededErrorCode, single.code())
+
+	require.Equal(t, limitExceededErrorCode, callAccessTest(t, server, "test_large", nil).code())
+
+	resp = batchAccessTest(t, server, "rpc_modules", "test_large")
+	require.Len(t, resp, 2)
+	require.Zero(t, resp[0].code())
+	require.Equal(t, limitExceededErrorCode, resp[1].code())
+}
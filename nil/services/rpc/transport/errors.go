@@ -9,10 +9,18 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(InvalidParamsError)
 	_ Error = new(CustomError)
+	_ Error = new(rejectedError)
 )
 
 const defaultErrorCode = -32000
 
+// Codes of the requests rejected by the access config, the last two are defined by EIP-1474.
+const (
+	unauthorizedErrorCode       = -32001
+	methodNotSupportedErrorCode = -32004
+	limitExceededErrorCode      = -32005
+)
+
 type methodNotFoundError struct{ method string }
 
 func (e *methodNotFoundError) ErrorCode() int { return -32601 }
@@ -57,3 +65,23 @@ type CustomError struct {
 func (e *CustomError) ErrorCode() int { return e.Code }
 
 func (e *CustomError) Error() string { return e.Message }
+
+// Reasons of the rejected requests reported in metrics.
+const (
+	rejectUnauthorized     = "unauthorized"
+	rejectMethodNotAllowed = "method_not_allowed"
+	rejectRateLimited      = "rate_limited"
+	rejectBatchTooLarge    = "batch_too_large"
+	rejectResponseTooLarge = "response_too_large"
+)
+
+// request is rejected by the access config of the server
+type rejectedError struct {
+	code    int
+	reason  string
+	message string
+}
+
+func (e *rejectedError) ErrorCode() int { return e.code }
+
+func (e *rejectedError) Error() string { return e.message }
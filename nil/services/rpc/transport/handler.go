@@ -40,6 +40,9 @@ type handler struct {
 
 	// requests with heavy params, logged only on trace level
 	heavyLogBlacklist map[string]struct{}
+
+	metrics *metricsHandler
+	access  *clientAccess
 }
 
 func HandleError(err error, stream *jsoniter.Stream) {
@@ -74,7 +77,7 @@ func HandleError(err error, stream *jsoniter.Stream) {
 	stream.WriteObjectEnd()
 }
 
-func newHandler(connCtx context.Context, conn JsonWriter, reg *serviceRegistry, maxBatchConcurrency uint, traceRequests bool, logger zerolog.Logger, rpcSlowLogThreshold time.Duration) *handler {
+func newHandler(connCtx context.Context, conn JsonWriter, reg *serviceRegistry, maxBatchConcurrency uint, traceRequests bool, logger zerolog.Logger, rpcSlowLogThreshold time.Duration, metrics *metricsHandler, access *clientAccess) *handler {
 	rootCtx, cancelRoot := context.WithCancel(connCtx)
 
 	h := &handler{
@@ -90,6 +93,9 @@ func newHandler(connCtx context.Context, conn JsonWriter, reg *serviceRegistry,
 		slowLogThreshold:  rpcSlowLogThreshold,
 		slowLogBlacklist:  rpccfg.SlowLogBlackList,
 		heavyLogBlacklist: rpccfg.HeavyLogMethods,
+
+		metrics: metrics,
+		access:  access,
 	}
 
 	return h
@@ -185,6 +191,7 @@ func (h *handler) handleBatch(msgs []*Message) {
 		}(i)
 	}
 	wg.Wait()
+	h.limitBatchResponseSize(msgs, answers)
 	if len(answers) > 0 {
 		_ = h.conn.WriteJSON(h.rootCtx, answers)
 	}
@@ -198,9 +205,37 @@ func (h *handler) handleMsg(msg *Message) {
 		buffer, _ := json.Marshal(answer) //nolint: errchkjson
 		_, _ = stream.Write(buffer)
 	}
+	if err := h.access.controller.checkResponseSize(len(stream.Buffer())); err != nil {
+		recordRejected(h.rootCtx, h.metrics, h.reg, msg.Method, err)
+		_ = h.conn.WriteJSON(h.rootCtx, msg.errorResponse(err))
+		return
+	}
 	_ = h.conn.WriteJSON(h.rootCtx, json.RawMessage(stream.Buffer()))
 }
 
+// limitBatchResponseSize replaces the answers which don't fit into the response size limit with errors.
+func (h *handler) limitBatchResponseSize(msgs []*Message, answers []interface{}) {
+	if h.access.controller.cfg.MaxResponseSize <= 0 {
+		return
+	}
+
+	total := 0
+	for i, answer := range answers {
+		if answer == nil {
+			continue
+		}
+		data, err := json.Marshal(answer)
+		if err != nil {
+			continue
+		}
+		total += len(data)
+		if err := h.access.controller.checkResponseSize(total); err != nil {
+			recordRejected(h.rootCtx, h.metrics, h.reg, msgs[i].Method, err)
+			answers[i] = msgs[i].errorResponse(err)
+		}
+	}
+}
+
 // handleCallMsg executes a call message and returns the answer.
 func (h *handler) handleCallMsg(ctx context.Context, msg *Message, stream *jsoniter.Stream) *Message {
 	start := time.Now()
@@ -219,6 +254,7 @@ func (h *handler) handleCallMsg(ctx context.Context, msg *Message, stream *jsoni
 
 		resp := h.handleCall(ctx, msg, stream)
 		requestDuration := time.Since(start)
+		h.recordRequest(ctx, msg, resp, requestDuration)
 
 		if doSlowLog {
 			if requestDuration > h.slowLogThreshold {
@@ -244,8 +280,21 @@ func (h *handler) handleCallMsg(ctx context.Context, msg *Message, stream *jsoni
 	}
 }
 
+func (h *handler) recordRequest(ctx context.Context, msg *Message, resp *Message, duration time.Duration) {
+	method := msg.Method
+	if h.reg.callback(method) == nil {
+		// don't let arbitrary method names blow up the metric cardinality
+		method = "unknown"
+	}
+	h.metrics.RecordRequest(ctx, method, resp != nil && resp.Error != nil, duration)
+}
+
 // handleCall processes method calls.
 func (h *handler) handleCall(ctx context.Context, msg *Message, stream *jsoniter.Stream) *Message {
+	if err := h.access.allow(msg.Method); err != nil {
+		recordRejected(ctx, h.metrics, h.reg, msg.Method, err)
+		return msg.errorResponse(err)
+	}
 	callb := h.reg.callback(msg.Method)
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
@@ -0,0 +1,49 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type metricsHandler struct {
+	requestDuration  telemetry.Histogram
+	rejectedRequests telemetry.Counter
+}
+
+func newMetricsHandler() (*metricsHandler, error) {
+	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/services/rpc")
+
+	requestDuration, err := meter.Int64Histogram("rpc_request_duration", metric.WithUnit("ms"),
+		metric.WithDescription("Duration of RPC requests per method"))
+	if err != nil {
+		return nil, err
+	}
+
+	rejectedRequests, err := meter.Int64Counter("rpc_rejected_requests",
+		metric.WithDescription("Number of RPC requests rejected by the access limits per method and reason"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &metricsHandler{requestDuration: requestDuration, rejectedRequests: rejectedRequests}, nil
+}
+
+func (mh *metricsHandler) RecordRequest(ctx context.Context, method string, failed bool, duration time.Duration) {
+	mh.requestDuration.Record(ctx, duration.Milliseconds(), telattr.With(
+		attribute.String(logging.FieldRpcMethod, method),
+		attribute.Bool("failed", failed),
+	))
+}
+
+func (mh *metricsHandler) RecordRejected(ctx context.Context, method string, reason string) {
+	mh.rejectedRequests.Add(ctx, 1, telattr.With(
+		attribute.String(logging.FieldRpcMethod, method),
+		attribute.String("reason", reason),
+	))
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -14,6 +13,7 @@ import (
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 	nil_http "github.com/NilFoundation/nil/nil/services/rpc/internal/http"
 	mapset "github.com/deckarep/golang-set"
 	"github.com/rs/zerolog"
@@ -37,10 +37,11 @@ type Server struct {
 	batchConcurrency    uint
 	traceRequests       bool     // Whether to print requests at INFO level
 	debugSingleRequest  bool     // Whether to print requests at INFO level
-	batchLimit          int      // Maximum number of requests in a batch
 	keepHeaders         []string // headers to pass to request handler
 	logger              zerolog.Logger
 	rpcSlowLogThreshold time.Duration
+	metrics             *metricsHandler
+	access              *accessController
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -48,9 +49,13 @@ func NewServer(traceRequests, debugSingleRequest bool, logger zerolog.Logger, rp
 	server := &Server{
 		services: serviceRegistry{logger: logger}, codecs: mapset.NewSet(), run: 1, batchConcurrency: defaultBatchConcurrency,
 		traceRequests: traceRequests, debugSingleRequest: debugSingleRequest, logger: logger, rpcSlowLogThreshold: rpcSlowLogThreshold,
-		keepHeaders: keepHeaders,
+		keepHeaders: keepHeaders, access: newAccessController(httpcfg.AccessCfg{}),
 	}
 
+	var err error
+	server.metrics, err = newMetricsHandler()
+	check.PanicIfErr(err)
+
 	// Register the default service providing meta-information about the RPC service such
 	// as the services and methods it offers.
 	check.PanicIfErr(server.RegisterName(MetadataApi, &RPCService{server: server}))
@@ -64,9 +69,9 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
-// SetBatchLimit sets limit of number of requests in a batch
-func (s *Server) SetBatchLimit(limit int) {
-	s.batchLimit = limit
+// SetAccess sets the limits of the served API, it must be called before the server starts serving.
+func (s *Server) SetAccess(cfg httpcfg.AccessCfg) {
+	s.access = newAccessController(cfg)
 }
 
 func newHTTPServerConn(r *http.Request, w http.ResponseWriter) ServerCodec {
@@ -120,7 +125,15 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 	}
 	ctx = context.WithValue(ctx, HeadersContextKey, headers)
 
-	h := newHandler(ctx, codec, &s.services, s.batchConcurrency, s.traceRequests, s.logger, s.rpcSlowLogThreshold)
+	client, err := s.access.client(r)
+	if err != nil {
+		recordRejected(ctx, s.metrics, &s.services, "", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = codec.WriteJSON(ctx, errorMessage(err))
+		return
+	}
+
+	h := newHandler(ctx, codec, &s.services, s.batchConcurrency, s.traceRequests, s.logger, s.rpcSlowLogThreshold, s.metrics, client)
 
 	reqs, batch, err := codec.Read()
 	if err != nil {
@@ -130,8 +143,9 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 		return
 	}
 	if batch {
-		if s.batchLimit > 0 && len(reqs) > s.batchLimit {
-			_ = codec.WriteJSON(ctx, errorMessage(fmt.Errorf("batch limit %d exceeded (can increase by --rpc.batch.limit). Requested batch of size: %d", s.batchLimit, len(reqs))))
+		if err := s.access.checkBatchSize(len(reqs)); err != nil {
+			recordRejected(ctx, s.metrics, &s.services, "", err)
+			_ = codec.WriteJSON(ctx, errorMessage(err))
 		} else {
 			h.handleBatch(reqs)
 		}
@@ -12,6 +12,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
 	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
@@ -29,11 +30,16 @@ type AggregatorMetrics interface {
 
 type AggregatorTaskStorage interface {
 	AddTaskEntries(ctx context.Context, tasks ...*types.TaskEntry) error
+	RemoveBatchTasks(ctx context.Context, batchIds ...types.BatchId) error
 }
 
 type AggregatorBlockStorage interface {
 	TryGetLatestFetched(ctx context.Context) (*types.MainBlockRef, error)
+	TryGetBlock(ctx context.Context, id types.BlockId) (*jsonrpc.RPCBlock, error)
 	SetBlockBatch(ctx context.Context, batch *types.BlockBatch) error
+	RollbackLatestFetched(ctx context.Context, ancestor types.MainBlockRef) ([]types.BatchId, error)
+	GetBatchesToProve(ctx context.Context, maxInFlight int) ([]*types.BlockBatch, error)
+	SetBatchAsProving(ctx context.Context, id types.BlockId) error
 }
 
 type aggregator struct {
@@ -45,6 +51,7 @@ type aggregator struct {
 	timer          common.Timer
 	metrics        AggregatorMetrics
 	workerAction   *concurrent.Suspendable
+	maxInFlight    int
 }
 
 func NewAggregator(
@@ -55,6 +62,7 @@ func NewAggregator(
 	logger zerolog.Logger,
 	metrics AggregatorMetrics,
 	pollingDelay time.Duration,
+	maxBatchesInFlight int,
 ) *aggregator {
 	agg := &aggregator{
 		rpcClient:    rpcClient,
@@ -67,8 +75,9 @@ func NewAggregator(
 			logger,
 			batches.DefaultCommitOptions(),
 		),
-		timer:   timer,
-		metrics: metrics,
+		timer:       timer,
+		metrics:     metrics,
+		maxInFlight: maxBatchesInFlight,
 	}
 
 	agg.workerAction = concurrent.NewSuspendable(agg.runIteration, pollingDelay)
@@ -138,14 +147,108 @@ func (agg *aggregator) processNewBlocks(ctx context.Context) error {
 		return err
 	}
 
-	if err := agg.processShardBlocks(ctx, *latestBlock); err != nil {
-		// todo: launch block re-fetching in case of ErrBlockMismatch
+	err = agg.processShardBlocks(ctx, *latestBlock)
+	if errors.Is(err, types.ErrBlockMismatch) {
+		agg.logger.Warn().Err(err).Msg("main shard chain diverged from the fetched one, rolling back")
+		if err := agg.rollbackToCommonAncestor(ctx, *latestBlock); err != nil {
+			return fmt.Errorf("error handling main shard fork: %w", err)
+		}
+		err = agg.processShardBlocks(ctx, *latestBlock)
+	}
+	if err != nil {
 		return fmt.Errorf("error processing blocks: %w", err)
 	}
 
+	if err := agg.startProving(ctx); err != nil {
+		return fmt.Errorf("error starting batches proving: %w", err)
+	}
+
+	return nil
+}
+
+// startProving creates proof tasks for fetched batches while the number of batches in flight allows it.
+// Batches are admitted to proving in order of their main shard blocks.
+func (agg *aggregator) startProving(ctx context.Context) error {
+	batches, err := agg.blockStorage.GetBatchesToProve(ctx, agg.maxInFlight)
+	if err != nil {
+		return fmt.Errorf("error reading batches to prove: %w", err)
+	}
+
+	for _, batch := range batches {
+		if err := agg.createProofTasks(ctx, batch); err != nil {
+			return err
+		}
+
+		if err := agg.blockStorage.SetBatchAsProving(ctx, types.IdFromBlock(batch.MainShardBlock)); err != nil {
+			return fmt.Errorf("error setting batch as proving, mainHash=%s: %w", batch.MainShardBlock.Hash, err)
+		}
+	}
+
+	return nil
+}
+
+// rollbackToCommonAncestor drops fetched blocks and their tasks which are not a part of the actual main shard chain,
+// so fetching continues from the latest common block. The batches committed to L1 are not dropped,
+// ErrForkBelowProposed is returned if the chain diverged below them.
+func (agg *aggregator) rollbackToCommonAncestor(ctx context.Context, actualLatest types.MainBlockRef) error {
+	ancestor, err := agg.findCommonAncestor(ctx, actualLatest)
+	if err != nil {
+		return err
+	}
+
+	removedBatches, err := agg.blockStorage.RollbackLatestFetched(ctx, *ancestor)
+	if err != nil {
+		return fmt.Errorf("error rolling back fetched blocks: %w", err)
+	}
+
+	if err := agg.taskStorage.RemoveBatchTasks(ctx, removedBatches...); err != nil {
+		return fmt.Errorf("error removing tasks of rolled back batches: %w", err)
+	}
+
+	agg.logger.Info().
+		Stringer(logging.FieldBlockHash, ancestor.Hash).
+		Stringer(logging.FieldBlockNumber, ancestor.Number).
+		Int("removedBatches", len(removedBatches)).
+		Msg("rolled back to the common ancestor of the main shard chain")
 	return nil
 }
 
+// findCommonAncestor walks back from the latest fetched block through the stored blocks
+// until it finds one which is a part of the actual main shard chain.
+// Blocks which are already proposed are not stored, so the walk stops at the latest proposed block.
+func (agg *aggregator) findCommonAncestor(
+	ctx context.Context, actualLatest types.MainBlockRef,
+) (*types.MainBlockRef, error) {
+	ref, err := agg.blockStorage.TryGetLatestFetched(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading latest fetched block from storage: %w", err)
+	}
+
+	for ref != nil {
+		if ref.Number <= actualLatest.Number {
+			block, err := agg.rpcClient.GetBlock(ctx, coreTypes.MainShardId, transport.BlockNumber(ref.Number), false)
+			if err != nil {
+				return nil, fmt.Errorf("error fetching main shard block %d: %w", ref.Number, err)
+			}
+			if block != nil && block.Hash == ref.Hash {
+				return ref, nil
+			}
+		}
+
+		stored, err := agg.blockStorage.TryGetBlock(ctx, types.NewBlockId(coreTypes.MainShardId, ref.Hash))
+		if err != nil {
+			return nil, fmt.Errorf("error reading block from storage, mainHash=%s: %w", ref.Hash, err)
+		}
+		if stored == nil || stored.Number == 0 {
+			return nil, fmt.Errorf("%w: no common block found, last checked hash=%s", types.ErrForkBelowProposed, ref.Hash)
+		}
+
+		ref = &types.MainBlockRef{Hash: stored.ParentHash, Number: stored.Number - 1}
+	}
+
+	return nil, errors.New("latest fetched block is not set")
+}
+
 // fetchLatestBlockRef retrieves the latest block for main shard
 func (agg *aggregator) fetchLatestBlockRef(ctx context.Context) (*types.MainBlockRef, error) {
 	block, err := agg.rpcClient.GetBlock(ctx, coreTypes.MainShardId, "latest", false)
@@ -244,10 +347,6 @@ func (agg *aggregator) handleBlockBatch(ctx context.Context, batch *types.BlockB
 		return err
 	}
 
-	if err := agg.createProofTasks(ctx, batch); err != nil {
-		return fmt.Errorf("error creating proof tasks, mainHash=%s: %w", batch.MainShardBlock.Hash, err)
-	}
-
 	if err := agg.blockStorage.SetBlockBatch(ctx, batch); err != nil {
 		return fmt.Errorf("error storing block batch, mainHash=%s: %w", batch.MainShardBlock.Hash, err)
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
@@ -60,6 +61,7 @@ func (s *AggregatorTestSuite) SetupSuite() {
 		logger,
 		metricsHandler,
 		time.Second,
+		NewDefaultConfig().MaxBatchesInFlight,
 	)
 }
 
@@ -77,6 +79,8 @@ func (s *AggregatorTestSuite) Test_No_New_Block_To_Fetch() {
 	batch := testaide.NewBlockBatch(testaide.ShardsCount)
 	err := s.blockStorage.SetBlockBatch(s.ctx, batch)
 	s.Require().NoError(err)
+	err = s.blockStorage.SetBatchAsProving(s.ctx, scTypes.IdFromBlock(batch.MainShardBlock))
+	s.Require().NoError(err)
 
 	s.rpcClientMock.GetBlockFunc = func(_ context.Context, shardId types.ShardId, blockId any, fullTx bool) (*jsonrpc.RPCBlock, error) {
 		if shardId == types.MainShardId {
@@ -97,6 +101,46 @@ func (s *AggregatorTestSuite) Test_No_New_Block_To_Fetch() {
 	s.requireNoNewTasks()
 }
 
+func (s *AggregatorTestSuite) Test_Batches_In_Flight_Limit() {
+	maxInFlight := NewDefaultConfig().MaxBatchesInFlight
+	batches := testaide.NewBatchesSequence(maxInFlight + 2)
+	for _, batch := range batches {
+		err := s.blockStorage.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+	}
+
+	s.rpcClientMock.GetBlockFunc = blockGenerator(batches[len(batches)-1].MainShardBlock)
+
+	err := s.aggregator.processNewBlocks(s.ctx)
+	s.Require().NoError(err)
+
+	// only the earliest batches were admitted to proving
+	views, err := s.blockStorage.GetBatchViews(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(views, len(batches))
+	for i, view := range views {
+		s.Require().Equal(batches[i].Id, view.Id)
+		if i < maxInFlight {
+			s.Require().Equal(scTypes.BatchStatusProving, view.Status)
+		} else {
+			s.Require().Equal(scTypes.BatchStatusFetched, view.Status)
+		}
+	}
+
+	// the next batch is admitted once the earliest in-flight batch is finalized
+	firstId := scTypes.IdFromBlock(batches[0].MainShardBlock)
+	s.Require().NoError(s.blockStorage.SetBlockAsProved(s.ctx, firstId))
+	s.Require().NoError(s.blockStorage.SetBlockAsProposed(s.ctx, firstId))
+	err = s.aggregator.processNewBlocks(s.ctx)
+	s.Require().NoError(err)
+
+	views, err = s.blockStorage.GetBatchViews(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(scTypes.BatchStatusFinalized, views[0].Status)
+	s.Require().Equal(scTypes.BatchStatusProving, views[maxInFlight].Status)
+	s.Require().Equal(scTypes.BatchStatusFetched, views[maxInFlight+1].Status)
+}
+
 func (s *AggregatorTestSuite) Test_Fetched_Not_Ready_Batch() {
 	mainBlock := testaide.NewMainShardBlock()
 	mainBlock.ChildBlocks[1] = common.EmptyHash
@@ -122,13 +166,75 @@ func (s *AggregatorTestSuite) Test_Fetched_Not_Ready_Batch() {
 	s.requireNoNewTasks()
 }
 
-func (s *AggregatorTestSuite) Test_Main_Parent_Hash_Mismatch() {
-	batches := testaide.NewBatchesSequence(2)
-	err := s.blockStorage.SetBlockBatch(s.ctx, batches[0])
+func (s *AggregatorTestSuite) Test_Main_Shard_Fork() {
+	batches := testaide.NewBatchesSequence(3)
+	for _, batch := range batches {
+		err := s.blockStorage.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+	}
+
+	rolledBackTask := testaide.NewTaskEntry(time.Now(), scTypes.WaitingForExecutor, scTypes.UnknownExecutorId)
+	rolledBackTask.Task.BatchId = batches[2].Id
+	err := s.taskStorage.AddTaskEntries(s.ctx, rolledBackTask)
 	s.Require().NoError(err)
 
-	nextMainBlock := batches[1].MainShardBlock
-	nextMainBlock.ParentHash = testaide.RandomHash()
+	// the actual chain diverges after the first stored block and is longer than the fetched one
+	commonBlock := batches[0].MainShardBlock
+	forkBlocks := testaide.NewBatchesSequence(4)
+	forkBlocks[0].MainShardBlock = commonBlock
+	chain := make([]*jsonrpc.RPCBlock, 0, len(forkBlocks))
+	for i, batch := range forkBlocks {
+		if i > 0 {
+			batch.MainShardBlock.ParentHash = forkBlocks[i-1].MainShardBlock.Hash
+			batch.MainShardBlock.Number = commonBlock.Number + types.BlockNumber(i)
+		}
+		chain = append(chain, batch.MainShardBlock)
+	}
+
+	s.rpcClientMock.GetBlockFunc = chainGenerator(chain)
+	s.rpcClientMock.GetBlocksRangeFunc = func(_ context.Context, _ types.ShardId, from types.BlockNumber, to types.BlockNumber, _ bool, _ int) ([]*jsonrpc.RPCBlock, error) {
+		var blocks []*jsonrpc.RPCBlock
+		for _, block := range chain {
+			if block.Number >= from && block.Number < to {
+				blocks = append(blocks, block)
+			}
+		}
+		return blocks, nil
+	}
+
+	err = s.aggregator.processNewBlocks(s.ctx)
+	s.Require().NoError(err)
+
+	// latest fetched block is the head of the actual chain
+	mainRef, err := s.blockStorage.TryGetLatestFetched(s.ctx)
+	s.Require().NoError(err)
+	s.Require().True(mainRef.Equals(chain[len(chain)-1]))
+
+	// blocks from the abandoned branch were removed along with their tasks
+	s.requireBlockStored(scTypes.IdFromBlock(commonBlock))
+	for _, batch := range batches[1:] {
+		storedBlock, err := s.blockStorage.TryGetBlock(s.ctx, scTypes.IdFromBlock(batch.MainShardBlock))
+		s.Require().NoError(err)
+		s.Require().Nil(storedBlock)
+	}
+	taskEntry, err := s.taskStorage.TryGetTaskEntry(s.ctx, rolledBackTask.Task.Id)
+	s.Require().NoError(err)
+	s.Require().Nil(taskEntry)
+
+	for _, block := range chain[1:] {
+		s.requireBlockStored(scTypes.IdFromBlock(block))
+	}
+}
+
+func (s *AggregatorTestSuite) Test_Main_Shard_Fork_Below_Proposed() {
+	batches := testaide.NewBatchesSequence(2)
+	for _, batch := range batches {
+		err := s.blockStorage.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+	}
+
+	nextMainBlock := testaide.NewMainShardBlock()
+	nextMainBlock.Number = batches[1].MainShardBlock.Number + 1
 
 	s.rpcClientMock.GetBlockFunc = blockGenerator(nextMainBlock)
 
@@ -136,17 +242,53 @@ func (s *AggregatorTestSuite) Test_Main_Parent_Hash_Mismatch() {
 		return []*jsonrpc.RPCBlock{nextMainBlock}, nil
 	}
 
-	err = s.aggregator.processNewBlocks(s.ctx)
-	s.Require().ErrorIs(err, scTypes.ErrBlockMismatch)
+	err := s.aggregator.processNewBlocks(s.ctx)
+	s.Require().ErrorIs(err, scTypes.ErrForkBelowProposed)
 
 	// latest fetched block was not updated
 	mainRef, err := s.blockStorage.TryGetLatestFetched(s.ctx)
 	s.Require().NoError(err)
-	s.Require().True(mainRef.Equals(batches[0].MainShardBlock))
+	s.Require().True(mainRef.Equals(batches[1].MainShardBlock))
 
 	s.requireNoNewTasks()
 }
 
+func (s *AggregatorTestSuite) Test_Main_Shard_Fork_Below_Committed() {
+	batches := testaide.NewBatchesSequence(2)
+	for _, batch := range batches {
+		err := s.blockStorage.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+	}
+	committedId := scTypes.IdFromBlock(batches[1].MainShardBlock)
+	err := s.blockStorage.SetBlockAsProved(s.ctx, scTypes.IdFromBlock(batches[0].MainShardBlock))
+	s.Require().NoError(err)
+	err = s.blockStorage.SetBlockAsProved(s.ctx, committedId)
+	s.Require().NoError(err)
+	err = s.blockStorage.SetBatchAsCommitted(s.ctx, committedId)
+	s.Require().NoError(err)
+
+	// the actual chain diverges after the first stored block, below the committed batch
+	commonBlock := batches[0].MainShardBlock
+	forkBlock := testaide.NewMainShardBlock()
+	forkBlock.ParentHash = commonBlock.Hash
+	forkBlock.Number = commonBlock.Number + 1
+	chain := []*jsonrpc.RPCBlock{commonBlock, forkBlock}
+
+	s.rpcClientMock.GetBlockFunc = chainGenerator(chain)
+	s.rpcClientMock.GetBlocksRangeFunc = func(_ context.Context, _ types.ShardId, from types.BlockNumber, to types.BlockNumber, _ bool, _ int) ([]*jsonrpc.RPCBlock, error) {
+		return nil, nil
+	}
+
+	err = s.aggregator.processNewBlocks(s.ctx)
+	s.Require().ErrorIs(err, scTypes.ErrForkBelowProposed)
+
+	// the committed batch is kept until the manual reset
+	mainRef, err := s.blockStorage.TryGetLatestFetched(s.ctx)
+	s.Require().NoError(err)
+	s.Require().True(mainRef.Equals(batches[1].MainShardBlock))
+	s.requireBlockStored(committedId)
+}
+
 func (s *AggregatorTestSuite) Test_Fetch_At_Zero_State() {
 	mainRef, err := s.blockStorage.TryGetLatestFetched(s.ctx)
 	s.Require().NoError(err)
@@ -212,6 +354,29 @@ func blockGenerator(mainBlock *jsonrpc.RPCBlock) func(context.Context, types.Sha
 	}
 }
 
+// chainGenerator serves main shard blocks of the given chain by number, the last one is the latest
+func chainGenerator(chain []*jsonrpc.RPCBlock) func(context.Context, types.ShardId, any, bool) (*jsonrpc.RPCBlock, error) {
+	latest := chain[len(chain)-1]
+	childGenerator := blockGenerator(latest)
+
+	return func(ctx context.Context, shardId types.ShardId, blockId any, fullTx bool) (*jsonrpc.RPCBlock, error) {
+		if shardId != types.MainShardId {
+			return childGenerator(ctx, shardId, blockId, fullTx)
+		}
+
+		number, ok := blockId.(transport.BlockNumber)
+		if !ok {
+			return latest, nil
+		}
+		for _, block := range chain {
+			if block.Number == types.BlockNumber(number) {
+				return block, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
 // requireNoNewTasks asserts that there are no new tasks available for execution
 func (s *AggregatorTestSuite) requireNoNewTasks() {
 	s.T().Helper()
@@ -0,0 +1,23 @@
+package core
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+)
+
+type BatchViewsGetter interface {
+	GetBatchViews(ctx context.Context) ([]*public.BatchView, error)
+}
+
+type batchDebugApi struct {
+	storage BatchViewsGetter
+}
+
+func newBatchDebugApi(storage BatchViewsGetter) public.BatchDebugApi {
+	return &batchDebugApi{storage: storage}
+}
+
+func (api *batchDebugApi) GetBatches(ctx context.Context) ([]*public.BatchView, error) {
+	return api.storage.GetBatchViews(ctx)
+}
@@ -14,6 +14,7 @@ type Config struct {
 	RpcEndpoint             string
 	TaskListenerRpcEndpoint string
 	PollingDelay            time.Duration
+	MaxBatchesInFlight      int
 	ProposerParams          *ProposerParams
 	Telemetry               *telemetry.Config
 }
@@ -23,6 +24,7 @@ func NewDefaultConfig() *Config {
 		RpcEndpoint:             "tcp://127.0.0.1:8529",
 		TaskListenerRpcEndpoint: DefaultTaskRpcEndpoint,
 		PollingDelay:            time.Second,
+		MaxBatchesInFlight:      4,
 		ProposerParams:          NewDefaultProposerParams(),
 		Telemetry: &telemetry.Config{
 			ServiceName: "sync_committee",
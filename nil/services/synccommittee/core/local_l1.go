@@ -0,0 +1,29 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// NewLocalL1 creates the in-process L1 chain for the local runs of the sync committee.
+// The chain has the fake rollup contract at the address from the params, initialized with the genesis state root,
+// and the account of the proposer private key is funded. The blocks are produced while its Run method is running.
+func NewLocalL1(params *ProposerParams, genesisStateRoot common.Hash, blockPeriod time.Duration) (*rollupcontract.LocalL1, error) {
+	key, err := crypto.HexToECDSA(params.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("converting private key hex to ECDSA: %w", err)
+	}
+	return rollupcontract.NewLocalL1(
+		params.ContractAddress,
+		[]ethcommon.Address{crypto.PubkeyToAddress(key.PublicKey)},
+		genesisStateRoot,
+		blockPeriod,
+		logging.NewLogger("local_l1"),
+	)
+}
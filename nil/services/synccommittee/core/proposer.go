@@ -8,12 +8,15 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/concurrent"
+	"github.com/NilFoundation/nil/nil/internal/remotesigner"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/srv"
 	scTypes "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/rs/zerolog"
 )
@@ -26,6 +29,12 @@ type ProposerStorage interface {
 	TryGetNextProposalData(ctx context.Context) (*scTypes.ProposalData, error)
 
 	SetBlockAsProposed(ctx context.Context, id scTypes.BlockId) error
+
+	TryGetNextBatchToCommit(ctx context.Context, maxInFlight int) (*scTypes.ProposalData, error)
+
+	SetBatchAsCommitted(ctx context.Context, id scTypes.BlockId) error
+
+	rollupcontract.L1TransactionStorage
 }
 
 type ProposerMetrics interface {
@@ -37,9 +46,11 @@ type proposer struct {
 	storage     ProposerStorage
 	retryRunner common.RetryRunner
 	ethClient   rollupcontract.EthClient
+	timer       common.Timer
 
 	rollupContractWrapper *rollupcontract.Wrapper
 	params                *ProposerParams
+	maxInFlight           int
 
 	metrics ProposerMetrics
 	logger  zerolog.Logger
@@ -48,9 +59,12 @@ type proposer struct {
 type ProposerParams struct {
 	Endpoint          string
 	PrivateKey        string
+	RemoteSigner      string
+	RemoteTransport   remotesigner.TransportConfig
 	ContractAddress   string
 	ProposingInterval time.Duration
 	EthClientTimeout  time.Duration
+	FeePolicy         *rollupcontract.FeePolicy
 }
 
 func NewDefaultProposerParams() *ProposerParams {
@@ -60,6 +74,7 @@ func NewDefaultProposerParams() *ProposerParams {
 		ContractAddress:   "0x796baf7E572948CD0cbC374f345963bA433b47a2",
 		ProposingInterval: 10 * time.Second,
 		EthClientTimeout:  10 * time.Second,
+		FeePolicy:         rollupcontract.NewDefaultFeePolicy(),
 	}
 }
 
@@ -68,8 +83,10 @@ func NewProposer(
 	params *ProposerParams,
 	storage ProposerStorage,
 	ethClient rollupcontract.EthClient,
+	timer common.Timer,
 	metrics ProposerMetrics,
 	logger zerolog.Logger,
+	maxBatchesInFlight int,
 ) (*proposer, error) {
 	retryRunner := common.NewRetryRunner(
 		common.RetryConfig{
@@ -82,7 +99,9 @@ func NewProposer(
 	p := &proposer{
 		storage:     storage,
 		ethClient:   ethClient,
+		timer:       timer,
 		params:      params,
+		maxInFlight: maxBatchesInFlight,
 		retryRunner: retryRunner,
 		metrics:     metrics,
 	}
@@ -126,14 +145,48 @@ func (p *proposer) initRollupContractWrapper(ctx context.Context) error {
 		return nil
 	}
 
-	var err error
-	p.rollupContractWrapper, err = rollupcontract.NewWrapper(ctx, p.params.ContractAddress, p.params.PrivateKey, p.ethClient, p.params.EthClientTimeout, p.logger)
+	txnSigner, err := p.newTransactionSigner(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.rollupContractWrapper, err = rollupcontract.NewWrapper(
+		ctx,
+		p.params.ContractAddress,
+		txnSigner,
+		p.ethClient,
+		p.storage,
+		p.params.FeePolicy,
+		p.timer,
+		p.params.EthClientTimeout,
+		p.logger,
+	)
 	if err != nil {
 		return fmt.Errorf("failed create rollup contract wrapper: %w", err)
 	}
 	return nil
 }
 
+func (p *proposer) newTransactionSigner(ctx context.Context) (rollupcontract.TransactionSigner, error) {
+	if p.params.RemoteSigner != "" {
+		client, err := remotesigner.NewClient(p.params.RemoteSigner, &p.params.RemoteTransport)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := client.EcdsaSigner(ctx, ethcommon.Address{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get L1 key from the remote signer: %w", err)
+		}
+		return signer, nil
+	}
+
+	key, err := crypto.HexToECDSA(p.params.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("converting private key hex to ECDSA: %w", err)
+	}
+	return remotesigner.NewLocalEcdsaSigner(key, nil), nil
+}
+
 func (p *proposer) initializeProvedStateRoot(ctx context.Context) error {
 	storedStateRoot, err := p.storage.TryGetProvedStateRoot(ctx)
 	if err != nil {
@@ -194,6 +247,9 @@ func (p *proposer) updateStoredStateRoot(ctx context.Context, stateRoot common.H
 	return nil
 }
 
+// proposeNextBlock runs the commit and the finalization stages. They are independent: proved batches are committed
+// regardless of whether the preceding batches can be finalized, and committed batches are finalized
+// even if committing the following ones fails.
 func (p *proposer) proposeNextBlock(ctx context.Context) error {
 	if p.rollupContractWrapper == nil {
 		err := p.initializeProvedStateRoot(ctx)
@@ -201,26 +257,72 @@ func (p *proposer) proposeNextBlock(ctx context.Context) error {
 			return err
 		}
 	}
+
+	commitErr := p.commitNextBatches(ctx)
+	return errors.Join(commitErr, p.finalizeBatches(ctx))
+}
+
+// finalizeBatches finalizes all committed batches which are ready for it.
+func (p *proposer) finalizeBatches(ctx context.Context) error {
+	for {
+		finalized, err := p.finalizeNextBatch(ctx)
+		if err != nil || !finalized {
+			return err
+		}
+	}
+}
+
+func (p *proposer) finalizeNextBatch(ctx context.Context) (bool, error) {
 	data, err := p.storage.TryGetNextProposalData(ctx)
 	if err != nil {
-		return fmt.Errorf("failed get next block to propose: %w", err)
+		return false, fmt.Errorf("failed get next block to propose: %w", err)
 	}
 	if data == nil {
 		p.logger.Debug().Msg("no block to propose")
-		return nil
+		return false, nil
+	}
+	if !data.Committed {
+		p.logger.Debug().Stringer("batchId", data.BatchId).Msg("next batch to propose is not committed yet")
+		return false, nil
 	}
 
 	err = p.sendProof(ctx, data)
 	if err != nil {
-		return fmt.Errorf("failed to send proof to L1 for block with hash=%s: %w", data.MainShardBlockHash, err)
+		return false, fmt.Errorf("failed to send proof to L1 for block with hash=%s: %w", data.MainShardBlockHash, err)
 	}
 
 	blockId := scTypes.NewBlockId(types.MainShardId, data.MainShardBlockHash)
 	err = p.storage.SetBlockAsProposed(ctx, blockId)
 	if err != nil {
-		return fmt.Errorf("failed set block with hash=%s as proposed: %w", data.MainShardBlockHash, err)
+		return false, fmt.Errorf("failed set block with hash=%s as proposed: %w", data.MainShardBlockHash, err)
+	}
+	return true, nil
+}
+
+// commitNextBatches commits data of proved batches in order, ahead of their finalization,
+// so their finalization takes only the UpdateState call once the preceding batches are finalized.
+// The number of committed, but not finalized batches is limited by the in-flight limit.
+func (p *proposer) commitNextBatches(ctx context.Context) error {
+	for {
+		data, err := p.storage.TryGetNextBatchToCommit(ctx, p.maxInFlight)
+		if err != nil {
+			return fmt.Errorf("failed get next batch to commit: %w", err)
+		}
+		if data == nil {
+			return nil
+		}
+
+		// TODO: populate with actual data
+		blobs := []kzg4844.Blob{{0x01}, {0x02}, {0x03}}
+		if err := p.commitBatch(ctx, blobs, batchIndexOf(data.BatchId)); err != nil {
+			return fmt.Errorf("failed to commit batch with id=%s: %w", data.BatchId, err)
+		}
+
+		blockId := scTypes.NewBlockId(types.MainShardId, data.MainShardBlockHash)
+		if err := p.storage.SetBatchAsCommitted(ctx, blockId); err != nil {
+			return fmt.Errorf("failed set batch with id=%s as committed: %w", data.BatchId, err)
+		}
 	}
-	return nil
 }
 
 func (p *proposer) getLatestProvedStateRoot(ctx context.Context) (common.Hash, error) {
@@ -247,7 +349,7 @@ func (p *proposer) getLatestProvedStateRoot(ctx context.Context) (common.Hash, e
 	return latestProvedState, err
 }
 
-func (p *proposer) commitBatch(ctx context.Context, blobs []kzg4844.Blob, batchIndexInBlobStorage string) (*ethtypes.Transaction, bool, error) {
+func (p *proposer) commitBatch(ctx context.Context, blobs []kzg4844.Blob, batchIndexInBlobStorage string) error {
 	var tx *ethtypes.Transaction
 	batchTxSkipped := false
 	err := p.retryRunner.Do(ctx, func(context.Context) error {
@@ -261,7 +363,7 @@ func (p *proposer) commitBatch(ctx context.Context, blobs []kzg4844.Blob, batchI
 		return err
 	})
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to upload blob: %w", err)
+		return fmt.Errorf("failed to upload blob: %w", err)
 	}
 
 	if !batchTxSkipped {
@@ -273,23 +375,17 @@ func (p *proposer) commitBatch(ctx context.Context, blobs []kzg4844.Blob, batchI
 			Any("blobHashes", tx.BlobHashes()).
 			Msg("blob transaction sent")
 
-		receipt, err := p.rollupContractWrapper.WaitForReceipt(ctx, tx.Hash())
-		if err != nil {
-			return nil, false, err
-		}
-		if receipt == nil {
-			return nil, false, errors.New("CommitBatch tx mining timout exceeded")
-		}
-		if receipt.Status != ethtypes.ReceiptStatusSuccessful {
-			return nil, false, errors.New("CommitBatch tx failed")
+		if err := p.waitForConfirmation(ctx, tx, "CommitBatch"); err != nil {
+			return err
 		}
 	}
 
-	return tx, batchTxSkipped, nil
+	return nil
 }
 
-func (p *proposer) updateState(ctx context.Context, tx *ethtypes.Transaction, data *scTypes.ProposalData, batchIndexInBlobStorage string) error {
-	blobTxSidecar := tx.BlobTxSidecar()
+func (p *proposer) updateState(
+	ctx context.Context, blobTxSidecar *ethtypes.BlobTxSidecar, data *scTypes.ProposalData, batchIndexInBlobStorage string,
+) error {
 	dataProofs, err := rollupcontract.ComputeDataProofs(blobTxSidecar)
 	if err != nil {
 		return err
@@ -303,6 +399,7 @@ func (p *proposer) updateState(ctx context.Context, tx *ethtypes.Transaction, da
 		Int("txCount", len(data.Transactions)).
 		Msg("calling UpdateState L1 method")
 
+	var tx *ethtypes.Transaction
 	updateTxSkipped := false
 	err = p.retryRunner.Do(ctx, func(context.Context) error {
 		var err error
@@ -338,24 +435,50 @@ func (p *proposer) updateState(ctx context.Context, tx *ethtypes.Transaction, da
 			Msg("UpdateState transaction sent")
 
 		p.metrics.RecordProposerTxSent(ctx, data)
+
+		if err := p.waitForConfirmation(ctx, tx, "UpdateState"); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// waitForConfirmation waits until the transaction is confirmed on L1 and checks its status.
+// If the transaction is not confirmed in time, it stays tracked by the submission manager
+// and is picked up again on the next proposing attempt.
+func (p *proposer) waitForConfirmation(ctx context.Context, tx *ethtypes.Transaction, method string) error {
+	receipt, err := p.rollupContractWrapper.WaitForReceipt(ctx, tx.Hash())
+	if err != nil {
+		return fmt.Errorf("%s tx is not confirmed: %w", method, err)
+	}
+	if receipt == nil {
+		return fmt.Errorf("%s tx mining timeout exceeded", method)
+	}
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("%s tx failed", method)
+	}
+	return nil
+}
+
+// sendProof finalizes the batch committed by commitNextBatches. The blob sidecar of the batch is recomputed
+// from its data, so the batch is not committed again.
 func (p *proposer) sendProof(ctx context.Context, data *scTypes.ProposalData) error {
+	if !data.Committed {
+		return fmt.Errorf("batch with id=%s is not committed", data.BatchId)
+	}
+
 	// TODO: populate with actual data
 	blobs := []kzg4844.Blob{{0x01}, {0x02}, {0x03}}
-	batchIndexInBlobStorage := "0x0000000000000000000000000000000000000000000000000000000000000001"
-
-	tx, _, err := p.commitBatch(ctx, blobs, batchIndexInBlobStorage)
+	blobTxSidecar, err := rollupcontract.ComputeSidecar(blobs)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to compute blob sidecar: %w", err)
 	}
 
-	if err := p.updateState(ctx, tx, data, batchIndexInBlobStorage); err != nil {
-		return err
-	}
+	return p.updateState(ctx, blobTxSidecar, data, batchIndexOf(data.BatchId))
+}
 
-	return nil
+// batchIndexOf returns the index of the batch in the L1 rollup contract
+func batchIndexOf(batchId scTypes.BatchId) string {
+	return common.BytesToHash(batchId[:]).Hex()
 }
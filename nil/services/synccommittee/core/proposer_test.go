@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
@@ -21,6 +22,8 @@ import (
 	"github.com/stretchr/testify/suite"
 )
 
+const maxBatchesInFlight = 2
+
 type ProposerTestSuite struct {
 	suite.Suite
 
@@ -116,31 +119,44 @@ func (s *ProposerTestSuite) SetupSuite() {
 	s.timer = testaide.NewTestTimer()
 	s.storage = storage.NewBlockStorage(s.db, s.timer, metricsHandler, logger)
 	s.params = NewDefaultProposerParams()
+	s.params.FeePolicy.ConfirmationDepth = 1
+	s.params.FeePolicy.PollInterval = 10 * time.Millisecond
 	s.testData = testaide.NewProposalData(3, s.timer.NowTime())
+	s.testData.Committed = true
 	s.callContractMock = newCallContractMock()
 	s.ethClient = &rollupcontract.EthClientMock{
 		CallContractFunc:    s.callContractMock.CallContract,
 		EstimateGasFunc:     func(ctx context.Context, call ethereum.CallMsg) (uint64, error) { return 123, nil },
 		SuggestGasPriceFunc: func(ctx context.Context) (*big.Int, error) { return big.NewInt(123), nil },
 		HeaderByNumberFunc: func(ctx context.Context, number *big.Int) (*ethtypes.Header, error) {
-			excessBlobGas := uint64(123)
-			return &ethtypes.Header{BaseFee: big.NewInt(123), ExcessBlobGas: &excessBlobGas}, nil
+			return testL1Header(), nil
 		},
 		PendingCodeAtFunc:    func(ctx context.Context, account ethcommon.Address) ([]byte, error) { return []byte{123}, nil },
 		PendingNonceAtFunc:   func(ctx context.Context, account ethcommon.Address) (uint64, error) { return 123, nil },
-		ChainIDFunc:          func(ctx context.Context) (*big.Int, error) { return big.NewInt(0), nil },
+		ChainIDFunc:          func(ctx context.Context) (*big.Int, error) { return big.NewInt(1), nil },
 		SuggestGasTipCapFunc: func(ctx context.Context) (*big.Int, error) { return big.NewInt(123), nil },
 		CodeAtFunc: func(ctx context.Context, contract ethcommon.Address, blockNumber *big.Int) ([]byte, error) {
 			return []byte{123}, nil
 		},
 		TransactionReceiptFunc: func(ctx context.Context, txHash ethcommon.Hash) (*ethtypes.Receipt, error) {
-			return &ethtypes.Receipt{Status: ethtypes.ReceiptStatusSuccessful}, nil
+			header := testL1Header()
+			return &ethtypes.Receipt{
+				Status:      ethtypes.ReceiptStatusSuccessful,
+				TxHash:      txHash,
+				BlockHash:   header.Hash(),
+				BlockNumber: header.Number,
+			}, nil
 		},
 	}
-	s.proposer, err = NewProposer(s.ctx, s.params, s.storage, s.ethClient, metricsHandler, logger)
+	s.proposer, err = NewProposer(s.ctx, s.params, s.storage, s.ethClient, s.timer, metricsHandler, logger, maxBatchesInFlight)
 	s.Require().NoError(err)
 }
 
+func testL1Header() *ethtypes.Header {
+	excessBlobGas := uint64(123)
+	return &ethtypes.Header{Number: big.NewInt(1), BaseFee: big.NewInt(123), ExcessBlobGas: &excessBlobGas}
+}
+
 func (s *ProposerTestSuite) SetupTest() {
 	err := s.db.DropAll()
 	s.Require().NoError(err, "failed to clear database in SetUpTest")
@@ -152,9 +168,8 @@ func (s *ProposerTestSuite) TearDownSuite() {
 	s.cancellation()
 }
 
+// Only UpdateState tx should be created, the batch is committed by the commit stage
 func (s *ProposerTestSuite) TestSendProof() {
-	// Calls inside CommitBatch
-	s.callContractMock.AddExpectedCall("isBatchCommitted", false)
 	// Calls inside UpdateState
 	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
 	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
@@ -168,32 +183,22 @@ func (s *ProposerTestSuite) TestSendProof() {
 	s.Require().NoError(err, "failed to send proof")
 
 	s.Require().NoError(s.callContractMock.EverythingCalled())
-	s.Require().Len(s.ethClient.SendTransactionCalls(), 2, "wrong number of calls to rpc client")
+	s.Require().Len(s.ethClient.SendTransactionCalls(), 1, "wrong number of calls to rpc client")
 }
 
-// Only UpdateState tx should be created
-func (s *ProposerTestSuite) TestSendProofCommitedBatch() {
-	// Calls inside CommitBatch
-	s.callContractMock.AddExpectedCall("isBatchCommitted", true)
-	// Calls inside UpdateState
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
-	s.callContractMock.AddExpectedCall("isBatchFinalized", false)
-	s.callContractMock.AddExpectedCall("isBatchCommitted", true)
-	s.callContractMock.AddExpectedCall("lastFinalizedBatchIndex", "testingFinalizedBatchIndex")
-	s.callContractMock.AddExpectedCall("finalizedStateRoots", s.testData.OldProvedStateRoot)
+// No tx should be created
+func (s *ProposerTestSuite) TestSendProofNotCommittedBatch() {
+	data := *s.testData
+	data.Committed = false
 
-	err := s.proposer.sendProof(s.ctx, s.testData)
-	s.Require().NoError(err, "failed to send proof")
+	err := s.proposer.sendProof(s.ctx, &data)
+	s.Require().Error(err, "batch must be committed before its finalization")
 
-	s.Require().Len(s.ethClient.SendTransactionCalls(), 1, "wrong number of calls to rpc client")
+	s.Require().Empty(s.ethClient.SendTransactionCalls(), "no tx should be created")
 }
 
 // No tx should be created
 func (s *ProposerTestSuite) TestSendProofFinalizedBatch() {
-	// Calls inside CommitBatch
-	s.callContractMock.AddExpectedCall("isBatchCommitted", true)
 	// Calls inside UpdateState
 	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
 	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
@@ -205,3 +210,31 @@ func (s *ProposerTestSuite) TestSendProofFinalizedBatch() {
 
 	s.Require().Empty(s.ethClient.SendTransactionCalls(), "no tx should be created")
 }
+
+// Proved batches are committed ahead of finalization up to the in-flight limit
+func (s *ProposerTestSuite) TestCommitNextBatches() {
+	batches := testaide.NewBatchesSequence(maxBatchesInFlight + 1)
+	for _, batch := range batches {
+		err := s.storage.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+		err = s.storage.SetBlockAsProved(s.ctx, types.IdFromBlock(batch.MainShardBlock))
+		s.Require().NoError(err)
+	}
+	for range maxBatchesInFlight {
+		s.callContractMock.AddExpectedCall("isBatchCommitted", false)
+	}
+
+	err := s.proposer.commitNextBatches(s.ctx)
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.callContractMock.EverythingCalled())
+	s.Require().Len(s.ethClient.SendTransactionCalls(), maxBatchesInFlight, "wrong number of calls to rpc client")
+
+	views, err := s.storage.GetBatchViews(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(views, len(batches))
+	for _, view := range views[:maxBatchesInFlight] {
+		s.Require().Equal(types.BatchStatusCommitted, view.Status)
+	}
+	s.Require().Equal(types.BatchStatusProved, views[maxBatchesInFlight].Status)
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// ResetToMainBlock drops all fetched blocks and their tasks and makes the main shard block with the given number
+// both the latest fetched and the latest proposed one, so the sync committee continues from the next block.
+// Must not be called while the sync committee service is running on the same database.
+func ResetToMainBlock(
+	ctx context.Context,
+	database db.DB,
+	rpcClient client.Client,
+	blockNumber coreTypes.BlockNumber,
+	logger zerolog.Logger,
+) error {
+	block, err := rpcClient.GetBlock(ctx, coreTypes.MainShardId, transport.BlockNumber(blockNumber), false)
+	if err != nil {
+		return fmt.Errorf("error fetching main shard block %d: %w", blockNumber, err)
+	}
+	if block == nil {
+		return fmt.Errorf("main shard block %d is not found", blockNumber)
+	}
+	blockRef, err := types.NewBlockRef(block)
+	if err != nil {
+		return err
+	}
+
+	metricsHandler, err := metrics.NewSyncCommitteeMetrics()
+	if err != nil {
+		return fmt.Errorf("error initializing metrics: %w", err)
+	}
+
+	timer := common.NewTimer()
+	blockStorage := storage.NewBlockStorage(database, timer, metricsHandler, logger)
+	taskStorage := storage.NewTaskStorage(database, timer, metricsHandler, logger)
+
+	removedBatches, err := blockStorage.ResetToBlock(ctx, block)
+	if err != nil {
+		return fmt.Errorf("error resetting block storage: %w", err)
+	}
+	if err := taskStorage.RemoveBatchTasks(ctx, removedBatches...); err != nil {
+		return fmt.Errorf("error removing tasks of dropped batches: %w", err)
+	}
+
+	logger.Info().
+		Stringer(logging.FieldBlockHash, blockRef.Hash).
+		Stringer(logging.FieldBlockNumber, blockRef.Number).
+		Int("removedBatches", len(removedBatches)).
+		Msg("sync committee state is reset")
+	return nil
+}
@@ -48,6 +48,7 @@ func New(cfg *Config, database db.DB, ethClient rollupcontract.EthClient) (*Sync
 		logger,
 		metricsHandler,
 		cfg.PollingDelay,
+		cfg.MaxBatchesInFlight,
 	)
 
 	ctx := context.Background()
@@ -57,8 +58,10 @@ func New(cfg *Config, database db.DB, ethClient rollupcontract.EthClient) (*Sync
 		cfg.ProposerParams,
 		blockStorage,
 		ethClient,
+		timer,
 		metricsHandler,
 		logger,
+		cfg.MaxBatchesInFlight,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create proposer: %w", err)
@@ -72,7 +75,10 @@ func New(cfg *Config, database db.DB, ethClient rollupcontract.EthClient) (*Sync
 	)
 
 	taskListener := rpc.NewTaskListener(
-		&rpc.TaskListenerConfig{HttpEndpoint: cfg.TaskListenerRpcEndpoint},
+		&rpc.TaskListenerConfig{
+			HttpEndpoint:  cfg.TaskListenerRpcEndpoint,
+			BatchDebugApi: newBatchDebugApi(blockStorage),
+		},
 		taskScheduler,
 		logger,
 	)
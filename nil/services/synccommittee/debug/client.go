@@ -6,6 +6,6 @@ import (
 	"github.com/rs/zerolog"
 )
 
-func NewClient(endpoint string, logger zerolog.Logger) public.TaskDebugApi {
+func NewClient(endpoint string, logger zerolog.Logger) public.DebugApi {
 	return rpc.NewTaskDebugRpcClient(endpoint, logger)
 }
@@ -5,4 +5,6 @@ import "errors"
 var (
 	ErrBatchAlreadyFinalized = errors.New("batch already finalized")
 	ErrBatchAlreadyCommitted = errors.New("batch already committed")
+	ErrSpendCapExceeded      = errors.New("batch spend cap exceeded")
+	ErrNonceConsumed         = errors.New("nonce is consumed by another transaction")
 )
@@ -0,0 +1,145 @@
+package rollupcontract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	ethparams "github.com/ethereum/go-ethereum/params"
+)
+
+// FeePolicy defines how fees of L1 transactions are estimated, bumped and capped.
+type FeePolicy struct {
+	// MaxGasTipCap is the upper bound of the priority fee per gas.
+	MaxGasTipCap *big.Int
+	// MaxGasFeeCap is the upper bound of the total fee per gas.
+	MaxGasFeeCap *big.Int
+	// MaxBlobFeeCap is the upper bound of the fee per blob gas.
+	MaxBlobFeeCap *big.Int
+	// MaxSpendPerBatch limits the worst-case cost of all pending transactions of a single batch.
+	MaxSpendPerBatch *big.Int
+
+	// BaseFeeMultiplier is applied to the current base fee and blob base fee,
+	// so the transaction stays includable while fees grow during several blocks.
+	BaseFeeMultiplier uint64
+	// FeeBumpPercent is the minimal fee increase of a regular transaction replacement.
+	FeeBumpPercent uint64
+	// BlobFeeBumpPercent is the minimal fee increase of a blob transaction replacement.
+	// Geth's blob pool rejects replacements with less than 100% bump.
+	BlobFeeBumpPercent uint64
+
+	// StuckTimeout is the time after which a transaction which is not included yet gets replaced.
+	StuckTimeout time.Duration
+	// ConfirmationDepth is the number of L1 blocks, including the one with the transaction,
+	// after which the transaction is considered final.
+	ConfirmationDepth uint64
+	// ReceiptTimeout limits the time of a single wait for a transaction receipt.
+	ReceiptTimeout time.Duration
+	// PollInterval is the interval between transaction status checks.
+	PollInterval time.Duration
+}
+
+func NewDefaultFeePolicy() *FeePolicy {
+	return &FeePolicy{
+		MaxGasTipCap:       gwei(10),
+		MaxGasFeeCap:       gwei(500),
+		MaxBlobFeeCap:      gwei(500),
+		MaxSpendPerBatch:   gwei(1_000_000_000), // 1 ETH
+		BaseFeeMultiplier:  2,
+		FeeBumpPercent:     10,
+		BlobFeeBumpPercent: 100,
+		StuckTimeout:       3 * time.Minute,
+		ConfirmationDepth:  3,
+		ReceiptTimeout:     2 * time.Minute,
+		PollInterval:       2 * time.Second,
+	}
+}
+
+func gwei(amount uint64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(amount), big.NewInt(ethparams.GWei))
+}
+
+// txFees holds fee parameters of a dynamic fee or blob transaction
+type txFees struct {
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	BlobFeeCap *big.Int // nil for non-blob transactions
+}
+
+// estimateFees computes fees based on the latest L1 block and the suggested priority fee, capped by the policy
+func (p *FeePolicy) estimateFees(ctx context.Context, client EthClient, withBlobs bool) (*txFees, error) {
+	gasTipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("suggesting gas tip cap: %w", err)
+	}
+
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting header: %w", err)
+	}
+	if head.BaseFee == nil {
+		return nil, errors.New("L1 block has no base fee")
+	}
+
+	multiplier := new(big.Int).SetUint64(p.BaseFeeMultiplier)
+	fees := &txFees{
+		GasTipCap: capped(gasTipCap, p.MaxGasTipCap),
+	}
+	fees.GasFeeCap = capped(new(big.Int).Add(fees.GasTipCap, new(big.Int).Mul(head.BaseFee, multiplier)), p.MaxGasFeeCap)
+	if fees.GasFeeCap.Cmp(fees.GasTipCap) < 0 {
+		fees.GasTipCap = new(big.Int).Set(fees.GasFeeCap)
+	}
+
+	if withBlobs {
+		if head.ExcessBlobGas == nil {
+			return nil, errors.New("L1 block has no excess blob gas")
+		}
+		blobFee := eip4844.CalcBlobFee(*head.ExcessBlobGas)
+		fees.BlobFeeCap = capped(new(big.Int).Mul(blobFee, multiplier), p.MaxBlobFeeCap)
+	}
+
+	return fees, nil
+}
+
+// bumpFees computes fees of a replacement transaction: every fee is raised by the required percentage
+// or up to the currently estimated value, whichever is greater. Returns false if the policy caps
+// don't allow a bump large enough for the replacement to be accepted by L1 nodes.
+func (p *FeePolicy) bumpFees(prev, estimated *txFees) (*txFees, bool) {
+	percent := p.FeeBumpPercent
+	if prev.BlobFeeCap != nil {
+		percent = p.BlobFeeBumpPercent
+	}
+
+	bumped := &txFees{}
+	ok := true
+	bump := func(prevFee, estimatedFee, maxFee *big.Int) *big.Int {
+		minFee := new(big.Int).Mul(prevFee, new(big.Int).SetUint64(100+percent))
+		minFee.Add(minFee, big.NewInt(99)).Div(minFee, big.NewInt(100))
+		fee := minFee
+		if estimatedFee.Cmp(fee) > 0 {
+			fee = estimatedFee
+		}
+		fee = capped(fee, maxFee)
+		if fee.Cmp(minFee) < 0 {
+			ok = false
+		}
+		return fee
+	}
+
+	bumped.GasTipCap = bump(prev.GasTipCap, estimated.GasTipCap, p.MaxGasTipCap)
+	bumped.GasFeeCap = bump(prev.GasFeeCap, estimated.GasFeeCap, p.MaxGasFeeCap)
+	if prev.BlobFeeCap != nil {
+		bumped.BlobFeeCap = bump(prev.BlobFeeCap, estimated.BlobFeeCap, p.MaxBlobFeeCap)
+	}
+	return bumped, ok
+}
+
+func capped(value, maxValue *big.Int) *big.Int {
+	if maxValue != nil && value.Cmp(maxValue) > 0 {
+		return new(big.Int).Set(maxValue)
+	}
+	return new(big.Int).Set(value)
+}
@@ -0,0 +1,269 @@
+package rollupcontract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/concurrent"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/rs/zerolog"
+)
+
+// LocalGenesisBatchIndex is the index of the batch finalized at the deployment of the local rollup contract.
+const LocalGenesisBatchIndex = "GENESIS_BATCH_INDEX"
+
+// ErrLocalCallReverted is returned by LocalL1 for the rollup contract calls which the contract reverts.
+var ErrLocalCallReverted = errors.New("execution reverted")
+
+// localCodePlaceholder is reported as the code of the local rollup contract, so the bindings treat it as deployed.
+var localCodePlaceholder = []byte{0x00}
+
+// LocalL1 is the in-process L1 chain for the local runs of the sync committee.
+// It's the simulated go-ethereum chain with a fake of the rollup contract: the contract calls are served
+// from the state kept in memory, which follows the commitBatch and updateState transactions included into blocks.
+// The proofs are not verified.
+type LocalL1 struct {
+	simulated.Client
+
+	backend         *simulated.Backend
+	contractAddress ethcommon.Address
+	abi             *abi.ABI
+	blockPeriod     time.Duration
+	logger          zerolog.Logger
+
+	mu       sync.Mutex
+	contract *localRollupState
+}
+
+// localRollupState is the state of the fake rollup contract.
+type localRollupState struct {
+	committed          map[string]bool
+	finalizedRoots     map[string]common.Hash
+	lastCommitted      string
+	lastFinalized      string
+	finalizedRootBatch map[common.Hash]string
+}
+
+// NewLocalL1 creates the chain with the given accounts funded and the rollup contract at the address,
+// which is initialized with the genesis state root. The blocks are produced by Run.
+func NewLocalL1(
+	contractAddressHex string,
+	accounts []ethcommon.Address,
+	genesisStateRoot common.Hash,
+	blockPeriod time.Duration,
+	logger zerolog.Logger,
+) (*LocalL1, error) {
+	if genesisStateRoot.Empty() {
+		return nil, errors.New("genesis state root is empty")
+	}
+	contractAbi, err := RollupcontractMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("getting ABI: %w", err)
+	}
+
+	balance := new(big.Int).Mul(big.NewInt(1000), big.NewInt(ethparams.Ether))
+	alloc := make(ethtypes.GenesisAlloc, len(accounts))
+	for _, account := range accounts {
+		alloc[account] = ethtypes.Account{Balance: balance}
+	}
+	backend := simulated.NewBackend(alloc)
+
+	return &LocalL1{
+		Client:          backend.Client(),
+		backend:         backend,
+		contractAddress: ethcommon.HexToAddress(contractAddressHex),
+		abi:             contractAbi,
+		blockPeriod:     blockPeriod,
+		logger:          logger,
+		contract: &localRollupState{
+			committed:          map[string]bool{LocalGenesisBatchIndex: true},
+			finalizedRoots:     map[string]common.Hash{LocalGenesisBatchIndex: genesisStateRoot},
+			lastCommitted:      LocalGenesisBatchIndex,
+			lastFinalized:      LocalGenesisBatchIndex,
+			finalizedRootBatch: map[common.Hash]string{genesisStateRoot: LocalGenesisBatchIndex},
+		},
+	}, nil
+}
+
+// Run produces the blocks until the context is done, then closes the chain.
+func (l *LocalL1) Run(ctx context.Context) error {
+	concurrent.RunTickerLoop(ctx, l.blockPeriod, func(ctx context.Context) {
+		if err := l.commitBlock(ctx); err != nil {
+			l.logger.Error().Err(err).Msg("failed to apply the block to the local rollup contract")
+		}
+	})
+	return l.backend.Close()
+}
+
+// commitBlock produces the block and applies its rollup contract transactions.
+func (l *LocalL1) commitBlock(ctx context.Context) error {
+	block, err := l.BlockByHash(ctx, l.backend.Commit())
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, txn := range block.Transactions() {
+		if txn.To() == nil || *txn.To() != l.contractAddress {
+			continue
+		}
+		// The transactions are checked when they are sent, but the contract state might change since then
+		if err := l.execute(txn, true); err != nil {
+			l.logger.Warn().Err(err).Stringer("txHash", txn.Hash()).Msg("rollup contract transaction is reverted")
+		}
+	}
+	return nil
+}
+
+// SendTransaction rejects the rollup contract transactions which would be reverted.
+func (l *LocalL1) SendTransaction(ctx context.Context, txn *ethtypes.Transaction) error {
+	if txn.To() != nil && *txn.To() == l.contractAddress {
+		l.mu.Lock()
+		err := l.execute(txn, false)
+		l.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return l.Client.SendTransaction(ctx, txn)
+}
+
+func (l *LocalL1) CodeAt(ctx context.Context, account ethcommon.Address, blockNumber *big.Int) ([]byte, error) {
+	if account == l.contractAddress {
+		return localCodePlaceholder, nil
+	}
+	return l.Client.CodeAt(ctx, account, blockNumber)
+}
+
+func (l *LocalL1) PendingCodeAt(ctx context.Context, account ethcommon.Address) ([]byte, error) {
+	if account == l.contractAddress {
+		return localCodePlaceholder, nil
+	}
+	return l.Client.PendingCodeAt(ctx, account)
+}
+
+// CallContract serves the view methods of the rollup contract from the latest state.
+func (l *LocalL1) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if call.To == nil || *call.To != l.contractAddress {
+		return l.Client.CallContract(ctx, call, blockNumber)
+	}
+
+	method, args, err := l.unpackCall(call.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	state := l.contract
+
+	var result []any
+	switch method.Name {
+	case "GENESIS_BATCH_INDEX":
+		result = []any{LocalGenesisBatchIndex}
+	case "isBatchCommitted":
+		result = []any{state.committed[args[0].(string)]}
+	case "isBatchFinalized":
+		_, finalized := state.finalizedRoots[args[0].(string)]
+		result = []any{finalized}
+	case "isRootFinalized":
+		_, finalized := state.finalizedRootBatch[common.Hash(args[0].([32]byte))]
+		result = []any{finalized}
+	case "batchIndexOfRoot":
+		result = []any{state.finalizedRootBatch[common.Hash(args[0].([32]byte))]}
+	case "finalizedStateRoots":
+		result = []any{[32]byte(state.finalizedRoots[args[0].(string)])}
+	case "getLastCommittedBatchIndex", "lastCommittedBatchIndex":
+		result = []any{state.lastCommitted}
+	case "getLastFinalizedBatchIndex", "lastFinalizedBatchIndex":
+		result = []any{state.lastFinalized}
+	case "verifyDataProof":
+		result = nil
+	default:
+		return nil, fmt.Errorf("%w: method %s is not supported by the local rollup contract", ErrLocalCallReverted, method.Name)
+	}
+	return method.Outputs.Pack(result...)
+}
+
+func (l *LocalL1) unpackCall(data []byte) (*abi.Method, []any, error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("%w: no method selector", ErrLocalCallReverted)
+	}
+	method, err := l.abi.MethodById(data[:4])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrLocalCallReverted, err)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: can't unpack %s arguments: %w", ErrLocalCallReverted, method.Name, err)
+	}
+	return method, args, nil
+}
+
+// execute checks the rollup contract transaction the way the contract does and applies it if requested.
+// It must be called with the mutex held.
+func (l *LocalL1) execute(txn *ethtypes.Transaction, apply bool) error {
+	method, args, err := l.unpackCall(txn.Data())
+	if err != nil {
+		return err
+	}
+	state := l.contract
+
+	switch method.Name {
+	case "commitBatch":
+		batchIndex := args[0].(string)
+		blobCount := args[1].(*big.Int)
+		if state.committed[batchIndex] {
+			return fmt.Errorf("%w: batch %s is already committed", ErrLocalCallReverted, batchIndex)
+		}
+		if blobCount.Sign() == 0 || !blobCount.IsInt64() || int64(len(txn.BlobHashes())) != blobCount.Int64() {
+			return fmt.Errorf("%w: batch %s has %d blobs, %s expected",
+				ErrLocalCallReverted, batchIndex, len(txn.BlobHashes()), blobCount)
+		}
+		if apply {
+			state.committed[batchIndex] = true
+			state.lastCommitted = batchIndex
+		}
+
+	case "updateState":
+		batchIndex := args[0].(string)
+		oldStateRoot := common.Hash(args[1].([32]byte))
+		newStateRoot := common.Hash(args[2].([32]byte))
+		if !state.committed[batchIndex] {
+			return fmt.Errorf("%w: batch %s is not committed", ErrLocalCallReverted, batchIndex)
+		}
+		if _, finalized := state.finalizedRoots[batchIndex]; finalized {
+			return fmt.Errorf("%w: batch %s is already finalized", ErrLocalCallReverted, batchIndex)
+		}
+		if lastRoot := state.finalizedRoots[state.lastFinalized]; oldStateRoot != lastRoot {
+			return fmt.Errorf("%w: old state root %s doesn't match the last finalized %s",
+				ErrLocalCallReverted, oldStateRoot, lastRoot)
+		}
+		if newStateRoot.Empty() {
+			return fmt.Errorf("%w: new state root is empty", ErrLocalCallReverted)
+		}
+		if _, finalized := state.finalizedRootBatch[newStateRoot]; finalized {
+			return fmt.Errorf("%w: new state root %s is already finalized", ErrLocalCallReverted, newStateRoot)
+		}
+		if apply {
+			state.finalizedRoots[batchIndex] = newStateRoot
+			state.finalizedRootBatch[newStateRoot] = batchIndex
+			state.lastFinalized = batchIndex
+		}
+
+	default:
+		return fmt.Errorf("%w: method %s is not supported by the local rollup contract", ErrLocalCallReverted, method.Name)
+	}
+	return nil
+}
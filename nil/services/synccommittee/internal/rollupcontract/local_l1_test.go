@@ -0,0 +1,102 @@
+package rollupcontract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/remotesigner"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalL1RollupContract(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := logging.NewLogger("local_l1_test")
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	genesisRoot := common.HexToHash("0x01")
+	contractAddress := "0x796baf7E572948CD0cbC374f345963bA433b47a2"
+	l1, err := NewLocalL1(contractAddress, []ethcommon.Address{crypto.PubkeyToAddress(key.PublicKey)},
+		genesisRoot, time.Hour, logger)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, l1.backend.Close()) }()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+	metricsHandler, err := metrics.NewSyncCommitteeMetrics()
+	require.NoError(t, err)
+	timer := testaide.NewTestTimer()
+	blockStorage := storage.NewBlockStorage(database, timer, metricsHandler, logger)
+
+	policy := NewDefaultFeePolicy()
+	policy.ConfirmationDepth = 1
+	wrapper, err := NewWrapper(ctx, contractAddress, remotesigner.NewLocalEcdsaSigner(key, nil), l1,
+		blockStorage, policy, timer, time.Minute, logger)
+	require.NoError(t, err)
+
+	mine := func(txn *ethtypes.Transaction) {
+		t.Helper()
+		require.NoError(t, l1.commitBlock(ctx))
+		receipt, err := wrapper.WaitForReceipt(ctx, txn.Hash())
+		require.NoError(t, err)
+		require.NotNil(t, receipt)
+		require.Equal(t, ethtypes.ReceiptStatusSuccessful, receipt.Status)
+	}
+
+	index, err := wrapper.FinalizedBatchIndex(ctx)
+	require.NoError(t, err)
+	require.Equal(t, LocalGenesisBatchIndex, index)
+	root, err := wrapper.StateRoots(ctx, index)
+	require.NoError(t, err)
+	require.Equal(t, genesisRoot, common.Hash(root))
+
+	const batchIndex = "batch"
+	commitTx, err := wrapper.CommitBatch(ctx, []kzg4844.Blob{{0x01}, {0x02}}, batchIndex)
+	require.NoError(t, err)
+
+	newRoot := common.HexToHash("0x02")
+	dataProofs, err := ComputeDataProofs(commitTx.BlobTxSidecar())
+	require.NoError(t, err)
+	updateState := func(oldRoot common.Hash) (*ethtypes.Transaction, error) {
+		return wrapper.UpdateState(ctx, batchIndex, oldRoot, newRoot, dataProofs,
+			commitTx.BlobHashes(), []byte{0x01}, INilRollupPublicDataInfo{})
+	}
+
+	_, err = updateState(genesisRoot)
+	require.Error(t, err, "the batch is not committed until its transaction is included")
+	mine(commitTx)
+
+	_, err = wrapper.CommitBatch(ctx, []kzg4844.Blob{{0x01}, {0x02}}, batchIndex)
+	require.ErrorIs(t, err, ErrBatchAlreadyCommitted)
+
+	_, err = updateState(newRoot)
+	require.Error(t, err, "the old state root must be the last finalized one")
+	updateTx, err := updateState(genesisRoot)
+	require.NoError(t, err)
+	mine(updateTx)
+
+	index, err = wrapper.FinalizedBatchIndex(ctx)
+	require.NoError(t, err)
+	require.Equal(t, batchIndex, index)
+	root, err = wrapper.StateRoots(ctx, index)
+	require.NoError(t, err)
+	require.Equal(t, newRoot, common.Hash(root))
+
+	_, err = updateState(genesisRoot)
+	require.ErrorIs(t, err, ErrBatchAlreadyFinalized)
+}
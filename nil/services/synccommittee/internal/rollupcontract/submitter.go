@@ -0,0 +1,511 @@
+package rollupcontract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/concurrent"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	scTypes "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	ethereum "github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+	"github.com/rs/zerolog"
+)
+
+// L1TransactionStorage persists pending L1 transactions, so they can be tracked after a restart.
+type L1TransactionStorage interface {
+	PutPendingL1Transaction(ctx context.Context, txn *scTypes.PendingL1Transaction) error
+	GetPendingL1Transactions(ctx context.Context) ([]*scTypes.PendingL1Transaction, error)
+	DeletePendingL1Transaction(ctx context.Context, nonce uint64) error
+}
+
+// TransactionSigner signs L1 transactions, the key may be held by a remote signer.
+type TransactionSigner interface {
+	Address() ethcommon.Address
+	// SignTransaction returns the transaction signed for the chain.
+	SignTransaction(ctx context.Context, txn *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error)
+}
+
+// SubmissionManager sends L1 transactions and tracks them until they are confirmed.
+// Fees are estimated and capped according to FeePolicy, stuck transactions are replaced
+// with bumped fees at the same nonce, and inclusion is re-checked to handle L1 reorgs.
+type SubmissionManager struct {
+	ethClient EthClient
+	storage   L1TransactionStorage
+	policy    *FeePolicy
+	txnSigner TransactionSigner
+	from      ethcommon.Address
+	chainID   *big.Int
+	signer    ethtypes.Signer
+	timer     common.Timer
+	logger    zerolog.Logger
+
+	mu      sync.Mutex
+	pending map[uint64]*scTypes.PendingL1Transaction
+}
+
+func NewSubmissionManager(
+	ctx context.Context,
+	ethClient EthClient,
+	storage L1TransactionStorage,
+	policy *FeePolicy,
+	txnSigner TransactionSigner,
+	chainID *big.Int,
+	timer common.Timer,
+	logger zerolog.Logger,
+) (*SubmissionManager, error) {
+	m := &SubmissionManager{
+		ethClient: ethClient,
+		storage:   storage,
+		policy:    policy,
+		txnSigner: txnSigner,
+		from:      txnSigner.Address(),
+		chainID:   chainID,
+		signer:    ethtypes.LatestSignerForChainID(chainID),
+		timer:     timer,
+		logger:    logger,
+		pending:   make(map[uint64]*scTypes.PendingL1Transaction),
+	}
+
+	stored, err := storage.GetPendingL1Transactions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pending L1 transactions: %w", err)
+	}
+	for _, txn := range stored {
+		m.pending[txn.Nonce] = txn
+		m.logger.Info().
+			Str(logging.FieldBatchId, txn.BatchId).
+			Str("method", txn.Method).
+			Uint64("nonce", txn.Nonce).
+			Msg("restored pending L1 transaction")
+	}
+
+	return m, nil
+}
+
+// Send fills in the nonce and fees of the transaction, signs and broadcasts it. If a transaction calling
+// the same method for the same batch is already pending, it is returned and no new transaction is sent.
+// Supported transaction data types are *ethtypes.DynamicFeeTx and *ethtypes.BlobTx.
+func (m *SubmissionManager) Send(
+	ctx context.Context,
+	batchId string,
+	method string,
+	txData ethtypes.TxData,
+) (*ethtypes.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, pending := range m.pending {
+		if pending.BatchId == batchId && pending.Method == method {
+			m.logger.Info().
+				Str(logging.FieldBatchId, batchId).
+				Str("method", method).
+				Uint64("nonce", pending.Nonce).
+				Msg("transaction is already pending, skipping")
+			return decodeTransaction(pending.LatestVersion())
+		}
+	}
+
+	_, withBlobs := txData.(*ethtypes.BlobTx)
+	fees, err := m.policy.estimateFees(ctx, m.ethClient, withBlobs)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := m.nextNonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := m.signWith(ctx, txData, nonce, fees)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.checkSpendCap(batchId, nonce, txn); err != nil {
+		return nil, err
+	}
+
+	pending := &scTypes.PendingL1Transaction{
+		BatchId: batchId,
+		Method:  method,
+		Nonce:   nonce,
+	}
+	if err := m.broadcast(ctx, pending, txn); err != nil {
+		return nil, err
+	}
+
+	m.logger.Info().
+		Str(logging.FieldBatchId, batchId).
+		Str("method", method).
+		Uint64("nonce", nonce).
+		Stringer(logging.FieldTransactionHash, txn.Hash()).
+		Stringer("gasTipCap", txn.GasTipCap()).
+		Stringer("gasFeeCap", txn.GasFeeCap()).
+		Stringer("blobFeeCap", txn.BlobGasFeeCap()).
+		Msg("L1 transaction sent")
+
+	return txn, nil
+}
+
+// WaitForReceipt waits until the transaction, or one of its replacements, is included in L1
+// and confirmed by FeePolicy.ConfirmationDepth blocks. While waiting, the transaction is replaced
+// if it gets stuck and re-broadcast if it's dropped from the canonical chain by a reorg.
+func (m *SubmissionManager) WaitForReceipt(ctx context.Context, txnHash ethcommon.Hash) (*ethtypes.Receipt, error) {
+	return concurrent.WaitFor(ctx, m.policy.ReceiptTimeout, m.policy.PollInterval,
+		func(ctx context.Context) (*ethtypes.Receipt, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			pending := m.findByHash(txnHash)
+			if pending == nil {
+				receipt, err := m.ethClient.TransactionReceipt(ctx, txnHash)
+				if isReceiptNotFound(err) {
+					return nil, nil
+				}
+				return receipt, err
+			}
+			return m.checkPending(ctx, pending)
+		})
+}
+
+// CheckPending performs a single status check of all pending transactions.
+func (m *SubmissionManager) CheckPending(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []error
+	for _, pending := range m.pending {
+		if _, err := m.checkPending(ctx, pending); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkPending returns a receipt once the transaction is confirmed, (nil, nil) if it's still pending
+func (m *SubmissionManager) checkPending(
+	ctx context.Context,
+	pending *scTypes.PendingL1Transaction,
+) (*ethtypes.Receipt, error) {
+	logger := m.logger.With().
+		Str(logging.FieldBatchId, pending.BatchId).
+		Str("method", pending.Method).
+		Uint64("nonce", pending.Nonce).
+		Logger()
+
+	receipt, err := m.findCanonicalReceipt(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	if receipt != nil {
+		includedIn := &scTypes.L1BlockRef{Hash: common.Hash(receipt.BlockHash), Number: receipt.BlockNumber.Uint64()}
+		if pending.IncludedIn == nil || *pending.IncludedIn != *includedIn {
+			logger.Info().
+				Stringer(logging.FieldTransactionHash, receipt.TxHash).
+				Uint64("l1BlockNumber", includedIn.Number).
+				Msg("L1 transaction is included")
+			pending.IncludedIn = includedIn
+			if err := m.storage.PutPendingL1Transaction(ctx, pending); err != nil {
+				return nil, err
+			}
+		}
+
+		head, err := m.ethClient.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("getting header: %w", err)
+		}
+		if head.Number.Uint64()+1 < includedIn.Number+m.policy.ConfirmationDepth {
+			return nil, nil
+		}
+
+		logger.Info().Stringer(logging.FieldTransactionHash, receipt.TxHash).Msg("L1 transaction is confirmed")
+		if err := m.forget(ctx, pending); err != nil {
+			return nil, err
+		}
+		return receipt, nil
+	}
+
+	if pending.IncludedIn != nil {
+		logger.Warn().
+			Stringer("l1BlockHash", pending.IncludedIn.Hash).
+			Uint64("l1BlockNumber", pending.IncludedIn.Number).
+			Msg("L1 transaction is no longer in the canonical chain, re-broadcasting")
+		pending.IncludedIn = nil
+		txn, err := decodeTransaction(pending.LatestVersion())
+		if err != nil {
+			return nil, err
+		}
+		if err := m.ethClient.SendTransaction(ctx, txn); err != nil && !isAlreadyKnown(err) {
+			logger.Warn().Err(err).Msg("failed to re-broadcast L1 transaction")
+		}
+		return nil, m.storage.PutPendingL1Transaction(ctx, pending)
+	}
+
+	confirmedNonce, err := m.ethClient.NonceAt(ctx, m.from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting nonce: %w", err)
+	}
+	if confirmedNonce > pending.Nonce {
+		// The receipt of our transaction could appear in the meantime, so check once more before giving up.
+		if receipt, err := m.findCanonicalReceipt(ctx, pending); err != nil || receipt != nil {
+			return nil, err
+		}
+		logger.Error().Msg("nonce of pending L1 transaction is consumed by another transaction")
+		if err := m.forget(ctx, pending); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: nonce=%d", ErrNonceConsumed, pending.Nonce)
+	}
+
+	if m.timer.NowTime().Sub(pending.SentAt) >= m.policy.StuckTimeout {
+		return nil, m.replace(ctx, pending, logger)
+	}
+
+	return nil, nil
+}
+
+// findCanonicalReceipt looks for a receipt of any version of the transaction included in the canonical chain
+func (m *SubmissionManager) findCanonicalReceipt(
+	ctx context.Context,
+	pending *scTypes.PendingL1Transaction,
+) (*ethtypes.Receipt, error) {
+	for i := len(pending.Versions) - 1; i >= 0; i-- {
+		txn, err := decodeTransaction(pending.Versions[i])
+		if err != nil {
+			return nil, err
+		}
+
+		receipt, err := m.ethClient.TransactionReceipt(ctx, txn.Hash())
+		if isReceiptNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting receipt: %w", err)
+		}
+
+		header, err := m.ethClient.HeaderByNumber(ctx, receipt.BlockNumber)
+		if errors.Is(err, ethereum.NotFound) {
+			// the chain was rolled back below the block with the transaction
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting header: %w", err)
+		}
+		if header.Hash() == receipt.BlockHash {
+			return receipt, nil
+		}
+	}
+	return nil, nil
+}
+
+// replace sends a new version of the stuck transaction with bumped fees
+func (m *SubmissionManager) replace(
+	ctx context.Context,
+	pending *scTypes.PendingL1Transaction,
+	logger zerolog.Logger,
+) error {
+	prev, err := decodeTransaction(pending.LatestVersion())
+	if err != nil {
+		return err
+	}
+	prevFees := &txFees{GasTipCap: prev.GasTipCap(), GasFeeCap: prev.GasFeeCap(), BlobFeeCap: prev.BlobGasFeeCap()}
+
+	estimated, err := m.policy.estimateFees(ctx, m.ethClient, prevFees.BlobFeeCap != nil)
+	if err != nil {
+		return err
+	}
+	fees, ok := m.policy.bumpFees(prevFees, estimated)
+	if !ok {
+		logger.Warn().
+			Stringer("gasTipCap", prev.GasTipCap()).
+			Stringer("gasFeeCap", prev.GasFeeCap()).
+			Stringer("blobFeeCap", prev.BlobGasFeeCap()).
+			Msg("L1 transaction is stuck, but fee caps don't allow to replace it")
+		return nil
+	}
+
+	txn, err := m.signWith(ctx, txDataOf(prev), pending.Nonce, fees)
+	if err != nil {
+		return err
+	}
+	if err := m.checkSpendCap(pending.BatchId, pending.Nonce, txn); err != nil {
+		logger.Warn().Err(err).Msg("L1 transaction is stuck, but can't be replaced")
+		return nil
+	}
+
+	if err := m.broadcast(ctx, pending, txn); err != nil {
+		return err
+	}
+
+	logger.Info().
+		Stringer(logging.FieldTransactionHash, txn.Hash()).
+		Stringer("replacedTxnHash", prev.Hash()).
+		Stringer("gasTipCap", txn.GasTipCap()).
+		Stringer("gasFeeCap", txn.GasFeeCap()).
+		Stringer("blobFeeCap", txn.BlobGasFeeCap()).
+		Msg("stuck L1 transaction is replaced")
+	return nil
+}
+
+// broadcast sends the transaction and stores it as the latest version of the pending one
+func (m *SubmissionManager) broadcast(
+	ctx context.Context,
+	pending *scTypes.PendingL1Transaction,
+	txn *ethtypes.Transaction,
+) error {
+	encoded, err := txn.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("encoding transaction: %w", err)
+	}
+
+	// The transaction is stored before sending, otherwise it can't be tracked after a restart.
+	pending.Versions = append(pending.Versions, encoded)
+	pending.SentAt = m.timer.NowTime()
+	if err := m.storage.PutPendingL1Transaction(ctx, pending); err != nil {
+		pending.Versions = pending.Versions[:len(pending.Versions)-1]
+		return err
+	}
+	m.pending[pending.Nonce] = pending
+
+	if err := m.ethClient.SendTransaction(ctx, txn); err != nil && !isAlreadyKnown(err) {
+		return fmt.Errorf("sending transaction: %w", err)
+	}
+	return nil
+}
+
+func (m *SubmissionManager) forget(ctx context.Context, pending *scTypes.PendingL1Transaction) error {
+	if err := m.storage.DeletePendingL1Transaction(ctx, pending.Nonce); err != nil {
+		return err
+	}
+	delete(m.pending, pending.Nonce)
+	return nil
+}
+
+func (m *SubmissionManager) findByHash(txnHash ethcommon.Hash) *scTypes.PendingL1Transaction {
+	for _, pending := range m.pending {
+		for _, version := range pending.Versions {
+			txn, err := decodeTransaction(version)
+			if err == nil && txn.Hash() == txnHash {
+				return pending
+			}
+		}
+	}
+	return nil
+}
+
+// nextNonce returns the nonce for a new transaction taking into account transactions
+// which are tracked, but could be missing in the L1 node's pool
+func (m *SubmissionManager) nextNonce(ctx context.Context) (uint64, error) {
+	nonce, err := m.ethClient.PendingNonceAt(ctx, m.from)
+	if err != nil {
+		return 0, fmt.Errorf("getting nonce: %w", err)
+	}
+	for pendingNonce := range m.pending {
+		if pendingNonce >= nonce {
+			nonce = pendingNonce + 1
+		}
+	}
+	return nonce, nil
+}
+
+// checkSpendCap verifies that the worst-case cost of all pending transactions of the batch
+// doesn't exceed the limit if the transaction with the given nonce is replaced by txn
+func (m *SubmissionManager) checkSpendCap(batchId string, nonce uint64, txn *ethtypes.Transaction) error {
+	if m.policy.MaxSpendPerBatch == nil {
+		return nil
+	}
+
+	total := new(big.Int).Set(txn.Cost())
+	for _, pending := range m.pending {
+		if pending.BatchId != batchId || pending.Nonce == nonce {
+			continue
+		}
+		other, err := decodeTransaction(pending.LatestVersion())
+		if err != nil {
+			return err
+		}
+		total.Add(total, other.Cost())
+	}
+
+	if total.Cmp(m.policy.MaxSpendPerBatch) > 0 {
+		return fmt.Errorf("%w: batchId=%s, cost=%s, limit=%s", ErrSpendCapExceeded, batchId, total, m.policy.MaxSpendPerBatch)
+	}
+	return nil
+}
+
+func (m *SubmissionManager) signWith(
+	ctx context.Context, txData ethtypes.TxData, nonce uint64, fees *txFees) (*ethtypes.Transaction, error) {
+	switch data := txData.(type) {
+	case *ethtypes.DynamicFeeTx:
+		data.ChainID = m.chainID
+		data.Nonce = nonce
+		data.GasTipCap = fees.GasTipCap
+		data.GasFeeCap = fees.GasFeeCap
+	case *ethtypes.BlobTx:
+		data.ChainID = uint256.MustFromBig(m.chainID)
+		data.Nonce = nonce
+		data.GasTipCap = uint256.MustFromBig(fees.GasTipCap)
+		data.GasFeeCap = uint256.MustFromBig(fees.GasFeeCap)
+		data.BlobFeeCap = uint256.MustFromBig(fees.BlobFeeCap)
+	default:
+		return nil, fmt.Errorf("unsupported transaction data type %T", txData)
+	}
+
+	signed, err := m.txnSigner.SignTransaction(ctx, ethtypes.NewTx(txData), m.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("signing transaction: %w", err)
+	}
+	return signed, nil
+}
+
+// txDataOf returns a copy of the transaction data which can be re-signed with other fees
+func txDataOf(txn *ethtypes.Transaction) ethtypes.TxData {
+	if txn.Type() == ethtypes.BlobTxType {
+		return &ethtypes.BlobTx{
+			Gas:        txn.Gas(),
+			To:         *txn.To(),
+			Value:      uint256.MustFromBig(txn.Value()),
+			Data:       txn.Data(),
+			AccessList: txn.AccessList(),
+			BlobHashes: txn.BlobHashes(),
+			Sidecar:    txn.BlobTxSidecar(),
+		}
+	}
+	return &ethtypes.DynamicFeeTx{
+		Gas:        txn.Gas(),
+		To:         txn.To(),
+		Value:      txn.Value(),
+		Data:       txn.Data(),
+		AccessList: txn.AccessList(),
+	}
+}
+
+func decodeTransaction(encoded hexutil.Bytes) (*ethtypes.Transaction, error) {
+	txn := new(ethtypes.Transaction)
+	if err := txn.UnmarshalBinary(encoded); err != nil {
+		return nil, fmt.Errorf("decoding transaction: %w", err)
+	}
+	return txn, nil
+}
+
+// isReceiptNotFound checks whether the receipt is not available yet.
+// Geth reports unknown transactions as not found only after the transaction index is built.
+func isReceiptNotFound(err error) bool {
+	return errors.Is(err, ethereum.NotFound) ||
+		(err != nil && strings.Contains(err.Error(), "transaction indexing is in progress"))
+}
+
+// isAlreadyKnown checks whether the L1 node rejected the transaction because it has already seen it
+func isAlreadyKnown(err error) bool {
+	return strings.Contains(err.Error(), "already known")
+}
@@ -0,0 +1,261 @@
+package rollupcontract
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/remotesigner"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SubmissionManagerTestSuite struct {
+	suite.Suite
+
+	ctx          context.Context
+	cancellation context.CancelFunc
+
+	db        db.DB
+	storage   *storage.BlockStorage
+	timer     *common.TestTimerImpl
+	key       *ecdsa.PrivateKey
+	backend   *simulated.Backend
+	ethClient simulated.Client
+	policy    *FeePolicy
+	manager   *SubmissionManager
+}
+
+func TestSubmissionManagerSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SubmissionManagerTestSuite))
+}
+
+func (s *SubmissionManagerTestSuite) SetupSuite() {
+	s.ctx, s.cancellation = context.WithCancel(context.Background())
+
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	metricsHandler, err := metrics.NewSyncCommitteeMetrics()
+	s.Require().NoError(err)
+
+	s.timer = testaide.NewTestTimer()
+	s.storage = storage.NewBlockStorage(s.db, s.timer, metricsHandler, logging.NewLogger("submitter_test"))
+
+	s.key, err = crypto.GenerateKey()
+	s.Require().NoError(err)
+}
+
+func (s *SubmissionManagerTestSuite) SetupTest() {
+	s.Require().NoError(s.db.DropAll())
+
+	balance := new(big.Int).Mul(big.NewInt(100), big.NewInt(ethparams.Ether))
+	s.backend = simulated.NewBackend(ethtypes.GenesisAlloc{
+		crypto.PubkeyToAddress(s.key.PublicKey): {Balance: balance},
+	})
+	s.ethClient = s.backend.Client()
+
+	s.policy = NewDefaultFeePolicy()
+	s.policy.StuckTimeout = time.Minute
+	s.policy.ConfirmationDepth = 2
+	s.policy.ReceiptTimeout = time.Second
+	s.policy.PollInterval = 10 * time.Millisecond
+
+	s.manager = s.newManager()
+}
+
+func (s *SubmissionManagerTestSuite) TearDownTest() {
+	s.Require().NoError(s.backend.Close())
+}
+
+func (s *SubmissionManagerTestSuite) TearDownSuite() {
+	s.cancellation()
+}
+
+func (s *SubmissionManagerTestSuite) newManager() *SubmissionManager {
+	s.T().Helper()
+
+	chainID, err := s.ethClient.ChainID(s.ctx)
+	s.Require().NoError(err)
+	manager, err := NewSubmissionManager(
+		s.ctx, s.ethClient, s.storage, s.policy, remotesigner.NewLocalEcdsaSigner(s.key, nil), chainID, s.timer, logging.NewLogger("submitter_test"),
+	)
+	s.Require().NoError(err)
+	return manager
+}
+
+func (s *SubmissionManagerTestSuite) send(batchId string) *ethtypes.Transaction {
+	s.T().Helper()
+
+	to := ethcommon.HexToAddress("0x1234")
+	tx, err := s.manager.Send(s.ctx, batchId, "transfer", &ethtypes.DynamicFeeTx{
+		To:    &to,
+		Gas:   ethparams.TxGas,
+		Value: big.NewInt(1),
+	})
+	s.Require().NoError(err)
+	return tx
+}
+
+func (s *SubmissionManagerTestSuite) pendingTransactions() int {
+	s.T().Helper()
+
+	txns, err := s.storage.GetPendingL1Transactions(s.ctx)
+	s.Require().NoError(err)
+	return len(txns)
+}
+
+func (s *SubmissionManagerTestSuite) TestConfirmation() {
+	tx := s.send("batch")
+	s.backend.Commit()
+
+	s.Require().NoError(s.manager.CheckPending(s.ctx))
+	s.Require().Equal(1, s.pendingTransactions(), "transaction is not confirmed by enough blocks yet")
+
+	s.backend.Commit()
+	receipt, err := s.manager.WaitForReceipt(s.ctx, tx.Hash())
+	s.Require().NoError(err)
+	s.Require().Equal(tx.Hash(), receipt.TxHash)
+	s.Require().Equal(ethtypes.ReceiptStatusSuccessful, receipt.Status)
+	s.Require().Zero(s.pendingTransactions())
+}
+
+func (s *SubmissionManagerTestSuite) TestSameBatchMethodIsSentOnce() {
+	first := s.send("batch")
+	second := s.send("batch")
+	s.Require().Equal(first.Hash(), second.Hash())
+
+	other := s.send("other batch")
+	s.Require().Equal(first.Nonce()+1, other.Nonce())
+}
+
+func (s *SubmissionManagerTestSuite) TestStuckTransactionIsReplaced() {
+	tx := s.send("batch")
+
+	s.Require().NoError(s.manager.CheckPending(s.ctx))
+	txns, err := s.storage.GetPendingL1Transactions(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(txns[0].Versions, 1, "transaction is not stuck yet")
+
+	s.timer.Add(2 * s.policy.StuckTimeout)
+	s.Require().NoError(s.manager.CheckPending(s.ctx))
+	txns, err = s.storage.GetPendingL1Transactions(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(txns[0].Versions, 2)
+
+	replacement, err := decodeTransaction(txns[0].LatestVersion())
+	s.Require().NoError(err)
+	s.Require().Equal(tx.Nonce(), replacement.Nonce())
+	s.Require().Positive(replacement.GasTipCap().Cmp(tx.GasTipCap()))
+	s.Require().Positive(replacement.GasFeeCap().Cmp(tx.GasFeeCap()))
+
+	s.backend.Commit()
+	s.backend.Commit()
+	receipt, err := s.manager.WaitForReceipt(s.ctx, tx.Hash())
+	s.Require().NoError(err)
+	s.Require().Equal(replacement.Hash(), receipt.TxHash)
+	s.Require().Zero(s.pendingTransactions())
+}
+
+func (s *SubmissionManagerTestSuite) TestReplacementIsLimitedByCaps() {
+	tx := s.send("batch")
+	s.policy.MaxGasTipCap = tx.GasTipCap()
+
+	s.timer.Add(2 * s.policy.StuckTimeout)
+	s.Require().NoError(s.manager.CheckPending(s.ctx))
+	txns, err := s.storage.GetPendingL1Transactions(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(txns[0].Versions, 1)
+}
+
+func (s *SubmissionManagerTestSuite) TestSpendCapExceeded() {
+	s.policy.MaxSpendPerBatch = big.NewInt(1)
+
+	to := ethcommon.HexToAddress("0x1234")
+	_, err := s.manager.Send(s.ctx, "batch", "transfer", &ethtypes.DynamicFeeTx{To: &to, Gas: ethparams.TxGas})
+	s.Require().ErrorIs(err, ErrSpendCapExceeded)
+	s.Require().Zero(s.pendingTransactions())
+}
+
+func (s *SubmissionManagerTestSuite) TestReorg() {
+	tx := s.send("batch")
+	parentHash := s.latestHeader().Hash()
+	s.backend.Commit()
+
+	s.Require().NoError(s.manager.CheckPending(s.ctx))
+	txns, err := s.storage.GetPendingL1Transactions(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(txns[0].IncludedIn)
+
+	s.Require().NoError(s.backend.Fork(parentHash))
+
+	s.Require().NoError(s.manager.CheckPending(s.ctx))
+	txns, err = s.storage.GetPendingL1Transactions(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Nil(txns[0].IncludedIn, "transaction is not in the canonical chain after reorg")
+
+	s.backend.Commit()
+	s.backend.Commit()
+	receipt, err := s.manager.WaitForReceipt(s.ctx, tx.Hash())
+	s.Require().NoError(err)
+	s.Require().Equal(tx.Hash(), receipt.TxHash)
+	s.Require().Zero(s.pendingTransactions())
+}
+
+func (s *SubmissionManagerTestSuite) TestRestart() {
+	tx := s.send("batch")
+
+	s.manager = s.newManager()
+	s.Require().Equal(tx.Hash(), s.send("batch").Hash(), "restored transaction is not resent")
+
+	s.backend.Commit()
+	s.backend.Commit()
+	receipt, err := s.manager.WaitForReceipt(s.ctx, tx.Hash())
+	s.Require().NoError(err)
+	s.Require().Equal(tx.Hash(), receipt.TxHash)
+	s.Require().Zero(s.pendingTransactions())
+}
+
+func (s *SubmissionManagerTestSuite) latestHeader() *ethtypes.Header {
+	s.T().Helper()
+
+	header, err := s.ethClient.HeaderByNumber(s.ctx, nil)
+	s.Require().NoError(err)
+	return header
+}
+
+func TestBumpFees(t *testing.T) {
+	t.Parallel()
+
+	policy := NewDefaultFeePolicy()
+	prev := &txFees{GasTipCap: big.NewInt(100), GasFeeCap: big.NewInt(1000)}
+
+	bumped, ok := policy.bumpFees(prev, &txFees{GasTipCap: big.NewInt(50), GasFeeCap: big.NewInt(2000)})
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(110), bumped.GasTipCap, "tip is bumped by FeeBumpPercent")
+	require.Equal(t, big.NewInt(2000), bumped.GasFeeCap, "fee cap is raised up to the estimated value")
+
+	prev.BlobFeeCap = big.NewInt(10)
+	bumped, ok = policy.bumpFees(prev, &txFees{GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), BlobFeeCap: big.NewInt(1)})
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(200), bumped.GasTipCap, "blob transaction fees are bumped by BlobFeeBumpPercent")
+	require.Equal(t, big.NewInt(20), bumped.BlobFeeCap)
+
+	policy.MaxBlobFeeCap = big.NewInt(15)
+	_, ok = policy.bumpFees(prev, prev)
+	require.False(t, ok, "fees can't be bumped above the cap")
+}
@@ -3,19 +3,15 @@ package rollupcontract
 import (
 	"bytes"
 	"context"
-	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"math/big"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
-	"github.com/NilFoundation/nil/nil/common/concurrent"
-	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/rs/zerolog"
 )
 
@@ -23,24 +19,30 @@ type Wrapper struct {
 	rollupContract  *Rollupcontract
 	contractAddress ethcommon.Address
 	requestTimeout  time.Duration
-	privateKey      *ecdsa.PrivateKey
+	txnSigner       TransactionSigner
 	chainID         *big.Int
 	ethClient       EthClient
+	submitter       *SubmissionManager
 	logger          zerolog.Logger
 }
 
-func NewWrapper(ctx context.Context, contractAddressHex, privateKeyHex string, ethClient EthClient, requestTimeout time.Duration, logger zerolog.Logger) (*Wrapper, error) {
+func NewWrapper(
+	ctx context.Context,
+	contractAddressHex string,
+	txnSigner TransactionSigner,
+	ethClient EthClient,
+	storage L1TransactionStorage,
+	feePolicy *FeePolicy,
+	timer common.Timer,
+	requestTimeout time.Duration,
+	logger zerolog.Logger,
+) (*Wrapper, error) {
 	contactAddress := ethcommon.HexToAddress(contractAddressHex)
 	rollupContract, err := NewRollupcontract(contactAddress, ethClient)
 	if err != nil {
 		return nil, fmt.Errorf("can't create rollup contract instance: %w", err)
 	}
 
-	privateKeyECDSA, err := crypto.HexToECDSA(privateKeyHex)
-	if err != nil {
-		return nil, fmt.Errorf("converting private key hex to ECDSA: %w", err)
-	}
-
 	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
 	defer cancel()
 	chainID, err := ethClient.ChainID(ctx)
@@ -48,13 +50,19 @@ func NewWrapper(ctx context.Context, contractAddressHex, privateKeyHex string, e
 		return nil, fmt.Errorf("failed to retrieve chain ID: %w", err)
 	}
 
+	submitter, err := NewSubmissionManager(ctx, ethClient, storage, feePolicy, txnSigner, chainID, timer, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Wrapper{
 		rollupContract:  rollupContract,
 		contractAddress: contactAddress,
 		requestTimeout:  requestTimeout,
-		privateKey:      privateKeyECDSA,
+		txnSigner:       txnSigner,
 		chainID:         chainID,
 		ethClient:       ethClient,
+		submitter:       submitter,
 		logger:          logger,
 	}, nil
 }
@@ -94,7 +102,8 @@ func (r *Wrapper) UpdateState(
 	}
 	defer cancel()
 
-	return r.rollupContract.UpdateState(
+	// The transaction is only built here, fees, signing and sending are handled by the submission manager.
+	tx, err := r.rollupContract.UpdateState(
 		transactOpts,
 		batchIndex,
 		oldStateRoot,
@@ -103,6 +112,15 @@ func (r *Wrapper) UpdateState(
 		validityProof,
 		publicDataInputs,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.submitter.Send(transactOpts.Context, batchIndex, "updateState", &ethtypes.DynamicFeeTx{
+		To:   tx.To(),
+		Gas:  tx.Gas(),
+		Data: tx.Data(),
+	})
 }
 
 func (r *Wrapper) StateRoots(ctx context.Context, finalizedBatchIndex string) ([32]byte, error) {
@@ -117,21 +135,11 @@ func (r *Wrapper) FinalizedBatchIndex(ctx context.Context) (string, error) {
 	return r.rollupContract.GetLastFinalizedBatchIndex(callOpts)
 }
 
-// WaitForReceipt repeatedly tries to get tx receipt, retrying on `NotFound` error (tx not mined yet).
-// In case `ReceiptWaitFor` timeout is reached, returns `(nil, nil)`.
+// WaitForReceipt waits for the transaction to be included and confirmed on L1.
+// Stuck transactions are replaced with bumped fees and reorged ones are re-broadcast while waiting,
+// see SubmissionManager.WaitForReceipt.
 func (r *Wrapper) WaitForReceipt(ctx context.Context, txnHash ethcommon.Hash) (*ethtypes.Receipt, error) {
-	const (
-		ReceiptWaitFor  = 30 * time.Second
-		ReceiptWaitTick = 500 * time.Millisecond
-	)
-	return concurrent.WaitFor(ctx, ReceiptWaitFor, ReceiptWaitTick, func(ctx context.Context) (*ethtypes.Receipt, error) {
-		receipt, err := r.ethClient.TransactionReceipt(ctx, txnHash)
-		if errors.Is(err, ethereum.NotFound) {
-			// retry
-			return nil, nil
-		}
-		return receipt, err
-	})
+	return r.submitter.WaitForReceipt(ctx, txnHash)
 }
 
 func (r *Wrapper) verifyDataProofs(
@@ -165,15 +173,21 @@ func (r *Wrapper) callWithTimeout(ctx context.Context, call contractCall) error
 	return call(&bind.CallOpts{Context: ctxWithTimeout})
 }
 
+// getEthTransactOpts returns the options which only build the transaction with the contract bindings.
+// The built transaction is left unsigned, as the bindings don't set its chain ID.
 func (r *Wrapper) getEthTransactOpts(ctx context.Context) (*bind.TransactOpts, context.CancelFunc, error) {
-	keyedTransactor, err := bind.NewKeyedTransactorWithChainID(r.privateKey, r.chainID)
-	if err != nil {
-		return nil, nil, fmt.Errorf("creating keyed transactor with chain ID: %w", err)
-	}
-
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, r.requestTimeout)
-	keyedTransactor.Context = ctxWithTimeout
-	return keyedTransactor, cancel, nil
+	return &bind.TransactOpts{
+		From: r.txnSigner.Address(),
+		Signer: func(address ethcommon.Address, txn *ethtypes.Transaction) (*ethtypes.Transaction, error) {
+			if address != r.txnSigner.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return txn, nil
+		},
+		Context: ctxWithTimeout,
+		NoSend:  true,
+	}, cancel, nil
 }
 
 // BatchValidation contains validation results for a batch
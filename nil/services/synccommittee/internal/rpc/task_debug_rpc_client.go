@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
@@ -13,7 +14,7 @@ type taskDebugRpcClient struct {
 	client client.RawClient
 }
 
-func NewTaskDebugRpcClient(apiEndpoint string, logger zerolog.Logger) public.TaskDebugApi {
+func NewTaskDebugRpcClient(apiEndpoint string, logger zerolog.Logger) public.DebugApi {
 	return &taskDebugRpcClient{
 		client: NewRetryClient(apiEndpoint, logger),
 	}
@@ -36,3 +37,14 @@ func (c *taskDebugRpcClient) GetTaskTree(ctx context.Context, taskId types.TaskI
 		taskId,
 	)
 }
+
+func (c *taskDebugRpcClient) GetBatches(ctx context.Context) ([]*public.BatchView, error) {
+	rawResponse, err := c.client.RawCall(ctx, public.DebugGetBatches)
+	if err != nil {
+		return nil, err
+	}
+
+	var batches []*public.BatchView
+	err = json.Unmarshal(rawResponse, &batches)
+	return batches, err
+}
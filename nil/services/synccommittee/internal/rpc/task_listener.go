@@ -15,6 +15,8 @@ import (
 
 type TaskListenerConfig struct {
 	HttpEndpoint string
+	// BatchDebugApi is exposed in the debug namespace if set
+	BatchDebugApi public.BatchDebugApi
 }
 
 type TaskListener struct {
@@ -64,6 +66,15 @@ func (l *TaskListener) Run(context context.Context, started chan<- struct{}) err
 		},
 	}
 
+	if l.config.BatchDebugApi != nil {
+		apiList = append(apiList, transport.API{
+			Namespace: public.DebugNamespace,
+			Public:    true,
+			Service:   l.config.BatchDebugApi,
+			Version:   "1.0",
+		})
+	}
+
 	l.logger.Info().Msgf("Open task listener endpoint %v", l.config.HttpEndpoint)
 	return rpc.StartRpcServer(context, httpConfig, apiList, l.logger, started)
 }
@@ -1,12 +1,14 @@
 package storage
 
 import (
+	"cmp"
 	"context"
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -15,6 +17,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	scTypes "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
 	"github.com/rs/zerolog"
 )
 
@@ -27,15 +30,47 @@ const (
 	stateRootTable db.TableName = "state_root"
 	// nextToProposeTable stores parent's hash of the next block to propose (single value). Key: mainShardKey, Value: common.Hash.
 	nextToProposeTable db.TableName = "next_to_propose_parent_hash"
+	// latestFinalizedTable stores the latest finalized batch (single value). Key: mainShardKey, Value: finalizedBatchEntry.
+	latestFinalizedTable db.TableName = "latest_finalized"
 )
 
 var mainShardKey = makeShardKey(types.MainShardId)
 
 type blockEntry struct {
-	Block     jsonrpc.RPCBlock `json:"block"`
-	IsProved  bool             `json:"isProved"`
-	BatchId   scTypes.BatchId  `json:"batchId"`
-	FetchedAt time.Time        `json:"fetchedAt"`
+	Block     jsonrpc.RPCBlock    `json:"block"`
+	Status    scTypes.BatchStatus `json:"status"`
+	BatchId   scTypes.BatchId     `json:"batchId"`
+	FetchedAt time.Time           `json:"fetchedAt"`
+}
+
+// UnmarshalJSON decodes the entry, including the ones stored before the batch lifecycle was introduced.
+// Such entries have the isProved flag instead of the status, and the proof tasks of their batches are already created.
+func (e *blockEntry) UnmarshalJSON(data []byte) error {
+	type entryFields blockEntry
+	var decoded struct {
+		entryFields
+		IsProved *bool `json:"isProved,omitempty"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*e = blockEntry(decoded.entryFields)
+	if e.Status == scTypes.BatchStatusNone && decoded.IsProved != nil {
+		if *decoded.IsProved {
+			e.Status = scTypes.BatchStatusProved
+		} else {
+			e.Status = scTypes.BatchStatusProving
+		}
+	}
+	return nil
+}
+
+// finalizedBatchEntry keeps the latest finalized batch after its blocks are removed from the storage
+type finalizedBatchEntry struct {
+	BatchId   scTypes.BatchId      `json:"batchId"`
+	Block     scTypes.MainBlockRef `json:"block"`
+	FetchedAt time.Time            `json:"fetchedAt"`
 }
 
 type BlockStorageMetrics interface {
@@ -55,7 +90,7 @@ func NewBlockStorage(
 	logger zerolog.Logger,
 ) *BlockStorage {
 	return &BlockStorage{
-		commonStorage: makeCommonStorage(database, logger, common.DoNotRetryIf(scTypes.ErrBlockMismatch)),
+		commonStorage: makeCommonStorage(database, logger, common.DoNotRetryIf(scTypes.ErrBlockMismatch, scTypes.ErrForkBelowProposed)),
 		timer:         timer,
 		metrics:       metrics,
 	}
@@ -172,7 +207,7 @@ func (bs *BlockStorage) setBlockBatchImpl(ctx context.Context, batch *scTypes.Bl
 
 func (bs *BlockStorage) putBlockTx(tx db.RwTx, batchId scTypes.BatchId, block *jsonrpc.RPCBlock) error {
 	currentTime := bs.timer.NowTime()
-	entry := blockEntry{Block: *block, BatchId: batchId, FetchedAt: currentTime}
+	entry := blockEntry{Block: *block, Status: scTypes.BatchStatusFetched, BatchId: batchId, FetchedAt: currentTime}
 	value, err := marshallEntry(&entry)
 	if err != nil {
 		return err
@@ -262,18 +297,13 @@ func (bs *BlockStorage) setBlockAsProvedImpl(ctx context.Context, id scTypes.Blo
 	if entry == nil {
 		return false, fmt.Errorf("block with id=%s is not found", id)
 	}
-	if entry.IsProved {
+	if entry.Status >= scTypes.BatchStatusProved {
 		bs.logger.Debug().Stringer("blockId", id).Msg("block is already marked as proved")
 		return false, nil
 	}
 
-	entry.IsProved = true
-	value, err := marshallEntry(entry)
-	if err != nil {
-		return false, err
-	}
-
-	if err := tx.Put(blocksTable, id.Bytes(), value); err != nil {
+	entry.Status = scTypes.BatchStatusProved
+	if err := bs.putEntryTx(tx, entry); err != nil {
 		return false, err
 	}
 
@@ -325,6 +355,63 @@ func (bs *BlockStorage) TryGetNextProposalData(ctx context.Context) (*scTypes.Pr
 		return nil, nil
 	}
 
+	data, err := bs.makeProposalDataTx(tx, mainShardEntry)
+	if err != nil {
+		return nil, err
+	}
+	data.OldProvedStateRoot = *currentProvedStateRoot
+	return data, nil
+}
+
+// TryGetNextBatchToCommit returns the first proved, but not committed batch following the latest proposed block.
+// Batches are committed strictly in order, so nil is returned if some preceding batch is still being proved.
+// Nil is also returned if maxInFlight batches are already committed, but not finalized.
+// OldProvedStateRoot of the returned data is not set, as preceding batches might be not finalized yet.
+func (bs *BlockStorage) TryGetNextBatchToCommit(ctx context.Context, maxInFlight int) (*scTypes.ProposalData, error) {
+	tx, err := bs.database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	parentHash, err := bs.getParentOfNextToPropose(tx)
+	if err != nil {
+		return nil, err
+	}
+	if parentHash == nil {
+		return nil, nil
+	}
+
+	byParent := make(map[common.Hash]*blockEntry)
+	err = iterateOverEntries(tx, func(entry *blockEntry) (bool, error) {
+		if entry.Block.ShardId == types.MainShardId {
+			byParent[entry.Block.ParentHash] = entry
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	committed := 0
+	for entry := byParent[*parentHash]; entry != nil; entry = byParent[entry.Block.Hash] {
+		switch entry.Status {
+		case scTypes.BatchStatusCommitted:
+			committed++
+			continue
+		case scTypes.BatchStatusProved:
+			if committed >= maxInFlight {
+				return nil, nil
+			}
+			return bs.makeProposalDataTx(tx, entry)
+		default:
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
+
+func (bs *BlockStorage) makeProposalDataTx(tx db.RoTx, mainShardEntry *blockEntry) (*scTypes.ProposalData, error) {
 	transactions := scTypes.BlockTransactions(&mainShardEntry.Block)
 
 	childIds, err := scTypes.ChildBlockIds(&mainShardEntry.Block)
@@ -346,14 +433,170 @@ func (bs *BlockStorage) TryGetNextProposalData(ctx context.Context) (*scTypes.Pr
 	}
 
 	return &scTypes.ProposalData{
+		BatchId:            mainShardEntry.BatchId,
 		MainShardBlockHash: mainShardEntry.Block.Hash,
 		Transactions:       transactions,
-		OldProvedStateRoot: *currentProvedStateRoot,
 		NewProvedStateRoot: mainShardEntry.Block.ChildBlocksRootHash,
 		MainBlockFetchedAt: mainShardEntry.FetchedAt,
+		Committed:          mainShardEntry.Status >= scTypes.BatchStatusCommitted,
 	}, nil
 }
 
+// SetBatchAsCommitted marks the main shard block batch as committed to L1.
+func (bs *BlockStorage) SetBatchAsCommitted(ctx context.Context, id scTypes.BlockId) error {
+	return bs.retryRunner.Do(ctx, func(ctx context.Context) error {
+		return bs.updateMainEntry(ctx, id, func(entry *blockEntry) error {
+			if entry.Status < scTypes.BatchStatusProved {
+				return fmt.Errorf("block with id=%s is not proved", id)
+			}
+			entry.Status = scTypes.BatchStatusCommitted
+			return nil
+		})
+	})
+}
+
+// SetBatchAsProving marks the main shard block batch as admitted to proving.
+func (bs *BlockStorage) SetBatchAsProving(ctx context.Context, id scTypes.BlockId) error {
+	return bs.retryRunner.Do(ctx, func(ctx context.Context) error {
+		return bs.updateMainEntry(ctx, id, func(entry *blockEntry) error {
+			if entry.Status < scTypes.BatchStatusProving {
+				entry.Status = scTypes.BatchStatusProving
+			}
+			return nil
+		})
+	})
+}
+
+func (bs *BlockStorage) updateMainEntry(ctx context.Context, id scTypes.BlockId, update func(entry *blockEntry) error) error {
+	tx, err := bs.database.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	entry, err := bs.getBlockEntry(tx, id)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return fmt.Errorf("block with id=%s is not found", id)
+	}
+	if entry.Block.ShardId != types.MainShardId {
+		return fmt.Errorf("block with id=%s is not from main shard", id)
+	}
+
+	if err := update(entry); err != nil {
+		return err
+	}
+	if err := bs.putEntryTx(tx, entry); err != nil {
+		return err
+	}
+	return bs.commit(tx)
+}
+
+// GetBatchesToProve returns fetched batches which can be admitted to proving, ordered by the main shard block number.
+// The number of batches being proved, committed or finalized concurrently is limited by maxInFlight.
+func (bs *BlockStorage) GetBatchesToProve(ctx context.Context, maxInFlight int) ([]*scTypes.BlockBatch, error) {
+	tx, err := bs.database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	inFlight := 0
+	var fetched []*blockEntry
+	err = iterateOverEntries(tx, func(entry *blockEntry) (bool, error) {
+		if entry.Block.ShardId != types.MainShardId {
+			return true, nil
+		}
+		switch {
+		case entry.Status.IsInFlight():
+			inFlight++
+		case entry.Status == scTypes.BatchStatusFetched:
+			fetched = append(fetched, entry)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(fetched, func(l, r *blockEntry) int {
+		return cmp.Compare(l.Block.Number, r.Block.Number)
+	})
+	fetched = fetched[:max(0, min(len(fetched), maxInFlight-inFlight))]
+
+	batches := make([]*scTypes.BlockBatch, 0, len(fetched))
+	for _, mainShardEntry := range fetched {
+		batch := &scTypes.BlockBatch{Id: mainShardEntry.BatchId, MainShardBlock: &mainShardEntry.Block}
+
+		childIds, err := scTypes.ChildBlockIds(&mainShardEntry.Block)
+		if err != nil {
+			return nil, err
+		}
+		for _, childId := range childIds {
+			childEntry, err := bs.getBlockEntry(tx, childId)
+			if err != nil {
+				return nil, err
+			}
+			if childEntry == nil {
+				return nil, fmt.Errorf("child block with id=%s is not found", childId)
+			}
+			batch.ChildBlocks = append(batch.ChildBlocks, &childEntry.Block)
+		}
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// GetBatchViews returns the lifecycle state of all batches which are not finalized yet
+// along with the latest finalized one, ordered by the main shard block number.
+func (bs *BlockStorage) GetBatchViews(ctx context.Context) ([]*public.BatchView, error) {
+	tx, err := bs.database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var views []*public.BatchView
+
+	finalized, err := bs.getLatestFinalizedTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if finalized != nil {
+		views = append(views, &public.BatchView{
+			Id:              finalized.BatchId,
+			MainBlockHash:   finalized.Block.Hash,
+			MainBlockNumber: finalized.Block.Number,
+			Status:          scTypes.BatchStatusFinalized,
+			FetchedAt:       finalized.FetchedAt,
+		})
+	}
+
+	err = iterateOverEntries(tx, func(entry *blockEntry) (bool, error) {
+		if entry.Block.ShardId == types.MainShardId {
+			views = append(views, &public.BatchView{
+				Id:              entry.BatchId,
+				MainBlockHash:   entry.Block.Hash,
+				MainBlockNumber: entry.Block.Number,
+				ChildBlocks:     len(entry.Block.ChildBlocks),
+				Status:          entry.Status,
+				FetchedAt:       entry.FetchedAt,
+			})
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(views, func(l, r *public.BatchView) int {
+		return cmp.Compare(l.MainBlockNumber, r.MainBlockNumber)
+	})
+	return views, nil
+}
+
 func (bs *BlockStorage) SetBlockAsProposed(ctx context.Context, id scTypes.BlockId) error {
 	return bs.retryRunner.Do(ctx, func(ctx context.Context) error {
 		return bs.setBlockAsProposedImpl(ctx, id)
@@ -376,36 +619,142 @@ func (bs *BlockStorage) setBlockAsProposedImpl(ctx context.Context, id scTypes.B
 		return err
 	}
 
-	childIds, err := scTypes.ChildBlockIds(&mainShardEntry.Block)
-	if err != nil {
+	if err := bs.deleteMainShardEntryTx(tx, mainShardEntry); err != nil {
 		return err
 	}
 
-	for _, childId := range childIds {
-		if err := tx.Delete(blocksTable, childId.Bytes()); err != nil {
-			return fmt.Errorf("failed to delete child block with id=%s: %w", childId, err)
+	if err := tx.Put(stateRootTable, mainShardKey, mainShardEntry.Block.ChildBlocksRootHash.Bytes()); err != nil {
+		return fmt.Errorf("failed to put state root: %w", err)
+	}
+
+	if err := bs.setParentOfNextToPropose(tx, mainShardEntry.Block.Hash); err != nil {
+		return err
+	}
+
+	if err := bs.putLatestFinalizedTx(tx, mainShardEntry); err != nil {
+		return err
+	}
+
+	return bs.commit(tx)
+}
+
+// RollbackLatestFetched removes main shard blocks above the given common ancestor along with their child blocks
+// and sets the ancestor as the latest fetched block. Returns ids of the removed batches.
+func (bs *BlockStorage) RollbackLatestFetched(
+	ctx context.Context, ancestor scTypes.MainBlockRef,
+) ([]scTypes.BatchId, error) {
+	var removed []scTypes.BatchId
+	err := bs.retryRunner.Do(ctx, func(ctx context.Context) error {
+		var err error
+		removed, err = bs.resetLatestFetchedImpl(ctx, ancestor, nil)
+		return err
+	})
+	return removed, err
+}
+
+// ResetToBlock removes all stored blocks and makes the given main shard block both the latest fetched
+// and the latest proposed one, so the proved state root is set from it. Returns ids of the removed batches.
+func (bs *BlockStorage) ResetToBlock(ctx context.Context, block *jsonrpc.RPCBlock) ([]scTypes.BatchId, error) {
+	var removed []scTypes.BatchId
+	err := bs.retryRunner.Do(ctx, func(ctx context.Context) error {
+		blockRef, err := scTypes.NewBlockRef(block)
+		if err != nil {
+			return err
 		}
+		removed, err = bs.resetLatestFetchedImpl(ctx, *blockRef, &block.ChildBlocksRootHash)
+		return err
+	})
+	return removed, err
+}
+
+// resetLatestFetchedImpl removes main shard blocks above the given one, or all of them if the proved state root
+// is given. In the latter case the block also becomes the latest proposed one with the given proved state root.
+// Otherwise, ErrForkBelowProposed is returned if some of the removed batches are already committed to L1.
+func (bs *BlockStorage) resetLatestFetchedImpl(
+	ctx context.Context, block scTypes.MainBlockRef, provedStateRoot *common.Hash,
+) ([]scTypes.BatchId, error) {
+	resetProposed := provedStateRoot != nil
+
+	tx, err := bs.database.CreateRwTx(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	mainBlockId := scTypes.IdFromBlock(&mainShardEntry.Block)
-	if err := tx.Delete(blocksTable, mainBlockId.Bytes()); err != nil {
-		return fmt.Errorf("failed to delete main shard block with id=%s: %w", mainBlockId, err)
+	var toRemove []*blockEntry
+	err = iterateOverEntries(tx, func(entry *blockEntry) (bool, error) {
+		if entry.Block.ShardId == types.MainShardId && (resetProposed || entry.Block.Number > block.Number) {
+			toRemove = append(toRemove, entry)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := tx.Put(stateRootTable, mainShardKey, mainShardEntry.Block.ChildBlocksRootHash.Bytes()); err != nil {
-		return fmt.Errorf("failed to put state root: %w", err)
+	// Committed batches are already sent to L1, so they can be dropped only by the forced reset
+	if !resetProposed {
+		for _, entry := range toRemove {
+			if entry.Status >= scTypes.BatchStatusCommitted {
+				return nil, fmt.Errorf("%w: batch with id=%s is committed above the common block %s",
+					scTypes.ErrForkBelowProposed, entry.BatchId, block.Hash)
+			}
+		}
 	}
 
-	if err := bs.setParentOfNextToPropose(tx, mainShardEntry.Block.Hash); err != nil {
+	removed := make([]scTypes.BatchId, 0, len(toRemove))
+	for _, entry := range toRemove {
+		if err := bs.deleteMainShardEntryTx(tx, entry); err != nil {
+			return nil, err
+		}
+		removed = append(removed, entry.BatchId)
+	}
+
+	if resetProposed {
+		if err := bs.setParentOfNextToPropose(tx, block.Hash); err != nil {
+			return nil, err
+		}
+		if err := tx.Delete(latestFinalizedTable, mainShardKey); err != nil {
+			return nil, fmt.Errorf("failed to delete latest finalized batch: %w", err)
+		}
+		if err := tx.Put(stateRootTable, mainShardKey, provedStateRoot.Bytes()); err != nil {
+			return nil, fmt.Errorf("failed to put state root: %w", err)
+		}
+	}
+
+	if err := bs.putLatestFetchedBlockTx(tx, types.MainShardId, block); err != nil {
+		return nil, err
+	}
+
+	if err := bs.commit(tx); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// deleteMainShardEntryTx deletes main shard block with all its child blocks
+func (bs *BlockStorage) deleteMainShardEntryTx(tx db.RwTx, entry *blockEntry) error {
+	childIds, err := scTypes.ChildBlockIds(&entry.Block)
+	if err != nil {
 		return err
 	}
 
-	return bs.commit(tx)
+	for _, childId := range childIds {
+		if err := tx.Delete(blocksTable, childId.Bytes()); err != nil {
+			return fmt.Errorf("failed to delete child block with id=%s: %w", childId, err)
+		}
+	}
+
+	mainBlockId := scTypes.IdFromBlock(&entry.Block)
+	if err := tx.Delete(blocksTable, mainBlockId.Bytes()); err != nil {
+		return fmt.Errorf("failed to delete main shard block with id=%s: %w", mainBlockId, err)
+	}
+	return nil
 }
 
 func isValidProposalCandidate(entry *blockEntry, parentHash common.Hash) bool {
 	return entry.Block.ShardId == types.MainShardId &&
-		entry.IsProved &&
+		entry.Status >= scTypes.BatchStatusProved &&
 		entry.Block.ParentHash == parentHash
 }
 
@@ -443,7 +792,7 @@ func (bs *BlockStorage) validateMainShardEntry(tx db.RoTx, id scTypes.BlockId, e
 		return fmt.Errorf("block with id=%s is not from main shard", id.String())
 	}
 
-	if !entry.IsProved {
+	if entry.Status < scTypes.BatchStatusProved {
 		return fmt.Errorf("block with id=%s is not proved", id.String())
 	}
 
@@ -457,7 +806,8 @@ func (bs *BlockStorage) validateMainShardEntry(tx db.RoTx, id scTypes.BlockId, e
 
 	if *parentHash != entry.Block.ParentHash {
 		return fmt.Errorf(
-			"parent's block hash=%s is not equal to the stored value=%s",
+			"%w: parent's block hash=%s is not equal to the stored value=%s",
+			scTypes.ErrBlockMismatch,
 			entry.Block.ParentHash.String(),
 			parentHash.String(),
 		)
@@ -496,6 +846,42 @@ func (bs *BlockStorage) putLatestFetchedBlockTx(tx db.RwTx, shardId types.ShardI
 	return nil
 }
 
+func (bs *BlockStorage) getLatestFinalizedTx(tx db.RoTx) (*finalizedBatchEntry, error) {
+	value, err := tx.Get(latestFinalizedTable, mainShardKey)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &finalizedBatchEntry{}
+	if err := json.Unmarshal(value, entry); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode latest finalized batch: %w", ErrSerializationFailed, err)
+	}
+	return entry, nil
+}
+
+func (bs *BlockStorage) putLatestFinalizedTx(tx db.RwTx, mainShardEntry *blockEntry) error {
+	blockRef, err := scTypes.NewBlockRef(&mainShardEntry.Block)
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(&finalizedBatchEntry{
+		BatchId:   mainShardEntry.BatchId,
+		Block:     *blockRef,
+		FetchedAt: mainShardEntry.FetchedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: failed to encode finalized batch with id=%s: %w", ErrSerializationFailed, mainShardEntry.BatchId, err)
+	}
+	if err := tx.Put(latestFinalizedTable, mainShardKey, value); err != nil {
+		return fmt.Errorf("failed to put latest finalized batch: %w", err)
+	}
+	return nil
+}
+
 func makeShardKey(shardId types.ShardId) []byte {
 	key := make([]byte, 4)
 	binary.LittleEndian.PutUint32(key, uint32(shardId))
@@ -548,6 +934,19 @@ func iterateOverEntries(tx db.RoTx, action func(entry *blockEntry) (shouldContin
 	return nil
 }
 
+func (bs *BlockStorage) putEntryTx(tx db.RwTx, entry *blockEntry) error {
+	value, err := marshallEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	blockId := scTypes.IdFromBlock(&entry.Block)
+	if err := tx.Put(blocksTable, blockId.Bytes(), value); err != nil {
+		return fmt.Errorf("failed to put block %s: %w", blockId, err)
+	}
+	return nil
+}
+
 func marshallEntry(entry *blockEntry) ([]byte, error) {
 	bytes, err := json.Marshal(entry)
 	if err != nil {
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"sync"
 	"testing"
 	"time"
@@ -163,6 +164,291 @@ func (s *BlockStorageTestSuite) TestSetBlockBatch_ParentHashMismatch() {
 	s.Require().ErrorContains(err, "unable to update latest fetched block: block mismatch")
 }
 
+func (s *BlockStorageTestSuite) TestRollbackLatestFetched() {
+	batches := testaide.NewBatchesSequence(4)
+	for _, batch := range batches {
+		err := s.bs.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+	}
+
+	ancestor, err := scTypes.NewBlockRef(batches[1].MainShardBlock)
+	s.Require().NoError(err)
+
+	removed, err := s.bs.RollbackLatestFetched(s.ctx, *ancestor)
+	s.Require().NoError(err)
+	s.Require().ElementsMatch([]scTypes.BatchId{batches[2].Id, batches[3].Id}, removed)
+
+	latestFetched, err := s.bs.TryGetLatestFetched(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(ancestor, latestFetched)
+
+	for idx, batch := range batches {
+		s.requireBatchStored(batch, idx <= 1)
+	}
+
+	// fetching continues from the common ancestor
+	forkBatch := testaide.NewBlockBatch(testaide.ShardsCount)
+	forkBatch.MainShardBlock.ParentHash = ancestor.Hash
+	forkBatch.MainShardBlock.Number = ancestor.Number + 1
+	err = s.bs.SetBlockBatch(s.ctx, forkBatch)
+	s.Require().NoError(err)
+
+	// blocks below the ancestor are still proposed in order
+	err = s.bs.SetProvedStateRoot(s.ctx, testaide.RandomHash())
+	s.Require().NoError(err)
+	err = s.bs.SetBlockAsProved(s.ctx, scTypes.IdFromBlock(batches[0].MainShardBlock))
+	s.Require().NoError(err)
+	data, err := s.bs.TryGetNextProposalData(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(data)
+	s.Require().Equal(batches[0].MainShardBlock.Hash, data.MainShardBlockHash)
+}
+
+func (s *BlockStorageTestSuite) TestRollbackLatestFetched_CommittedAboveAncestor() {
+	batches := testaide.NewBatchesSequence(3)
+	for _, batch := range batches {
+		err := s.bs.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+	}
+	for _, batch := range batches[:2] {
+		err := s.bs.SetBlockAsProved(s.ctx, scTypes.IdFromBlock(batch.MainShardBlock))
+		s.Require().NoError(err)
+	}
+	err := s.bs.SetBatchAsCommitted(s.ctx, scTypes.IdFromBlock(batches[1].MainShardBlock))
+	s.Require().NoError(err)
+
+	ancestor, err := scTypes.NewBlockRef(batches[0].MainShardBlock)
+	s.Require().NoError(err)
+
+	_, err = s.bs.RollbackLatestFetched(s.ctx, *ancestor)
+	s.Require().ErrorIs(err, scTypes.ErrForkBelowProposed)
+
+	latestFetched, err := s.bs.TryGetLatestFetched(s.ctx)
+	s.Require().NoError(err)
+	s.Require().True(latestFetched.Equals(batches[2].MainShardBlock))
+	for _, batch := range batches {
+		s.requireBatchStored(batch, true)
+	}
+}
+
+func (s *BlockStorageTestSuite) TestResetToBlock() {
+	batches := testaide.NewBatchesSequence(3)
+	for _, batch := range batches {
+		err := s.bs.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+	}
+	err := s.bs.SetProvedStateRoot(s.ctx, testaide.RandomHash())
+	s.Require().NoError(err)
+
+	resetBlock := testaide.NewMainShardBlock()
+	resetRef, err := scTypes.NewBlockRef(resetBlock)
+	s.Require().NoError(err)
+
+	removed, err := s.bs.ResetToBlock(s.ctx, resetBlock)
+	s.Require().NoError(err)
+	s.Require().Len(removed, len(batches))
+
+	// the state root of the reset block replaces the previously proved one
+	stateRoot, err := s.bs.TryGetProvedStateRoot(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(stateRoot)
+	s.Require().Equal(resetBlock.ChildBlocksRootHash, *stateRoot)
+
+	latestFetched, err := s.bs.TryGetLatestFetched(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(resetRef, latestFetched)
+
+	for _, batch := range batches {
+		s.requireBatchStored(batch, false)
+	}
+
+	// the next block after the reset one is the next to propose
+	nextBatch := testaide.NewBlockBatch(testaide.ShardsCount)
+	nextBatch.MainShardBlock.ParentHash = resetRef.Hash
+	nextBatch.MainShardBlock.Number = resetRef.Number + 1
+	err = s.bs.SetBlockBatch(s.ctx, nextBatch)
+	s.Require().NoError(err)
+
+	err = s.bs.SetBlockAsProved(s.ctx, scTypes.IdFromBlock(nextBatch.MainShardBlock))
+	s.Require().NoError(err)
+	data, err := s.bs.TryGetNextProposalData(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(data)
+	s.Require().Equal(nextBatch.MainShardBlock.Hash, data.MainShardBlockHash)
+	s.Require().Equal(resetBlock.ChildBlocksRootHash, data.OldProvedStateRoot)
+}
+
+func (s *BlockStorageTestSuite) TestGetBatchesToProve() {
+	const maxInFlight = 2
+	batches := testaide.NewBatchesSequence(4)
+	for _, batch := range batches {
+		err := s.bs.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+	}
+
+	toProve, err := s.bs.GetBatchesToProve(s.ctx, maxInFlight)
+	s.Require().NoError(err)
+	s.Require().Len(toProve, maxInFlight)
+	for i, batch := range toProve {
+		s.Require().Equal(batches[i].Id, batch.Id)
+		s.Require().Len(batch.ChildBlocks, len(batches[i].ChildBlocks))
+		err := s.bs.SetBatchAsProving(s.ctx, scTypes.IdFromBlock(batch.MainShardBlock))
+		s.Require().NoError(err)
+	}
+
+	toProve, err = s.bs.GetBatchesToProve(s.ctx, maxInFlight)
+	s.Require().NoError(err)
+	s.Require().Empty(toProve, "all in-flight slots are taken")
+}
+
+func (s *BlockStorageTestSuite) TestTryGetNextBatchToCommit() {
+	const maxInFlight = 4
+	batches := testaide.NewBatchesSequence(3)
+	for _, batch := range batches {
+		err := s.bs.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+	}
+
+	err := s.bs.SetBatchAsCommitted(s.ctx, scTypes.IdFromBlock(batches[1].MainShardBlock))
+	s.Require().Error(err, "batch can't be committed before it's proved")
+
+	// batches are committed in order, so the proved second batch waits for the first one
+	err = s.bs.SetBlockAsProved(s.ctx, scTypes.IdFromBlock(batches[1].MainShardBlock))
+	s.Require().NoError(err)
+	data, err := s.bs.TryGetNextBatchToCommit(s.ctx, maxInFlight)
+	s.Require().NoError(err)
+	s.Require().Nil(data)
+
+	err = s.bs.SetBlockAsProved(s.ctx, scTypes.IdFromBlock(batches[0].MainShardBlock))
+	s.Require().NoError(err)
+
+	for _, batch := range batches[:2] {
+		data, err := s.bs.TryGetNextBatchToCommit(s.ctx, maxInFlight)
+		s.Require().NoError(err)
+		s.Require().NotNil(data)
+		s.Require().Equal(batch.Id, data.BatchId)
+		s.Require().Equal(batch.MainShardBlock.Hash, data.MainShardBlockHash)
+
+		err = s.bs.SetBatchAsCommitted(s.ctx, scTypes.IdFromBlock(batch.MainShardBlock))
+		s.Require().NoError(err)
+	}
+
+	data, err = s.bs.TryGetNextBatchToCommit(s.ctx, maxInFlight)
+	s.Require().NoError(err)
+	s.Require().Nil(data)
+
+	views, err := s.bs.GetBatchViews(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(views, len(batches))
+	s.Require().Equal(scTypes.BatchStatusCommitted, views[0].Status)
+	s.Require().Equal(scTypes.BatchStatusCommitted, views[1].Status)
+	s.Require().Equal(scTypes.BatchStatusFetched, views[2].Status)
+
+	// committed batch is still finalized in order
+	err = s.bs.SetProvedStateRoot(s.ctx, testaide.RandomHash())
+	s.Require().NoError(err)
+	proposalData, err := s.bs.TryGetNextProposalData(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(proposalData)
+	s.Require().Equal(batches[0].Id, proposalData.BatchId)
+	s.Require().True(proposalData.Committed)
+}
+
+func (s *BlockStorageTestSuite) TestTryGetNextBatchToCommitInFlightLimit() {
+	const maxInFlight = 1
+	batches := testaide.NewBatchesSequence(2)
+	for _, batch := range batches {
+		err := s.bs.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+		err = s.bs.SetBlockAsProved(s.ctx, scTypes.IdFromBlock(batch.MainShardBlock))
+		s.Require().NoError(err)
+	}
+
+	data, err := s.bs.TryGetNextBatchToCommit(s.ctx, maxInFlight)
+	s.Require().NoError(err)
+	s.Require().NotNil(data)
+	s.Require().Equal(batches[0].Id, data.BatchId)
+	s.Require().False(data.Committed)
+	err = s.bs.SetBatchAsCommitted(s.ctx, scTypes.IdFromBlock(batches[0].MainShardBlock))
+	s.Require().NoError(err)
+
+	// the second batch waits until the first one is finalized
+	data, err = s.bs.TryGetNextBatchToCommit(s.ctx, maxInFlight)
+	s.Require().NoError(err)
+	s.Require().Nil(data)
+
+	err = s.bs.SetProvedStateRoot(s.ctx, testaide.RandomHash())
+	s.Require().NoError(err)
+	err = s.bs.SetBlockAsProposed(s.ctx, scTypes.IdFromBlock(batches[0].MainShardBlock))
+	s.Require().NoError(err)
+
+	data, err = s.bs.TryGetNextBatchToCommit(s.ctx, maxInFlight)
+	s.Require().NoError(err)
+	s.Require().NotNil(data)
+	s.Require().Equal(batches[1].Id, data.BatchId)
+}
+
+// Entries stored before the batch lifecycle was introduced have the isProved flag instead of the status
+func (s *BlockStorageTestSuite) TestLegacyBlockEntries() {
+	const maxInFlight = 4
+	batches := testaide.NewBatchesSequence(2)
+	for _, batch := range batches {
+		err := s.bs.SetBlockBatch(s.ctx, batch)
+		s.Require().NoError(err)
+	}
+	s.putLegacyEntry(batches[0].MainShardBlock, true)
+	s.putLegacyEntry(batches[1].MainShardBlock, false)
+
+	views, err := s.bs.GetBatchViews(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(views, len(batches))
+	s.Require().Equal(scTypes.BatchStatusProved, views[0].Status)
+	s.Require().Equal(scTypes.BatchStatusProving, views[1].Status)
+
+	// the proof tasks of the unproved batch are already created
+	toProve, err := s.bs.GetBatchesToProve(s.ctx, maxInFlight)
+	s.Require().NoError(err)
+	s.Require().Empty(toProve)
+
+	data, err := s.bs.TryGetNextBatchToCommit(s.ctx, maxInFlight)
+	s.Require().NoError(err)
+	s.Require().NotNil(data)
+	s.Require().Equal(batches[0].Id, data.BatchId)
+}
+
+func (s *BlockStorageTestSuite) putLegacyEntry(block *jsonrpc.RPCBlock, isProved bool) {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	key := scTypes.IdFromBlock(block).Bytes()
+	value, err := tx.Get(blocksTable, key)
+	s.Require().NoError(err)
+	var fields map[string]json.RawMessage
+	s.Require().NoError(json.Unmarshal(value, &fields))
+	delete(fields, "status")
+	fields["isProved"], err = json.Marshal(isProved)
+	s.Require().NoError(err)
+	value, err = json.Marshal(fields)
+	s.Require().NoError(err)
+
+	s.Require().NoError(tx.Put(blocksTable, key, value))
+	s.Require().NoError(tx.Commit())
+}
+
+func (s *BlockStorageTestSuite) requireBatchStored(batch *scTypes.BlockBatch, stored bool) {
+	s.T().Helper()
+
+	blocks := append([]*jsonrpc.RPCBlock{batch.MainShardBlock}, batch.ChildBlocks...)
+	for _, block := range blocks {
+		fromDb, err := s.bs.TryGetBlock(s.ctx, scTypes.IdFromBlock(block))
+		s.Require().NoError(err)
+		s.Require().Equal(stored, fromDb != nil, "unexpected presence of block %s", block.Hash)
+	}
+}
+
 func (s *BlockStorageTestSuite) TestSetBlockBatch_ParentMismatch() {
 	const childBlocksCount = 4
 
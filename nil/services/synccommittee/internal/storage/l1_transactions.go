@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	scTypes "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+// pendingL1TxsTable stores L1 transactions which are sent but not confirmed yet.
+// Key: nonce (big-endian uint64), Value: scTypes.PendingL1Transaction.
+const pendingL1TxsTable db.TableName = "pending_l1_transactions"
+
+func (bs *BlockStorage) PutPendingL1Transaction(ctx context.Context, txn *scTypes.PendingL1Transaction) error {
+	return bs.retryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := bs.database.CreateRwTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		value, err := json.Marshal(txn)
+		if err != nil {
+			return fmt.Errorf("%w: failed to encode L1 transaction with nonce=%d: %w", ErrSerializationFailed, txn.Nonce, err)
+		}
+		if err := tx.Put(pendingL1TxsTable, makeNonceKey(txn.Nonce), value); err != nil {
+			return fmt.Errorf("failed to put L1 transaction with nonce=%d: %w", txn.Nonce, err)
+		}
+
+		return bs.commit(tx)
+	})
+}
+
+// GetPendingL1Transactions returns all stored L1 transactions ordered by nonce.
+func (bs *BlockStorage) GetPendingL1Transactions(ctx context.Context) ([]*scTypes.PendingL1Transaction, error) {
+	tx, err := bs.database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	iter, err := tx.Range(pendingL1TxsTable, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var txns []*scTypes.PendingL1Transaction
+	for iter.HasNext() {
+		key, val, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		txn := &scTypes.PendingL1Transaction{}
+		if err := json.Unmarshal(val, txn); err != nil {
+			return nil, fmt.Errorf(
+				"%w: failed to decode L1 transaction with nonce=%d: %w",
+				ErrSerializationFailed, binary.BigEndian.Uint64(key), err,
+			)
+		}
+		txns = append(txns, txn)
+	}
+	return txns, nil
+}
+
+func (bs *BlockStorage) DeletePendingL1Transaction(ctx context.Context, nonce uint64) error {
+	return bs.retryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := bs.database.CreateRwTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := tx.Delete(pendingL1TxsTable, makeNonceKey(nonce)); err != nil {
+			return fmt.Errorf("failed to delete L1 transaction with nonce=%d: %w", nonce, err)
+		}
+
+		return bs.commit(tx)
+	})
+}
+
+func makeNonceKey(nonce uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, nonce)
+	return key
+}
@@ -400,6 +400,49 @@ func (st *TaskStorage) updateDependentsTx(
 	return nil
 }
 
+// RemoveBatchTasks removes all tasks which belong to the given batches.
+func (st *TaskStorage) RemoveBatchTasks(ctx context.Context, batchIds ...types.BatchId) error {
+	if len(batchIds) == 0 {
+		return nil
+	}
+
+	return st.retryRunner.Do(ctx, func(ctx context.Context) error {
+		return st.removeBatchTasksImpl(ctx, batchIds)
+	})
+}
+
+func (st *TaskStorage) removeBatchTasksImpl(ctx context.Context, batchIds []types.BatchId) error {
+	tx, err := st.database.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	toRemove := make(map[types.BatchId]bool, len(batchIds))
+	for _, batchId := range batchIds {
+		toRemove[batchId] = true
+	}
+
+	var taskIds []types.TaskId
+	err = st.iterateOverTaskEntries(tx, func(entry *types.TaskEntry) (bool, error) {
+		if toRemove[entry.Task.BatchId] {
+			taskIds = append(taskIds, entry.Task.Id)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, taskId := range taskIds {
+		if err := tx.Delete(taskEntriesTable, taskId.Bytes()); err != nil {
+			return fmt.Errorf("failed to delete task with id=%s: %w", taskId, err)
+		}
+	}
+
+	return st.commit(tx)
+}
+
 type rescheduledTask struct {
 	taskType         types.TaskType
 	previousExecutor types.TaskExecutorId
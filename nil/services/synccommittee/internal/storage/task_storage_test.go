@@ -226,6 +226,34 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_MultipleTasks() {
 	s.Require().Nil(taskToExecute)
 }
 
+func (s *TaskStorageSuite) Test_RemoveBatchTasks() {
+	now := s.timer.NowTime()
+
+	removedBatchId := types.NewBatchId()
+	entries := []*types.TaskEntry{
+		testaide.NewTaskEntry(now, types.WaitingForExecutor, types.UnknownExecutorId),
+		testaide.NewTaskEntry(now, types.Running, testaide.RandomExecutorId()),
+		testaide.NewTaskEntry(now, types.WaitingForExecutor, types.UnknownExecutorId),
+	}
+	entries[0].Task.BatchId = removedBatchId
+	entries[1].Task.BatchId = removedBatchId
+
+	err := s.ts.AddTaskEntries(s.ctx, entries...)
+	s.Require().NoError(err)
+
+	err = s.ts.RemoveBatchTasks(s.ctx, removedBatchId)
+	s.Require().NoError(err)
+
+	for _, entry := range entries[:2] {
+		removed, err := s.ts.TryGetTaskEntry(s.ctx, entry.Task.Id)
+		s.Require().NoError(err)
+		s.Require().Nil(removed)
+	}
+
+	// Tasks of other batches are kept
+	s.requireExactTasksCount(1)
+}
+
 func (s *TaskStorageSuite) Test_AddSingleTaskEntry_Concurrently() {
 	now := s.timer.NowTime()
 
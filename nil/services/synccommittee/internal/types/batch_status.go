@@ -0,0 +1,23 @@
+package types
+
+// BatchStatus represents a stage of the block batch lifecycle in the sync committee.
+type BatchStatus uint8
+
+const (
+	BatchStatusNone BatchStatus = iota
+	// BatchStatusFetched indicates that the batch is stored, but it's not admitted to proving yet.
+	BatchStatusFetched
+	// BatchStatusProving indicates that the proof tasks of the batch are created.
+	BatchStatusProving
+	// BatchStatusProved indicates that the aggregated batch proof is ready.
+	BatchStatusProved
+	// BatchStatusCommitted indicates that the batch data is committed to L1.
+	BatchStatusCommitted
+	// BatchStatusFinalized indicates that the batch state is finalized on L1 via UpdateState.
+	BatchStatusFinalized
+)
+
+// IsInFlight checks whether the batch is admitted to proving, but not finalized yet.
+func (s BatchStatus) IsInFlight() bool {
+	return s >= BatchStatusProving && s < BatchStatusFinalized
+}
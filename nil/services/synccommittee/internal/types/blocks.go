@@ -192,9 +192,12 @@ func NewTransaction(transaction *jsonrpc.RPCInTransaction) *PrunedTransaction {
 }
 
 type ProposalData struct {
+	BatchId            BatchId
 	MainShardBlockHash common.Hash
 	Transactions       []*PrunedTransaction
 	OldProvedStateRoot common.Hash
 	NewProvedStateRoot common.Hash
 	MainBlockFetchedAt time.Time
+	// Committed indicates that the batch data is already committed to L1.
+	Committed bool
 }
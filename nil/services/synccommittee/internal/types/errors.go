@@ -9,8 +9,11 @@ import (
 )
 
 var (
-	ErrBlockMismatch   = errors.New("block mismatch")
-	ErrBlockProcessing = errors.New("block processing error")
+	ErrBlockMismatch     = errors.New("block mismatch")
+	ErrBlockProcessing   = errors.New("block processing error")
+	ErrForkBelowProposed = errors.New(
+		"main shard chain diverged below the latest proposed or committed block, manual reset is required",
+	)
 )
 
 var (
@@ -5,3 +5,4 @@ package types
 //go:generate stringer -type=TaskStatus -trimprefix=TaskStatus
 //go:generate stringer -type=CircuitType -trimprefix=Circuit
 //go:generate stringer -type=TaskErrType -trimprefix=TaskErr
+//go:generate stringer -type=BatchStatus -trimprefix=BatchStatus
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+)
+
+// L1BlockRef represents a reference to a specific L1 block
+type L1BlockRef struct {
+	Hash   common.Hash `json:"hash"`
+	Number uint64      `json:"number"`
+}
+
+// PendingL1Transaction is a transaction sent to L1 by the sync committee which is not confirmed yet.
+// All versions of the transaction share the same nonce, each fee bump appends a new version.
+type PendingL1Transaction struct {
+	// BatchId is the index of the batch the transaction belongs to.
+	BatchId string `json:"batchId"`
+	// Method is the name of the rollup contract method called by the transaction.
+	Method string `json:"method"`
+	Nonce  uint64 `json:"nonce"`
+	// Versions contains binary encoded signed transactions, the latest version is the last one.
+	Versions []hexutil.Bytes `json:"versions"`
+	// SentAt is the time the latest version was sent at.
+	SentAt time.Time `json:"sentAt"`
+	// IncludedIn references the L1 block the transaction was last seen in, nil if it is not included yet.
+	IncludedIn *L1BlockRef `json:"includedIn,omitempty"`
+}
+
+func (t *PendingL1Transaction) LatestVersion() hexutil.Bytes {
+	return t.Versions[len(t.Versions)-1]
+}
@@ -762,10 +762,24 @@ func (tsdb *TracerStateDB) AddOutRequestTransaction(
 	payload *types.InternalTransactionPayload,
 	responseProcessingGas types.Gas,
 	isAwait bool,
+	timeout uint64,
 ) (*types.Transaction, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (tsdb *TracerStateDB) ScheduleCall(
+	caller types.Address,
+	payload *types.InternalTransactionPayload,
+	blockId types.BlockNumber,
+	timestamp uint64,
+) (common.Hash, error) {
+	return common.EmptyHash, errors.New("not implemented")
+}
+
+func (tsdb *TracerStateDB) CancelScheduledCall(caller types.Address, id common.Hash) (*types.ScheduledCall, error) {
+	return nil, errors.New("not implemented")
+}
+
 // Get current transaction
 func (tsdb *TracerStateDB) GetInTransaction() *types.Transaction {
 	if len(tsdb.InTransactions) == 0 {
@@ -0,0 +1,37 @@
+package public
+
+import (
+	"context"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+const (
+	DebugGetBatches = DebugNamespace + "_getBatches"
+)
+
+type BatchStatus = types.BatchStatus
+
+// BatchView represents the lifecycle state of a block batch
+type BatchView struct {
+	Id              BatchId     `json:"id"`
+	MainBlockHash   common.Hash `json:"mainBlockHash"`
+	MainBlockNumber BlockNumber `json:"mainBlockNumber"`
+	ChildBlocks     int         `json:"childBlocks"`
+	Status          BatchStatus `json:"status"`
+	FetchedAt       time.Time   `json:"fetchedAt"`
+}
+
+// BatchDebugApi provides methods to retrieve debug information on block batches.
+type BatchDebugApi interface {
+	// GetBatches retrieves all batches which are not finalized yet along with the latest finalized one.
+	GetBatches(ctx context.Context) ([]*BatchView, error)
+}
+
+// DebugApi combines all debug methods exposed by the sync committee.
+type DebugApi interface {
+	TaskDebugApi
+	BatchDebugApi
+}
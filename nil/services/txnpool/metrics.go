@@ -0,0 +1,49 @@
+package txnpool
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type metricsHandler struct {
+	shardAttr attribute.KeyValue
+
+	discarded telemetry.Counter
+}
+
+func newMetricsHandler(p *TxnPool) (*metricsHandler, error) {
+	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/services/txnpool")
+
+	mh := &metricsHandler{shardAttr: telattr.ShardId(p.cfg.ShardId)}
+
+	var err error
+	mh.discarded, err = meter.Int64Counter("txnpool_discarded",
+		metric.WithDescription("Transactions rejected by or removed from the pool, by reason"))
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := meter.Int64ObservableGauge("txnpool_size",
+		metric.WithDescription("Number of transactions in the pool"))
+	if err != nil {
+		return nil, err
+	}
+
+	option := metric.WithAttributes(mh.shardAttr)
+	if _, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(size, int64(p.TransactionCount()), option)
+		return nil
+	}, size); err != nil {
+		return nil, err
+	}
+
+	return mh, nil
+}
+
+func (mh *metricsHandler) RecordDiscarded(reason DiscardReason) {
+	mh.discarded.Add(context.Background(), 1, telattr.With(mh.shardAttr, attribute.String("reason", reason.String())))
+}
@@ -52,6 +52,8 @@ type TxnPool struct {
 	all    *ByReceiverAndSeqno // from => (sorted map of txn seqno => *txn)
 	queue  *TxnQueue
 	logger zerolog.Logger
+
+	metrics *metricsHandler
 }
 
 func New(ctx context.Context, cfg Config, networkManager *network.Manager) (*TxnPool, error) {
@@ -71,6 +73,12 @@ func New(ctx context.Context, cfg Config, networkManager *network.Manager) (*Txn
 		logger: logger,
 	}
 
+	var err error
+	res.metrics, err = newMetricsHandler(res)
+	if err != nil {
+		return nil, err
+	}
+
 	if networkManager == nil {
 		// we don't always want to run the network (e.g., in tests)
 		return res, nil
@@ -155,16 +163,19 @@ func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
 
 		if reason, ok := p.validateTxn(txn); !ok {
 			discardReasons[i] = reason
+			p.metrics.RecordDiscarded(reason)
 			continue
 		}
 
 		if _, ok := p.byHash[string(txn.hash.Bytes())]; ok {
 			discardReasons[i] = DuplicateHash
+			p.metrics.RecordDiscarded(DuplicateHash)
 			continue
 		}
 
 		if reason := p.addLocked(txn); reason != NotSet {
 			discardReasons[i] = reason
+			p.metrics.RecordDiscarded(reason)
 			continue
 		}
 		discardReasons[i] = NotSet // unnecessary
@@ -194,6 +205,16 @@ func (p *TxnPool) validateTxn(txn *metaTxn) (DiscardReason, bool) {
 		return InvalidChainId, false
 	}
 
+	if txn.HasPaymaster() && txn.Paymaster.ShardId() != txn.To.ShardId() {
+		return InvalidPaymaster, false
+	}
+
+	if txn.IsBatch() {
+		if _, err := types.ParseBatchPayload(txn.Data); err != nil {
+			return InvalidBatch, false
+		}
+	}
+
 	return NotSet, true
 }
 
@@ -286,6 +307,7 @@ func (p *TxnPool) discardLocked(mm *metaTxn, reason DiscardReason) {
 	hashStr := string(mm.hash.Bytes())
 	delete(p.byHash, hashStr)
 	p.all.delete(mm, reason)
+	p.metrics.RecordDiscarded(reason)
 }
 
 func (p *TxnPool) Discard(_ context.Context, txns []*types.Transaction, reason DiscardReason) error {
@@ -121,6 +121,27 @@ func (s *SuiteTxnPool) TestAdd() {
 	s.addTransactionsSuccessfully(otherAddressTxn)
 }
 
+func (s *SuiteTxnPool) TestAddWithPaymaster() {
+	txn := newTransaction(0, 123)
+	txn.Paymaster = types.ShardAndHexToAddress(1, "beef")
+	s.addTransactionWithDiscardReason(txn, InvalidPaymaster)
+
+	txn.Paymaster = types.ShardAndHexToAddress(0, "beef")
+	s.addTransactionsSuccessfully(txn)
+}
+
+func (s *SuiteTxnPool) TestAddBatch() {
+	txn := newTransaction(0, 123)
+	txn.Flags = types.NewTransactionFlags(types.TransactionFlagBatch)
+	txn.Data = []byte("not a batch")
+	s.addTransactionWithDiscardReason(txn, InvalidBatch)
+
+	data, err := types.NewBatchPayload(true, types.Code("first"), types.Code("second")).Bytes()
+	s.Require().NoError(err)
+	txn.Data = data
+	s.addTransactionsSuccessfully(txn)
+}
+
 func (s *SuiteTxnPool) TestAddOverflow() {
 	s.pool.cfg.Size = 1
 
@@ -35,6 +35,8 @@ const (
 	NotReplaced         DiscardReason = 20 // There was an existing transaction with the same sender and seqno, not enough price bump to replace
 	DuplicateHash       DiscardReason = 21 // There was an existing transaction with the same hash
 	Unverified          DiscardReason = 22 // Transaction verification failed
+	InvalidPaymaster    DiscardReason = 23 // The paymaster is not in the shard of the transaction recipient
+	InvalidBatch        DiscardReason = 24 // The data of a batched transaction is not a valid batch
 )
 
 func (r DiscardReason) String() string {
@@ -63,6 +65,10 @@ func (r DiscardReason) String() string {
 		return "duplicate hash"
 	case Unverified:
 		return "verification failed"
+	case InvalidPaymaster:
+		return "invalid paymaster"
+	case InvalidBatch:
+		return "invalid batch"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"strings"
 	"testing"
 
@@ -88,6 +89,38 @@ func (s *SuiteCliNoServer) TestCliEncodeInternalTransaction() {
 	s.Contains(expected, res)
 }
 
+func (s *SuiteCliNoServer) TestCliOutputFormat() {
+	s.Run("Json", func() {
+		res := s.RunCli("-o", "json", "abi", "encode", "get", "--path", s.incAbiPath)
+		var out map[string]string
+		s.Require().NoError(json.Unmarshal([]byte(res), &out))
+		s.Equal("0x6d4ce63c", out["calldata"])
+	})
+
+	s.Run("Yaml", func() {
+		res := s.RunCli("--output", "yaml", "abi", "decode", "get",
+			"0x000000000000000000000000000000000000000000000000000000000001e1ba", "--path", s.incAbiPath)
+		s.Equal("result:\n  - type: uint256\n    value: 123322", res)
+	})
+
+	s.Run("ValidationError", func() {
+		res, err := s.RunCliNoCheck("-o", "json", "abi", "encode", "unknownMethod", "--path", s.incAbiPath)
+		var exitErr *exec.ExitError
+		s.Require().ErrorAs(err, &exitErr)
+		s.Equal(2, exitErr.ExitCode())
+
+		var out struct {
+			Error struct {
+				Kind string `json:"kind"`
+				Code int    `json:"code"`
+			} `json:"error"`
+		}
+		s.Require().NoError(json.Unmarshal([]byte(res), &out))
+		s.Equal("validation", out.Error.Kind)
+		s.Equal(2, out.Error.Code)
+	})
+}
+
 func (s *SuiteCliNoServer) TestCliConfig() {
 	cfgPath := s.TmpDir + "/config.ini"
 	endpoint := "localhost:10325"
@@ -336,7 +336,9 @@ faucet_endpoint = {{ .FaucetUrl }}
 
 	res := s.RunCli("-c", cfgPath, "keygen", "new")
 	s.Run("Generate a key", func() {
-		s.Contains(res, "Private key:")
+		s.Contains(res, "Account: default")
+		s.Contains(res, "Public key:")
+		s.NotContains(res, "Private key:")
 	})
 
 	s.Run("Address not specified", func() {
@@ -36,7 +36,7 @@ func runCliNoCheck(t *testing.T, binPath string, args ...string) (string, error)
 	}
 
 	cmd := exec.Command(binPath, args...)
-	cmd.Env = append(os.Environ(), "INVOCATION_ID=")
+	cmd.Env = append(os.Environ(), "INVOCATION_ID=", "NIL_KEYSTORE_PASSPHRASE=test")
 	data, err := cmd.CombinedOutput()
 	return strings.TrimSpace(string(data)), err
 }
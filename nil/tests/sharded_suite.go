@@ -76,6 +76,7 @@ func newZeroState(oldZeroState *execution.ZeroStateConfig, validators []config.L
 			Validators: config.ParamValidators{
 				Validators: validators,
 			},
+			Forks: oldZeroState.ConfigParams.Forks,
 		},
 		Contracts:     oldZeroState.Contracts,
 		MainPublicKey: oldZeroState.MainPublicKey,
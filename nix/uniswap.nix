@@ -64,7 +64,8 @@ stdenv.mkDerivation rec {
 
     nil config set rpc_endpoint $NIL_RPC_ENDPOINT
 
-    export PRIVATE_KEY=`nil keygen new -q`
+    export NIL_KEYSTORE_PASSPHRASE=test
+    export PRIVATE_KEY=`nil keygen new -q --print-private-key`
     export SMART_ACCOUNT_ADDR=`nil smart-account new -q`
 
     echo "Checking uniswap"
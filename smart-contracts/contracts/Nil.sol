@@ -29,6 +29,8 @@ library Nil {
     address private constant SEND_REQUEST = address(0xd8);
     address public constant IS_RESPONSE_TRANSACTION = address(0xd9);
     address public constant LOG = address(0xda);
+    address private constant SCHEDULE_CALL = address(0xdb);
+    address private constant CANCEL_SCHEDULED_CALL = address(0xdc);
 
     // The following constants specify from where and how the gas should be taken during async call.
     // Forwarding values are calculated in the following order: FORWARD_VALUE, FORWARD_PERCENTAGE, FORWARD_REMAINING.
@@ -247,6 +249,87 @@ library Nil {
         __Precompile__(SEND_REQUEST).precompileSendRequest{value: value}(dst, tokens, responseProcessingGas, context, callData);
     }
 
+    /**
+     * @dev Makes an asynchronous call to a contract and waits for the result at most `timeout` blocks of the main shard.
+     * If the response doesn't arrive in time, the call fails and the reserved gas is used to continue the execution.
+     * The value of the call isn't returned in this case, because the call can still be executed.
+     * The timeout is supported since the async-timeouts fork.
+     * @param dst Destination address of the call.
+     * @param responseProcessingGas Amount of gas is being bought and reserved to process the response.
+     *        should be >= `ASYNC_REQUEST_MIN_GAS` to make a call, otherwise `awaitCall` will fail.
+     * @param timeout Number of main shard blocks to wait for the response, zero means no timeout.
+     * @param callData Calldata for the call.
+     * @return returnData Data returned from the call.
+     * @return success Boolean indicating if the call was successful.
+     */
+    function awaitCallWithTimeout(
+        address dst,
+        uint responseProcessingGas,
+        uint timeout,
+        bytes memory callData
+    ) internal returns(bytes memory, bool) {
+        return __Precompile__(AWAIT_CALL).precompileAwaitCallWithTimeout(dst, responseProcessingGas, timeout, callData);
+    }
+
+    /**
+     * @dev Sends a request to a contract, which fails if the response doesn't arrive in `timeout` blocks of the main shard.
+     * The response method is called with `success` set to false in this case.
+     * The timeout is supported since the async-timeouts fork.
+     * @param dst Destination address of the request.
+     * @param value Value to be sent with the request.
+     * @param tokens Array of tokens to be sent with the request.
+     * @param responseProcessingGas Amount of gas is being bought and reserved to process the response.
+     *        Should be >= `ASYNC_REQUEST_MIN_GAS` to make a call, otherwise `sendRequest` will fail.
+     * @param timeout Number of main shard blocks to wait for the response, zero means no timeout.
+     * @param context Context data that is preserved in order to be available in the response method.
+     * @param callData Calldata for the request.
+     */
+    function sendRequestWithTimeout(
+        address dst,
+        uint256 value,
+        Token[] memory tokens,
+        uint responseProcessingGas,
+        uint timeout,
+        bytes memory context,
+        bytes memory callData
+    ) internal {
+        __Precompile__(SEND_REQUEST).precompileSendRequestWithTimeout{value: value}(
+            dst, tokens, responseProcessingGas, timeout, context, callData);
+    }
+
+    /**
+     * @dev Schedules a call to a contract of the same shard. The collator executes the call in the first block
+     * which number and timestamp are not less than the given ones. The fee credit and the value of the call
+     * are withdrawn from the caller, they are returned if the call is cancelled.
+     * The precompile is available since the scheduled-calls fork.
+     * @param dst Destination address of the call. It must be in the shard of the caller.
+     * @param blockNumber The block number at or after which the call is executed.
+     * @param timestamp The block timestamp at or after which the call is executed.
+     * @param feeCredit Fee credit prepaid for the execution of the call.
+     * @param value Value to be sent with the call.
+     * @param callData Calldata for the call.
+     * @return The id of the scheduled call.
+     */
+    function scheduleCall(
+        address dst,
+        uint blockNumber,
+        uint timestamp,
+        uint feeCredit,
+        uint value,
+        bytes memory callData
+    ) internal returns(uint256) {
+        return __Precompile__(SCHEDULE_CALL).precompileScheduleCall{value: feeCredit + value}(
+            dst, blockNumber, timestamp, feeCredit, callData);
+    }
+
+    /**
+     * @dev Cancels the call scheduled by this contract and returns its fee credit and value to the contract.
+     * @param id The id of the scheduled call.
+     */
+    function cancelScheduledCall(uint256 id) internal {
+        __Precompile__(CANCEL_SCHEDULED_CALL).precompileCancelScheduledCall(id);
+    }
+
     /**
      * @dev Sends a raw internal transaction using a special precompiled contract.
      * @param transaction The transaction to be sent.
@@ -416,6 +499,15 @@ library Nil {
         bytes32 hash;
     }
 
+    struct ForkActivation {
+        uint32 fork;
+        uint64 height;
+    }
+
+    struct ParamForks {
+        ForkActivation[] schedule;
+    }
+
     /**
      * @dev Returns the current validators.
      * @return Struct containing the list of validators.
@@ -502,18 +594,23 @@ contract __Precompile__ {
     function precompileAsyncCall(bool, uint8, address, address, address, uint, Nil.Token[] memory, bytes memory) public payable returns(bool) {}
     function precompileAwaitCall(address, uint, bytes memory) public payable returns(bytes memory, bool) {}
     function precompileSendRequest(address, Nil.Token[] memory, uint, bytes memory, bytes memory) public payable returns(bool) {}
+    function precompileAwaitCallWithTimeout(address, uint, uint, bytes memory) public payable returns(bytes memory, bool) {}
+    function precompileSendRequestWithTimeout(address, Nil.Token[] memory, uint, uint, bytes memory, bytes memory) public payable returns(bool) {}
     function precompileSendTokens(address, Nil.Token[] memory) public returns(bool) {}
     function precompileGetTransactionTokens() public returns(Nil.Token[] memory) {}
     function precompileGetGasPrice(uint id) public returns(uint256) {}
     function precompileGetPoseidonHash(bytes memory data) public returns(uint256) {}
     function precompileConfigParam(bool isSet, string calldata name, bytes calldata data) public returns(bytes memory) {}
     function precompileLog(string memory transaction, int[] memory data) public returns(bool) {}
+    function precompileScheduleCall(address, uint, uint, uint, bytes memory) public payable returns(uint256) {}
+    function precompileCancelScheduledCall(uint256 id) public returns(bool) {}
 }
 
 contract NilConfigAbi {
     function curr_validators(Nil.ParamValidators memory) public {}
     function gas_price(Nil.ParamGasPrice memory) public {}
     function l1block(Nil.ParamL1BlockInfo memory) public {}
+    function forks(Nil.ParamForks memory) public {}
 }
 
 function tokenIdEqual(TokenId a, TokenId b) pure returns (bool) {